@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runCheck runs every configured scraper once, prints the results and returns the exit code:
+// 0 when all scrapers are healthy, 1 when any is unhealthy and 2 on usage or setup errors
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	output := flags.String("output", "table", "Output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown output format: %s\n", *output)
+		return 2
+	}
+
+	// Logs go to stderr so they don't mix with the report on stdout
+	logger := newLogger()
+	logger.SetLevel(logrus.WarnLevel)
+
+	cfg, err := config.NewConfig(logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load configuration")
+		return 2
+	}
+
+	manager := healthcheck.NewManager(cfg, logger)
+	if err := manager.Initialize(); err != nil {
+		logger.WithError(err).Error("Failed to initialize healthcheck manager")
+		return 2
+	}
+
+	report := manager.RunOnce(context.Background())
+
+	if *output == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteTable(os.Stdout)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to write report")
+		return 2
+	}
+
+	if !report.Healthy {
+		return 1
+	}
+	return 0
+}
@@ -1,21 +1,46 @@
 package main
 
 import (
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"text/tabwriter"
+	// Embed the timezone database for active hours, as the container image has none
+	_ "time/tzdata"
 
 	"healthcheck/pkg/config"
 	"healthcheck/pkg/healthcheck"
+	"healthcheck/pkg/kafka"
+	"healthcheck/pkg/metrics"
+	"healthcheck/pkg/otlp"
+	"healthcheck/pkg/scraper"
 
 	"github.com/sirupsen/logrus"
 )
 
+// version is the version of the build, set with -ldflags "-X main.version=<version>"
+var version = "dev"
+
 func main() {
+	// Handle subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "list-types":
+			listTypes(os.Stdout)
+			return
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", os.Args[1])
+			os.Exit(2)
+		}
+	}
+
 	// Setup logging
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.JSONFormatter{})
-	logger.SetLevel(logrus.InfoLevel)
+	logger := newLogger()
 
 	// Load configuration
 	cfg, err := config.NewConfig(logger)
@@ -24,7 +49,7 @@ func main() {
 	}
 
 	// Validate configuration
-	if len(cfg.Scrapers) == 0 {
+	if len(cfg.Scrapers) == 0 && cfg.DiscoveryURL == "" {
 		logger.Warn("No scrapers configured - application will exit")
 		return
 	}
@@ -35,9 +60,32 @@ func main() {
 		logger.WithError(err).Fatal("Failed to initialize healthcheck manager")
 	}
 
+	// Export scrape results to an OTLP endpoint when configured
+	var exporter *otlp.Exporter
+	if cfg.OTLPEndpoint != "" {
+		exporter = otlp.NewExporter(cfg.OTLPEndpoint, cfg.OTLPExportInterval, logger)
+		manager.AddSink(exporter)
+		exporter.Start()
+	}
+
+	// Publish scrape results to a Kafka topic when configured
+	var sink *kafka.Sink
+	if len(cfg.KafkaBrokers) > 0 {
+		sink = kafka.NewSink(kafka.NewBrokerProducer(cfg.KafkaBrokers), cfg.KafkaTopic, logger)
+		manager.AddBatchSink(sink, cfg.KafkaBatchSize, cfg.KafkaFlushInterval)
+	}
+
 	// Start the manager
 	manager.Start()
 
+	// Expose metrics when an address is configured
+	var metricsServer *http.Server
+	if cfg.MetricsAddress != "" {
+		metricsServer = startMetricsServer(cfg.MetricsAddress, cfg.EnableStatusUI, manager, logger)
+	}
+
+	manager.LogStartup(version)
+
 	// Setup graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -46,7 +94,71 @@ func main() {
 	sig := <-sigChan
 	logger.WithField("signal", sig).Info("Received shutdown signal")
 
-	// Gracefully stop the manager
-	manager.Stop()
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+
+	// Gracefully stop the manager, forcing an exit if it takes too long
+	if err := manager.StopWithTimeout(cfg.ShutdownTimeout); err != nil {
+		logger.WithError(err).Error("Graceful shutdown timed out, forcing exit")
+		os.Exit(1)
+	}
+
+	// Export the results of the final scrapes, the manager having published the last Kafka
+	// batch before stopping
+	if exporter != nil {
+		exporter.Stop()
+	}
+	if sink != nil {
+		sink.Stop()
+	}
 	logger.Info("Application shutdown complete")
 }
+
+// newLogger creates the JSON logger used by all commands
+func newLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetLevel(logrus.InfoLevel)
+	return logger
+}
+
+// startMetricsServer serves the metrics registry on /metrics, the scraper statuses on
+// /status and as application/health+json on /health, their schedules on /schedule, their
+// latest results on /history.csv and the synchronous scrape of all scrapers on
+// /scrape-all-sync at the given address, and the status dashboard on / if enabled
+func startMetricsServer(address string, statusUI bool, manager *healthcheck.Manager, logger *logrus.Logger) *http.Server {
+	mux := http.NewServeMux()
+	if statusUI {
+		mux.Handle("/", manager.DashboardHandler())
+	}
+	mux.Handle("/metrics", metrics.DefaultRegistry.Handler())
+	mux.Handle("/status", manager.StatusHandler())
+	mux.Handle("/schedule", manager.ScheduleHandler())
+	mux.Handle("/health", manager.HealthHandler())
+	mux.Handle("/history.csv", manager.HistoryCSVHandler())
+	mux.Handle("/scrape-all-sync", manager.ScrapeAllSyncHandler())
+
+	server := &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.WithError(err).Error("Metrics server failed")
+		}
+	}()
+
+	logger.WithField("address", address).Info("Serving metrics")
+	return server
+}
+
+// listTypes prints all registered scraper types with their descriptions
+func listTypes(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, info := range scraper.RegisteredTypes() {
+		fmt.Fprintf(tw, "%s\t%s\n", info.Type, info.Description)
+	}
+	tw.Flush()
+}
@@ -4,19 +4,186 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 type HealthcheckScraper struct {
-	Type                  string `json:"healthcheck-scraper-type"`
-	ScrapeURL             string `json:"scrape_url"`
-	PingURL               string `json:"ping_url"`
-	ScrapeIntervalSeconds int    `json:"scrape_interval_seconds"`
+	Name                       string            `json:"name"`
+	Type                       string            `json:"healthcheck-scraper-type"`
+	ScrapeURL                  string            `json:"scrape_url"`
+	PingURL                    string            `json:"ping_url"`
+	ScrapeIntervalSeconds      int               `json:"scrape_interval_seconds"`
+	ScrapeInterval             string            `json:"scrape_interval"`
+	DNSCacheTTLSeconds         int               `json:"dns_cache_ttl_seconds"`
+	NotifyURL                  string            `json:"notify_url"`
+	NotifyOnDetailChange       []string          `json:"notify_on_detail_change"`
+	NotifyOnStateChange        bool              `json:"notify_on_state_change"`
+	FlapDampeningSeconds       int               `json:"flap_dampening_seconds"`
+	NotifyGroup                string            `json:"notify_group"`
+	VaultNamespace             string            `json:"vault_namespace"`
+	VaultStandbyHealthy        bool              `json:"vault_standby_healthy"`
+	ReadFirstLine              bool              `json:"read_first_line"`
+	AnnotationHeaders          []string          `json:"annotation_headers"`
+	JSONPath                   string            `json:"json_path"`
+	MinLength                  *int              `json:"min_length"`
+	MaxLength                  *int              `json:"max_length"`
+	HealthExpression           string            `json:"health_expression"`
+	MessageExpression          string            `json:"message_expression"`
+	BadPagePatterns            []string          `json:"bad_page_patterns"`
+	BadPageTitles              []string          `json:"bad_page_titles"`
+	VerifyContentLength        bool              `json:"verify_content_length"`
+	FailOnEmptyResponse        bool              `json:"fail_on_empty_response"`
+	Warmup                     bool              `json:"warmup"`
+	MinQuotaRemaining          int               `json:"min_quota_remaining"`
+	MinQuotaPercent            float64           `json:"min_quota_percent"`
+	Hostname                   string            `json:"hostname"`
+	Resolvers                  []string          `json:"resolvers"`
+	SourceAddress              string            `json:"source_address"`
+	GraphQLQuery               string            `json:"graphql_query"`
+	GraphQLDataPath            string            `json:"graphql_data_path"`
+	GraphQLExpectedValue       string            `json:"graphql_expected_value"`
+	LogSampling                int               `json:"log_sampling"`
+	GRPCMethod                 string            `json:"grpc_method"`
+	GRPCRequestMessage         string            `json:"grpc_request_message"`
+	GRPCBidi                   bool              `json:"grpc_bidi"`
+	AuthHeader                 string            `json:"auth_header"`
+	AuthHeaderFile             string            `json:"auth_header_file"`
+	RequestHeaders             map[string]string `json:"request_headers"`
+	MetricName                 string            `json:"metric_name"`
+	Labels                     map[string]string `json:"labels"`
+	Operator                   string            `json:"operator"`
+	Threshold                  *float64          `json:"threshold"`
+	SizeMetrics                bool              `json:"size_metrics"`
+	QueueBackend               string            `json:"queue_backend"`
+	QueueName                  string            `json:"queue_name"`
+	QueueVHost                 string            `json:"queue_vhost"`
+	MaxDepth                   int64             `json:"max_depth"`
+	AWSRegion                  string            `json:"aws_region"`
+	CollapseFailureLogsSeconds int               `json:"collapse_failure_logs_seconds"`
+	EnableScrapeCache          bool              `json:"enable_scrape_cache"`
+	ScrapeCacheTTLSeconds      int               `json:"scrape_cache_ttl_seconds"`
+	Burst                      int               `json:"burst"`
+	BurstQuorum                int               `json:"burst_quorum"`
+	Probes                     int               `json:"probes"`
+	ProbeWindowSeconds         int               `json:"probe_window_seconds"`
+	MinSuccessRatio            float64           `json:"min_success_ratio"`
+	MinDaysRemaining           int               `json:"min_days_remaining"`
+	RequireSCT                 bool              `json:"require_sct"`
+	ScrapeURLFile              string            `json:"scrape_url_file"`
+	PingURLFile                string            `json:"ping_url_file"`
+	NotifyURLFile              string            `json:"notify_url_file"`
+	MaxTTFBMs                  int               `json:"max_ttfb_ms"`
+	DependsOn                  string            `json:"depends_on"`
+	GateURL                    string            `json:"gate_url"`
+	Group                      string            `json:"group"`
+	LatencyAnomalySigma        float64           `json:"latency_anomaly_sigma"`
+	LatencyEWMAAlpha           float64           `json:"latency_ewma_alpha"`
+	MountPath                  string            `json:"mount_path"`
+	TrailerKey                 string            `json:"trailer_key"`
+	ExpectedTrailerValue       string            `json:"expected_trailer_value"`
+	ExpectedETag               string            `json:"expected_etag"`
+	AlarmName                  string            `json:"alarm_name"`
+	AWSAccessKeyID             string            `json:"aws_access_key_id"`
+	AWSSecretAccessKey         string            `json:"aws_secret_access_key"`
+	AWSSecretAccessKeyFile     string            `json:"aws_secret_access_key_file"`
+	AWSSessionToken            string            `json:"aws_session_token"`
+	NotifyTemplate             string            `json:"notify_template"`
+	NotifyContentType          string            `json:"notify_content_type"`
+	NotifyRetries              *int              `json:"notify_retries"`
+	NotifyBackoffMs            int               `json:"notify_backoff_ms"`
+	StartTLS                   string            `json:"starttls"`
+	PingTimeoutSeconds         int               `json:"ping_timeout_seconds"`
+	MaxConsecutivePings        int               `json:"max_consecutive_pings"`
+	MinTLSVersion              string            `json:"min_tls_version"`
+	CheckResumption            bool              `json:"check_resumption"`
+	DoHResolverURL             string            `json:"doh_resolver_url"`
+	Format                     string            `json:"format"`
+	Backend                    string            `json:"backend"`
+	MinHealthyBackends         int               `json:"min_healthy_backends"`
+	CheckCRL                   bool              `json:"check_crl"`
+	CRLHardFail                bool              `json:"crl_hard_fail"`
+	SRVName                    string            `json:"srv_name"`
+	SRVScheme                  string            `json:"srv_scheme"`
+	SRVPath                    string            `json:"srv_path"`
+	Quorum                     int               `json:"quorum"`
+	CACertPEM                  string            `json:"ca_cert_pem"`
+	CACertFile                 string            `json:"ca_cert_file"`
+	SourceType                 string            `json:"source_type"`
+	MaxAgeSeconds              int               `json:"max_age_seconds"`
+	FilePath                   string            `json:"file_path"`
+	RedisKey                   string            `json:"redis_key"`
+	ActiveHours                string            `json:"active_hours"`
+	Timezone                   string            `json:"timezone"`
+	RequestTimeoutMs           int               `json:"request_timeout_ms"`
+	AllowedRedirectHosts       []string          `json:"allowed_redirect_hosts"`
+	Kubeconfig                 string            `json:"kubeconfig"`
+	Namespace                  string            `json:"namespace"`
+	WorkloadKind               string            `json:"workload_kind"`
+	WorkloadName               string            `json:"workload_name"`
+	MinReadyReplicas           int               `json:"min_ready_replicas"`
+	ProcessName                string            `json:"process_name"`
+	CmdlinePattern             string            `json:"cmdline_pattern"`
+	MinCount                   int               `json:"min_count"`
+	PID                        int               `json:"pid"`
+	MaxFDPercent               float64           `json:"max_fd_percent"`
+	MaxGoroutines              int               `json:"max_goroutines"`
+	MaxHeapMB                  int               `json:"max_heap_mb"`
+	RepeatDelayMs              int               `json:"repeat_delay_ms"`
+	IgnoreFields               []string          `json:"ignore_fields"`
+	OverlapPolicy              string            `json:"overlap_policy"`
+	Bucket                     string            `json:"bucket"`
+	S3KeyPrefix                string            `json:"s3_key_prefix"`
+	MaxLatencyMs               int               `json:"max_latency_ms"`
+	InjectTraceHeader          bool              `json:"inject_trace_header"`
+	MinReadyNodes              int               `json:"min_ready_nodes"`
+	MinReadyPercent            float64           `json:"min_ready_percent"`
+	PreScrapeCmd               []string          `json:"pre_scrape_cmd"`
+	PostScrapeCmd              []string          `json:"post_scrape_cmd"`
+	HookTimeoutSeconds         int               `json:"hook_timeout_seconds"`
+	PublishURL                 string            `json:"publish_url"`
+	SubscribeURL               string            `json:"subscribe_url"`
+	PipelineMessage            string            `json:"pipeline_message"`
+}
+
+// ScraperGroup is a named group of scrapers that pings its own URL once all of its members
+// are healthy
+type ScraperGroup struct {
+	Name         string `json:"name"`
+	GroupPingURL string `json:"group_ping_url"`
 }
 
 type Config struct {
-	Scrapers []HealthcheckScraper `mapstructure:"scrapers"`
+	Scrapers                 []HealthcheckScraper `mapstructure:"scrapers"`
+	Groups                   []ScraperGroup       `mapstructure:"groups"`
+	NotificationWorkers      int                  `mapstructure:"notification_workers"`
+	NotificationQueueSize    int                  `mapstructure:"notification_queue_size"`
+	ShutdownTimeout          time.Duration        `mapstructure:"shutdown_timeout"`
+	DrainTimeout             time.Duration        `mapstructure:"drain_timeout"`
+	InitialScrapeSpread      time.Duration        `mapstructure:"initial_scrape_spread"`
+	MetricsAddress           string               `mapstructure:"metrics_address"`
+	ScrapeSizeMetrics        bool                 `mapstructure:"scrape_size_metrics"`
+	Sequential               bool                 `mapstructure:"sequential"`
+	OTLPEndpoint             string               `mapstructure:"otlp_endpoint"`
+	OTLPExportInterval       time.Duration        `mapstructure:"otlp_export_interval"`
+	StatusTTLFactor          int                  `mapstructure:"status_ttl_factor"`
+	MaxConcurrentScrapes     int                  `mapstructure:"max_concurrent_scrapes"`
+	NotifyGroupWindow        time.Duration        `mapstructure:"notify_group_window"`
+	DiscoveryURL             string               `mapstructure:"discovery_url"`
+	DiscoveryIntervalSeconds int                  `mapstructure:"discovery_interval_seconds"`
+	EnableScrapeHooks        bool                 `mapstructure:"enable_scrape_hooks"`
+	HistorySize              int                  `mapstructure:"history_size"`
+	FlapWindow               time.Duration        `mapstructure:"flap_window"`
+	MinScrapeInterval        time.Duration        `mapstructure:"min_scrape_interval"`
+	EnableStatusUI           bool                 `mapstructure:"enable_status_ui"`
+	KafkaBrokers             []string             `mapstructure:"kafka_brokers"`
+	KafkaTopic               string               `mapstructure:"kafka_topic"`
+	KafkaBatchSize           int                  `mapstructure:"kafka_batch_size"`
+	KafkaFlushInterval       time.Duration        `mapstructure:"kafka_flush_interval"`
 }
 
 func NewConfig(logger *logrus.Logger) (*Config, error) {
@@ -30,7 +197,222 @@ func NewConfig(logger *logrus.Logger) (*Config, error) {
 		}
 	}
 
-	logger.WithField("config", fmt.Sprintf("%+v", config)).Info("Loaded configuration")
+	if groupsJSON := os.Getenv("HEALTHCHECK_GROUPS"); groupsJSON != "" {
+		if err := json.Unmarshal([]byte(groupsJSON), &config.Groups); err != nil {
+			return nil, fmt.Errorf("failed to parse HEALTHCHECK_GROUPS JSON: %w", err)
+		}
+	}
+
+	if err := parseIntEnv("HEALTHCHECK_NOTIFICATION_WORKERS", &config.NotificationWorkers); err != nil {
+		return nil, err
+	}
+
+	if err := parseIntEnv("HEALTHCHECK_NOTIFICATION_QUEUE_SIZE", &config.NotificationQueueSize); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_SHUTDOWN_TIMEOUT", &config.ShutdownTimeout); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_DRAIN_TIMEOUT", &config.DrainTimeout); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_INITIAL_SCRAPE_SPREAD", &config.InitialScrapeSpread); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_MIN_SCRAPE_INTERVAL", &config.MinScrapeInterval); err != nil {
+		return nil, err
+	}
+
+	if err := parseBoolEnv("HEALTHCHECK_ENABLE_STATUS_UI", &config.EnableStatusUI); err != nil {
+		return nil, err
+	}
+
+	if err := parseBoolEnv("HEALTHCHECK_SEQUENTIAL", &config.Sequential); err != nil {
+		return nil, err
+	}
+
+	config.MetricsAddress = os.Getenv("HEALTHCHECK_METRICS_ADDRESS")
+
+	if err := parseBoolEnv("HEALTHCHECK_SCRAPE_SIZE_METRICS", &config.ScrapeSizeMetrics); err != nil {
+		return nil, err
+	}
+
+	config.OTLPEndpoint = os.Getenv("HEALTHCHECK_OTLP_ENDPOINT")
+
+	if err := parseDurationEnv("HEALTHCHECK_OTLP_EXPORT_INTERVAL", &config.OTLPExportInterval); err != nil {
+		return nil, err
+	}
+
+	if brokers := os.Getenv("HEALTHCHECK_KAFKA_BROKERS"); brokers != "" {
+		for _, broker := range strings.Split(brokers, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				config.KafkaBrokers = append(config.KafkaBrokers, broker)
+			}
+		}
+	}
+
+	config.KafkaTopic = os.Getenv("HEALTHCHECK_KAFKA_TOPIC")
+
+	if err := parseIntEnv("HEALTHCHECK_KAFKA_BATCH_SIZE", &config.KafkaBatchSize); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_KAFKA_FLUSH_INTERVAL", &config.KafkaFlushInterval); err != nil {
+		return nil, err
+	}
+
+	if err := parseIntEnv("HEALTHCHECK_STATUS_TTL_FACTOR", &config.StatusTTLFactor); err != nil {
+		return nil, err
+	}
+
+	if err := parseIntEnv("HEALTHCHECK_HISTORY_SIZE", &config.HistorySize); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_FLAP_WINDOW", &config.FlapWindow); err != nil {
+		return nil, err
+	}
+
+	if err := parseIntEnv("HEALTHCHECK_MAX_CONCURRENT_SCRAPES", &config.MaxConcurrentScrapes); err != nil {
+		return nil, err
+	}
+
+	if err := parseDurationEnv("HEALTHCHECK_NOTIFY_GROUP_WINDOW", &config.NotifyGroupWindow); err != nil {
+		return nil, err
+	}
+
+	config.DiscoveryURL = os.Getenv("HEALTHCHECK_DISCOVERY_URL")
+
+	if err := parseIntEnv("HEALTHCHECK_DISCOVERY_INTERVAL_SECONDS", &config.DiscoveryIntervalSeconds); err != nil {
+		return nil, err
+	}
+
+	if err := parseBoolEnv("HEALTHCHECK_ENABLE_SCRAPE_HOOKS", &config.EnableScrapeHooks); err != nil {
+		return nil, err
+	}
+
+	if err := PrepareScrapers(config.Scrapers, "", config.ScrapeSizeMetrics); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Only names and counts are logged, the configuration holds credentials read from secret
+	// files and URLs that may embed them
+	names := make([]string, 0, len(config.Scrapers))
+	for _, scraper := range config.Scrapers {
+		names = append(names, scraper.Name)
+	}
+	logger.WithFields(logrus.Fields{
+		"scrapers":      len(config.Scrapers),
+		"scraper_names": names,
+		"groups":        len(config.Groups),
+	}).Info("Loaded configuration")
 
 	return config, nil
 }
+
+// PrepareScrapers names unnamed scrapers after the prefix, their type and position, reads
+// their secret files and enables size metrics for every scraper able to record them when
+// requested globally
+func PrepareScrapers(scrapers []HealthcheckScraper, namePrefix string, sizeMetrics bool) error {
+	for i := range scrapers {
+		if scrapers[i].Name == "" {
+			scrapers[i].Name = fmt.Sprintf("%s%s-%d", namePrefix, scrapers[i].Type, i)
+		}
+		if err := readSecretFiles(&scrapers[i]); err != nil {
+			return fmt.Errorf("scraper %s: %w", scrapers[i].Name, err)
+		}
+		if sizeMetrics && slices.Contains(instrumentedTypes, scrapers[i].Type) {
+			scrapers[i].SizeMetrics = true
+		}
+	}
+	return nil
+}
+
+// readSecretFiles sets the sensitive fields of the scraper, which may embed credentials,
+// from the contents of their *_file counterparts such as mounted Kubernetes or Docker secrets
+func readSecretFiles(scraper *HealthcheckScraper) error {
+	secrets := []struct {
+		name   string
+		path   string
+		target *string
+	}{
+		{"scrape_url", scraper.ScrapeURLFile, &scraper.ScrapeURL},
+		{"ping_url", scraper.PingURLFile, &scraper.PingURL},
+		{"notify_url", scraper.NotifyURLFile, &scraper.NotifyURL},
+		{"aws_secret_access_key", scraper.AWSSecretAccessKeyFile, &scraper.AWSSecretAccessKey},
+		{"auth_header", scraper.AuthHeaderFile, &scraper.AuthHeader},
+	}
+
+	for _, secret := range secrets {
+		if secret.path == "" {
+			continue
+		}
+		if *secret.target != "" {
+			return fmt.Errorf("%s and %s_file are mutually exclusive", secret.name, secret.name)
+		}
+
+		contents, err := os.ReadFile(secret.path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_file: %w", secret.name, err)
+		}
+		*secret.target = strings.TrimRight(string(contents), "\r\n")
+	}
+
+	return nil
+}
+
+// parseIntEnv parses the integer environment variable name into target if it is set
+func parseIntEnv(name string, target *int) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	*target = parsed
+	return nil
+}
+
+// parseBoolEnv parses the boolean environment variable name (e.g. "true") into target if it is set
+func parseBoolEnv(name string, target *bool) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	*target = parsed
+	return nil
+}
+
+// parseDurationEnv parses the duration environment variable name (e.g. "30s") into target if it is set
+func parseDurationEnv(name string, target *time.Duration) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	*target = parsed
+	return nil
+}
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// httpClientTypes are the scraper types making their requests with the shared HTTP client
+var httpClientTypes = []string{
+	"aws-health", "cloudflared-tunnel-connector", "graphql", "http", "idempotency", "job-freshness", "lb-pool",
+	"pipeline", "prometheus-metric", "queue-depth", "s3-roundtrip", "srv-discovery", "vault", "vault-seal",
+}
+
+// tracedTypes are the scraper types able to send a traceparent header, the shared HTTP client
+// and the gRPC scrapers
+var tracedTypes = append(slices.Clone(httpClientTypes), "grpc-stream", "grpc-web")
+
+// dialTypes are the scraper types connecting through the shared dial and TLS settings, which
+// adds the Kubernetes API client and the TLS handshake to the traced types
+var dialTypes = append(slices.Clone(tracedTypes), "k8s-nodes", "k8s-workload", "tls")
+
+// instrumentedTypes are the scraper types able to record request and response size metrics,
+// the dial types except the TLS handshake, which has no bodies
+var instrumentedTypes = slices.DeleteFunc(slices.Clone(dialTypes), func(scraperType string) bool {
+	return scraperType == "tls"
+})
+
+// annotatedTypes are the scraper types reporting response headers as annotations
+var annotatedTypes = []string{
+	"cloudflared-tunnel-connector", "graphql", "grpc-stream", "grpc-web", "http", "idempotency", "lb-pool",
+	"prometheus-metric", "vault", "vault-seal",
+}
+
+// cachedTypes are the scraper types able to share responses through the scrape cache, all
+// users of the shared HTTP client except idempotency, whose second request it would answer
+var cachedTypes = slices.DeleteFunc(slices.Clone(httpClientTypes), func(scraperType string) bool {
+	return scraperType == "idempotency"
+})
+
+// typeSpecificFields maps the JSON keys only some scraper types use to those types
+var typeSpecificFields = map[string][]string{
+	"source_address":         dialTypes,
+	"min_tls_version":        dialTypes,
+	"doh_resolver_url":       dialTypes,
+	"dns_cache_ttl_seconds":  dialTypes,
+	"ca_cert_pem":            dialTypes,
+	"ca_cert_file":           dialTypes,
+	"size_metrics":           instrumentedTypes,
+	"inject_trace_header":    tracedTypes,
+	"annotation_headers":     annotatedTypes,
+	"enable_scrape_cache":    cachedTypes,
+	"vault_namespace":        {"vault", "vault-seal"},
+	"vault_standby_healthy":  {"vault", "vault-seal"},
+	"read_first_line":        {"http"},
+	"json_path":              {"http", "job-freshness"},
+	"min_length":             {"http"},
+	"max_length":             {"http"},
+	"health_expression":      {"http"},
+	"message_expression":     {"http"},
+	"bad_page_patterns":      {"http"},
+	"bad_page_titles":        {"http"},
+	"verify_content_length":  {"http"},
+	"fail_on_empty_response": {"http"},
+	"warmup":                 {"http"},
+	"min_quota_remaining":    {"http"},
+	"min_quota_percent":      {"http"},
+	"burst":                  {"http"},
+	"burst_quorum":           {"http"},
+	"probes":                 {"http"},
+	"probe_window_seconds":   {"http"},
+	"min_success_ratio":      {"http"},
+	"max_ttfb_ms":            {"http"},
+	"request_timeout_ms":     {"http"},
+	"allowed_redirect_hosts": {"http"},
+	"trailer_key":            {"http"},
+	"expected_trailer_value": {"http"},
+	"expected_etag":          {"http"},
+	"hostname":               {"dns-consistency"},
+	"resolvers":              {"dns-consistency"},
+	"graphql_query":          {"graphql"},
+	"graphql_data_path":      {"graphql"},
+	"graphql_expected_value": {"graphql"},
+	"grpc_method":            {"grpc-stream", "grpc-web"},
+	"grpc_request_message":   {"grpc-stream", "grpc-web"},
+	"grpc_bidi":              {"grpc-stream"},
+	"auth_header":            {"grpc-web"},
+	"auth_header_file":       {"grpc-web"},
+	"request_headers":        {"grpc-web"},
+	"metric_name":            {"prometheus-metric"},
+	"labels":                 {"prometheus-metric"},
+	"operator":               {"prometheus-metric"},
+	"threshold":              {"prometheus-metric"},
+	"queue_backend":          {"queue-depth"},
+	"queue_name":             {"queue-depth"},
+	"queue_vhost":            {"queue-depth"},
+	"max_depth":              {"queue-depth"},
+	"aws_region":             {"queue-depth", "aws-health", "s3-roundtrip"},
+	"min_days_remaining":     {"tls"},
+	"require_sct":            {"tls"},
+	"starttls":               {"tls"},
+	"check_crl":              {"tls"},
+	"crl_hard_fail":          {"tls"},
+	"check_resumption":       {"tls"},
+	"mount_path":             {"mount"},
+	"format":                 {"lb-pool"},
+	"backend":                {"lb-pool"},
+	"min_healthy_backends":   {"lb-pool"},
+	"srv_name":               {"srv-discovery"},
+	"srv_scheme":             {"srv-discovery"},
+	"srv_path":               {"srv-discovery"},
+	"quorum":                 {"srv-discovery"},
+	"source_type":            {"job-freshness"},
+	"max_age_seconds":        {"job-freshness", "http"},
+	"file_path":              {"job-freshness"},
+	"redis_key":              {"job-freshness"},
+	"kubeconfig":             {"k8s-workload", "k8s-nodes"},
+	"namespace":              {"k8s-workload"},
+	"workload_kind":          {"k8s-workload"},
+	"workload_name":          {"k8s-workload"},
+	"min_ready_replicas":     {"k8s-workload"},
+	"min_ready_nodes":        {"k8s-nodes"},
+	"min_ready_percent":      {"k8s-nodes"},
+	"process_name":           {"process", "fd-usage"},
+	"cmdline_pattern":        {"process"},
+	"min_count":              {"process"},
+	"pid":                    {"fd-usage"},
+	"max_fd_percent":         {"fd-usage"},
+	"max_goroutines":         {"self"},
+	"max_heap_mb":            {"self"},
+	"repeat_delay_ms":        {"idempotency"},
+	"ignore_fields":          {"idempotency"},
+	"bucket":                 {"s3-roundtrip"},
+	"s3_key_prefix":          {"s3-roundtrip"},
+	"max_latency_ms":         {"s3-roundtrip", "stun", "pipeline"},
+	"publish_url":            {"pipeline"},
+	"subscribe_url":          {"pipeline"},
+	"pipeline_message":       {"pipeline"},
+	"alarm_name":             {"aws-health"},
+	"aws_access_key_id":      {"aws-health", "s3-roundtrip"},
+	"aws_secret_access_key":  {"aws-health", "s3-roundtrip"},
+	"aws_session_token":      {"aws-health", "s3-roundtrip"},
+}
+
+// exclusiveFields lists pairs of JSON keys that cannot be set together
+var exclusiveFields = [][2]string{
+	{"read_first_line", "json_path"},
+	{"read_first_line", "burst"},
+	{"read_first_line", "enable_scrape_cache"},
+	{"burst", "enable_scrape_cache"},
+	{"burst", "trailer_key"},
+	{"burst", "json_path"},
+	{"burst", "max_ttfb_ms"},
+	{"burst", "allowed_redirect_hosts"},
+	{"health_expression", "json_path"},
+	{"health_expression", "burst"},
+	{"bad_page_patterns", "read_first_line"},
+	{"bad_page_patterns", "burst"},
+	{"bad_page_titles", "read_first_line"},
+	{"bad_page_titles", "burst"},
+	{"verify_content_length", "read_first_line"},
+	{"verify_content_length", "burst"},
+	{"fail_on_empty_response", "burst"},
+	{"fail_on_empty_response", "probes"},
+	{"fail_on_empty_response", "read_first_line"},
+	{"fail_on_empty_response", "json_path"},
+	{"fail_on_empty_response", "health_expression"},
+	{"warmup", "burst"},
+	{"warmup", "probes"},
+	{"warmup", "read_first_line"},
+	{"warmup", "enable_scrape_cache"},
+	{"min_quota_remaining", "min_quota_percent"},
+	{"min_quota_remaining", "burst"},
+	{"min_quota_percent", "burst"},
+	{"read_first_line", "trailer_key"},
+	{"ca_cert_pem", "ca_cert_file"},
+	{"pid", "process_name"},
+	{"min_ready_nodes", "min_ready_percent"},
+	{"scrape_interval", "scrape_interval_seconds"},
+	{"probes", "burst"},
+	{"probes", "read_first_line"},
+	{"probes", "enable_scrape_cache"},
+	{"probes", "json_path"},
+	{"probes", "health_expression"},
+	{"probes", "trailer_key"},
+	{"probes", "bad_page_patterns"},
+	{"probes", "bad_page_titles"},
+	{"probes", "verify_content_length"},
+	{"probes", "min_quota_remaining"},
+	{"probes", "min_quota_percent"},
+	{"probes", "max_ttfb_ms"},
+	{"probes", "allowed_redirect_hosts"},
+	{"max_age_seconds", "burst"},
+	{"max_age_seconds", "probes"},
+	{"expected_etag", "burst"},
+	{"expected_etag", "probes"},
+	{"annotation_headers", "burst"},
+	{"annotation_headers", "probes"},
+}
+
+// unixSocketIgnoredFields lists the JSON keys of the network dial, which a unix:// scrape URL
+// bypasses to connect to its socket
+var unixSocketIgnoredFields = []string{"source_address", "doh_resolver_url", "dns_cache_ttl_seconds"}
+
+// dependentFields maps JSON keys to the key they require to be set as well
+var dependentFields = map[string]string{
+	"min_length":               "json_path",
+	"max_length":               "json_path",
+	"burst_quorum":             "burst",
+	"probe_window_seconds":     "probes",
+	"min_success_ratio":        "probes",
+	"message_expression":       "health_expression",
+	"expected_trailer_value":   "trailer_key",
+	"graphql_expected_value":   "graphql_data_path",
+	"scrape_cache_ttl_seconds": "enable_scrape_cache",
+	"notify_template":          "notify_url",
+	"notify_retries":           "notify_url",
+	"notify_backoff_ms":        "notify_url",
+	"flap_dampening_seconds":   "notify_on_state_change",
+	"notify_group":             "notify_on_state_change",
+	"crl_hard_fail":            "check_crl",
+	"srv_path":                 "srv_scheme",
+	"timezone":                 "active_hours",
+	"max_consecutive_pings":    "ping_url",
+	"latency_ewma_alpha":       "latency_anomaly_sigma",
+}
+
+// Validate checks that the Kafka brokers and topic are set together, the scraper groups and
+// the scraper configurations for fields that conflict with each other or do not apply to the
+// scraper's type
+func (c *Config) Validate() error {
+	if len(c.KafkaBrokers) > 0 && c.KafkaTopic == "" {
+		return errors.New("kafka_brokers requires kafka_topic")
+	}
+	if c.KafkaTopic != "" && len(c.KafkaBrokers) == 0 {
+		return errors.New("kafka_topic requires kafka_brokers")
+	}
+	if err := ValidateGroups(c.Groups, c.Scrapers); err != nil {
+		return err
+	}
+	return ValidateScrapers(c.Scrapers)
+}
+
+// ValidateGroups checks that every group has a unique name and a group_ping_url, and that
+// every scraper's group is one of them
+func ValidateGroups(groups []ScraperGroup, scrapers []HealthcheckScraper) error {
+	names := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if group.Name == "" {
+			return errors.New("scraper groups require a name")
+		}
+		if names[group.Name] {
+			return fmt.Errorf("duplicate scraper group %s", group.Name)
+		}
+		if group.GroupPingURL == "" {
+			return fmt.Errorf("scraper group %s requires group_ping_url", group.Name)
+		}
+		names[group.Name] = true
+	}
+
+	for _, scraper := range scrapers {
+		if scraper.Group != "" && !names[scraper.Group] {
+			return fmt.Errorf("scraper %s is in unknown group %s", scraper.Name, scraper.Group)
+		}
+	}
+	return nil
+}
+
+// ValidateScrapers checks the scraper configurations for duplicate names, mutually exclusive
+// fields and fields that do not apply to the scraper's type, reporting every problem found
+func ValidateScrapers(scrapers []HealthcheckScraper) error {
+	var errs []error
+	names := make(map[string]bool, len(scrapers))
+
+	for i, scraper := range scrapers {
+		name := scraper.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		} else if names[name] {
+			errs = append(errs, fmt.Errorf("scraper %s: name is used by more than one scraper", name))
+		}
+		names[name] = true
+
+		set := setFields(scraper)
+
+		for _, key := range sortedKeys(set) {
+			types, ok := typeSpecificFields[key]
+			if ok && !slices.Contains(types, scraper.Type) {
+				errs = append(errs, fmt.Errorf("scraper %s: %s does not apply to type %s, only to %s", name, key, scraper.Type, strings.Join(types, ", ")))
+			}
+			if required, ok := dependentFields[key]; ok && !set[required] {
+				errs = append(errs, fmt.Errorf("scraper %s: %s requires %s", name, key, required))
+			}
+		}
+
+		if strings.HasPrefix(scraper.ScrapeURL, "unix://") {
+			for _, key := range unixSocketIgnoredFields {
+				if set[key] {
+					errs = append(errs, fmt.Errorf("scraper %s: %s does not apply to a unix:// scrape_url", name, key))
+				}
+			}
+		}
+
+		for _, pair := range exclusiveFields {
+			if set[pair[0]] && set[pair[1]] {
+				errs = append(errs, fmt.Errorf("scraper %s: %s and %s are mutually exclusive", name, pair[0], pair[1]))
+			}
+		}
+
+		if scraper.MinLength != nil && scraper.MaxLength != nil && *scraper.MinLength > *scraper.MaxLength {
+			errs = append(errs, fmt.Errorf("scraper %s: min_length %d exceeds max_length %d", name, *scraper.MinLength, *scraper.MaxLength))
+		}
+		if scraper.BurstQuorum > scraper.Burst && set["burst"] {
+			errs = append(errs, fmt.Errorf("scraper %s: burst_quorum %d exceeds burst %d", name, scraper.BurstQuorum, scraper.Burst))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// setFields returns the JSON keys of the scraper's fields that are set to a non-zero value
+func setFields(scraper HealthcheckScraper) map[string]bool {
+	set := make(map[string]bool)
+	value := reflect.ValueOf(scraper)
+	for i := 0; i < value.NumField(); i++ {
+		key, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		if key != "" && !value.Field(i).IsZero() {
+			set[key] = true
+		}
+	}
+	return set
+}
+
+// sortedKeys returns the keys of the set in sorted order so errors are reported deterministically
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate_Valid(t *testing.T) {
+	minLength, maxLength := 1, 10
+	config := &Config{
+		Scrapers: []HealthcheckScraper{
+			{Name: "nodes", Type: "http", JSONPath: "$.nodes", MinLength: &minLength, MaxLength: &maxLength},
+			{Name: "burst", Type: "http", Burst: 5, BurstQuorum: 4},
+			{Name: "vault", Type: "vault-seal", VaultNamespace: "team-a"},
+			{Name: "sqs", Type: "queue-depth", QueueBackend: "sqs", AWSRegion: "eu-west-1"},
+			{Name: "cert", Type: "tls", SourceAddress: "10.0.0.1", MinTLSVersion: "1.3"},
+			{Name: "seal", Type: "vault", AnnotationHeaders: []string{"X-Vault-Index"}, EnableScrapeCache: true},
+			{Name: "stream", Type: "grpc-stream", GRPCMethod: "/health.Health/Watch", InjectTraceHeader: true},
+			{Name: "pool", Type: "lb-pool", DNSCacheTTLSeconds: 30, CACertFile: "/etc/ssl/internal.pem"},
+			{Name: "nodes-api", Type: "k8s-nodes", SizeMetrics: true},
+			{Name: "docker", Type: "http", ScrapeURL: "unix:///var/run/docker.sock:/_ping", EnableScrapeCache: true},
+		},
+	}
+
+	assert.NoError(t, config.Validate())
+}
+
+func TestConfig_Validate_Conflicts(t *testing.T) {
+	minLength, maxLength := 5, 2
+
+	tests := []struct {
+		name    string
+		scraper HealthcheckScraper
+		err     string
+	}{
+		{
+			name:    "mutually exclusive modes",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", ReadFirstLine: true, JSONPath: "$.nodes"},
+			err:     "scraper api: read_first_line and json_path are mutually exclusive",
+		},
+		{
+			name:    "burst with cache",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Burst: 3, EnableScrapeCache: true},
+			err:     "scraper api: burst and enable_scrape_cache are mutually exclusive",
+		},
+		{
+			name:    "burst with json path",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Burst: 3, JSONPath: "$.nodes"},
+			err:     "scraper api: burst and json_path are mutually exclusive",
+		},
+		{
+			name:    "burst with ttfb limit",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Burst: 3, MaxTTFBMs: 500},
+			err:     "scraper api: burst and max_ttfb_ms are mutually exclusive",
+		},
+		{
+			name:    "burst with redirect hosts",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Burst: 3, AllowedRedirectHosts: []string{"example.com"}},
+			err:     "scraper api: burst and allowed_redirect_hosts are mutually exclusive",
+		},
+		{
+			name:    "field of another type",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", GraphQLQuery: "{ health }"},
+			err:     "scraper api: graphql_query does not apply to type http, only to graphql",
+		},
+		{
+			name:    "field shared by other types",
+			scraper: HealthcheckScraper{Name: "tunnel", Type: "cloudflared-tunnel-connector", AWSRegion: "eu-west-1"},
+			err:     "scraper tunnel: aws_region does not apply to type cloudflared-tunnel-connector, only to queue-depth, aws-health",
+		},
+		{
+			name:    "source address on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "stun", Type: "stun", SourceAddress: "10.0.0.1"},
+			err:     "scraper stun: source_address does not apply to type stun",
+		},
+		{
+			name:    "minimum TLS version on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "dns", Type: "dns-consistency", MinTLSVersion: "1.3"},
+			err:     "scraper dns: min_tls_version does not apply to type dns-consistency",
+		},
+		{
+			name:    "DoH resolver on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "nfs", Type: "mount", DoHResolverURL: "https://dns.example.com/dns-query"},
+			err:     "scraper nfs: doh_resolver_url does not apply to type mount",
+		},
+		{
+			name:    "source address on a Unix socket",
+			scraper: HealthcheckScraper{Name: "docker", Type: "http", ScrapeURL: "unix:///var/run/docker.sock:/_ping", SourceAddress: "10.0.0.1"},
+			err:     "scraper docker: source_address does not apply to a unix:// scrape_url",
+		},
+		{
+			name:    "DoH resolver on a Unix socket",
+			scraper: HealthcheckScraper{Name: "docker", Type: "http", ScrapeURL: "unix:///var/run/docker.sock:/_ping", DoHResolverURL: "https://dns.example.com/dns-query"},
+			err:     "scraper docker: doh_resolver_url does not apply to a unix:// scrape_url",
+		},
+		{
+			name:    "DNS cache on a Unix socket",
+			scraper: HealthcheckScraper{Name: "docker", Type: "http", ScrapeURL: "unix:///var/run/docker.sock:/_ping", DNSCacheTTLSeconds: 30},
+			err:     "scraper docker: dns_cache_ttl_seconds does not apply to a unix:// scrape_url",
+		},
+		{
+			name:    "DNS cache on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "stun", Type: "stun", DNSCacheTTLSeconds: 30},
+			err:     "scraper stun: dns_cache_ttl_seconds does not apply to type stun",
+		},
+		{
+			name:    "CA certificate on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "worker", Type: "process", CACertPEM: "-----BEGIN CERTIFICATE-----"},
+			err:     "scraper worker: ca_cert_pem does not apply to type process",
+		},
+		{
+			name:    "CA certificate file on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "nfs", Type: "mount", CACertFile: "/etc/ssl/internal.pem"},
+			err:     "scraper nfs: ca_cert_file does not apply to type mount",
+		},
+		{
+			name:    "size metrics on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "nfs", Type: "mount", SizeMetrics: true},
+			err:     "scraper nfs: size_metrics does not apply to type mount",
+		},
+		{
+			name:    "size metrics on a TLS scraper",
+			scraper: HealthcheckScraper{Name: "cert", Type: "tls", SizeMetrics: true},
+			err:     "scraper cert: size_metrics does not apply to type tls",
+		},
+		{
+			name:    "trace header on a TLS scraper",
+			scraper: HealthcheckScraper{Name: "cert", Type: "tls", InjectTraceHeader: true},
+			err:     "scraper cert: inject_trace_header does not apply to type tls",
+		},
+		{
+			name:    "annotation headers without a response",
+			scraper: HealthcheckScraper{Name: "queue", Type: "queue-depth", AnnotationHeaders: []string{"X-Region"}},
+			err:     "scraper queue: annotation_headers does not apply to type queue-depth",
+		},
+		{
+			name:    "scrape cache on a non-HTTP type",
+			scraper: HealthcheckScraper{Name: "self", Type: "self", EnableScrapeCache: true},
+			err:     "scraper self: enable_scrape_cache does not apply to type self",
+		},
+		{
+			name:    "scrape cache on idempotency",
+			scraper: HealthcheckScraper{Name: "orders", Type: "idempotency", EnableScrapeCache: true},
+			err:     "scraper orders: enable_scrape_cache does not apply to type idempotency",
+		},
+		{
+			name:    "annotation headers with burst",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Burst: 3, AnnotationHeaders: []string{"X-Region"}},
+			err:     "scraper api: annotation_headers and burst are mutually exclusive",
+		},
+		{
+			name:    "annotation headers with probes",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, AnnotationHeaders: []string{"X-Region"}},
+			err:     "scraper api: annotation_headers and probes are mutually exclusive",
+		},
+		{
+			name:    "fail on empty with read first line",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", FailOnEmptyResponse: true, ReadFirstLine: true},
+			err:     "scraper api: fail_on_empty_response and read_first_line are mutually exclusive",
+		},
+		{
+			name:    "fail on empty with json path",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", FailOnEmptyResponse: true, JSONPath: "$.nodes"},
+			err:     "scraper api: fail_on_empty_response and json_path are mutually exclusive",
+		},
+		{
+			name:    "fail on empty with health expression",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", FailOnEmptyResponse: true, HealthExpression: "body.ok"},
+			err:     "scraper api: fail_on_empty_response and health_expression are mutually exclusive",
+		},
+		{
+			name:    "missing required field",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", ExpectedTrailerValue: "0"},
+			err:     "scraper api: expected_trailer_value requires trailer_key",
+		},
+		{
+			name:    "content length with burst",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", VerifyContentLength: true, Burst: 3},
+			err:     "scraper api: verify_content_length and burst are mutually exclusive",
+		},
+		{
+			name:    "probes with burst",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, Burst: 3},
+			err:     "scraper api: probes and burst are mutually exclusive",
+		},
+		{
+			name:    "probes with bad page patterns",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, BadPagePatterns: []string{"maintenance"}},
+			err:     "scraper api: probes and bad_page_patterns are mutually exclusive",
+		},
+		{
+			name:    "probes with content length",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, VerifyContentLength: true},
+			err:     "scraper api: probes and verify_content_length are mutually exclusive",
+		},
+		{
+			name:    "probes with quota",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, MinQuotaPercent: 10},
+			err:     "scraper api: probes and min_quota_percent are mutually exclusive",
+		},
+		{
+			name:    "probes with ttfb limit",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, MaxTTFBMs: 500},
+			err:     "scraper api: probes and max_ttfb_ms are mutually exclusive",
+		},
+		{
+			name:    "probes with redirect hosts",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Probes: 3, AllowedRedirectHosts: []string{"example.com"}},
+			err:     "scraper api: probes and allowed_redirect_hosts are mutually exclusive",
+		},
+		{
+			name:    "probe window without probes",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", ProbeWindowSeconds: 10},
+			err:     "scraper api: probe_window_seconds requires probes",
+		},
+		{
+			name:    "warmup with probes",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", Warmup: true, Probes: 5},
+			err:     "scraper api: warmup and probes are mutually exclusive",
+		},
+		{
+			name:    "etag with burst",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", ExpectedETag: `"v42"`, Burst: 3},
+			err:     "scraper api: expected_etag and burst are mutually exclusive",
+		},
+		{
+			name:    "expression with json path",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", HealthExpression: "body.ok", JSONPath: "$.nodes"},
+			err:     "scraper api: health_expression and json_path are mutually exclusive",
+		},
+		{
+			name:    "both interval fields",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", ScrapeInterval: "500ms", ScrapeIntervalSeconds: 1},
+			err:     "scraper api: scrape_interval and scrape_interval_seconds are mutually exclusive",
+		},
+		{
+			name:    "ping cap without ping url",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", MaxConsecutivePings: 100},
+			err:     "scraper api: max_consecutive_pings requires ping_url",
+		},
+		{
+			name:    "ewma alpha without anomaly sigma",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", LatencyEWMAAlpha: 0.2},
+			err:     "scraper api: latency_ewma_alpha requires latency_anomaly_sigma",
+		},
+		{
+			name:    "message without health expression",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", MessageExpression: "body.status"},
+			err:     "scraper api: message_expression requires health_expression",
+		},
+		{
+			name:    "inverted bounds",
+			scraper: HealthcheckScraper{Name: "api", Type: "http", JSONPath: "$.nodes", MinLength: &minLength, MaxLength: &maxLength},
+			err:     "scraper api: min_length 5 exceeds max_length 2",
+		},
+		{
+			name:    "unnamed scraper",
+			scraper: HealthcheckScraper{Type: "tls", MountPath: "/mnt"},
+			err:     "scraper #0: mount_path does not apply to type tls, only to mount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Scrapers: []HealthcheckScraper{tt.scraper}}
+			assert.ErrorContains(t, config.Validate(), tt.err)
+		})
+	}
+}
+
+func TestConfig_Validate_ReportsAllProblems(t *testing.T) {
+	config := &Config{
+		Scrapers: []HealthcheckScraper{
+			{Name: "api", Type: "http", Burst: 2, BurstQuorum: 3, TrailerKey: "grpc-status"},
+			{Name: "api", Type: "dns-consistency", Hostname: "example.com"},
+		},
+	}
+
+	err := config.Validate()
+
+	assert.ErrorContains(t, err, "scraper api: burst_quorum 3 exceeds burst 2")
+	assert.ErrorContains(t, err, "scraper api: burst and trailer_key are mutually exclusive")
+	assert.ErrorContains(t, err, "scraper api: name is used by more than one scraper")
+}
+
+func TestNewConfig_InvalidScraperFields(t *testing.T) {
+	logger := logrus.New()
+
+	os.Setenv("HEALTHCHECK_SCRAPERS", `[{"name":"api","healthcheck-scraper-type":"http","scrape_url":"http://localhost:8080","metric_name":"up"}]`)
+	defer os.Unsetenv("HEALTHCHECK_SCRAPERS")
+
+	config, err := NewConfig(logger)
+
+	assert.ErrorContains(t, err, "scraper api: metric_name does not apply to type http")
+	assert.Nil(t, config)
+}
+
+func TestValidateGroups(t *testing.T) {
+	checkout := ScraperGroup{Name: "checkout", GroupPingURL: "http://example.com/ping"}
+
+	tests := []struct {
+		name     string
+		groups   []ScraperGroup
+		scrapers []HealthcheckScraper
+		err      string
+	}{
+		{name: "valid", groups: []ScraperGroup{checkout}, scrapers: []HealthcheckScraper{{Name: "api", Group: "checkout"}, {Name: "other"}}},
+		{name: "unnamed group", groups: []ScraperGroup{{GroupPingURL: "http://example.com/ping"}}, err: "scraper groups require a name"},
+		{name: "duplicate group", groups: []ScraperGroup{checkout, checkout}, err: "duplicate scraper group checkout"},
+		{name: "missing ping url", groups: []ScraperGroup{{Name: "checkout"}}, err: "scraper group checkout requires group_ping_url"},
+		{name: "unknown group", groups: []ScraperGroup{checkout}, scrapers: []HealthcheckScraper{{Name: "api", Group: "payments"}}, err: "scraper api is in unknown group payments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroups(tt.groups, tt.scrapers)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// weekdays maps the day abbreviations accepted in active_hours to their weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// activeWindow is the recurring schedule during which a scraper is active
+type activeWindow struct {
+	days       [7]bool
+	start, end time.Duration
+	location   *time.Location
+}
+
+// parseActiveHours parses the scraper's active_hours such as "09:00-17:00" or
+// "Mon-Fri 09:00-17:00" in its timezone, returning nil when the scraper is always active
+func parseActiveHours(scraperConfig config.HealthcheckScraper) (*activeWindow, error) {
+	if scraperConfig.ActiveHours == "" {
+		return nil, nil
+	}
+
+	window := &activeWindow{location: time.Local}
+	if scraperConfig.Timezone != "" {
+		location, err := time.LoadLocation(scraperConfig.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: invalid timezone: %w", scraperConfig.Name, err)
+		}
+		window.location = location
+	}
+
+	fields := strings.Fields(scraperConfig.ActiveHours)
+	hours := fields[len(fields)-1]
+	switch len(fields) {
+	case 1:
+		window.days = [7]bool{true, true, true, true, true, true, true}
+	case 2:
+		if err := window.parseDays(fields[0]); err != nil {
+			return nil, fmt.Errorf("scraper %s: invalid active_hours %q: %w", scraperConfig.Name, scraperConfig.ActiveHours, err)
+		}
+	default:
+		return nil, fmt.Errorf("scraper %s: invalid active_hours %q, expected [days] HH:MM-HH:MM", scraperConfig.Name, scraperConfig.ActiveHours)
+	}
+
+	start, end, ok := strings.Cut(hours, "-")
+	var err error
+	if ok {
+		if window.start, err = parseTimeOfDay(start); err == nil {
+			window.end, err = parseTimeOfDay(end)
+		}
+	}
+	if !ok || err != nil || window.start == window.end {
+		return nil, fmt.Errorf("scraper %s: invalid active_hours %q, expected [days] HH:MM-HH:MM", scraperConfig.Name, scraperConfig.ActiveHours)
+	}
+
+	return window, nil
+}
+
+// parseDays parses a day range such as "Mon-Fri" or a list such as "Mon,Wed,Fri"
+func (w *activeWindow) parseDays(days string) error {
+	for _, part := range strings.Split(days, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, ok := weekdays[strings.ToLower(first)]
+		if !ok {
+			return fmt.Errorf("unknown day %s", first)
+		}
+		to := from
+		if isRange {
+			if to, ok = weekdays[strings.ToLower(last)]; !ok {
+				return fmt.Errorf("unknown day %s", last)
+			}
+		}
+
+		// Ranges such as Fri-Mon wrap around the end of the week
+		for day := from; ; day = (day + 1) % 7 {
+			w.days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseTimeOfDay parses HH:MM into the duration since midnight, allowing 24:00 as the end
+// of the day
+func parseTimeOfDay(value string) (time.Duration, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil {
+		return 0, err
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time of day %s", value)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// contains reports whether the window is active at t. Windows ending before they start,
+// such as 22:00-06:00, run past midnight and belong to the day they start on.
+func (w *activeWindow) contains(t time.Time) bool {
+	t = t.In(w.location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.location)
+	sinceMidnight := t.Sub(midnight)
+
+	if w.start < w.end {
+		return w.days[t.Weekday()] && sinceMidnight >= w.start && sinceMidnight < w.end
+	}
+
+	if sinceMidnight >= w.start {
+		return w.days[t.Weekday()]
+	}
+	yesterday := (t.Weekday() + 6) % 7
+	return sinceMidnight < w.end && w.days[yesterday]
+}
+
+// outsideActiveHours reports whether the scraper is outside its active hours, logging when
+// it becomes inactive or active again
+func (m *Manager) outsideActiveHours(s scraper.Scraper, state *scraperState) bool {
+	if state.activeHours == nil {
+		return false
+	}
+
+	inactive := !state.activeHours.contains(m.now())
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if inactive != state.inactive {
+		entry := m.logger.WithFields(logrus.Fields{
+			"name":         state.config.Name,
+			"scraper_type": s.Type(),
+			"active_hours": state.config.ActiveHours,
+		})
+		if inactive {
+			entry.Info("Healthcheck paused outside active hours")
+		} else {
+			entry.Info("Healthcheck resumed within active hours")
+		}
+	}
+	state.inactive = inactive
+
+	return inactive
+}
@@ -0,0 +1,104 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActiveHours_Invalid(t *testing.T) {
+	tests := []struct {
+		activeHours string
+		timezone    string
+		err         string
+	}{
+		{activeHours: "9-5", err: "expected [days] HH:MM-HH:MM"},
+		{activeHours: "09:00-25:00", err: "expected [days] HH:MM-HH:MM"},
+		{activeHours: "09:00-09:00", err: "expected [days] HH:MM-HH:MM"},
+		{activeHours: "Mon-Fri 09:00-17:00 UTC", err: "expected [days] HH:MM-HH:MM"},
+		{activeHours: "Mon-Fry 09:00-17:00", err: "unknown day Fry"},
+		{activeHours: "09:00-17:00", timezone: "Mars/Olympus_Mons", err: "invalid timezone"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseActiveHours(config.HealthcheckScraper{Name: "batch", ActiveHours: tt.activeHours, Timezone: tt.timezone})
+		assert.ErrorContains(t, err, tt.err, tt.activeHours)
+	}
+
+	window, err := parseActiveHours(config.HealthcheckScraper{})
+	require.NoError(t, err)
+	assert.Nil(t, window)
+}
+
+func TestActiveWindow_Contains(t *testing.T) {
+	tests := []struct {
+		name        string
+		activeHours string
+		at          string
+		active      bool
+	}{
+		{name: "within daily window", activeHours: "09:00-17:00", at: "2024-01-06T12:00:00", active: true},
+		{name: "at start", activeHours: "09:00-17:00", at: "2024-01-06T09:00:00", active: true},
+		{name: "at end", activeHours: "09:00-17:00", at: "2024-01-06T17:00:00", active: false},
+		{name: "weekday within window", activeHours: "Mon-Fri 09:00-17:00", at: "2024-01-05T12:00:00", active: true},
+		{name: "weekend", activeHours: "Mon-Fri 09:00-17:00", at: "2024-01-06T12:00:00", active: false},
+		{name: "listed day", activeHours: "Mon,Wed 09:00-17:00", at: "2024-01-03T12:00:00", active: true},
+		{name: "unlisted day", activeHours: "Mon,Wed 09:00-17:00", at: "2024-01-02T12:00:00", active: false},
+		{name: "overnight before midnight", activeHours: "Fri 22:00-06:00", at: "2024-01-05T23:00:00", active: true},
+		{name: "overnight after midnight", activeHours: "Fri 22:00-06:00", at: "2024-01-06T05:00:00", active: true},
+		{name: "overnight of another day", activeHours: "Fri 22:00-06:00", at: "2024-01-05T05:00:00", active: false},
+		{name: "until end of day", activeHours: "18:00-24:00", at: "2024-01-05T23:59:00", active: true},
+		{name: "wrapping day range", activeHours: "Sat-Sun 00:00-24:00", at: "2024-01-07T12:00:00", active: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := parseActiveHours(config.HealthcheckScraper{ActiveHours: tt.activeHours, Timezone: "UTC"})
+			require.NoError(t, err)
+
+			at, err := time.Parse("2006-01-02T15:04:05", tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.active, window.contains(at))
+		})
+	}
+}
+
+func TestActiveWindow_Contains_Timezone(t *testing.T) {
+	window, err := parseActiveHours(config.HealthcheckScraper{ActiveHours: "09:00-17:00", Timezone: "America/New_York"})
+	require.NoError(t, err)
+
+	// 15:00 UTC is 10:00 in New York in January, 20:00 UTC is 15:00 and 23:00 UTC is 18:00
+	assert.True(t, window.contains(time.Date(2024, 1, 5, 15, 0, 0, 0, time.UTC)))
+	assert.True(t, window.contains(time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC)))
+	assert.False(t, window.contains(time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestManager_ActiveHours(t *testing.T) {
+	manager, clock := newStatusTestManager(0)
+	clock.now = time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC) // Saturday
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "batch", ActiveHours: "Mon-Fri 09:00-17:00", Timezone: "UTC"}, true)
+
+	manager.runSingleHealthcheck(s)
+	assert.Equal(t, 0, s.calls)
+	statuses := manager.Status()
+	assert.Equal(t, StatusInactive, statuses[0].Status)
+	assert.Equal(t, "Mon-Fri 09:00-17:00", statuses[0].ActiveHours)
+
+	clock.now = time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC) // Monday
+	manager.runSingleHealthcheck(s)
+	assert.Equal(t, 1, s.calls)
+	assert.Equal(t, StatusHealthy, manager.Status()[0].Status)
+}
+
+func TestValidateScraperConfigs_ActiveHours(t *testing.T) {
+	err := validateScraperConfigs([]config.HealthcheckScraper{{Name: "batch", Type: "http", ActiveHours: "nine to five"}})
+	assert.ErrorContains(t, err, "scraper batch: invalid active_hours")
+
+	manager := NewManager(&config.Config{Scrapers: []config.HealthcheckScraper{{Name: "batch", Type: "http", ActiveHours: "25:00-26:00"}}}, logrus.New())
+	assert.Error(t, manager.Initialize())
+}
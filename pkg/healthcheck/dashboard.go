@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// dashboardTemplate renders the status of every scraper as an HTML table. The script polls
+// /status every 5 seconds and rebuilds the rows, so the page keeps working as a static snapshot without it.
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Healthcheck Status</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.5em 0.75em; border-bottom: 1px solid #ddd; vertical-align: top; }
+th { background: #f5f5f5; }
+.status { font-weight: bold; border-radius: 4px; padding: 0.15em 0.5em; color: #fff; }
+.healthy { background: #2e7d32; }
+.unhealthy { background: #c62828; }
+.stale { background: #ef6c00; }
+.unknown, .inactive, .skipped { background: #757575; }
+#updated { color: #757575; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Healthcheck Status</h1>
+<p id="updated">Loaded at {{.Now.Format "2006-01-02 15:04:05 MST"}}</p>
+<table>
+<thead><tr><th>Name</th><th>Type</th><th>Status</th><th>Last Message</th><th>Last Checked</th></tr></thead>
+<tbody id="scrapers">
+{{- range .Statuses}}
+<tr><td>{{.Name}}</td><td>{{.Type}}</td><td><span class="status {{.Status}}">{{.Status}}</span></td><td>{{.Message}}</td><td>{{if .LastScrape}}{{.LastScrape.Format "2006-01-02 15:04:05 MST"}}{{end}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+function cell(row, text) {
+  var td = document.createElement("td");
+  td.textContent = text;
+  row.appendChild(td);
+  return td;
+}
+function refresh() {
+  fetch("status", {cache: "no-store"}).then(function (resp) { return resp.json(); }).then(function (statuses) {
+    var body = document.getElementById("scrapers");
+    body.replaceChildren();
+    statuses.forEach(function (s) {
+      var row = document.createElement("tr");
+      cell(row, s.name);
+      cell(row, s.type);
+      var badge = document.createElement("span");
+      badge.className = "status " + s.status;
+      badge.textContent = s.status;
+      cell(row, "").appendChild(badge);
+      cell(row, s.message || "");
+      cell(row, s.last_scrape ? new Date(s.last_scrape).toLocaleString() : "");
+      body.appendChild(row);
+    });
+    document.getElementById("updated").textContent = "Updated at " + new Date().toLocaleString();
+  }).catch(function () {
+    document.getElementById("updated").textContent = "Failed to update, retrying";
+  });
+}
+setInterval(refresh, 5000);
+</script>
+</body>
+</html>
+`))
+
+// DashboardHandler returns an HTTP handler serving a minimal HTML dashboard of the status of
+// every scraper, meant to be mounted at / next to the status endpoint it polls
+func (m *Manager) DashboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		dashboardTemplate.Execute(w, struct {
+			Now      time.Time
+			Statuses []ScraperStatus
+		}{m.now(), m.Status()})
+	})
+}
@@ -0,0 +1,46 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_DashboardHandler(t *testing.T) {
+	manager, _ := newStatusTestManager(0)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false)
+	db.messages = []string{"<script>alert(1)</script>"}
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "queue"}, true)
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+
+	recorder := httptest.NewRecorder()
+	manager.DashboardHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+	body := recorder.Body.String()
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, body, `<tr><td>api</td><td>fake</td><td><span class="status healthy">healthy</span></td><td>fake result</td><td>2024-01-01 12:00:00 UTC</td></tr>`)
+	assert.Contains(t, body, `<td>db</td><td>fake</td><td><span class="status unhealthy">unhealthy</span></td>`)
+	assert.Contains(t, body, `<tr><td>queue</td><td>fake</td><td><span class="status unknown">unknown</span></td><td></td><td></td></tr>`)
+	assert.Contains(t, body, "&lt;script&gt;alert(1)&lt;/script&gt;", "messages are escaped")
+	assert.Contains(t, body, `fetch("status"`)
+	assert.Contains(t, body, "setInterval(refresh, 5000)")
+}
+
+func TestManager_DashboardHandler_OtherPaths(t *testing.T) {
+	manager, _ := newStatusTestManager(0)
+
+	recorder := httptest.NewRecorder()
+	manager.DashboardHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	manager.DashboardHandler().ServeHTTP(recorder, httptest.NewRequest("POST", "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,100 @@
+package healthcheck
+
+import (
+	"fmt"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// validateDependencies checks that every depends_on names another configured scraper and
+// that the dependencies do not form a cycle
+func validateDependencies(scraperConfigs []config.HealthcheckScraper) error {
+	dependsOn := make(map[string]string, len(scraperConfigs))
+	for _, scraperConfig := range scraperConfigs {
+		dependsOn[scraperConfig.Name] = scraperConfig.DependsOn
+	}
+
+	for _, scraperConfig := range scraperConfigs {
+		if scraperConfig.DependsOn == "" {
+			continue
+		}
+		if _, ok := dependsOn[scraperConfig.DependsOn]; !ok {
+			return fmt.Errorf("scraper %s depends on unknown scraper %s", scraperConfig.Name, scraperConfig.DependsOn)
+		}
+
+		// Following a chain longer than the number of scrapers means it loops
+		name := scraperConfig.Name
+		for i := 0; dependsOn[name] != ""; i++ {
+			if i == len(scraperConfigs) {
+				return fmt.Errorf("scraper %s has a dependency cycle", scraperConfig.Name)
+			}
+			name = dependsOn[name]
+		}
+	}
+
+	return nil
+}
+
+// dependencyPending reports whether the scraper's dependency has not reported a healthy
+// result yet, logging when the scraper becomes pending
+func (m *Manager) dependencyPending(s scraper.Scraper, state *scraperState) bool {
+	if state.config.DependsOn == "" {
+		return false
+	}
+
+	var dependency *scraperState
+	m.mu.RLock()
+	for _, candidate := range m.states {
+		if candidate.config.Name == state.config.DependsOn {
+			dependency = candidate
+			break
+		}
+	}
+	m.mu.RUnlock()
+
+	pending := true
+	if dependency != nil {
+		dependency.mu.Lock()
+		pending = !dependency.healthy
+		dependency.mu.Unlock()
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if pending && !state.pending {
+		m.logger.WithFields(logrus.Fields{
+			"name":         state.config.Name,
+			"scraper_type": s.Type(),
+			"depends_on":   state.config.DependsOn,
+		}).Info("Healthcheck pending until dependency is healthy")
+	}
+	state.pending = pending
+
+	return pending
+}
+
+// recordHealth stores the outcome of the scraper's latest scrape for its dependents and
+// the status endpoint, counting it as a flap when the health changed
+func (m *Manager) recordHealth(s scraper.Scraper, healthy bool, message string) {
+	state := m.state(s)
+	if state == nil {
+		return
+	}
+
+	now := m.now()
+	state.mu.Lock()
+	if !state.lastScrape.IsZero() && state.healthy != healthy {
+		state.flaps.record(now)
+	}
+	state.healthy = healthy
+	state.lastScrape = now
+	state.lastMessage = message
+	flaps := state.flaps.count(now, m.flapWindow())
+	state.mu.Unlock()
+
+	scraperFlaps.Set(float64(flaps), state.config.Name)
+}
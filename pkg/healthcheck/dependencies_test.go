@@ -0,0 +1,133 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []config.HealthcheckScraper
+		err     string
+	}{
+		{
+			name: "valid chain",
+			configs: []config.HealthcheckScraper{
+				{Name: "db"},
+				{Name: "api", DependsOn: "db"},
+				{Name: "frontend", DependsOn: "api"},
+			},
+		},
+		{
+			name:    "unknown dependency",
+			configs: []config.HealthcheckScraper{{Name: "api", DependsOn: "db"}},
+			err:     "depends on unknown scraper db",
+		},
+		{
+			name:    "self dependency",
+			configs: []config.HealthcheckScraper{{Name: "api", DependsOn: "api"}},
+			err:     "dependency cycle",
+		},
+		{
+			name: "cycle",
+			configs: []config.HealthcheckScraper{
+				{Name: "db", DependsOn: "api"},
+				{Name: "api", DependsOn: "db"},
+			},
+			err: "dependency cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependencies(tt.configs)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestManager_DependsOn_Satisfied(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.Config{}, logrus.New())
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, true)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", DependsOn: "db"}, true)
+	api.pingURL = server.URL
+	manager.dispatcher.start()
+	defer manager.dispatcher.stop()
+
+	manager.runSingleHealthcheck(db)
+	manager.runSingleHealthcheck(api)
+
+	assert.Equal(t, 1, api.calls)
+	assert.Eventually(t, func() bool { return pings.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestManager_DependsOn_Unhealthy(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	defer server.Close()
+
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false, true, false)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", DependsOn: "db"}, true)
+	api.pingURL = server.URL
+	manager.dispatcher.start()
+	defer manager.dispatcher.stop()
+
+	// Pending before the dependency reported any result
+	manager.runSingleHealthcheck(api)
+	assert.Equal(t, 0, api.calls)
+
+	// Still pending while the dependency is unhealthy
+	manager.runSingleHealthcheck(db)
+	manager.runSingleHealthcheck(api)
+	assert.Equal(t, 0, api.calls)
+	assert.Equal(t, 1, countLogs(hook, "Healthcheck pending until dependency is healthy"))
+
+	// Resumes once the dependency is healthy
+	manager.runSingleHealthcheck(db)
+	manager.runSingleHealthcheck(api)
+	assert.Equal(t, 1, api.calls)
+	assert.Eventually(t, func() bool { return pings.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Pending again after the dependency fails
+	manager.runSingleHealthcheck(db)
+	manager.runSingleHealthcheck(api)
+	assert.Equal(t, 1, api.calls)
+	assert.Equal(t, 2, countLogs(hook, "Healthcheck pending until dependency is healthy"))
+	assert.Equal(t, int32(1), pings.Load())
+}
+
+func TestManager_Initialize_InvalidDependency(t *testing.T) {
+	manager := NewManager(&config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{Name: "api", Type: "http", ScrapeURL: "http://localhost:8080/health", DependsOn: "db"},
+		},
+	}, logrus.New())
+
+	err := manager.Initialize()
+
+	assert.ErrorContains(t, err, "unknown scraper db")
+}
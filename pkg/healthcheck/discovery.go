@@ -0,0 +1,146 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+const (
+	// defaultDiscoveryInterval is how often the discovery URL is polled when no interval is
+	// configured
+	defaultDiscoveryInterval = 60 * time.Second
+	// discoveryTimeout bounds a single request to the discovery URL
+	discoveryTimeout = 10 * time.Second
+	// discoveredNamePrefix is prepended to the names of discovered scrapers without a name, so
+	// they do not collide with the default names of the configured scrapers
+	discoveredNamePrefix = "discovered-"
+)
+
+// discoveryLoop polls the discovery URL right away and then on every discovery interval,
+// reconciling the running scrapers with the configured and discovered ones until the
+// manager stops
+func (m *Manager) discoveryLoop() {
+	defer m.wg.Done()
+
+	interval := defaultDiscoveryInterval
+	if m.config.DiscoveryIntervalSeconds > 0 {
+		interval = time.Duration(m.config.DiscoveryIntervalSeconds) * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var discovered []config.HealthcheckScraper
+	for {
+		discovered = m.discover(discovered)
+
+		select {
+		case <-ticker.C:
+		case <-m.stopChan:
+			return
+		}
+	}
+}
+
+// discover fetches the scrapers from the discovery URL and reloads the manager with them
+// when they differ from the previously discovered ones, returning the scrapers now running.
+// When the discovery URL cannot be read or the reload fails, the running scrapers are kept.
+func (m *Manager) discover(previous []config.HealthcheckScraper) []config.HealthcheckScraper {
+	logger := m.logger.WithField("discovery_url", m.config.DiscoveryURL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	discovered, err := m.fetchDiscoveredScrapers(ctx)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to discover scrapers, keeping the running scrapers")
+		return previous
+	}
+	if previous != nil && reflect.DeepEqual(discovered, previous) {
+		return previous
+	}
+
+	scraperConfigs := append(append([]config.HealthcheckScraper(nil), m.config.Scrapers...), discovered...)
+	if err := m.Reload(scraperConfigs); err != nil {
+		logger.WithError(err).Error("Failed to reload discovered scrapers, keeping the running scrapers")
+		return previous
+	}
+
+	logger.WithField("discovered", len(discovered)).Info("Discovered scrapers")
+	return discovered
+}
+
+// fetchDiscoveredScrapers reads the JSON array of scraper configurations served at the
+// discovery URL
+func (m *Manager) fetchDiscoveredScrapers(ctx context.Context) ([]config.HealthcheckScraper, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", m.config.DiscoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	discovered := []config.HealthcheckScraper{}
+	if err := json.NewDecoder(resp.Body).Decode(&discovered); err != nil {
+		return nil, fmt.Errorf("failed to parse scrapers: %w", err)
+	}
+	// Hooks run commands on the host and local files could be sent to a URL of the registry's
+	// choosing, which the registry must never be able to do, even with
+	// HEALTHCHECK_ENABLE_SCRAPE_HOOKS set for the configured scrapers
+	for i, scraperConfig := range discovered {
+		name := scraperConfig.Name
+		if name == "" {
+			name = fmt.Sprintf("%s%s-%d", discoveredNamePrefix, scraperConfig.Type, i)
+		}
+		if len(scraperConfig.PreScrapeCmd) > 0 || len(scraperConfig.PostScrapeCmd) > 0 {
+			return nil, fmt.Errorf("scraper %s: pre_scrape_cmd and post_scrape_cmd are not allowed on discovered scrapers", name)
+		}
+		if fields := localFileFields(scraperConfig); len(fields) > 0 {
+			return nil, fmt.Errorf("scraper %s: %s not allowed on discovered scrapers", name, strings.Join(fields, ", "))
+		}
+	}
+	if err := config.PrepareScrapers(discovered, discoveredNamePrefix, m.config.ScrapeSizeMetrics); err != nil {
+		return nil, err
+	}
+	return discovered, nil
+}
+
+// localFileFields returns the JSON keys of the scraper's fields that are set and read a local
+// file, such as its secret files, CA bundle and kubeconfig
+func localFileFields(scraperConfig config.HealthcheckScraper) []string {
+	paths := []struct {
+		name string
+		path string
+	}{
+		{"scrape_url_file", scraperConfig.ScrapeURLFile},
+		{"ping_url_file", scraperConfig.PingURLFile},
+		{"notify_url_file", scraperConfig.NotifyURLFile},
+		{"aws_secret_access_key_file", scraperConfig.AWSSecretAccessKeyFile},
+		{"auth_header_file", scraperConfig.AuthHeaderFile},
+		{"ca_cert_file", scraperConfig.CACertFile},
+		{"kubeconfig", scraperConfig.Kubeconfig},
+	}
+
+	var fields []string
+	for _, p := range paths {
+		if p.path != "" {
+			fields = append(fields, p.name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,194 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// discoveryTestServer is a mock service registry serving a replaceable response
+type discoveryTestServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	status int
+	body   string
+}
+
+func newDiscoveryTestServer(t *testing.T, body string) *discoveryTestServer {
+	server := &discoveryTestServer{status: http.StatusOK, body: body}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		w.WriteHeader(server.status)
+		w.Write([]byte(server.body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// serve replaces the response of the registry
+func (s *discoveryTestServer) serve(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body = status, body
+}
+
+// newDiscoveryTestManager creates an initialized manager with one configured scraper
+// discovering scrapers from the registry
+func newDiscoveryTestManager(t *testing.T, registry *discoveryTestServer) (*Manager, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{Name: "static", Type: "http", ScrapeURL: "http://localhost:8080/health"},
+		},
+		DiscoveryURL: registry.URL,
+	}, logger)
+	require.NoError(t, manager.Initialize())
+	return manager, hook
+}
+
+// scraperNames returns the names of the manager's scrapers in order
+func scraperNames(m *Manager) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.scrapers))
+	for _, s := range m.scrapers {
+		names = append(names, m.states[s].config.Name)
+	}
+	return names
+}
+
+func TestManager_Discover_AddsAndRemovesScrapers(t *testing.T) {
+	registry := newDiscoveryTestServer(t, `[
+		{"name": "orders", "healthcheck-scraper-type": "http", "scrape_url": "http://orders:8080/health"},
+		{"name": "billing", "healthcheck-scraper-type": "http", "scrape_url": "http://billing:8080/health"}
+	]`)
+	manager, _ := newDiscoveryTestManager(t, registry)
+
+	discovered := manager.discover(nil)
+	require.Len(t, discovered, 2)
+	assert.Equal(t, []string{"static", "orders", "billing"}, scraperNames(manager))
+	orders := manager.scrapers[1]
+
+	registry.serve(http.StatusOK, `[
+		{"name": "orders", "healthcheck-scraper-type": "http", "scrape_url": "http://orders:8080/health"}
+	]`)
+	discovered = manager.discover(discovered)
+
+	require.Len(t, discovered, 1)
+	assert.Equal(t, []string{"static", "orders"}, scraperNames(manager))
+	assert.Same(t, orders, manager.scrapers[1], "unchanged scraper should keep running")
+}
+
+func TestManager_Discover_Unchanged(t *testing.T) {
+	registry := newDiscoveryTestServer(t, `[{"name": "orders", "healthcheck-scraper-type": "http", "scrape_url": "http://orders:8080/health"}]`)
+	manager, hook := newDiscoveryTestManager(t, registry)
+
+	discovered := manager.discover(nil)
+	manager.discover(discovered)
+
+	assert.Equal(t, 1, countLogs(hook, "Reloaded scrapers"))
+}
+
+func TestManager_Discover_NamesUnnamedScrapers(t *testing.T) {
+	registry := newDiscoveryTestServer(t, `[{"healthcheck-scraper-type": "http", "scrape_url": "http://orders:8080/health"}]`)
+	manager, _ := newDiscoveryTestManager(t, registry)
+
+	manager.discover(nil)
+
+	assert.Equal(t, []string{"static", "discovered-http-0"}, scraperNames(manager))
+}
+
+func TestManager_Discover_KeepsScrapersOnFailure(t *testing.T) {
+	registry := newDiscoveryTestServer(t, `[{"name": "orders", "healthcheck-scraper-type": "http", "scrape_url": "http://orders:8080/health"}]`)
+	manager, hook := newDiscoveryTestManager(t, registry)
+	discovered := manager.discover(nil)
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		log    string
+	}{
+		{name: "unavailable", status: http.StatusServiceUnavailable, log: "Failed to discover scrapers, keeping the running scrapers"},
+		{name: "invalid JSON", status: http.StatusOK, body: `{"scrapers": []}`, log: "Failed to discover scrapers, keeping the running scrapers"},
+		{name: "invalid scraper", status: http.StatusOK, body: `[{"name": "static", "healthcheck-scraper-type": "http"}]`, log: "Failed to reload discovered scrapers, keeping the running scrapers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+			registry.serve(tt.status, tt.body)
+
+			assert.Equal(t, discovered, manager.discover(discovered))
+			assert.Equal(t, []string{"static", "orders"}, scraperNames(manager))
+			assert.Equal(t, 1, countLogs(hook, tt.log))
+		})
+	}
+}
+
+func TestManager_Discover_RejectsHooks(t *testing.T) {
+	registry := newDiscoveryTestServer(t, `[{"name": "orders", "healthcheck-scraper-type": "http", "scrape_url": "http://orders:8080/health", "pre_scrape_cmd": ["touch", "/tmp/pwned"]}]`)
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{
+		Scrapers:          []config.HealthcheckScraper{{Name: "static", Type: "http", ScrapeURL: "http://localhost:8080/health"}},
+		DiscoveryURL:      registry.URL,
+		EnableScrapeHooks: true,
+	}, logger)
+	require.NoError(t, manager.Initialize())
+
+	assert.Nil(t, manager.discover(nil))
+	assert.Equal(t, []string{"static"}, scraperNames(manager))
+	require.Equal(t, 1, countLogs(hook, "Failed to discover scrapers, keeping the running scrapers"))
+	assert.ErrorContains(t, hook.LastEntry().Data[logrus.ErrorKey].(error), "scraper orders: pre_scrape_cmd and post_scrape_cmd are not allowed on discovered scrapers")
+}
+
+func TestManager_Discover_RejectsLocalFiles(t *testing.T) {
+	token := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(token, []byte("secret-token"), 0o600))
+	registry := newDiscoveryTestServer(t, `[{"healthcheck-scraper-type": "grpc-web", "scrape_url": "http://attacker:8080/", "auth_header_file": "`+token+`"}]`)
+	manager, hook := newDiscoveryTestManager(t, registry)
+
+	assert.Nil(t, manager.discover(nil))
+	assert.Equal(t, []string{"static"}, scraperNames(manager))
+	require.Equal(t, 1, countLogs(hook, "Failed to discover scrapers, keeping the running scrapers"))
+	assert.EqualError(t, hook.LastEntry().Data[logrus.ErrorKey].(error), "scraper discovered-grpc-web-0: auth_header_file not allowed on discovered scrapers")
+}
+
+func TestLocalFileFields(t *testing.T) {
+	assert.Empty(t, localFileFields(config.HealthcheckScraper{ScrapeURL: "http://orders:8080/health"}))
+	assert.Equal(t, []string{"scrape_url_file", "ca_cert_file", "kubeconfig"}, localFileFields(config.HealthcheckScraper{
+		ScrapeURLFile: "/etc/passwd",
+		CACertFile:    "/etc/ssl/ca.pem",
+		Kubeconfig:    "/root/.kube/config",
+	}))
+}
+
+func TestManager_DiscoveryLoop(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	registry := newDiscoveryTestServer(t, `[{"name": "orders", "healthcheck-scraper-type": "http", "scrape_url": "`+target.URL+`"}]`)
+
+	manager := NewManager(&config.Config{DiscoveryURL: registry.URL}, logrus.New())
+	require.NoError(t, manager.Initialize())
+	manager.Start()
+	defer manager.Stop()
+
+	// The discovered scraper is started and scrapes its target
+	require.Eventually(t, func() bool {
+		statuses := manager.Status()
+		return len(statuses) == 1 && statuses[0].Name == "orders" && statuses[0].Status == StatusHealthy
+	}, 2*time.Second, 10*time.Millisecond)
+}
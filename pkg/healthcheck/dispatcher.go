@@ -0,0 +1,103 @@
+package healthcheck
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultNotificationWorkers   = 4
+	defaultNotificationQueueSize = 100
+)
+
+// notification is a unit of work delivered by the dispatcher
+type notification struct {
+	scraperType string
+	url         string
+	deliver     func()
+}
+
+// dispatcher delivers notifications from a bounded queue using a fixed number of workers,
+// decoupling notification delivery from the scrape loop
+type dispatcher struct {
+	queue   chan notification
+	workers int
+	logger  *logrus.Logger
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+// newDispatcher creates a new notification dispatcher
+func newDispatcher(workers, queueSize int, logger *logrus.Logger) *dispatcher {
+	if workers <= 0 {
+		workers = defaultNotificationWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultNotificationQueueSize
+	}
+
+	return &dispatcher{
+		queue:   make(chan notification, queueSize),
+		workers: workers,
+		logger:  logger,
+	}
+}
+
+// start launches the dispatcher workers
+func (d *dispatcher) start() {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// stop stops accepting notifications and waits for the queued ones to be delivered
+func (d *dispatcher) stop() {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	d.stopped = true
+	close(d.queue)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// dispatch queues a notification without blocking, dropping it when the queue is full
+func (d *dispatcher) dispatch(n notification) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fields := logrus.Fields{
+		"scraper_type": n.scraperType,
+		"url":          n.url,
+	}
+
+	if d.stopped {
+		d.logger.WithFields(fields).Warn("Notification dispatcher stopped, dropping notification")
+		return false
+	}
+
+	select {
+	case d.queue <- n:
+		d.logger.WithFields(fields).WithField("queued", len(d.queue)).Debug("Queued notification")
+		return true
+	default:
+		d.logger.WithFields(fields).WithField("queue_size", cap(d.queue)).Warn("Notification queue full, dropping notification")
+		return false
+	}
+}
+
+// work delivers queued notifications until the queue is closed
+func (d *dispatcher) work() {
+	defer d.wg.Done()
+
+	for n := range d.queue {
+		n.deliver()
+	}
+}
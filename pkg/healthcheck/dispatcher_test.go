@@ -0,0 +1,55 @@
+package healthcheck
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDispatcher_Defaults(t *testing.T) {
+	d := newDispatcher(0, 0, logrus.New())
+
+	assert.Equal(t, defaultNotificationWorkers, d.workers)
+	assert.Equal(t, defaultNotificationQueueSize, cap(d.queue))
+}
+
+func TestDispatcher_DeliversQueuedNotifications(t *testing.T) {
+	d := newDispatcher(2, 10, logrus.New())
+	d.start()
+
+	var delivered int32
+	for i := 0; i < 5; i++ {
+		assert.True(t, d.dispatch(notification{
+			url: "http://localhost:8081/ping",
+			deliver: func() {
+				atomic.AddInt32(&delivered, 1)
+			},
+		}))
+	}
+
+	// Stop drains the queue before returning
+	d.stop()
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(&delivered))
+}
+
+func TestDispatcher_DropsWhenQueueFull(t *testing.T) {
+	d := newDispatcher(1, 1, logrus.New())
+
+	// Workers are not started, so the queue fills up
+	assert.True(t, d.dispatch(notification{deliver: func() {}}))
+	assert.False(t, d.dispatch(notification{deliver: func() {}}))
+}
+
+func TestDispatcher_DropsAfterStop(t *testing.T) {
+	d := newDispatcher(1, 1, logrus.New())
+	d.start()
+	d.stop()
+
+	assert.False(t, d.dispatch(notification{deliver: func() {}}))
+
+	// Stopping twice should not panic
+	d.stop()
+}
@@ -0,0 +1,61 @@
+package healthcheck
+
+import (
+	"time"
+
+	"healthcheck/pkg/metrics"
+)
+
+// defaultFlapWindow is the window health transitions are counted over when none is configured
+const defaultFlapWindow = time.Hour
+
+var scraperFlaps = metrics.DefaultRegistry.NewGaugeVec(
+	"healthcheck_flaps",
+	"Health state transitions of the scraper within the flap window.",
+	"scraper",
+)
+
+// flapCounter keeps the times of a scraper's health transitions within the flap window,
+// guarded by the scraper state's mutex. Transitions older than the window are dropped as
+// they are counted, so the count decays once the scraper stabilizes.
+type flapCounter struct {
+	transitions []time.Time
+}
+
+// record records a transition at the given time
+func (f *flapCounter) record(at time.Time) {
+	f.transitions = append(f.transitions, at)
+}
+
+// count drops the transitions older than the window and returns how many remain
+func (f *flapCounter) count(now time.Time, window time.Duration) int {
+	cutoff := now.Add(-window)
+	expired := 0
+	for expired < len(f.transitions) && !f.transitions[expired].After(cutoff) {
+		expired++
+	}
+	f.transitions = f.transitions[expired:]
+	return len(f.transitions)
+}
+
+// flapWindow returns the window health transitions are counted over
+func (m *Manager) flapWindow() time.Duration {
+	if m.config.FlapWindow > 0 {
+		return m.config.FlapWindow
+	}
+	return defaultFlapWindow
+}
+
+// forgetFlaps removes the flap gauge of a removed scraper unless a running scraper took
+// over its name
+func (m *Manager) forgetFlaps(name string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, state := range m.states {
+		if state.config.Name == name {
+			return
+		}
+	}
+	scraperFlaps.Delete(name)
+}
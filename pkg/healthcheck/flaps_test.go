@@ -0,0 +1,76 @@
+package healthcheck
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsOutput returns the metrics served on /metrics
+func metricsOutput() string {
+	var out strings.Builder
+	metrics.DefaultRegistry.Write(&out)
+	return out.String()
+}
+
+func TestFlapCounter_Decay(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var flaps flapCounter
+	flaps.record(start)
+	flaps.record(start.Add(10 * time.Minute))
+	flaps.record(start.Add(20 * time.Minute))
+
+	assert.Equal(t, 3, flaps.count(start.Add(30*time.Minute), time.Hour))
+	assert.Equal(t, 2, flaps.count(start.Add(time.Hour), time.Hour))
+	assert.Equal(t, 0, flaps.count(start.Add(2*time.Hour), time.Hour))
+	assert.Empty(t, flaps.transitions)
+}
+
+func TestManager_Flaps(t *testing.T) {
+	manager, clock := newStatusTestManager(0)
+	manager.config.FlapWindow = 10 * time.Minute
+	// Healthy, unhealthy, healthy, healthy, unhealthy: three transitions
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "flappy-api"}, true, false, true, true, false)
+	stable := addFakeScraper(manager, config.HealthcheckScraper{Name: "stable-db"}, false)
+
+	for range 5 {
+		manager.runSingleHealthcheck(api)
+		manager.runSingleHealthcheck(stable)
+		clock.now = clock.now.Add(time.Minute)
+	}
+
+	statuses := manager.Status()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, 3, statuses[0].Flaps)
+	assert.Equal(t, 0, statuses[1].Flaps, "a steadily unhealthy scraper does not flap")
+	assert.Equal(t, 3.0, scraperFlaps.Value("flappy-api"))
+	assert.Equal(t, 0.0, scraperFlaps.Value("stable-db"))
+
+	// The transitions decay once they leave the window, even while the scraper is not scraped
+	clock.now = clock.now.Add(7 * time.Minute)
+	assert.Equal(t, 1, manager.Status()[0].Flaps)
+
+	// Recovering is a transition of its own, counted along with the one still in the window
+	manager.runSingleHealthcheck(api)
+	assert.Equal(t, 2.0, scraperFlaps.Value("flappy-api"))
+}
+
+func TestManager_Flaps_RemovedOnReload(t *testing.T) {
+	manager := NewManager(&config.Config{DrainTimeout: time.Second}, logrus.New())
+	require.NoError(t, manager.Reload([]config.HealthcheckScraper{
+		{Name: "flaps-removed", Type: "http", ScrapeURL: "http://localhost:1/health"},
+	}))
+	manager.recordHealth(manager.scrapers[0], true, "ok")
+	assert.Contains(t, metricsOutput(), `healthcheck_flaps{scraper="flaps-removed"} 0`)
+
+	require.NoError(t, manager.Reload(nil))
+
+	assert.NotContains(t, metricsOutput(), `scraper="flaps-removed"`)
+}
@@ -0,0 +1,126 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// gateTimeout bounds the request to a scraper's gate_url
+	gateTimeout = 5 * time.Second
+	// maxGateBody caps how much of the gate's response is read to decide whether it is open
+	maxGateBody = 1024
+)
+
+// gateDecision is the outcome of checking a scraper's gate
+type gateDecision struct {
+	open bool
+	// reason describes why a closed gate is closed
+	reason  string
+	details map[string]interface{}
+}
+
+// checkGate requests the scraper's gate_url. The gate is open when it responds with HTTP 200
+// and a body other than false; any other response, or a gate that cannot be reached, closes
+// it so the scrape is skipped.
+func (m *Manager) checkGate(ctx context.Context, gateURL string) gateDecision {
+	ctx, cancel := context.WithTimeout(ctx, gateTimeout)
+	defer cancel()
+
+	details := map[string]interface{}{"gate_url": gateURL, "gate_open": false}
+	closed := func(reason string) gateDecision {
+		return gateDecision{reason: reason, details: details}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", gateURL, nil)
+	if err != nil {
+		details["gate_error"] = err.Error()
+		return closed(err.Error())
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		details["gate_error"] = err.Error()
+		return closed(err.Error())
+	}
+	defer resp.Body.Close()
+
+	details["gate_status_code"] = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		return closed(fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGateBody))
+	if err != nil {
+		details["gate_error"] = err.Error()
+		return closed(err.Error())
+	}
+	if strings.EqualFold(strings.TrimSpace(string(body)), "false") {
+		return closed("responded false")
+	}
+
+	details["gate_open"] = true
+	return gateDecision{open: true, details: details}
+}
+
+// skipScrape records that the scraper's gate is closed: the scrape is reported skipped to the
+// status endpoint and the sinks, leaving its latest result, health, pings and notifications
+// untouched.
+// Closing and reopening of the gate are logged. It returns the message reported for the skip.
+func (m *Manager) skipScrape(name string, s scraper.Scraper, state *scraperState, gate gateDecision) string {
+	message := fmt.Sprintf("Scrape skipped, gate %s is closed: %s", state.config.GateURL, gate.reason)
+
+	state.mu.Lock()
+	if !state.skipped {
+		m.logger.WithFields(logrus.Fields{
+			"name":         state.config.Name,
+			"scraper_type": s.Type(),
+			"gate_url":     state.config.GateURL,
+			"reason":       gate.reason,
+		}).Info("Healthcheck skipped while its gate is closed")
+	}
+	state.skipped = true
+	state.lastSkip = m.now()
+	state.skipMessage = message
+	state.mu.Unlock()
+
+	m.sinks.publish(name, scraper.ScrapeResult{
+		Skipped:   true,
+		Message:   message,
+		Timestamp: m.now(),
+		Details:   gate.details,
+		Type:      s.Type(),
+	})
+	return message
+}
+
+// openGate merges the decision of an open gate into the scrape's result, logging when the
+// gate reopened after skipped scrapes
+func (m *Manager) openGate(s scraper.Scraper, state *scraperState, gate gateDecision, result *scraper.ScrapeResult) {
+	state.mu.Lock()
+	if state.skipped {
+		m.logger.WithFields(logrus.Fields{
+			"name":         state.config.Name,
+			"scraper_type": s.Type(),
+			"gate_url":     state.config.GateURL,
+		}).Info("Healthcheck gate opened, resuming scrapes")
+	}
+	state.skipped = false
+	state.mu.Unlock()
+
+	if result == nil {
+		return
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{}, len(gate.details))
+	}
+	for key, value := range gate.details {
+		result.Details[key] = value
+	}
+}
@@ -0,0 +1,178 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGateServer serves the given status and body as the gate
+func newGateServer(t *testing.T, status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestManager_Gate_Open(t *testing.T) {
+	gate := newGateServer(t, http.StatusOK, "true")
+	manager := NewManager(&config.Config{}, logrus.New())
+	sink := &fakeSink{}
+	manager.AddSink(sink)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, true)
+
+	manager.runSingleHealthcheck(s)
+	manager.sinks.stop()
+
+	assert.Equal(t, 1, s.calls)
+	assert.Equal(t, StatusHealthy, manager.Status()[0].Status)
+
+	results := sink.published()
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Skipped)
+	assert.True(t, results[0].Healthy)
+	assert.Equal(t, true, results[0].Details["gate_open"])
+	assert.Equal(t, gate.URL, results[0].Details["gate_url"])
+	assert.Equal(t, http.StatusOK, results[0].Details["gate_status_code"])
+}
+
+func TestManager_Gate_Closed(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		reason string
+	}{
+		{name: "non-200", status: http.StatusServiceUnavailable, reason: "HTTP 503"},
+		{name: "false", status: http.StatusOK, body: "false\n", reason: "responded false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := newGateServer(t, tt.status, tt.body)
+			manager := NewManager(&config.Config{}, logrus.New())
+			sink := &fakeSink{}
+			manager.AddSink(sink)
+			s := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, true)
+
+			manager.runSingleHealthcheck(s)
+			manager.sinks.stop()
+
+			assert.Equal(t, 0, s.calls)
+			status := manager.Status()[0]
+			assert.Equal(t, StatusSkipped, status.Status)
+			assert.Contains(t, status.Message, tt.reason)
+
+			results := sink.published()
+			require.Len(t, results, 1)
+			assert.True(t, results[0].Skipped)
+			assert.Equal(t, false, results[0].Details["gate_open"])
+			assert.Equal(t, tt.status, results[0].Details["gate_status_code"])
+		})
+	}
+}
+
+func TestManager_Gate_Unreachable(t *testing.T) {
+	gate := newGateServer(t, http.StatusOK, "")
+	gate.Close()
+
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, true)
+
+	manager.runSingleHealthcheck(s)
+
+	assert.Equal(t, 0, s.calls)
+	assert.Equal(t, StatusSkipped, manager.Status()[0].Status)
+}
+
+func TestManager_Gate_Reopens(t *testing.T) {
+	open := false
+	gate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !open {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gate.Close()
+
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, false)
+
+	manager.runSingleHealthcheck(s)
+	assert.Equal(t, StatusSkipped, manager.Status()[0].Status)
+
+	open = true
+	manager.runSingleHealthcheck(s)
+	assert.Equal(t, 1, s.calls)
+	assert.Equal(t, StatusUnhealthy, manager.Status()[0].Status)
+}
+
+func TestManager_Gate_SkipsKeepLatestResult(t *testing.T) {
+	open := true
+	gate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !open {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer gate.Close()
+
+	manager, clock := newStatusTestManager(0)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, true)
+	manager.runSingleHealthcheck(s)
+	scraped := clock.now
+
+	open = false
+	clock.now = clock.now.Add(time.Minute)
+	manager.runSingleHealthcheck(s)
+
+	// The skip is reported, but the last scrape is still the healthy one
+	status := manager.Status()[0]
+	assert.Equal(t, StatusSkipped, status.Status)
+	assert.Contains(t, status.Message, "HTTP 503")
+	assert.Equal(t, scraped, *status.LastScrape)
+
+	// Skips that stopped coming leave the scraper stale
+	clock.now = clock.now.Add(2 * time.Minute)
+	assert.Equal(t, StatusStale, manager.Status()[0].Status)
+
+	// Reopening with the same health is no flap
+	open = true
+	manager.runSingleHealthcheck(s)
+	status = manager.Status()[0]
+	assert.Equal(t, StatusHealthy, status.Status)
+	assert.Equal(t, clock.now, *status.LastScrape)
+	assert.Zero(t, status.Flaps)
+}
+
+func TestManager_Gate_ClosedBeforeFirstScrape(t *testing.T) {
+	open := false
+	gate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !open {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer gate.Close()
+
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, true)
+
+	manager.runSingleHealthcheck(s)
+	status := manager.Status()[0]
+	assert.Equal(t, StatusSkipped, status.Status)
+	assert.Nil(t, status.LastScrape)
+
+	// The first result after the gate opens is no flap
+	open = true
+	manager.runSingleHealthcheck(s)
+	status = manager.Status()[0]
+	assert.Equal(t, StatusHealthy, status.Status)
+	assert.Zero(t, status.Flaps)
+}
@@ -0,0 +1,102 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Statuses of the Health Check Response Format for HTTP APIs (draft-inadarei-api-health-check)
+const (
+	HealthPass = "pass"
+	HealthWarn = "warn"
+	HealthFail = "fail"
+)
+
+// healthJSONContentType is the media type of the Health Check Response Format
+const healthJSONContentType = "application/health+json"
+
+// HealthResponse is the aggregate health of all scrapers in the Health Check Response Format
+type HealthResponse struct {
+	Status      string                   `json:"status"`
+	Description string                   `json:"description,omitempty"`
+	Checks      map[string][]HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the health of one scraper as an entry of the checks object
+type HealthCheck struct {
+	ComponentID   string `json:"componentId"`
+	ComponentType string `json:"componentType"`
+	Status        string `json:"status"`
+	Time          string `json:"time,omitempty"`
+	Output        string `json:"output,omitempty"`
+}
+
+// healthStatus maps a scraper status to a check status. Stale and unknown results warn, as
+// nothing is known to be failing, while scrapers paused outside their active hours or
+// skipped by a closed gate pass.
+func healthStatus(status string) string {
+	switch status {
+	case StatusHealthy, StatusInactive, StatusSkipped:
+		return HealthPass
+	case StatusUnhealthy:
+		return HealthFail
+	default:
+		return HealthWarn
+	}
+}
+
+// Health returns the status of every running scraper in the Health Check Response Format.
+// Each scraper is a check keyed by its name and type, and the aggregate status is the worst
+// status of the checks.
+func (m *Manager) Health() HealthResponse {
+	response := HealthResponse{
+		Status:      HealthPass,
+		Description: "Health of the healthcheck scrapers",
+		Checks:      make(map[string][]HealthCheck),
+	}
+
+	for _, status := range m.Status() {
+		check := HealthCheck{
+			ComponentID:   status.Name,
+			ComponentType: status.Type,
+			Status:        healthStatus(status.Status),
+		}
+		if status.LastScrape != nil {
+			check.Time = status.LastScrape.UTC().Format(time.RFC3339)
+		}
+		// The output is only meant for checks that warn or fail
+		switch {
+		case check.Status == HealthPass:
+		case status.Status == StatusStale:
+			check.Output = "no result within the result TTL, last result: " + status.Message
+		case status.Status == StatusUnknown:
+			check.Output = "no result yet"
+		default:
+			check.Output = status.Message
+		}
+
+		key := status.Name + ":" + status.Type
+		response.Checks[key] = append(response.Checks[key], check)
+
+		if check.Status == HealthFail || (check.Status == HealthWarn && response.Status == HealthPass) {
+			response.Status = check.Status
+		}
+	}
+
+	return response
+}
+
+// HealthHandler returns an HTTP handler serving the health of all scrapers as
+// application/health+json, with a 503 status while any scraper fails
+func (m *Manager) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := m.Health()
+		w.Header().Set("Content-Type", healthJSONContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		if health.Status == HealthFail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(health)
+	})
+}
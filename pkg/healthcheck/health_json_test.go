@@ -0,0 +1,100 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Health(t *testing.T) {
+	manager, _ := newStatusTestManager(0)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false)
+	db.messages = []string{"connection refused"}
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "queue"}, true)
+
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+
+	health := manager.Health()
+
+	assert.Equal(t, HealthFail, health.Status)
+	require.Len(t, health.Checks, 3)
+	assert.Equal(t, []HealthCheck{{
+		ComponentID:   "api",
+		ComponentType: "fake",
+		Status:        HealthPass,
+		Time:          "2024-01-01T12:00:00Z",
+	}}, health.Checks["api:fake"])
+	assert.Equal(t, HealthFail, health.Checks["db:fake"][0].Status)
+	assert.Equal(t, "connection refused", health.Checks["db:fake"][0].Output)
+	assert.Equal(t, HealthWarn, health.Checks["queue:fake"][0].Status)
+	assert.Equal(t, "no result yet", health.Checks["queue:fake"][0].Output)
+}
+
+func TestManager_Health_AggregateStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		status  string
+	}{
+		{name: "all passing", status: HealthPass},
+		{name: "stale result warns", elapsed: 91 * time.Second, status: HealthWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, clock := newStatusTestManager(0)
+			api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+			manager.runSingleHealthcheck(api)
+			clock.now = clock.now.Add(tt.elapsed)
+
+			health := manager.Health()
+
+			assert.Equal(t, tt.status, health.Status)
+			assert.Equal(t, tt.status, health.Checks["api:fake"][0].Status)
+		})
+	}
+}
+
+func TestManager_HealthHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		healthy bool
+		code    int
+		status  string
+	}{
+		{name: "pass", healthy: true, code: http.StatusOK, status: HealthPass},
+		{name: "fail", healthy: false, code: http.StatusServiceUnavailable, status: HealthFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, _ := newStatusTestManager(0)
+			api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, tt.healthy)
+			manager.runSingleHealthcheck(api)
+
+			recorder := httptest.NewRecorder()
+			manager.HealthHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/health", nil))
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.Equal(t, "application/health+json", recorder.Header().Get("Content-Type"))
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			assert.Equal(t, tt.status, body["status"])
+			checks := body["checks"].(map[string]interface{})
+			check := checks["api:fake"].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, "api", check["componentId"])
+			assert.Equal(t, "fake", check["componentType"])
+			assert.Equal(t, tt.status, check["status"])
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package healthcheck
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultHistorySize is how many results are kept per scraper when no size is configured
+const defaultHistorySize = 100
+
+// HistoryEntry is the outcome of one scheduled scrape kept in a scraper's history
+type HistoryEntry struct {
+	Timestamp time.Time
+	Healthy   bool
+	Message   string
+	Latency   time.Duration
+}
+
+// resultHistory is a ring buffer of a scraper's latest results, guarded by the scraper
+// state's mutex
+type resultHistory struct {
+	entries []HistoryEntry
+	// next is where the next entry is written, overwriting the oldest once the buffer is full
+	next int
+	full bool
+}
+
+func newResultHistory(size int) *resultHistory {
+	return &resultHistory{entries: make([]HistoryEntry, size)}
+}
+
+// add appends the entry, dropping the oldest one when the buffer is full
+func (h *resultHistory) add(entry HistoryEntry) {
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// snapshot returns a copy of the entries, oldest first
+func (h *resultHistory) snapshot() []HistoryEntry {
+	if !h.full {
+		return append([]HistoryEntry(nil), h.entries[:h.next]...)
+	}
+	return append(append([]HistoryEntry(nil), h.entries[h.next:]...), h.entries[:h.next]...)
+}
+
+// historySize returns how many results are kept per scraper
+func (m *Manager) historySize() int {
+	if m.config.HistorySize > 0 {
+		return m.config.HistorySize
+	}
+	return defaultHistorySize
+}
+
+// recordHistory adds the outcome of a scrape to the scraper's history
+func (m *Manager) recordHistory(state *scraperState, healthy bool, message string, latency time.Duration) {
+	if state == nil {
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.history == nil {
+		state.history = newResultHistory(m.historySize())
+	}
+	state.history.add(HistoryEntry{
+		Timestamp: m.now(),
+		Healthy:   healthy,
+		Message:   message,
+		Latency:   latency,
+	})
+}
+
+// History returns the buffered results of the named scraper, oldest first, and false if no
+// such scraper is running
+func (m *Manager) History(name string) ([]HistoryEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.scrapers {
+		state := m.states[s]
+		if state.config.Name != name {
+			continue
+		}
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		if state.history == nil {
+			return []HistoryEntry{}, true
+		}
+		return state.history.snapshot(), true
+	}
+	return nil, false
+}
+
+// HistoryCSVHandler returns an HTTP handler serving the buffered results of the scraper
+// named by the scraper query parameter as CSV
+func (m *Manager) HistoryCSVHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := r.URL.Query().Get("scraper")
+		if name == "" {
+			http.Error(w, "the scraper query parameter is required", http.StatusBadRequest)
+			return
+		}
+		entries, ok := m.History(name)
+		if !ok {
+			http.Error(w, fmt.Sprintf("scraper %q not found", name), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"-history.csv"))
+
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"timestamp", "healthy", "message", "latency_ms"})
+		for _, entry := range entries {
+			writer.Write([]string{
+				entry.Timestamp.UTC().Format(time.RFC3339Nano),
+				strconv.FormatBool(entry.Healthy),
+				entry.Message,
+				strconv.FormatInt(entry.Latency.Milliseconds(), 10),
+			})
+		}
+		writer.Flush()
+	})
+}
@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultHistory_Ring(t *testing.T) {
+	history := newResultHistory(3)
+	for _, message := range []string{"a", "b", "c", "d"} {
+		history.add(HistoryEntry{Message: message})
+	}
+
+	entries := history.snapshot()
+
+	require.Len(t, entries, 3)
+	assert.Equal(t, "b", entries[0].Message)
+	assert.Equal(t, "d", entries[2].Message)
+}
+
+func TestManager_History(t *testing.T) {
+	manager := NewManager(&config.Config{HistorySize: 2}, logrus.New())
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true, false, true)
+
+	for range 3 {
+		manager.runSingleHealthcheck(api)
+	}
+
+	entries, ok := manager.History("api")
+	require.True(t, ok)
+	require.Len(t, entries, 2)
+	assert.False(t, entries[0].Healthy)
+	assert.True(t, entries[1].Healthy)
+
+	_, ok = manager.History("missing")
+	assert.False(t, ok)
+}
+
+func TestManager_HistoryCSVHandler(t *testing.T) {
+	manager, clock := newStatusTestManager(0)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	api.messages = []string{`HTTP 503 from "api", retrying, then giving up`}
+	manager.runSingleHealthcheck(api)
+	clock.now = clock.now.Add(time.Minute)
+	manager.recordHistory(manager.state(api), false, "line one\nline two", 1500*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	manager.HistoryCSVHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/history.csv?scraper=api", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), `filename="api-history.csv"`)
+	assert.Contains(t, recorder.Body.String(), `"HTTP 503 from ""api"", retrying, then giving up"`)
+
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"timestamp", "healthy", "message", "latency_ms"}, records[0])
+	assert.Equal(t, "2024-01-01T12:00:00Z", records[1][0])
+	assert.Equal(t, "true", records[1][1])
+	assert.Equal(t, `HTTP 503 from "api", retrying, then giving up`, records[1][2])
+	assert.Equal(t, []string{"2024-01-01T12:01:00Z", "false", "line one\nline two", "1500"}, records[2])
+}
+
+func TestManager_HistoryCSVHandler_Errors(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	tests := []struct {
+		method string
+		target string
+		code   int
+	}{
+		{method: "GET", target: "/history.csv", code: http.StatusBadRequest},
+		{method: "GET", target: "/history.csv?scraper=missing", code: http.StatusNotFound},
+		{method: "POST", target: "/history.csv?scraper=api", code: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		manager.HistoryCSVHandler().ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.target, nil))
+		assert.Equal(t, tt.code, recorder.Code, tt.target)
+	}
+
+	// A scraper without results yet has only the header
+	recorder := httptest.NewRecorder()
+	manager.HistoryCSVHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/history.csv?scraper=api", nil))
+	assert.Equal(t, "timestamp,healthy,message,latency_ms\n", recorder.Body.String())
+}
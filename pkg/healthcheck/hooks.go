@@ -0,0 +1,152 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultHookTimeout bounds a pre or post scrape hook when hook_timeout_seconds is unset
+	defaultHookTimeout = 10 * time.Second
+	// maxHookOutput caps how much of a hook's combined output is kept for logs and details
+	maxHookOutput = 4096
+)
+
+// validateHooks rejects scrapers configuring hooks unless hooks were enabled, as hooks run
+// arbitrary commands and scrapers may come from a discovery registry
+func validateHooks(enabled bool, scraperConfigs []config.HealthcheckScraper) error {
+	if enabled {
+		return nil
+	}
+	for _, scraperConfig := range scraperConfigs {
+		if len(scraperConfig.PreScrapeCmd) > 0 || len(scraperConfig.PostScrapeCmd) > 0 {
+			return fmt.Errorf("scraper %s: pre_scrape_cmd and post_scrape_cmd require HEALTHCHECK_ENABLE_SCRAPE_HOOKS=true", scraperConfig.Name)
+		}
+	}
+	return nil
+}
+
+// scrapeWithHooks runs the scraper's pre_scrape_cmd, the scrape and then its post_scrape_cmd.
+// A failing pre-scrape hook skips the scrape and is reported as an unhealthy result, while a
+// failing post-scrape hook is only logged.
+func (m *Manager) scrapeWithHooks(ctx context.Context, s scraper.Scraper, state *scraperState) (*scraper.ScrapeResult, error) {
+	if state == nil {
+		return scrape(ctx, s)
+	}
+
+	if len(state.config.PreScrapeCmd) > 0 {
+		output, err := runHook(ctx, state.config.PreScrapeCmd, hookTimeout(state.config), hookEnv(state.config))
+		if err != nil {
+			return &scraper.ScrapeResult{
+				Healthy:   false,
+				Message:   fmt.Sprintf("Pre-scrape hook failed: %v", err),
+				Timestamp: time.Now(),
+				Details: map[string]interface{}{
+					"error_type":  "hook",
+					"hook_output": output,
+				},
+			}, nil
+		}
+	}
+
+	result, err := scrape(ctx, s)
+
+	if len(state.config.PostScrapeCmd) > 0 {
+		env := hookEnv(state.config)
+		env = append(env, "HEALTHCHECK_SCRAPE_HEALTHY="+strconv.FormatBool(err == nil && result.Healthy))
+		if output, hookErr := runHook(ctx, state.config.PostScrapeCmd, hookTimeout(state.config), env); hookErr != nil {
+			m.logger.WithFields(logrus.Fields{
+				"name":   state.config.Name,
+				"error":  hookErr.Error(),
+				"output": output,
+			}).Warn("Post-scrape hook failed")
+		}
+	}
+
+	return result, err
+}
+
+// scrape runs the scraper and normalizes the detail keys of its result, so every consumer of
+// the result sees the standard keys
+func scrape(ctx context.Context, s scraper.Scraper) (*scraper.ScrapeResult, error) {
+	result, err := s.Scrape(ctx)
+	if result != nil {
+		scraper.NormalizeDetails(result.Details)
+	}
+	return result, err
+}
+
+// hookTimeout returns the scraper's hook_timeout_seconds or the default
+func hookTimeout(scraperConfig config.HealthcheckScraper) time.Duration {
+	if scraperConfig.HookTimeoutSeconds > 0 {
+		return time.Duration(scraperConfig.HookTimeoutSeconds) * time.Second
+	}
+	return defaultHookTimeout
+}
+
+// hookEnv returns the environment of a hook: the process environment and the scraper's name
+// and type
+func hookEnv(scraperConfig config.HealthcheckScraper) []string {
+	return append(os.Environ(),
+		"HEALTHCHECK_SCRAPER_NAME="+scraperConfig.Name,
+		"HEALTHCHECK_SCRAPER_TYPE="+scraperConfig.Type,
+	)
+}
+
+// runHook runs the command, given as the program and its arguments, until it exits or the
+// timeout passes, and returns its combined output truncated to maxHookOutput
+func runHook(ctx context.Context, command []string, timeout time.Duration, env []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := &limitedBuffer{limit: maxHookOutput}
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// Do not wait on pipes held open by children of a killed hook
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("%s timed out after %s", command[0], timeout)
+	} else if err != nil {
+		err = fmt.Errorf("%s: %w", command[0], err)
+	}
+	return strings.TrimSpace(output.String()), err
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(remaining, 0)])
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "... (truncated)"
+	}
+	return b.buf.String()
+}
@@ -0,0 +1,110 @@
+package healthcheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_PreScrapeHook(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "refreshed")
+	manager := NewManager(&config.Config{EnableScrapeHooks: true}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{
+		Name:         "api",
+		PreScrapeCmd: []string{"sh", "-c", `echo "$HEALTHCHECK_SCRAPER_NAME" > "$0"`, marker},
+	}, true)
+
+	result, err := manager.scrapeWithHooks(context.Background(), s, manager.state(s))
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, 1, s.calls)
+	contents, err := os.ReadFile(marker)
+	require.NoError(t, err)
+	assert.Equal(t, "api\n", string(contents))
+}
+
+func TestManager_PreScrapeHook_Failure(t *testing.T) {
+	manager := NewManager(&config.Config{EnableScrapeHooks: true}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{
+		Name:         "api",
+		PreScrapeCmd: []string{"sh", "-c", "echo token refresh failed >&2; exit 3"},
+	}, true)
+
+	result, err := manager.scrapeWithHooks(context.Background(), s, manager.state(s))
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Pre-scrape hook failed")
+	assert.Contains(t, result.Message, "exit status 3")
+	assert.Equal(t, "hook", result.Details["error_type"])
+	assert.Equal(t, "token refresh failed", result.Details["hook_output"])
+	assert.Zero(t, s.calls, "the scrape is skipped when the pre-scrape hook fails")
+}
+
+func TestManager_PostScrapeHook(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "outcome")
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{EnableScrapeHooks: true}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{
+		Name:          "api",
+		PostScrapeCmd: []string{"sh", "-c", `echo "$HEALTHCHECK_SCRAPE_HEALTHY" > "$0"; exit 1`, marker},
+	}, false)
+
+	result, err := manager.scrapeWithHooks(context.Background(), s, manager.state(s))
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "fake result", result.Message, "a failing post-scrape hook leaves the result alone")
+	contents, err := os.ReadFile(marker)
+	require.NoError(t, err)
+	assert.Equal(t, "false\n", string(contents))
+	assert.Equal(t, 1, countLogs(hook, "Post-scrape hook failed"))
+}
+
+func TestManager_ScrapeNormalizesDetails(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	s.details = map[string]interface{}{"total_ms": 12, "http_status": 204.0}
+
+	result, err := manager.scrapeWithHooks(context.Background(), s, manager.state(s))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), result.Details["latency_ms"])
+	assert.Equal(t, 204, result.Details["status_code"])
+	assert.Equal(t, 12, result.Details["total_ms"], "aliases are kept")
+}
+
+func TestRunHook_Timeout(t *testing.T) {
+	start := time.Now()
+
+	_, err := runHook(context.Background(), []string{"sleep", "5"}, 50*time.Millisecond, nil)
+
+	assert.ErrorContains(t, err, "timed out after 50ms")
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestRunHook_TruncatesOutput(t *testing.T) {
+	output, err := runHook(context.Background(), []string{"sh", "-c", "head -c 10000 /dev/zero | tr '\\0' x"}, time.Second, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", maxHookOutput)+"... (truncated)", output)
+}
+
+func TestValidateHooks(t *testing.T) {
+	scrapers := []config.HealthcheckScraper{{Name: "api", PreScrapeCmd: []string{"true"}}}
+
+	assert.ErrorContains(t, validateHooks(false, scrapers), "HEALTHCHECK_ENABLE_SCRAPE_HOOKS")
+	assert.NoError(t, validateHooks(true, scrapers))
+	assert.NoError(t, validateHooks(false, []config.HealthcheckScraper{{Name: "api"}}))
+}
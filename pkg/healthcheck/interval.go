@@ -0,0 +1,71 @@
+package healthcheck
+
+import (
+	"fmt"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+)
+
+const (
+	// defaultScrapeInterval is used when a scraper configures no interval
+	defaultScrapeInterval = 30 * time.Second
+	// defaultMinScrapeInterval is the shortest interval accepted unless configured otherwise
+	defaultMinScrapeInterval = 100 * time.Millisecond
+)
+
+// scrapeInterval returns the interval the scraper runs at, its scrape_interval when set and
+// otherwise its interval in whole seconds
+func scrapeInterval(s scraper.Scraper, scraperConfig config.HealthcheckScraper) time.Duration {
+	if scraperConfig.ScrapeInterval != "" {
+		// The interval was validated along with the rest of the configuration
+		if interval, err := time.ParseDuration(scraperConfig.ScrapeInterval); err == nil && interval > 0 {
+			return interval
+		}
+	}
+
+	interval := s.GetScrapeInterval()
+	if interval <= 0 {
+		return defaultScrapeInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// minScrapeInterval returns the configured minimum scrape interval or the default
+func (m *Manager) minScrapeInterval() time.Duration {
+	if m.config.MinScrapeInterval > 0 {
+		return m.config.MinScrapeInterval
+	}
+	return defaultMinScrapeInterval
+}
+
+// validateIntervals rejects scrape intervals below the minimum, and sub-second intervals with
+// the queue overlap policy, which would run scrapes back to back whenever the endpoint
+// answers slower than the interval
+func validateIntervals(minInterval time.Duration, scraperConfigs []config.HealthcheckScraper) error {
+	for _, scraperConfig := range scraperConfigs {
+		interval := time.Duration(scraperConfig.ScrapeIntervalSeconds) * time.Second
+		if scraperConfig.ScrapeInterval != "" {
+			var err error
+			interval, err = time.ParseDuration(scraperConfig.ScrapeInterval)
+			if err != nil {
+				return fmt.Errorf("scraper %s: invalid scrape_interval %q: %w", scraperConfig.Name, scraperConfig.ScrapeInterval, err)
+			}
+			if interval <= 0 {
+				return fmt.Errorf("scraper %s: scrape_interval must be positive, got %s", scraperConfig.Name, interval)
+			}
+		}
+		if interval <= 0 {
+			continue
+		}
+
+		if interval < minInterval {
+			return fmt.Errorf("scraper %s: scrape interval %s is below the minimum of %s", scraperConfig.Name, interval, minInterval)
+		}
+		if interval < time.Second && scraperConfig.OverlapPolicy == overlapQueue {
+			return fmt.Errorf("scraper %s: overlap_policy %s cannot be combined with the sub-second scrape interval %s", scraperConfig.Name, overlapQueue, interval)
+		}
+	}
+	return nil
+}
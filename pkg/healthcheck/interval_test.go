@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrapeInterval(t *testing.T) {
+	s := &fakeScraper{healthy: []bool{true}}
+
+	assert.Equal(t, 250*time.Millisecond, scrapeInterval(s, config.HealthcheckScraper{ScrapeInterval: "250ms"}))
+	assert.Equal(t, 1500*time.Millisecond, scrapeInterval(s, config.HealthcheckScraper{ScrapeInterval: "1.5s"}))
+	assert.Equal(t, defaultScrapeInterval, scrapeInterval(s, config.HealthcheckScraper{}))
+}
+
+func TestValidateIntervals(t *testing.T) {
+	tests := []struct {
+		name    string
+		scraper config.HealthcheckScraper
+		err     string
+	}{
+		{name: "sub-second", scraper: config.HealthcheckScraper{Name: "api", ScrapeInterval: "250ms"}},
+		{name: "seconds", scraper: config.HealthcheckScraper{Name: "api", ScrapeIntervalSeconds: 10}},
+		{name: "unset", scraper: config.HealthcheckScraper{Name: "api"}},
+		{
+			name:    "invalid",
+			scraper: config.HealthcheckScraper{Name: "api", ScrapeInterval: "fast"},
+			err:     `scraper api: invalid scrape_interval "fast"`,
+		},
+		{
+			name:    "negative",
+			scraper: config.HealthcheckScraper{Name: "api", ScrapeInterval: "-1s"},
+			err:     "scraper api: scrape_interval must be positive",
+		},
+		{
+			name:    "below minimum",
+			scraper: config.HealthcheckScraper{Name: "api", ScrapeInterval: "10ms"},
+			err:     "scraper api: scrape interval 10ms is below the minimum of 100ms",
+		},
+		{
+			name:    "queued sub-second",
+			scraper: config.HealthcheckScraper{Name: "api", ScrapeInterval: "500ms", OverlapPolicy: "queue"},
+			err:     "overlap_policy queue cannot be combined with the sub-second scrape interval 500ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIntervals(defaultMinScrapeInterval, []config.HealthcheckScraper{tt.scraper})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestValidateIntervals_ConfiguredMinimum(t *testing.T) {
+	err := validateIntervals(5*time.Second, []config.HealthcheckScraper{{Name: "api", ScrapeIntervalSeconds: 2}})
+
+	assert.ErrorContains(t, err, "scraper api: scrape interval 2s is below the minimum of 5s")
+}
+
+func TestManager_Initialize_RejectsShortInterval(t *testing.T) {
+	manager := NewManager(&config.Config{
+		MinScrapeInterval: time.Second,
+		Scrapers: []config.HealthcheckScraper{{
+			Name:           "api",
+			Type:           "http",
+			ScrapeURL:      "http://localhost:8080/health",
+			ScrapeInterval: "500ms",
+		}},
+	}, logrus.New())
+
+	assert.ErrorContains(t, manager.Initialize(), "below the minimum of 1s")
+}
+
+func TestManager_SubSecondInterval(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", ScrapeInterval: "100ms"}, true)
+
+	manager.Start()
+	defer manager.Stop()
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.calls >= 3
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, manager.states[s].interval)
+}
@@ -0,0 +1,112 @@
+package healthcheck
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultLatencyEWMAAlpha is the weight of each new latency in the baseline when
+	// latency_ewma_alpha is unset
+	defaultLatencyEWMAAlpha = 0.1
+	// latencyAnomalyWarmup is how many latencies the baseline learns from before results are
+	// compared against it
+	latencyAnomalyWarmup = 10
+	// minLatencyStddevMs floors the standard deviation, so that a steady latency does not turn
+	// every slightly slower scrape into an anomaly
+	minLatencyStddevMs = 1.0
+	// severityWarning marks healthy results that deserve attention
+	severityWarning = "warning"
+)
+
+// latencyBaseline is an exponentially weighted moving average of a scraper's latency and of
+// its variance, in milliseconds
+type latencyBaseline struct {
+	samples  int
+	mean     float64
+	variance float64
+}
+
+// add folds a latency into the baseline, weighing it with alpha
+func (b *latencyBaseline) add(latencyMs, alpha float64) {
+	if b.samples == 0 {
+		b.mean = latencyMs
+	} else {
+		diff := latencyMs - b.mean
+		increment := alpha * diff
+		b.mean += increment
+		b.variance = (1 - alpha) * (b.variance + diff*increment)
+	}
+	b.samples++
+}
+
+// stddev returns the standard deviation of the baseline, at least minLatencyStddevMs
+func (b *latencyBaseline) stddev() float64 {
+	return max(math.Sqrt(b.variance), minLatencyStddevMs)
+}
+
+// validateLatencyAnomaly checks the scraper's latency_anomaly_sigma and latency_ewma_alpha
+func validateLatencyAnomaly(scraperConfig config.HealthcheckScraper) error {
+	if scraperConfig.LatencyAnomalySigma < 0 {
+		return fmt.Errorf("scraper %s: latency_anomaly_sigma %g must be positive", scraperConfig.Name, scraperConfig.LatencyAnomalySigma)
+	}
+	if scraperConfig.LatencyEWMAAlpha < 0 || scraperConfig.LatencyEWMAAlpha > 1 {
+		return fmt.Errorf("scraper %s: latency_ewma_alpha %g must be between 0 and 1", scraperConfig.Name, scraperConfig.LatencyEWMAAlpha)
+	}
+	return nil
+}
+
+// checkLatencyAnomaly compares the latency of a healthy result against the scraper's
+// baseline once it has learned from latencyAnomalyWarmup results, reporting the baseline and
+// the deviation in the details. A latency more than latency_anomaly_sigma standard deviations
+// above the baseline marks the result anomalous with warning severity, without failing it.
+// Every healthy latency is then folded into the baseline, so it follows lasting changes.
+func (m *Manager) checkLatencyAnomaly(s scraper.Scraper, state *scraperState, result *scraper.ScrapeResult, latency time.Duration) {
+	if state == nil || state.config.LatencyAnomalySigma <= 0 || !result.Healthy {
+		return
+	}
+	alpha := state.config.LatencyEWMAAlpha
+	if alpha <= 0 {
+		alpha = defaultLatencyEWMAAlpha
+	}
+	latencyMs := float64(latency.Microseconds()) / 1000
+
+	state.mu.Lock()
+	baseline := state.latencyBaseline
+	state.latencyBaseline.add(latencyMs, alpha)
+	state.mu.Unlock()
+
+	if baseline.samples < latencyAnomalyWarmup {
+		return
+	}
+
+	stddev := baseline.stddev()
+	deviation := (latencyMs - baseline.mean) / stddev
+	anomalous := deviation > state.config.LatencyAnomalySigma
+
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details["latency_baseline_ms"] = baseline.mean
+	result.Details["latency_stddev_ms"] = stddev
+	result.Details["latency_deviation"] = deviation
+	result.Details["latency_anomaly"] = anomalous
+	if !anomalous {
+		return
+	}
+	result.Details["severity"] = severityWarning
+
+	m.logger.WithFields(logrus.Fields{
+		"name":         state.config.Name,
+		"scraper_type": s.Type(),
+		"latency_ms":   latencyMs,
+		"baseline_ms":  baseline.mean,
+		"deviation":    deviation,
+	}).Warn("Healthcheck latency anomaly")
+}
@@ -0,0 +1,124 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feedLatencies checks a healthy result of every latency, in milliseconds, against the
+// scraper's baseline and returns the results
+func feedLatencies(manager *Manager, s scraper.Scraper, latencies ...int) []*scraper.ScrapeResult {
+	results := make([]*scraper.ScrapeResult, len(latencies))
+	for i, latency := range latencies {
+		results[i] = &scraper.ScrapeResult{Healthy: true}
+		manager.checkLatencyAnomaly(s, manager.states[s], results[i], time.Duration(latency)*time.Millisecond)
+	}
+	return results
+}
+
+func TestLatencyBaseline_Add(t *testing.T) {
+	var baseline latencyBaseline
+	baseline.add(100, 0.5)
+	assert.Equal(t, 100.0, baseline.mean)
+	assert.Zero(t, baseline.variance)
+
+	baseline.add(200, 0.5)
+	assert.Equal(t, 150.0, baseline.mean)
+	assert.Equal(t, 2500.0, baseline.variance)
+	assert.Equal(t, 50.0, baseline.stddev())
+	assert.Equal(t, 2, baseline.samples)
+
+	assert.Equal(t, minLatencyStddevMs, (&latencyBaseline{}).stddev())
+}
+
+func TestManager_CheckLatencyAnomaly(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", LatencyAnomalySigma: 3}, true)
+
+	// The baseline learns from a steady latency before results are compared against it
+	warmup := feedLatencies(manager, s, 100, 110, 90, 105, 95, 100, 110, 90, 105, 95)
+	for _, result := range warmup {
+		assert.Nil(t, result.Details)
+	}
+
+	results := feedLatencies(manager, s, 104, 400)
+
+	normal := results[0].Details
+	assert.Equal(t, false, normal["latency_anomaly"])
+	assert.InDelta(t, 100, normal["latency_baseline_ms"], 5)
+	assert.Positive(t, normal["latency_stddev_ms"])
+	assert.NotContains(t, normal, "severity")
+	assert.True(t, results[0].Healthy)
+
+	anomaly := results[1].Details
+	assert.Equal(t, true, anomaly["latency_anomaly"])
+	assert.Greater(t, anomaly["latency_deviation"], 3.0)
+	assert.Equal(t, "warning", anomaly["severity"])
+	assert.True(t, results[1].Healthy)
+	assert.Equal(t, 1, countLogs(hook, "Healthcheck latency anomaly"))
+}
+
+func TestManager_CheckLatencyAnomaly_GradualSlowdown(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", LatencyAnomalySigma: 3, LatencyEWMAAlpha: 0.2}, true)
+	feedLatencies(manager, s, 100, 102, 98, 101, 99, 100, 102, 98, 101, 99)
+
+	// Small steps are absorbed by the baseline, a jump beyond it is not
+	for _, result := range feedLatencies(manager, s, 101, 102, 103, 104, 105) {
+		assert.Equal(t, false, result.Details["latency_anomaly"])
+	}
+	assert.Equal(t, true, feedLatencies(manager, s, 160)[0].Details["latency_anomaly"])
+}
+
+func TestManager_CheckLatencyAnomaly_Disabled(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	results := feedLatencies(manager, s, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100, 5000)
+	assert.Nil(t, results[10].Details)
+}
+
+func TestManager_CheckLatencyAnomaly_IgnoresUnhealthyResults(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", LatencyAnomalySigma: 3}, true)
+
+	result := &scraper.ScrapeResult{Healthy: false}
+	manager.checkLatencyAnomaly(s, manager.states[s], result, 30*time.Second)
+
+	assert.Nil(t, result.Details)
+	assert.Zero(t, manager.states[s].latencyBaseline.samples)
+}
+
+func TestValidateLatencyAnomaly(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.HealthcheckScraper
+		wantErr string
+	}{
+		{name: "unset", config: config.HealthcheckScraper{Name: "api"}},
+		{name: "valid", config: config.HealthcheckScraper{Name: "api", LatencyAnomalySigma: 3, LatencyEWMAAlpha: 0.3}},
+		{name: "negative sigma", config: config.HealthcheckScraper{Name: "api", LatencyAnomalySigma: -1}, wantErr: "latency_anomaly_sigma -1 must be positive"},
+		{name: "alpha above 1", config: config.HealthcheckScraper{Name: "api", LatencyAnomalySigma: 3, LatencyEWMAAlpha: 1.5}, wantErr: "latency_ewma_alpha 1.5 must be between 0 and 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLatencyAnomaly(tt.config)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
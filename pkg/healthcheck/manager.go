@@ -3,8 +3,12 @@ package healthcheck
 import (
 	"context"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
+	"reflect"
 	"sync"
+	"text/template"
 	"time"
 
 	"healthcheck/pkg/config"
@@ -13,15 +17,161 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// defaultShutdownTimeout bounds how long StopWithTimeout waits when no timeout is configured
+	defaultShutdownTimeout = 30 * time.Second
+	// defaultDrainTimeout bounds how long a removed scraper's in-flight scrapes may run on
+	// when no drain timeout is configured
+	defaultDrainTimeout = 10 * time.Second
+	// drainCancelGracePeriod is how long to wait for scrapes to return after cancelling them
+	drainCancelGracePeriod = time.Second
+	// defaultPingTimeout bounds a ping when the scraper does not configure a ping timeout
+	defaultPingTimeout = 10 * time.Second
+	// defaultStatusTTLFactor is how many scrape intervals a result is reported for when no
+	// factor is configured
+	defaultStatusTTLFactor = 3
+)
+
 // Manager orchestrates healthcheck scrapers and handles ping functionality
 type Manager struct {
 	config     *config.Config
 	factory    *scraper.Factory
 	logger     *logrus.Logger
-	scrapers   []scraper.Scraper
 	httpClient *http.Client
-	stopChan   chan struct{}
-	wg         sync.WaitGroup
+	dispatcher *dispatcher
+	sinks      *sinkFanOut
+	// now returns the current time, replaced in tests to age results
+	now      func() time.Time
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	// scrapeQueue feeds due scrapes to the single worker in sequential mode
+	scrapeQueue chan func()
+	// pool bounds the scheduled scrapes running at once
+	pool *workerPool
+	// scrapeAll is held while a synchronous scrape of all scrapers runs
+	scrapeAll sync.Mutex
+	// groups buffers the state changes of notify groups by group and notify URL
+	groupsMu sync.Mutex
+	groups   map[string]*pendingGroup
+	// groupHealthy is the health of every scraper group that was evaluated, by group name
+	groupHealthMu sync.Mutex
+	groupHealthy  map[string]bool
+
+	// mu guards the running scrapers, which change on reload
+	mu       sync.RWMutex
+	started  bool
+	scrapers []scraper.Scraper
+	states   map[scraper.Scraper]*scraperState
+}
+
+// scraperState tracks per-scraper state between scrapes
+type scraperState struct {
+	config config.HealthcheckScraper
+	// notifyTemplate renders the scraper's notifications, nil for the default JSON event
+	notifyTemplate *template.Template
+	// activeHours is the schedule the scraper runs on, nil when it is always active
+	activeHours *activeWindow
+
+	// ctx is the parent of the scraper's scrapes and is cancelled once the scraper is removed
+	ctx    context.Context
+	cancel context.CancelFunc
+	// stop ends the scraper's loop, done is closed once the loop has exited
+	stop    chan struct{}
+	done    chan struct{}
+	scrapes sync.WaitGroup
+	// queued is set while a scrape waits for the worker in sequential mode
+	queued bool
+	// overlapPolicy decides what happens to a scrape due while running is set, rerun is
+	// set when a scrape was queued behind the running one
+	overlapPolicy string
+	// interval is how often the scraper runs, set when its loop starts
+	interval time.Duration
+
+	mu           sync.Mutex
+	running      bool
+	rerun        bool
+	lastDetails  map[string]interface{}
+	healthyCount int
+	// lastFailure is the message of the current run of identical failures, of which
+	// suppressedFailures were not logged since lastFailureLog
+	lastFailure        string
+	suppressedFailures int
+	lastFailureLog     time.Time
+	// consecutivePings counts the healthy results since the last failure, for the
+	// max_consecutive_pings cap
+	consecutivePings int
+	// healthy is the outcome of the latest scrape, pending is set while the scraper waits
+	// for its dependency to become healthy
+	healthy bool
+	pending bool
+	// inactive is set while the scraper is outside its active hours
+	inactive bool
+	// skipped is set while the scraper's gate is closed. lastSkip is when the latest scrape
+	// was skipped and skipMessage the reason, tracked apart from lastScrape so that skips
+	// neither refresh nor replace the latest result.
+	skipped     bool
+	lastSkip    time.Time
+	skipMessage string
+	// lastScrape is when the latest result was recorded and lastMessage describes it, both
+	// reported by the status endpoint
+	lastScrape  time.Time
+	lastMessage string
+	// nextRun is when the scraper's loop is next due to schedule a scrape, reported by the
+	// schedule endpoint
+	nextRun time.Time
+	// latencyBaseline learns the latency of healthy results for latency_anomaly_sigma
+	latencyBaseline latencyBaseline
+	// flaps counts the health transitions within the flap window
+	flaps flapCounter
+	// notifiedHealthy is the health state changes are compared against, known once the first
+	// result was seen. A change is held since changeSince until it outlasts the flap
+	// dampening window.
+	notifiedHealthy *bool
+	changeSince     time.Time
+	// history holds the latest results served by the history endpoint, created with the first
+	// result
+	history *resultHistory
+}
+
+// newScraperState creates the state of a scraper with the given configuration
+func newScraperState(scraperConfig config.HealthcheckScraper) *scraperState {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The template and active hours were validated along with the rest of the configuration
+	notifyTemplate, _ := parseNotifyTemplate(scraperConfig)
+	activeHours, _ := parseActiveHours(scraperConfig)
+	overlapPolicy, _ := parseOverlapPolicy(scraperConfig)
+	return &scraperState{
+		config:         scraperConfig,
+		notifyTemplate: notifyTemplate,
+		activeHours:    activeHours,
+		overlapPolicy:  overlapPolicy,
+		ctx:            ctx,
+		cancel:         cancel,
+		stop:           make(chan struct{}),
+	}
+}
+
+// validateScraperConfigs checks the parts of the scraper configurations the factory does
+// not, so that mistakes surface when the configuration is loaded rather than at alert time
+func validateScraperConfigs(scraperConfigs []config.HealthcheckScraper) error {
+	if err := config.ValidateScrapers(scraperConfigs); err != nil {
+		return err
+	}
+	for _, scraperConfig := range scraperConfigs {
+		if _, err := parseNotifyTemplate(scraperConfig); err != nil {
+			return err
+		}
+		if _, err := parseActiveHours(scraperConfig); err != nil {
+			return err
+		}
+		if _, err := parseOverlapPolicy(scraperConfig); err != nil {
+			return err
+		}
+		if err := validateLatencyAnomaly(scraperConfig); err != nil {
+			return err
+		}
+	}
+	return validateDependencies(scraperConfigs)
 }
 
 // NewManager creates a new healthcheck manager
@@ -30,17 +180,59 @@ func NewManager(cfg *config.Config, logger *logrus.Logger) *Manager {
 		config:  cfg,
 		factory: scraper.NewFactory(logger),
 		logger:  logger,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		stopChan: make(chan struct{}),
+		states:  make(map[scraper.Scraper]*scraperState),
+		groups:  make(map[string]*pendingGroup),
+		// Groups start unhealthy, so their first healthy evaluation pings
+		groupHealthy: make(map[string]bool),
+		// Requests are bounded by their context only, so a single deadline decides when they time out
+		httpClient:  &http.Client{},
+		dispatcher:  newDispatcher(cfg.NotificationWorkers, cfg.NotificationQueueSize, logger),
+		sinks:       newSinkFanOut(logger),
+		now:         time.Now,
+		stopChan:    make(chan struct{}),
+		scrapeQueue: make(chan func()),
+		pool:        newWorkerPool(scrapeLimit(cfg)),
 	}
 }
 
+// scrapeLimit returns how many scheduled scrapes may run at once, 0 for no limit
+func scrapeLimit(cfg *config.Config) int {
+	if cfg.Sequential {
+		return 1
+	}
+	return max(cfg.MaxConcurrentScrapes, 0)
+}
+
+// AddSink adds a sink receiving the result of every scrape, which must be added before Start.
+// Results queued for the sinks are published before Stop returns.
+func (m *Manager) AddSink(sink ResultSink) {
+	m.sinks.add(sink)
+}
+
+// AddBatchSink adds a sink receiving the results of scrapes in batches of up to batchSize, at
+// least every flushInterval, which must be added before Start. The last batch is published
+// before Stop returns.
+func (m *Manager) AddBatchSink(sink BatchResultSink, batchSize int, flushInterval time.Duration) {
+	m.sinks.addBatch(sink, batchSize, flushInterval)
+}
+
 // Initialize sets up all scrapers based on configuration
 func (m *Manager) Initialize() error {
 	m.logger.Info("Initializing healthcheck manager")
 
+	if err := validateScraperConfigs(m.config.Scrapers); err != nil {
+		return err
+	}
+	if err := config.ValidateGroups(m.config.Groups, m.config.Scrapers); err != nil {
+		return err
+	}
+	if err := validateHooks(m.config.EnableScrapeHooks, m.config.Scrapers); err != nil {
+		return err
+	}
+	if err := validateIntervals(m.minScrapeInterval(), m.config.Scrapers); err != nil {
+		return err
+	}
+
 	for _, scraperConfig := range m.config.Scrapers {
 		scraper, err := m.factory.CreateScraper(scraperConfig)
 		if err != nil {
@@ -48,7 +240,9 @@ func (m *Manager) Initialize() error {
 		}
 
 		m.scrapers = append(m.scrapers, scraper)
+		m.states[scraper] = newScraperState(scraperConfig)
 		m.logger.WithFields(logrus.Fields{
+			"name":       scraperConfig.Name,
 			"type":       scraper.Type(),
 			"scrape_url": scraperConfig.ScrapeURL,
 			"ping_url":   scraperConfig.PingURL,
@@ -63,10 +257,24 @@ func (m *Manager) Initialize() error {
 func (m *Manager) Start() {
 	m.logger.Info("Starting healthcheck manager")
 
+	// Start notification workers before any scrape can queue a ping
+	m.dispatcher.start()
+
+	if m.config.Sequential {
+		m.wg.Add(1)
+		go m.sequentialWorker()
+	}
+
 	// Start healthcheck loop
 	m.wg.Add(1)
 	go m.healthcheckLoop()
 
+	// Poll for discovered scrapers when a discovery URL is configured
+	if m.config.DiscoveryURL != "" {
+		m.wg.Add(1)
+		go m.discoveryLoop()
+	}
+
 	m.logger.Info("Healthcheck manager started")
 }
 
@@ -75,85 +283,603 @@ func (m *Manager) Stop() {
 	m.logger.Info("Stopping healthcheck manager")
 	close(m.stopChan)
 	m.wg.Wait()
+	m.flushNotifyGroups()
+	m.dispatcher.stop()
+	m.sinks.stop()
 	m.logger.Info("Healthcheck manager stopped")
 }
 
+// StopWithTimeout stops the manager like Stop, but gives up waiting once the timeout
+// elapses so a hung scrape or notification cannot block shutdown forever
+func (m *Manager) StopWithTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("healthcheck manager did not stop within %s", timeout)
+	}
+}
+
 // healthcheckLoop runs the main healthcheck loop with individual intervals
 func (m *Manager) healthcheckLoop() {
 	defer m.wg.Done()
 
-	// Create individual tickers for each scraper
-	scraperTickers := make(map[scraper.Scraper]*time.Ticker)
-	defer func() {
-		for _, ticker := range scraperTickers {
-			ticker.Stop()
+	// Start an individual loop for each scraper
+	m.mu.Lock()
+	m.started = true
+	for _, s := range m.scrapers {
+		m.startScraper(s, m.states[s])
+	}
+	m.mu.Unlock()
+
+	// Wait for stop signal
+	<-m.stopChan
+}
+
+// startScraper starts the loop of a scraper, spreading its initial healthcheck
+func (m *Manager) startScraper(s scraper.Scraper, state *scraperState) {
+	interval := scrapeInterval(s, state.config)
+	state.interval = interval
+	state.done = make(chan struct{})
+	go m.scraperLoop(s, state, interval, initialDelay(m.config.InitialScrapeSpread, interval))
+}
+
+// scraperLoop runs the initial healthcheck for a scraper after the given delay and then
+// keeps running it on its own interval until the manager stops or the scraper is removed.
+// Scrapes run in the background so the ticker keeps time, and a tick coming due while the
+// previous scrape still runs is left to the overlap policy.
+func (m *Manager) scraperLoop(s scraper.Scraper, state *scraperState, interval, delay time.Duration) {
+	defer close(state.done)
+
+	m.setNextRun(state, m.now().Add(delay))
+	if delay > 0 {
+		m.logger.WithFields(logrus.Fields{
+			"scraper_type": s.Type(),
+			"delay":        delay.String(),
+		}).Debug("Delaying initial healthcheck")
+
+		select {
+		case <-time.After(delay):
+		case <-m.stopChan:
+			return
+		case <-state.stop:
+			return
+		}
+	}
+
+	// Run initial healthcheck for this scraper
+	m.scheduleScrape(s, state)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	m.setNextRun(state, m.now().Add(interval))
+
+	for {
+		select {
+		case <-ticker.C:
+			m.setNextRun(state, m.now().Add(interval))
+			m.scheduleScrape(s, state)
+		case <-m.stopChan:
+			return
+		case <-state.stop:
+			return
+		}
+	}
+}
+
+// scheduleScrape runs a due scrape of the scraper in the background, unless the previous
+// scrape is still running and the overlap policy skips or queues it. In sequential mode the
+// scrape is queued for the single worker instead, and a scraper that is still waiting for
+// the worker is not queued again.
+func (m *Manager) scheduleScrape(s scraper.Scraper, state *scraperState) {
+	if !m.config.Sequential {
+		if !m.beginScrape(s, state) {
+			return
+		}
+		state.scrapes.Add(1)
+		go func() {
+			defer state.scrapes.Done()
+			m.runScrapes(s, state)
+		}()
+		return
+	}
+
+	state.mu.Lock()
+	if state.queued {
+		state.mu.Unlock()
+		m.logger.WithField("scraper_type", s.Type()).Debug("Skipping scrape still waiting for the worker")
+		return
+	}
+	state.queued = true
+	state.mu.Unlock()
+
+	state.scrapes.Add(1)
+	job := func() {
+		defer state.scrapes.Done()
+
+		state.mu.Lock()
+		state.queued = false
+		state.mu.Unlock()
+
+		// The single worker runs one job at a time, so a slot is always free
+		if !m.pool.acquire(m.stopChan, state.stop) {
+			return
+		}
+		defer m.pool.release()
+		m.runSingleHealthcheck(s)
+	}
+
+	go func() {
+		select {
+		case m.scrapeQueue <- job:
+			return
+		default:
+		}
+
+		// The worker is busy with another scrape
+		m.pool.wait()
+		defer m.pool.stopWaiting()
+
+		select {
+		case m.scrapeQueue <- job:
+		case <-m.stopChan:
+			state.scrapes.Done()
+		case <-state.stop:
+			state.scrapes.Done()
 		}
 	}()
+}
+
+// runScrapes runs the scrape begun with beginScrape once a worker is free, followed by any
+// scrape the overlap policy queued meanwhile, and returns the outcome of the first. It
+// returns false when the manager stopped or the scraper was removed before it ran.
+func (m *Manager) runScrapes(s scraper.Scraper, state *scraperState) (CheckResult, bool) {
+	var first CheckResult
+	ran := false
+	for {
+		if !m.pool.acquire(m.stopChan, state.stop) {
+			m.finishScrape(state)
+			return first, ran
+		}
+		result := m.runSingleHealthcheck(s)
+		m.pool.release()
+		if !ran {
+			first, ran = result, true
+		}
+		if !m.finishScrape(state) {
+			return first, ran
+		}
+	}
+}
+
+// sequentialWorker runs queued scrapes one at a time until the manager stops
+func (m *Manager) sequentialWorker() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case job := <-m.scrapeQueue:
+			job()
+		case <-m.stopChan:
+			return
+		}
+	}
+}
+
+// Reload reconciles the running scrapers with the given configurations. Scrapers whose
+// configuration is unchanged keep running, new ones are created and started and removed
+// ones are drained and closed. If any new scraper cannot be created, nothing changes.
+func (m *Manager) Reload(scraperConfigs []config.HealthcheckScraper) error {
+	if err := validateScraperConfigs(scraperConfigs); err != nil {
+		return err
+	}
+	if err := config.ValidateGroups(m.config.Groups, scraperConfigs); err != nil {
+		return err
+	}
+	if err := validateHooks(m.config.EnableScrapeHooks, scraperConfigs); err != nil {
+		return err
+	}
+	if err := validateIntervals(m.minScrapeInterval(), scraperConfigs); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+
+	unmatched := make(map[scraper.Scraper]bool, len(m.scrapers))
+	for _, s := range m.scrapers {
+		unmatched[s] = true
+	}
+
+	scrapers := make([]scraper.Scraper, 0, len(scraperConfigs))
+	states := make(map[scraper.Scraper]*scraperState, len(scraperConfigs))
+	var added []scraper.Scraper
+
+	for _, scraperConfig := range scraperConfigs {
+		if s := m.findUnchanged(scraperConfig, unmatched); s != nil {
+			delete(unmatched, s)
+			scrapers = append(scrapers, s)
+			states[s] = m.states[s]
+			continue
+		}
+
+		s, err := m.factory.CreateScraper(scraperConfig)
+		if err != nil {
+			m.mu.Unlock()
+			return fmt.Errorf("failed to create scraper %s: %w", scraperConfig.Type, err)
+		}
+		scrapers = append(scrapers, s)
+		states[s] = newScraperState(scraperConfig)
+		added = append(added, s)
+	}
+
+	removed := make(map[scraper.Scraper]*scraperState, len(unmatched))
+	for s := range unmatched {
+		removed[s] = m.states[s]
+	}
+
+	m.scrapers = scrapers
+	m.states = states
+	if m.started {
+		for _, s := range added {
+			m.startScraper(s, states[s])
+		}
+	}
+	m.mu.Unlock()
+
+	m.logger.WithFields(logrus.Fields{
+		"added":     len(added),
+		"removed":   len(removed),
+		"unchanged": len(scrapers) - len(added),
+	}).Info("Reloaded scrapers")
 
-	// Start individual tickers for each scraper
+	// Drain removed scrapers concurrently so one slow scrape does not hold up the others
+	var wg sync.WaitGroup
+	for s, state := range removed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.drainScraper(s, state)
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// findUnchanged returns the running scraper with exactly the given configuration, if any
+func (m *Manager) findUnchanged(scraperConfig config.HealthcheckScraper, candidates map[scraper.Scraper]bool) scraper.Scraper {
 	for _, s := range m.scrapers {
-		interval := s.GetScrapeInterval()
-		if interval <= 0 {
-			interval = 30 // Default to 30 seconds if not specified
+		if candidates[s] && reflect.DeepEqual(m.states[s].config, scraperConfig) {
+			return s
 		}
+	}
+	return nil
+}
 
-		ticker := time.NewTicker(time.Duration(interval) * time.Second)
-		scraperTickers[s] = ticker
+// drainScraper stops a removed scraper's loop, gives its in-flight scrapes up to the drain
+// timeout to finish before cancelling them and finally closes the scraper's connections
+func (m *Manager) drainScraper(s scraper.Scraper, state *scraperState) {
+	logger := m.logger.WithFields(logrus.Fields{
+		"name":         state.config.Name,
+		"scraper_type": s.Type(),
+	})
 
-		// Run initial healthcheck for this scraper
-		go m.runSingleHealthcheck(s)
+	close(state.stop)
 
-		// Start the ticker loop for this scraper
-		go func(scraper scraper.Scraper, ticker *time.Ticker) {
-			for {
-				select {
-				case <-ticker.C:
-					m.runSingleHealthcheck(scraper)
-				case <-m.stopChan: // Single stop chan all scrapers goroutines?
-					return
-				}
-			}
-		}(s, ticker)
+	drained := make(chan struct{})
+	go func() {
+		if state.done != nil {
+			<-state.done
+		}
+		state.scrapes.Wait()
+		close(drained)
+	}()
+
+	timeout := m.config.DrainTimeout
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
 	}
 
-	// Wait for stop signal
-	<-m.stopChan
+	select {
+	case <-drained:
+	case <-time.After(timeout):
+		logger.WithField("drain_timeout", timeout.String()).Warn("In-flight scrapes did not finish in time, cancelling them")
+		state.cancel()
+
+		select {
+		case <-drained:
+		case <-time.After(drainCancelGracePeriod):
+			logger.Error("In-flight scrapes did not return after cancellation")
+		}
+	}
+	state.cancel()
+
+	if closer, ok := s.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.WithError(err).Warn("Failed to close scraper")
+		}
+	}
+	m.forgetFlaps(state.config.Name)
+
+	logger.Info("Removed scraper")
+}
+
+// initialDelay picks a random delay for a scraper's initial healthcheck within the
+// configured spread, capped at the scraper's interval so that scrapers with short
+// intervals are spread over a proportionally shorter window
+func initialDelay(spread, interval time.Duration) time.Duration {
+	window := min(spread, interval)
+	if window <= 0 {
+		return 0
+	}
+	return rand.N(window)
 }
 
-// runSingleHealthcheck runs a healthcheck for a single scraper
-func (m *Manager) runSingleHealthcheck(s scraper.Scraper) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// runSingleHealthcheck runs a healthcheck for a single scraper and returns its outcome, which
+// is skipped when the scraper is outside its active hours, waiting for its dependency or
+// behind a closed gate
+func (m *Manager) runSingleHealthcheck(s scraper.Scraper) CheckResult {
+	parent := context.Background()
+	name := s.Type()
+	state := m.state(s)
+	if state != nil {
+		name = state.config.Name
+		if m.outsideActiveHours(s, state) {
+			return CheckResult{Name: name, Type: s.Type(), Skipped: true, Message: "Scrape skipped outside active hours"}
+		}
+		if m.dependencyPending(s, state) {
+			message := fmt.Sprintf("Scrape skipped until dependency %s is healthy", state.config.DependsOn)
+			return CheckResult{Name: name, Type: s.Type(), Skipped: true, Message: message}
+		}
+		parent = state.ctx
+	}
+
+	ctx, cancel := context.WithTimeout(parent, 30*time.Second)
 	defer cancel()
 
-	result, err := s.Scrape(ctx)
+	var gate gateDecision
+	if state != nil && state.config.GateURL != "" {
+		if gate = m.checkGate(ctx, state.config.GateURL); !gate.open {
+			message := m.skipScrape(name, s, state, gate)
+			return CheckResult{Name: name, Type: s.Type(), Skipped: true, Message: message, Details: gate.details}
+		}
+	}
+
+	start := time.Now()
+	result, err := m.scrapeWithHooks(ctx, s, state)
+	latency := time.Since(start)
+	checkResult := CheckResult{Name: name, Type: s.Type(), LatencyMs: latency.Milliseconds()}
+	if gate.open {
+		m.openGate(s, state, gate, result)
+	}
+	if err == nil {
+		m.checkLatencyAnomaly(s, state, result, latency)
+	}
+	m.publishResult(name, s, result, err, latency)
 	if err != nil {
+		m.allowPing(s, state, false)
+		m.recordHealth(s, false, err.Error())
+		m.evaluateGroup(state)
+		m.recordHistory(state, false, err.Error(), latency)
+		m.checkStateChange(s, false, err.Error(), nil)
+		checkResult.Message = err.Error()
+		if !m.shouldLogFailure(s, err.Error()) {
+			return checkResult
+		}
 		m.logger.WithFields(logrus.Fields{
 			"scraper_type": s.Type(),
 			"error":        err.Error(),
 		}).Error("Healthcheck failed with error")
+		return checkResult
+	}
+	checkResult.Healthy = result.Healthy
+	checkResult.Message = result.Message
+	checkResult.Details = result.Details
+
+	m.recordHealth(s, result.Healthy, result.Message)
+	m.evaluateGroup(state)
+	m.recordHistory(state, result.Healthy, result.Message, latency)
+
+	if m.shouldLogResult(s, result) {
+		m.logger.WithFields(logrus.Fields{
+			"scraper_type": s.Type(),
+			"healthy":      result.Healthy,
+			"message":      result.Message,
+			"timestamp":    result.Timestamp,
+		}).Info("Healthcheck completed")
+	}
+
+	m.checkDetailChanges(s, result)
+	m.checkStateChange(s, result.Healthy, result.Message, result.Details)
+
+	// If healthy, queue a ping to the success URL
+	if m.allowPing(s, state, result.Healthy) && s.GetPingURL() != "" {
+		pingURL := s.GetPingURL()
+		pingTimeout := m.pingTimeout(s)
+		m.dispatcher.dispatch(notification{
+			scraperType: s.Type(),
+			url:         pingURL,
+			deliver: func() {
+				m.pingSuccessURL(pingURL, pingTimeout)
+			},
+		})
+	}
+	return checkResult
+}
+
+// pingTimeout returns the scraper's configured ping timeout or the default
+func (m *Manager) pingTimeout(s scraper.Scraper) time.Duration {
+	if state := m.state(s); state != nil && state.config.PingTimeoutSeconds > 0 {
+		return time.Duration(state.config.PingTimeoutSeconds) * time.Second
+	}
+	return defaultPingTimeout
+}
+
+// state returns the state of a scraper, or nil if it is not managed
+func (m *Manager) state(s scraper.Scraper) *scraperState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.states[s]
+}
+
+// shouldLogResult applies the scraper's log sampling, logging only every Nth healthy
+// result, and collapses identical consecutive failures when configured
+func (m *Manager) shouldLogResult(s scraper.Scraper, result *scraper.ScrapeResult) bool {
+	if !result.Healthy {
+		return m.shouldLogFailure(s, result.Message)
+	}
+
+	state := m.state(s)
+	if state == nil {
+		return true
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	// Recovery ends the run of identical failures
+	state.lastFailure = ""
+	state.suppressedFailures = 0
+
+	if state.config.LogSampling <= 1 {
+		return true
+	}
+
+	state.healthyCount++
+	return (state.healthyCount-1)%state.config.LogSampling == 0
+}
+
+// shouldLogFailure reports whether a failure should be logged. With collapsing enabled only
+// the first of identical consecutive failures is logged, followed by a periodic summary of
+// how many were suppressed.
+func (m *Manager) shouldLogFailure(s scraper.Scraper, message string) bool {
+	state := m.state(s)
+	if state == nil {
+		return true
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	// Restart sampling after a failure so the first healthy result after recovery is logged
+	state.healthyCount = 0
+
+	if state.config.CollapseFailureLogsSeconds <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if message != state.lastFailure {
+		state.lastFailure = message
+		state.suppressedFailures = 0
+		state.lastFailureLog = now
+		return true
+	}
+
+	state.suppressedFailures++
+	if now.Sub(state.lastFailureLog) >= time.Duration(state.config.CollapseFailureLogsSeconds)*time.Second {
+		m.logger.WithFields(logrus.Fields{
+			"scraper_type": s.Type(),
+			"message":      message,
+			"times":        state.suppressedFailures,
+		}).Warn("Healthcheck still failing")
+		state.suppressedFailures = 0
+		state.lastFailureLog = now
+	}
+
+	return false
+}
+
+// checkDetailChanges notifies when watched detail keys changed since the previous scrape.
+// Unhealthy results are compared like healthy ones. Scrapes failing with an error have no
+// details and are not compared, so a change made meanwhile is reported by the next scrape
+// returning a result, against the details from before the errors.
+func (m *Manager) checkDetailChanges(s scraper.Scraper, result *scraper.ScrapeResult) {
+	state := m.state(s)
+	if state == nil || len(state.config.NotifyOnDetailChange) == 0 {
+		return
+	}
+
+	state.mu.Lock()
+	previous := state.lastDetails
+	state.lastDetails = result.Details
+	state.mu.Unlock()
+
+	// Nothing to compare against on the first scrape
+	if previous == nil {
+		return
+	}
+
+	changes := detailChanges(state.config.NotifyOnDetailChange, previous, result.Details)
+	if len(changes) == 0 {
 		return
 	}
 
 	m.logger.WithFields(logrus.Fields{
 		"scraper_type": s.Type(),
-		"healthy":      result.Healthy,
-		"message":      result.Message,
-		"timestamp":    result.Timestamp,
-	}).Info("Healthcheck completed")
+		"changes":      changes,
+	}).Info("Scraper details changed")
+
+	if state.config.NotifyURL == "" {
+		return
+	}
 
-	// If healthy, ping the success URL
-	if result.Healthy {
-		m.pingSuccessURL(s.GetPingURL())
+	m.notify(s, state, NotificationEvent{
+		Event:       "detail_change",
+		Name:        state.config.Name,
+		ScraperType: s.Type(),
+		Healthy:     result.Healthy,
+		Message:     result.Message,
+		Timestamp:   result.Timestamp,
+		Changes:     changes,
+	}, result.Details)
+}
+
+// notify renders the event with the scraper's notify template and queues it for delivery
+// to the scraper's notify URL
+func (m *Manager) notify(s scraper.Scraper, state *scraperState, event NotificationEvent, details map[string]interface{}) {
+	notifyURL := state.config.NotifyURL
+	body, err := notificationBody(state.notifyTemplate, event, details)
+	if err != nil {
+		m.logger.WithFields(logrus.Fields{
+			"scraper_type": s.Type(),
+			"error":        err.Error(),
+		}).Error("Failed to render notification")
+		return
 	}
+
+	contentType := state.config.NotifyContentType
+	if contentType == "" {
+		contentType = defaultNotifyContentType
+	}
+	policy := newNotifyRetryPolicy(state.config)
+	m.dispatcher.dispatch(notification{
+		scraperType: s.Type(),
+		url:         notifyURL,
+		deliver: func() {
+			m.sendNotification(notifyURL, contentType, event, body, policy)
+		},
+	})
 }
 
-// pingSuccessURL sends a GET request to the success URL
-func (m *Manager) pingSuccessURL(url string) {
+// pingSuccessURL sends a GET request to the success URL. The timeout is the only deadline
+// of the ping and covers connecting, sending the request and receiving the response headers.
+func (m *Manager) pingSuccessURL(url string, timeout time.Duration) {
 	if url == "" {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -168,8 +894,9 @@ func (m *Manager) pingSuccessURL(url string) {
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
 		m.logger.WithFields(logrus.Fields{
-			"url":   url,
-			"error": err.Error(),
+			"url":          url,
+			"error":        err.Error(),
+			"ping_timeout": timeout.String(),
 		}).Error("Failed to ping success URL")
 		return
 	}
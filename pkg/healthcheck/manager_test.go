@@ -1,18 +1,92 @@
 package healthcheck
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
 
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// fakeScraper is a scraper returning a scripted sequence of health results
+type fakeScraper struct {
+	mu       sync.Mutex
+	healthy  []bool
+	messages []string
+	calls    int
+	pingURL  string
+	details  map[string]interface{}
+}
+
+func (f *fakeScraper) Type() string {
+	return "fake"
+}
+
+func (f *fakeScraper) Scrape(ctx context.Context) (*scraper.ScrapeResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	healthy := f.healthy[f.calls%len(f.healthy)]
+	message := "fake result"
+	if len(f.messages) > 0 {
+		message = f.messages[f.calls%len(f.messages)]
+	}
+	f.calls++
+
+	details := make(map[string]interface{}, len(f.details))
+	for key, value := range f.details {
+		details[key] = value
+	}
+
+	return &scraper.ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+func (f *fakeScraper) GetPingURL() string {
+	return f.pingURL
+}
+
+func (f *fakeScraper) GetScrapeInterval() int {
+	return 30
+}
+
+// addFakeScraper registers a fake scraper with the given configuration on the manager
+func addFakeScraper(m *Manager, scraperConfig config.HealthcheckScraper, healthy ...bool) *fakeScraper {
+	s := &fakeScraper{healthy: healthy}
+	m.scrapers = append(m.scrapers, s)
+	m.states[s] = newScraperState(scraperConfig)
+	return s
+}
+
+// countCompletedLogs counts the "Healthcheck completed" entries captured by the hook
+func countCompletedLogs(hook *test.Hook) (healthy, unhealthy int) {
+	for _, entry := range hook.AllEntries() {
+		if entry.Message != "Healthcheck completed" {
+			continue
+		}
+		if entry.Data["healthy"] == true {
+			healthy++
+		} else {
+			unhealthy++
+		}
+	}
+	return healthy, unhealthy
+}
+
 func TestNewManager(t *testing.T) {
 	cfg := &config.Config{}
 	logger := logrus.New()
@@ -23,6 +97,7 @@ func TestNewManager(t *testing.T) {
 	assert.Equal(t, logger, manager.logger)
 	assert.NotNil(t, manager.factory)
 	assert.NotNil(t, manager.httpClient)
+	assert.NotNil(t, manager.dispatcher)
 	assert.NotNil(t, manager.stopChan)
 }
 
@@ -118,7 +193,7 @@ func TestManager_PingSuccessURL(t *testing.T) {
 	manager := NewManager(cfg, logger)
 
 	// Test ping with valid URL
-	manager.pingSuccessURL(server.URL)
+	manager.pingSuccessURL(server.URL, defaultPingTimeout)
 
 	// Give the HTTP client time to make the request
 	time.Sleep(100 * time.Millisecond)
@@ -132,7 +207,7 @@ func TestManager_PingSuccessURL_EmptyURL(t *testing.T) {
 	manager := NewManager(cfg, logger)
 
 	// Test ping with empty URL (should not panic)
-	manager.pingSuccessURL("")
+	manager.pingSuccessURL("", defaultPingTimeout)
 	// If we reach here without panic, the test passes
 }
 
@@ -142,6 +217,533 @@ func TestManager_PingSuccessURL_InvalidURL(t *testing.T) {
 	manager := NewManager(cfg, logger)
 
 	// Test ping with invalid URL (should not panic)
-	manager.pingSuccessURL("http://invalid-url-that-does-not-exist:99999")
+	manager.pingSuccessURL("http://invalid-url-that-does-not-exist:99999", defaultPingTimeout)
 	// If we reach here without panic, the test passes
 }
+
+func TestManager_PingSuccessURL_SlowEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+
+	start := time.Now()
+	manager.pingSuccessURL(server.URL, 50*time.Millisecond)
+
+	// The ping timeout alone decides when the ping gives up
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	require.Equal(t, 1, countLogs(hook, "Failed to ping success URL"))
+	assert.Contains(t, hook.LastEntry().Data["error"], "context deadline exceeded")
+	assert.Equal(t, "50ms", hook.LastEntry().Data["ping_timeout"])
+
+	manager.pingSuccessURL(server.URL, time.Second)
+
+	assert.Equal(t, 1, countLogs(hook, "Successfully pinged success URL"))
+}
+
+func TestManager_PingTimeout(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	configured := addFakeScraper(manager, config.HealthcheckScraper{PingTimeoutSeconds: 3}, true)
+	unconfigured := addFakeScraper(manager, config.HealthcheckScraper{}, true)
+
+	assert.Equal(t, 3*time.Second, manager.pingTimeout(configured))
+	assert.Equal(t, defaultPingTimeout, manager.pingTimeout(unconfigured))
+}
+
+func TestManager_StopWithTimeout_Graceful(t *testing.T) {
+	cfg := &config.Config{}
+	logger := logrus.New()
+	manager := NewManager(cfg, logger)
+
+	require.NoError(t, manager.Initialize())
+	manager.Start()
+
+	err := manager.StopWithTimeout(time.Second)
+
+	assert.NoError(t, err)
+}
+
+func TestManager_StopWithTimeout_ForceExit(t *testing.T) {
+	cfg := &config.Config{}
+	logger := logrus.New()
+	manager := NewManager(cfg, logger)
+
+	require.NoError(t, manager.Initialize())
+	manager.Start()
+
+	// Queue a notification that hangs until released so the graceful stop cannot finish
+	release := make(chan struct{})
+	defer close(release)
+	manager.dispatcher.dispatch(notification{
+		deliver: func() {
+			<-release
+		},
+	})
+
+	err := manager.StopWithTimeout(50 * time.Millisecond)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "did not stop within 50ms")
+}
+
+func TestInitialDelay_Disabled(t *testing.T) {
+	assert.Equal(t, time.Duration(0), initialDelay(0, 30*time.Second))
+}
+
+func TestInitialDelay_WithinSpread(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := initialDelay(10*time.Second, 30*time.Second)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, 10*time.Second)
+	}
+}
+
+func TestInitialDelay_CappedAtInterval(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := initialDelay(time.Minute, 5*time.Second)
+		assert.Less(t, delay, 5*time.Second)
+	}
+}
+
+func TestManager_Start_InitialScrapeSpread(t *testing.T) {
+	scraped := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case scraped <- struct{}{}:
+		default:
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{
+				Type:                  "http",
+				ScrapeURL:             server.URL,
+				ScrapeIntervalSeconds: 120,
+			},
+		},
+		InitialScrapeSpread: 200 * time.Millisecond,
+	}
+	manager := NewManager(cfg, logrus.New())
+	require.NoError(t, manager.Initialize())
+
+	manager.Start()
+	defer manager.Stop()
+
+	// The initial scrape still happens, just within the spread window
+	select {
+	case <-scraped:
+	case <-time.After(time.Second):
+		t.Fatal("Initial scrape should have run within the spread window")
+	}
+}
+
+func TestManager_Reload_InitialScrapeSpread(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	existing := config.HealthcheckScraper{Name: "existing", Type: "http", ScrapeURL: server.URL + "/existing", ScrapeIntervalSeconds: 120}
+	added := config.HealthcheckScraper{Name: "added", Type: "http", ScrapeURL: server.URL + "/added", ScrapeIntervalSeconds: 120}
+
+	manager := NewManager(&config.Config{
+		Scrapers:            []config.HealthcheckScraper{existing},
+		InitialScrapeSpread: time.Hour,
+	}, logrus.New())
+	var clock atomic.Int64
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock.Store(start.UnixNano())
+	manager.now = func() time.Time { return time.Unix(0, clock.Load()).UTC() }
+	require.NoError(t, manager.Initialize())
+	manager.Start()
+	defer manager.Stop()
+
+	nextRun := func(name string) *time.Time {
+		for _, schedule := range manager.Schedule() {
+			if schedule.Name == name {
+				return schedule.NextRun
+			}
+		}
+		return nil
+	}
+	require.Eventually(t, func() bool { return nextRun("existing") != nil }, time.Second, time.Millisecond)
+	existingNext := *nextRun("existing")
+
+	// An hour later, a restarted scraper would be scheduled after the reload
+	reloaded := start.Add(time.Hour)
+	clock.Store(reloaded.UnixNano())
+	require.NoError(t, manager.Reload([]config.HealthcheckScraper{existing, added}))
+
+	require.Eventually(t, func() bool { return nextRun("added") != nil }, time.Second, time.Millisecond)
+	addedNext := *nextRun("added")
+	// The spread is capped at the two minute interval
+	assert.True(t, addedNext.After(reloaded), "added scraper should be delayed within the spread, got %s", addedNext)
+	assert.True(t, addedNext.Before(reloaded.Add(2*time.Minute)), "added scraper should run within its interval, got %s", addedNext)
+	assert.Equal(t, existingNext, *nextRun("existing"), "unchanged scraper should keep its schedule")
+}
+
+func TestManager_LogSampling_HealthySampled(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{LogSampling: 5}, true)
+
+	for i := 0; i < 12; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	// Healthy results 1, 6 and 11 are logged
+	healthy, unhealthy := countCompletedLogs(hook)
+	assert.Equal(t, 3, healthy)
+	assert.Equal(t, 0, unhealthy)
+}
+
+func TestManager_LogSampling_UnhealthyAlwaysLogged(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{LogSampling: 5}, true, true, false)
+
+	for i := 0; i < 9; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	// Every failure is logged, as is the first healthy result after each failure
+	healthy, unhealthy := countCompletedLogs(hook)
+	assert.Equal(t, 3, healthy)
+	assert.Equal(t, 3, unhealthy)
+}
+
+func TestManager_LogSampling_Disabled(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{}, true)
+
+	for i := 0; i < 4; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	healthy, _ := countCompletedLogs(hook)
+	assert.Equal(t, 4, healthy)
+}
+
+// countLogs counts the entries with the given message captured by the hook
+func countLogs(hook *test.Hook, message string) int {
+	count := 0
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == message {
+			count++
+		}
+	}
+	return count
+}
+
+func TestManager_CollapseFailureLogs_IdenticalFailuresSuppressed(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{CollapseFailureLogsSeconds: 60}, false)
+
+	for i := 0; i < 5; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	// Only the first of the identical failures is logged within the summary period
+	_, unhealthy := countCompletedLogs(hook)
+	assert.Equal(t, 1, unhealthy)
+	assert.Equal(t, 0, countLogs(hook, "Healthcheck still failing"))
+}
+
+func TestManager_CollapseFailureLogs_PeriodicSummary(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{CollapseFailureLogsSeconds: 60}, false)
+	state := manager.states[s]
+
+	for i := 0; i < 4; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	// Pretend the summary period elapsed since the first failure was logged
+	state.mu.Lock()
+	state.lastFailureLog = time.Now().Add(-time.Minute)
+	state.mu.Unlock()
+	manager.runSingleHealthcheck(s)
+
+	require.Equal(t, 1, countLogs(hook, "Healthcheck still failing"))
+	summary := hook.LastEntry()
+	assert.Equal(t, 4, summary.Data["times"])
+	assert.Equal(t, "fake result", summary.Data["message"])
+
+	// The count restarts after the summary
+	manager.runSingleHealthcheck(s)
+	assert.Equal(t, 1, state.suppressedFailures)
+}
+
+func TestManager_CollapseFailureLogs_ResetOnChangeAndRecovery(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{CollapseFailureLogsSeconds: 60}, false, false, false, false, true, false)
+	s.messages = []string{"timeout", "timeout", "refused", "refused", "ok", "refused"}
+
+	for i := 0; i < 6; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	// Logged: the first timeout, the first refused, the recovery and refused again after it
+	healthy, unhealthy := countCompletedLogs(hook)
+	assert.Equal(t, 1, healthy)
+	assert.Equal(t, 3, unhealthy)
+}
+
+func TestManager_CollapseFailureLogs_Disabled(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{}, false)
+
+	for i := 0; i < 3; i++ {
+		manager.runSingleHealthcheck(s)
+	}
+
+	_, unhealthy := countCompletedLogs(hook)
+	assert.Equal(t, 3, unhealthy)
+}
+
+// concurrencyTracker records how many scrapes of trackingScrapers run at the same time
+type concurrencyTracker struct {
+	mu      sync.Mutex
+	running int
+	max     int
+	total   int
+}
+
+// trackingScraper is a scraper taking a while to scrape while tracking concurrency
+type trackingScraper struct {
+	fakeScraper
+	tracker *concurrencyTracker
+}
+
+func (s *trackingScraper) Scrape(ctx context.Context) (*scraper.ScrapeResult, error) {
+	s.tracker.mu.Lock()
+	s.tracker.running++
+	s.tracker.max = max(s.tracker.max, s.tracker.running)
+	s.tracker.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.tracker.mu.Lock()
+	s.tracker.running--
+	s.tracker.total++
+	s.tracker.mu.Unlock()
+
+	return s.fakeScraper.Scrape(ctx)
+}
+
+// runTrackedScrapers runs the initial scrapes of several tracking scrapers and returns the tracker
+func runTrackedScrapers(t *testing.T, cfg *config.Config) *concurrencyTracker {
+	manager := NewManager(cfg, logrus.New())
+	tracker := &concurrencyTracker{}
+	for i := 0; i < 4; i++ {
+		s := &trackingScraper{fakeScraper: fakeScraper{healthy: []bool{true}}, tracker: tracker}
+		manager.scrapers = append(manager.scrapers, s)
+		manager.states[s] = newScraperState(config.HealthcheckScraper{Type: "fake"})
+	}
+
+	manager.Start()
+	defer manager.Stop()
+
+	require.Eventually(t, func() bool {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		return tracker.total == 4
+	}, time.Second, 10*time.Millisecond)
+
+	return tracker
+}
+
+func TestManager_Sequential_OneScrapeAtATime(t *testing.T) {
+	tracker := runTrackedScrapers(t, &config.Config{Sequential: true})
+
+	assert.Equal(t, 1, tracker.max)
+}
+
+func TestManager_Concurrent_ScrapesOverlap(t *testing.T) {
+	tracker := runTrackedScrapers(t, &config.Config{})
+
+	assert.Greater(t, tracker.max, 1)
+}
+
+func TestManager_Sequential_ScraperQueuedOnce(t *testing.T) {
+	manager := NewManager(&config.Config{Sequential: true}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{}, true)
+	state := manager.states[s]
+
+	// Without a worker the first scrape stays queued, so further due scrapes are skipped
+	manager.scheduleScrape(s, state)
+	manager.scheduleScrape(s, state)
+	manager.scheduleScrape(s, state)
+
+	job := <-manager.scrapeQueue
+	select {
+	case <-manager.scrapeQueue:
+		t.Fatal("scraper should only be queued once")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	job()
+	assert.Equal(t, 1, s.calls)
+	assert.False(t, state.queued)
+}
+
+// slowScraper is a scraper whose scrapes block until released or cancelled
+type slowScraper struct {
+	fakeScraper
+	started   chan struct{}
+	release   chan struct{}
+	cancelled chan struct{}
+	closed    chan struct{}
+}
+
+func newSlowScraper() *slowScraper {
+	return &slowScraper{
+		fakeScraper: fakeScraper{healthy: []bool{true}},
+		started:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+		cancelled:   make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+func (s *slowScraper) Scrape(ctx context.Context) (*scraper.ScrapeResult, error) {
+	s.started <- struct{}{}
+	select {
+	case <-s.release:
+		return s.fakeScraper.Scrape(ctx)
+	case <-ctx.Done():
+		close(s.cancelled)
+		return nil, ctx.Err()
+	}
+}
+
+func (s *slowScraper) Close() error {
+	close(s.closed)
+	return nil
+}
+
+// startWithSlowScraper starts a manager running a single slow scraper and waits for its
+// first scrape to be in flight
+func startWithSlowScraper(t *testing.T, cfg *config.Config) (*Manager, *slowScraper, *scraperState) {
+	manager := NewManager(cfg, logrus.New())
+	s := newSlowScraper()
+	manager.scrapers = append(manager.scrapers, s)
+	state := newScraperState(config.HealthcheckScraper{Name: "slow", Type: "fake"})
+	manager.states[s] = state
+
+	manager.Start()
+	t.Cleanup(manager.Stop)
+
+	select {
+	case <-s.started:
+	case <-time.After(time.Second):
+		t.Fatal("Initial scrape should have started")
+	}
+
+	return manager, s, state
+}
+
+func TestManager_Reload_DrainsRemovedScraper(t *testing.T) {
+	manager, s, state := startWithSlowScraper(t, &config.Config{DrainTimeout: time.Second})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(s.release)
+	}()
+
+	require.NoError(t, manager.Reload(nil))
+
+	// The in-flight scrape finished on its own, after which the scraper was closed
+	assert.Empty(t, manager.scrapers)
+	assertClosed(t, state.done, "scraper loop should have exited")
+	assertClosed(t, s.closed, "scraper should have been closed")
+	select {
+	case <-s.cancelled:
+		t.Fatal("in-flight scrape should not have been cancelled")
+	default:
+	}
+}
+
+func TestManager_Reload_CancelsAfterDrainTimeout(t *testing.T) {
+	manager, s, state := startWithSlowScraper(t, &config.Config{DrainTimeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	require.NoError(t, manager.Reload(nil))
+
+	assert.Less(t, time.Since(start), drainCancelGracePeriod)
+	assertClosed(t, s.cancelled, "in-flight scrape should have been cancelled")
+	assertClosed(t, state.done, "scraper loop should have exited")
+	assertClosed(t, s.closed, "scraper should have been closed")
+}
+
+func TestManager_Reload_KeepsUnchangedAndAddsNew(t *testing.T) {
+	scraped := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scraped <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	existing := config.HealthcheckScraper{Name: "existing", Type: "http", ScrapeURL: server.URL + "/existing"}
+	added := config.HealthcheckScraper{Name: "added", Type: "http", ScrapeURL: server.URL + "/added"}
+
+	manager := NewManager(&config.Config{Scrapers: []config.HealthcheckScraper{existing}}, logrus.New())
+	require.NoError(t, manager.Initialize())
+	manager.Start()
+	defer manager.Stop()
+
+	assert.Equal(t, "/existing", <-scraped)
+	running := manager.scrapers[0]
+
+	require.NoError(t, manager.Reload([]config.HealthcheckScraper{existing, added}))
+
+	require.Len(t, manager.scrapers, 2)
+	assert.Same(t, running, manager.scrapers[0])
+	select {
+	case path := <-scraped:
+		assert.Equal(t, "/added", path)
+	case <-time.After(time.Second):
+		t.Fatal("Added scraper should have been started")
+	}
+}
+
+func TestManager_Reload_InvalidConfigKeepsRunningSet(t *testing.T) {
+	manager := NewManager(&config.Config{
+		Scrapers: []config.HealthcheckScraper{{Name: "web", Type: "http", ScrapeURL: "http://localhost:8080"}},
+	}, logrus.New())
+	require.NoError(t, manager.Initialize())
+	running := manager.scrapers[0]
+
+	err := manager.Reload([]config.HealthcheckScraper{{Type: "unknown"}})
+
+	assert.Error(t, err)
+	require.Len(t, manager.scrapers, 1)
+	assert.Same(t, running, manager.scrapers[0])
+}
+
+// assertClosed fails the test unless the channel is closed within a second
+func assertClosed(t *testing.T, ch <-chan struct{}, message string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal(message)
+	}
+}
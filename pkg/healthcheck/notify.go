@@ -0,0 +1,236 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"text/template"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultNotifyContentType is the content type of notifications unless configured otherwise
+	defaultNotifyContentType = "application/json"
+	// defaultNotifyRetries is how often a failed notification is retried unless configured otherwise
+	defaultNotifyRetries = 3
+	// defaultNotifyBackoff is the delay before the first retry of a failed notification,
+	// doubling with every further retry
+	defaultNotifyBackoff = time.Second
+)
+
+// notifyRetryPolicy is how often and how quickly a failed notification is retried
+type notifyRetryPolicy struct {
+	retries int
+	backoff time.Duration
+}
+
+// newNotifyRetryPolicy returns the scraper's configured notification retry policy or the defaults
+func newNotifyRetryPolicy(scraperConfig config.HealthcheckScraper) notifyRetryPolicy {
+	policy := notifyRetryPolicy{
+		retries: defaultNotifyRetries,
+		backoff: defaultNotifyBackoff,
+	}
+	if scraperConfig.NotifyRetries != nil {
+		policy.retries = max(*scraperConfig.NotifyRetries, 0)
+	}
+	if scraperConfig.NotifyBackoffMs > 0 {
+		policy.backoff = time.Duration(scraperConfig.NotifyBackoffMs) * time.Millisecond
+	}
+	return policy
+}
+
+// notifyTemplateFuncs are the functions available to notify templates in addition to the builtins
+var notifyTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. to embed the message as a string in a JSON body
+	"json": func(value interface{}) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+// DetailChange describes the previous and current value of a watched detail key
+type DetailChange struct {
+	Previous interface{} `json:"previous"`
+	Current  interface{} `json:"current"`
+}
+
+// NotificationEvent is the JSON payload posted to a scraper's notify URL
+type NotificationEvent struct {
+	Event       string                  `json:"event"`
+	Name        string                  `json:"name"`
+	ScraperType string                  `json:"scraper_type"`
+	Healthy     bool                    `json:"healthy"`
+	Message     string                  `json:"message"`
+	Timestamp   time.Time               `json:"timestamp"`
+	Changes     map[string]DetailChange `json:"changes,omitempty"`
+	// Group and Events are set on the summary of the coalesced state changes of a notify group
+	Group  string              `json:"group,omitempty"`
+	Events []NotificationEvent `json:"events,omitempty"`
+}
+
+// NotificationTemplateData is the data a scraper's notify template is executed with
+type NotificationTemplateData struct {
+	Event     string
+	Name      string
+	Type      string
+	State     string
+	Healthy   bool
+	Message   string
+	Details   map[string]interface{}
+	Changes   map[string]DetailChange
+	Group     string
+	Events    []NotificationEvent
+	Timestamp time.Time
+}
+
+// parseNotifyTemplate parses the scraper's notify template, returning nil if none is configured
+func parseNotifyTemplate(scraperConfig config.HealthcheckScraper) (*template.Template, error) {
+	if scraperConfig.NotifyTemplate == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(scraperConfig.Name).Funcs(notifyTemplateFuncs).Parse(scraperConfig.NotifyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify_template of scraper %s: %w", scraperConfig.Name, err)
+	}
+	return tmpl, nil
+}
+
+// notificationBody renders the event with the notify template, or encodes it as JSON if there is none
+func notificationBody(tmpl *template.Template, event NotificationEvent, details map[string]interface{}) ([]byte, error) {
+	if tmpl == nil {
+		return json.Marshal(event)
+	}
+
+	state := "unhealthy"
+	if event.Healthy {
+		state = "healthy"
+	}
+
+	var body bytes.Buffer
+	err := tmpl.Execute(&body, NotificationTemplateData{
+		Event:     event.Event,
+		Name:      event.Name,
+		Type:      event.ScraperType,
+		State:     state,
+		Healthy:   event.Healthy,
+		Message:   event.Message,
+		Details:   details,
+		Changes:   event.Changes,
+		Group:     event.Group,
+		Events:    event.Events,
+		Timestamp: event.Timestamp,
+	})
+	return body.Bytes(), err
+}
+
+// detailChanges returns the watched keys whose values differ between previous and current details
+func detailChanges(keys []string, previous, current map[string]interface{}) map[string]DetailChange {
+	changes := make(map[string]DetailChange)
+	for _, key := range keys {
+		if !reflect.DeepEqual(previous[key], current[key]) {
+			changes[key] = DetailChange{
+				Previous: previous[key],
+				Current:  current[key],
+			}
+		}
+	}
+	return changes
+}
+
+// sendNotification posts the rendered notification body to the notify URL, retrying failed
+// deliveries with exponential backoff. It runs on a notification worker, so retries never
+// delay the scrape loop. A notification still undelivered after all retries is logged with
+// its full payload as a dead letter, so it can at least be recovered from the logs. Retries
+// are abandoned on shutdown, so a failing endpoint cannot hold up stopping the dispatcher.
+func (m *Manager) sendNotification(url, contentType string, event NotificationEvent, body []byte, policy notifyRetryPolicy) {
+	delay := policy.backoff
+	attempts := 0
+	var err error
+	for attempts < policy.retries+1 {
+		if attempts > 0 {
+			m.logger.WithFields(logrus.Fields{
+				"url":     url,
+				"attempt": attempts,
+				"backoff": delay.String(),
+				"error":   err.Error(),
+			}).Warn("Notification failed, retrying")
+			if !m.waitForRetry(delay) {
+				break
+			}
+			delay *= 2
+		}
+		attempts++
+
+		var retryable bool
+		retryable, err = m.postNotification(url, contentType, event, body)
+		if err == nil {
+			return
+		}
+		if !retryable {
+			break
+		}
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"url":          url,
+		"event":        event.Event,
+		"name":         event.Name,
+		"content_type": contentType,
+		"attempts":     attempts,
+		"error":        err.Error(),
+		"payload":      string(body),
+	}).Error("Failed to deliver notification, dead letter")
+}
+
+// waitForRetry waits out the backoff before a retry and reports false when the manager
+// stops first
+func (m *Manager) waitForRetry(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-m.stopChan:
+		return false
+	}
+}
+
+// postNotification posts the notification body once and reports whether a failure is worth
+// retrying. Requests the endpoint rejected as invalid are not retried, except for rate limits.
+func (m *Manager) postNotification(url, contentType string, event NotificationEvent, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"url":         url,
+		"event":       event.Event,
+		"status_code": resp.StatusCode,
+	}).Info("Sent notification")
+	return false, nil
+}
@@ -0,0 +1,129 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultNotifyGroupWindow is how long the state changes of a notify group are buffered
+// unless configured otherwise
+const defaultNotifyGroupWindow = 10 * time.Second
+
+// groupedChange is a state change buffered in its notify group
+type groupedChange struct {
+	s       scraper.Scraper
+	state   *scraperState
+	event   NotificationEvent
+	details map[string]interface{}
+}
+
+// pendingGroup holds the state changes of a notify group until its window ends
+type pendingGroup struct {
+	changes []groupedChange
+	timer   *time.Timer
+}
+
+// notifyGrouped buffers a state change of a scraper in a notify group. The changes of all
+// scrapers sharing the group and notify URL within the group window are coalesced into a
+// single notification when the window ends, which starts with the first change.
+func (m *Manager) notifyGrouped(s scraper.Scraper, state *scraperState, event NotificationEvent, details map[string]interface{}) {
+	window := m.config.NotifyGroupWindow
+	if window <= 0 {
+		window = defaultNotifyGroupWindow
+	}
+	key := state.config.NotifyGroup + "\x00" + state.config.NotifyURL
+
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+
+	group, ok := m.groups[key]
+	if !ok {
+		group = &pendingGroup{}
+		group.timer = time.AfterFunc(window, func() {
+			m.flushNotifyGroup(key)
+		})
+		m.groups[key] = group
+	}
+	group.changes = append(group.changes, groupedChange{s: s, state: state, event: event, details: details})
+
+	m.logger.WithFields(logrus.Fields{
+		"scraper_type": s.Type(),
+		"group":        state.config.NotifyGroup,
+		"buffered":     len(group.changes),
+	}).Debug("Buffered state change for notify group")
+}
+
+// flushNotifyGroup sends the buffered state changes of a notify group, as the single change
+// itself if only one scraper changed or as a summary of all of them otherwise
+func (m *Manager) flushNotifyGroup(key string) {
+	m.groupsMu.Lock()
+	group, ok := m.groups[key]
+	delete(m.groups, key)
+	m.groupsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	first := group.changes[0]
+	if len(group.changes) == 1 {
+		m.notify(first.s, first.state, first.event, first.details)
+		return
+	}
+
+	name := first.state.config.NotifyGroup
+	healthy := true
+	events := make([]NotificationEvent, 0, len(group.changes))
+	for _, change := range group.changes {
+		healthy = healthy && change.event.Healthy
+		events = append(events, change.event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+
+	summaries := make([]string, 0, len(events))
+	for _, event := range events {
+		state := "unhealthy"
+		if event.Healthy {
+			state = "healthy"
+		}
+		summaries = append(summaries, fmt.Sprintf("%s %s", event.Name, state))
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"group":    name,
+		"scrapers": len(events),
+	}).Info("Coalesced state changes of notify group")
+
+	m.notify(first.s, first.state, NotificationEvent{
+		Event:     "group_state_change",
+		Name:      name,
+		Group:     name,
+		Healthy:   healthy,
+		Message:   fmt.Sprintf("%d scrapers of group %s changed state: %s", len(events), name, strings.Join(summaries, ", ")),
+		Timestamp: m.now(),
+		Events:    events,
+	}, nil)
+}
+
+// flushNotifyGroups sends the state changes of all notify groups without waiting for their
+// windows to end, so none are lost on shutdown
+func (m *Manager) flushNotifyGroups() {
+	m.groupsMu.Lock()
+	keys := make([]string, 0, len(m.groups))
+	for key, group := range m.groups {
+		group.timer.Stop()
+		keys = append(keys, key)
+	}
+	m.groupsMu.Unlock()
+
+	for _, key := range keys {
+		m.flushNotifyGroup(key)
+	}
+}
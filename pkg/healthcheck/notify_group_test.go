@@ -0,0 +1,103 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNotifyGroupTestManager creates a manager with a short notify group window
+func newNotifyGroupTestManager() *Manager {
+	manager := NewManager(&config.Config{NotifyGroupWindow: 50 * time.Millisecond}, logrus.New())
+	manager.dispatcher.start()
+	return manager
+}
+
+// addGroupedScraper adds a scraper in the notify group turning unhealthy after its first scrape
+func addGroupedScraper(manager *Manager, name, group, notifyURL string) *fakeScraper {
+	return addFakeScraper(manager, config.HealthcheckScraper{
+		Name:                name,
+		NotifyURL:           notifyURL,
+		NotifyOnStateChange: true,
+		NotifyGroup:         group,
+	}, true, false)
+}
+
+func TestManager_NotifyGroup_CoalescesChanges(t *testing.T) {
+	server, events := newStateChangeTestServer(t)
+	manager := newNotifyGroupTestManager()
+	scrapers := []*fakeScraper{
+		addGroupedScraper(manager, "web", "backend", server.URL),
+		addGroupedScraper(manager, "api", "backend", server.URL),
+		addGroupedScraper(manager, "worker", "backend", server.URL),
+	}
+
+	for i := 0; i < 2; i++ {
+		for _, s := range scrapers {
+			manager.runSingleHealthcheck(s)
+		}
+	}
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "group_state_change", event.Event)
+		assert.Equal(t, "backend", event.Group)
+		assert.False(t, event.Healthy)
+		assert.Equal(t, "3 scrapers of group backend changed state: api unhealthy, web unhealthy, worker unhealthy", event.Message)
+		require.Len(t, event.Events, 3)
+		assert.Equal(t, "state_change", event.Events[0].Event)
+	case <-time.After(time.Second):
+		t.Fatal("Summary notification should have been sent")
+	}
+
+	manager.dispatcher.stop()
+	assert.Empty(t, events, "only the summary should have been sent")
+}
+
+func TestManager_NotifyGroup_SingleChange(t *testing.T) {
+	server, events := newStateChangeTestServer(t)
+	manager := newNotifyGroupTestManager()
+	web := addGroupedScraper(manager, "web", "backend", server.URL)
+	// Another group is notified separately
+	db := addGroupedScraper(manager, "db", "storage", server.URL)
+
+	for i := 0; i < 2; i++ {
+		manager.runSingleHealthcheck(web)
+		manager.runSingleHealthcheck(db)
+	}
+
+	received := map[string]NotificationEvent{}
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-events:
+			received[event.Name] = event
+		case <-time.After(time.Second):
+			t.Fatal("Notifications should have been sent")
+		}
+	}
+	assert.Equal(t, "state_change", received["web"].Event)
+	assert.Equal(t, "state_change", received["db"].Event)
+	manager.dispatcher.stop()
+}
+
+func TestManager_NotifyGroup_FlushedOnStop(t *testing.T) {
+	server, events := newStateChangeTestServer(t)
+	manager := NewManager(&config.Config{NotifyGroupWindow: time.Hour}, logrus.New())
+	web := addGroupedScraper(manager, "web", "backend", server.URL)
+	api := addGroupedScraper(manager, "api", "backend", server.URL)
+	manager.dispatcher.start()
+
+	for i := 0; i < 2; i++ {
+		manager.runSingleHealthcheck(web)
+		manager.runSingleHealthcheck(api)
+	}
+	manager.Stop()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "group_state_change", (<-events).Event)
+}
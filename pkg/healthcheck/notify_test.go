@@ -0,0 +1,385 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetailChanges_WatchedKeyChanged(t *testing.T) {
+	previous := map[string]interface{}{"readyConnections": 4, "connectorId": "a"}
+	current := map[string]interface{}{"readyConnections": 2, "connectorId": "b"}
+
+	changes := detailChanges([]string{"readyConnections"}, previous, current)
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, DetailChange{Previous: 4, Current: 2}, changes["readyConnections"])
+}
+
+func TestDetailChanges_UnwatchedKeyIgnored(t *testing.T) {
+	previous := map[string]interface{}{"readyConnections": 4, "connectorId": "a"}
+	current := map[string]interface{}{"readyConnections": 4, "connectorId": "b"}
+
+	changes := detailChanges([]string{"readyConnections"}, previous, current)
+
+	assert.Empty(t, changes)
+}
+
+func TestDetailChanges_MissingKey(t *testing.T) {
+	previous := map[string]interface{}{"readyConnections": 4}
+	current := map[string]interface{}{"error": "connection refused"}
+
+	changes := detailChanges([]string{"readyConnections"}, previous, current)
+
+	assert.Equal(t, DetailChange{Previous: 4, Current: nil}, changes["readyConnections"])
+}
+
+func TestManager_CheckDetailChanges_SendsNotification(t *testing.T) {
+	events := make(chan NotificationEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event NotificationEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{
+				Type:                 "cloudflared-tunnel-connector",
+				ScrapeURL:            "http://localhost:8080/ready",
+				NotifyURL:            server.URL,
+				NotifyOnDetailChange: []string{"readyConnections"},
+			},
+		},
+	}
+	manager := NewManager(cfg, logrus.New())
+	require.NoError(t, manager.Initialize())
+	manager.dispatcher.start()
+	s := manager.scrapers[0]
+
+	manager.checkDetailChanges(s, &scraper.ScrapeResult{
+		Healthy: true,
+		Details: map[string]interface{}{"readyConnections": 4},
+	})
+	manager.checkDetailChanges(s, &scraper.ScrapeResult{
+		Healthy: true,
+		Details: map[string]interface{}{"readyConnections": 2},
+	})
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "detail_change", event.Event)
+		assert.True(t, event.Healthy)
+		assert.Equal(t, float64(4), event.Changes["readyConnections"].Previous)
+		assert.Equal(t, float64(2), event.Changes["readyConnections"].Current)
+	case <-time.After(time.Second):
+		t.Fatal("Notification should have been sent")
+	}
+
+	manager.dispatcher.stop()
+}
+
+func TestManager_CheckDetailChanges_NoChange(t *testing.T) {
+	notified := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notified = true
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{
+				Type:                 "cloudflared-tunnel-connector",
+				ScrapeURL:            "http://localhost:8080/ready",
+				NotifyURL:            server.URL,
+				NotifyOnDetailChange: []string{"readyConnections"},
+			},
+		},
+	}
+	manager := NewManager(cfg, logrus.New())
+	require.NoError(t, manager.Initialize())
+	manager.dispatcher.start()
+	s := manager.scrapers[0]
+
+	for i := 0; i < 2; i++ {
+		manager.checkDetailChanges(s, &scraper.ScrapeResult{
+			Healthy: true,
+			Details: map[string]interface{}{"readyConnections": 4},
+		})
+	}
+
+	manager.dispatcher.stop()
+	assert.False(t, notified, "Notify URL should not have been called")
+}
+
+// scriptedScrape is the outcome of one scrape of a scriptedScraper
+type scriptedScrape struct {
+	result *scraper.ScrapeResult
+	err    error
+}
+
+// scriptedScraper returns its scripted outcomes in order, repeating the last one
+type scriptedScraper struct {
+	fakeScraper
+	scrapes []scriptedScrape
+}
+
+func (s *scriptedScraper) Scrape(ctx context.Context) (*scraper.ScrapeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scrape := s.scrapes[min(s.calls, len(s.scrapes)-1)]
+	s.calls++
+	return scrape.result, scrape.err
+}
+
+// detailChangeLogs returns the changes of the "Scraper details changed" entries captured by the hook
+func detailChangeLogs(hook *test.Hook) []map[string]DetailChange {
+	var changes []map[string]DetailChange
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Scraper details changed" {
+			changes = append(changes, entry.Data["changes"].(map[string]DetailChange))
+		}
+	}
+	return changes
+}
+
+func TestManager_DetailChanges_UnhealthyResultsCompared(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := &scriptedScraper{scrapes: []scriptedScrape{
+		{result: &scraper.ScrapeResult{Healthy: true, Message: "ok", Details: map[string]interface{}{"days_remaining": 30}}},
+		{result: &scraper.ScrapeResult{Healthy: false, Message: "expiring", Details: map[string]interface{}{"days_remaining": 5}}},
+	}}
+	manager.scrapers = append(manager.scrapers, s)
+	manager.states[s] = newScraperState(config.HealthcheckScraper{Name: "cert", NotifyOnDetailChange: []string{"days_remaining"}})
+
+	manager.runSingleHealthcheck(s)
+	manager.runSingleHealthcheck(s)
+
+	changes := detailChangeLogs(hook)
+	require.Len(t, changes, 1)
+	assert.Equal(t, DetailChange{Previous: 30, Current: 5}, changes[0]["days_remaining"])
+}
+
+func TestManager_DetailChanges_ScrapeErrorsSkipped(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := &scriptedScraper{scrapes: []scriptedScrape{
+		{result: &scraper.ScrapeResult{Healthy: true, Message: "ok", Details: map[string]interface{}{"days_remaining": 30}}},
+		{err: errors.New("connection refused")},
+		{err: errors.New("connection refused")},
+		{result: &scraper.ScrapeResult{Healthy: true, Message: "ok", Details: map[string]interface{}{"days_remaining": 5}}},
+	}}
+	manager.scrapers = append(manager.scrapers, s)
+	manager.states[s] = newScraperState(config.HealthcheckScraper{Name: "cert", NotifyOnDetailChange: []string{"days_remaining"}})
+
+	// Scrape errors carry no details, so they neither report the watched keys as removed
+	// nor reset the comparison
+	manager.runSingleHealthcheck(s)
+	manager.runSingleHealthcheck(s)
+	manager.runSingleHealthcheck(s)
+	assert.Empty(t, detailChangeLogs(hook))
+
+	// The change made during the errors is reported against the details before them
+	manager.runSingleHealthcheck(s)
+	changes := detailChangeLogs(hook)
+	require.Len(t, changes, 1)
+	assert.Equal(t, DetailChange{Previous: 30, Current: 5}, changes[0]["days_remaining"])
+}
+
+func TestNotificationBody_DefaultJSON(t *testing.T) {
+	event := NotificationEvent{Event: "detail_change", Name: "api", ScraperType: "http", Healthy: true, Message: "ok"}
+
+	body, err := notificationBody(nil, event, nil)
+
+	require.NoError(t, err)
+	var decoded NotificationEvent
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, event, decoded)
+}
+
+func TestNotificationBody_Template(t *testing.T) {
+	tmpl, err := parseNotifyTemplate(config.HealthcheckScraper{
+		Name:           "api",
+		NotifyTemplate: `{"text": {{ printf "%s (%s) is %s: %s, %v connections" .Name .Type .State .Message (index .Details "readyConnections") | json }}}`,
+	})
+	require.NoError(t, err)
+
+	body, err := notificationBody(tmpl, NotificationEvent{
+		Name:        "api",
+		ScraperType: "cloudflared-tunnel-connector",
+		Healthy:     false,
+		Message:     `tunnel "a" down`,
+	}, map[string]interface{}{"readyConnections": 0})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text": "api (cloudflared-tunnel-connector) is unhealthy: tunnel \"a\" down, 0 connections"}`, string(body))
+}
+
+func TestParseNotifyTemplate(t *testing.T) {
+	tmpl, err := parseNotifyTemplate(config.HealthcheckScraper{Name: "api"})
+	assert.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	_, err = parseNotifyTemplate(config.HealthcheckScraper{Name: "api", NotifyTemplate: "{{ .Name "})
+	assert.ErrorContains(t, err, "invalid notify_template of scraper api")
+}
+
+func TestManager_Initialize_InvalidNotifyTemplate(t *testing.T) {
+	manager := NewManager(&config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{Name: "api", Type: "http", ScrapeURL: "http://localhost:8080/health", NotifyTemplate: "{{ if .Healthy }}"},
+		},
+	}, logrus.New())
+
+	err := manager.Initialize()
+
+	assert.ErrorContains(t, err, "notify_template")
+}
+
+func TestManager_CheckDetailChanges_TemplatedNotification(t *testing.T) {
+	type request struct {
+		contentType string
+		body        string
+	}
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{contentType: r.Header.Get("Content-Type"), body: string(body)}
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.Config{
+		Scrapers: []config.HealthcheckScraper{
+			{
+				Name:                 "tunnel",
+				Type:                 "cloudflared-tunnel-connector",
+				ScrapeURL:            "http://localhost:8080/ready",
+				NotifyURL:            server.URL,
+				NotifyOnDetailChange: []string{"readyConnections"},
+				NotifyTemplate:       `{{ .Name }}: {{ range $key, $change := .Changes }}{{ $key }} {{ $change.Previous }} -> {{ $change.Current }}{{ end }}`,
+				NotifyContentType:    "text/plain",
+			},
+		},
+	}, logrus.New())
+	require.NoError(t, manager.Initialize())
+	manager.dispatcher.start()
+	defer manager.dispatcher.stop()
+	s := manager.scrapers[0]
+
+	manager.checkDetailChanges(s, &scraper.ScrapeResult{Details: map[string]interface{}{"readyConnections": 4}})
+	manager.checkDetailChanges(s, &scraper.ScrapeResult{Details: map[string]interface{}{"readyConnections": 2}})
+
+	select {
+	case req := <-requests:
+		assert.Equal(t, "text/plain", req.contentType)
+		assert.Equal(t, "tunnel: readyConnections 4 -> 2", req.body)
+	case <-time.After(time.Second):
+		t.Fatal("Notification should have been sent")
+	}
+}
+
+func TestManager_SendNotification_RetriesWithBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flaky endpoint failing the first two attempts
+		if attempts.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+
+	start := time.Now()
+	manager.sendNotification(server.URL, defaultNotifyContentType, NotificationEvent{Event: "detail_change"}, []byte("{}"), notifyRetryPolicy{retries: 3, backoff: 10 * time.Millisecond})
+
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond) // 10ms then 20ms
+	assert.Equal(t, 2, countLogs(hook, "Notification failed, retrying"))
+	assert.Equal(t, 1, countLogs(hook, "Sent notification"))
+	assert.Equal(t, 0, countLogs(hook, "Failed to deliver notification, dead letter"))
+}
+
+func TestManager_SendNotification_DeadLetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int32
+	}{
+		{name: "retries exhausted", status: http.StatusServiceUnavailable, attempts: 3},
+		{name: "rejected request not retried", status: http.StatusBadRequest, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			logger, hook := test.NewNullLogger()
+			manager := NewManager(&config.Config{}, logger)
+
+			manager.sendNotification(server.URL, defaultNotifyContentType, NotificationEvent{Event: "detail_change", Name: "api"}, []byte(`{"event":"detail_change"}`), notifyRetryPolicy{retries: 2, backoff: time.Millisecond})
+
+			assert.Equal(t, tt.attempts, attempts.Load())
+			entry := hook.LastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, logrus.ErrorLevel, entry.Level)
+			assert.Equal(t, "Failed to deliver notification, dead letter", entry.Message)
+			assert.Equal(t, `{"event":"detail_change"}`, entry.Data["payload"])
+			assert.Equal(t, int(tt.attempts), entry.Data["attempts"])
+		})
+	}
+}
+
+func TestManager_SendNotification_AbandonsRetriesOnStop(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	close(manager.stopChan)
+
+	start := time.Now()
+	manager.sendNotification(server.URL, defaultNotifyContentType, NotificationEvent{Event: "detail_change", Name: "api"}, []byte(`{"event":"detail_change"}`), notifyRetryPolicy{retries: 3, backoff: time.Minute})
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), attempts.Load())
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Failed to deliver notification, dead letter", entry.Message)
+	assert.Equal(t, 1, entry.Data["attempts"])
+}
+
+func TestNewNotifyRetryPolicy(t *testing.T) {
+	disabled := 0
+
+	assert.Equal(t, notifyRetryPolicy{retries: defaultNotifyRetries, backoff: defaultNotifyBackoff}, newNotifyRetryPolicy(config.HealthcheckScraper{}))
+	assert.Equal(t, notifyRetryPolicy{retries: 0, backoff: 250 * time.Millisecond}, newNotifyRetryPolicy(config.HealthcheckScraper{NotifyRetries: &disabled, NotifyBackoffMs: 250}))
+}
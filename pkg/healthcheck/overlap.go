@@ -0,0 +1,88 @@
+package healthcheck
+
+import (
+	"fmt"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Overlap policies deciding what happens to a scrape that is due while the previous scrape
+// of the same scraper is still running
+const (
+	// overlapSkip drops the due scrape
+	overlapSkip = "skip"
+	// overlapQueue runs the due scrape once the previous one finishes, coalescing any further
+	// due scrapes into it
+	overlapQueue = "queue"
+)
+
+// parseOverlapPolicy returns the scraper's overlap_policy, skip when unset
+func parseOverlapPolicy(scraperConfig config.HealthcheckScraper) (string, error) {
+	switch scraperConfig.OverlapPolicy {
+	case "":
+		return overlapSkip, nil
+	case overlapSkip, overlapQueue:
+		return scraperConfig.OverlapPolicy, nil
+	default:
+		return "", fmt.Errorf("scraper %s: invalid overlap_policy %q, expected %s or %s", scraperConfig.Name, scraperConfig.OverlapPolicy, overlapSkip, overlapQueue)
+	}
+}
+
+// beginScrape marks a scrape of the scraper as running and returns true, or returns false
+// when the previous scrape is still running. Under the queue policy the due scrape is then
+// remembered and run by finishScrape.
+func (m *Manager) beginScrape(s scraper.Scraper, state *scraperState) bool {
+	state.mu.Lock()
+	running := state.running
+	if !running {
+		state.running = true
+	} else if state.overlapPolicy == overlapQueue {
+		state.rerun = true
+	}
+	state.mu.Unlock()
+
+	if !running {
+		return true
+	}
+
+	logger := m.logger.WithFields(logrus.Fields{
+		"name":           state.config.Name,
+		"scraper_type":   s.Type(),
+		"overlap_policy": state.overlapPolicy,
+	})
+	switch {
+	case state.overlapPolicy == overlapQueue:
+		logger.Debug("Queueing scrape until the previous scrape finishes")
+	case state.interval > 0 && state.interval < time.Second:
+		// Sub-second intervals routinely overlap with the scrape itself, a warning per tick
+		// would flood the log
+		logger.Debug("Skipping scrape, the previous scrape is still running")
+	default:
+		logger.Warn("Skipping scrape, the previous scrape is still running")
+	}
+	return false
+}
+
+// finishScrape ends the running scrape and returns true when a queued scrape should run
+// next, in which case the scraper stays marked as running
+func (m *Manager) finishScrape(state *scraperState) bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	rerun := state.rerun
+	state.rerun = false
+	select {
+	case <-m.stopChan:
+		rerun = false
+	case <-state.stop:
+		rerun = false
+	default:
+	}
+
+	state.running = rerun
+	return rerun
+}
@@ -0,0 +1,178 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scheduleOverlappingScrapes starts a slow scrape of a tracking scraper with the given
+// overlap policy and schedules two more while it runs, like the ticks of scraperLoop coming due
+// during a slow scrape, returning once all scrapes finished
+func scheduleOverlappingScrapes(t *testing.T, policy string) (*trackingScraper, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	tracker := &concurrencyTracker{}
+	s := &trackingScraper{fakeScraper: fakeScraper{healthy: []bool{true}}, tracker: tracker}
+	manager.scrapers = append(manager.scrapers, s)
+	state := newScraperState(config.HealthcheckScraper{Name: "slow", Type: "fake", OverlapPolicy: policy})
+	manager.states[s] = state
+
+	manager.scheduleScrape(s, state)
+	require.Eventually(t, func() bool {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		return tracker.running == 1
+	}, time.Second, time.Millisecond)
+
+	// Due again while the first scrape takes longer than the interval
+	manager.scheduleScrape(s, state)
+	manager.scheduleScrape(s, state)
+
+	state.scrapes.Wait()
+	return s, hook
+}
+
+func TestManager_OverlapSkip(t *testing.T) {
+	s, hook := scheduleOverlappingScrapes(t, "")
+
+	assert.Equal(t, 1, s.tracker.max)
+	assert.Equal(t, 1, s.tracker.total)
+	assert.Equal(t, 2, countLogs(hook, "Skipping scrape, the previous scrape is still running"))
+}
+
+func TestManager_OverlapQueue(t *testing.T) {
+	s, hook := scheduleOverlappingScrapes(t, "queue")
+
+	// The due scrapes were coalesced into one run after the first
+	assert.Equal(t, 1, s.tracker.max)
+	assert.Equal(t, 2, s.tracker.total)
+	assert.Zero(t, countLogs(hook, "Skipping scrape, the previous scrape is still running"))
+}
+
+func TestManager_ScraperLoop_NoOverlap(t *testing.T) {
+	for _, policy := range []string{"skip", "queue"} {
+		t.Run(policy, func(t *testing.T) {
+			manager := NewManager(&config.Config{}, logrus.New())
+			tracker := &concurrencyTracker{}
+			s := &trackingScraper{fakeScraper: fakeScraper{healthy: []bool{true}}, tracker: tracker}
+			manager.scrapers = append(manager.scrapers, s)
+			// The scrape takes 20ms, four times the interval
+			state := newScraperState(config.HealthcheckScraper{Name: "slow", Type: "fake", ScrapeInterval: "5ms", OverlapPolicy: policy})
+			manager.states[s] = state
+
+			manager.startScraper(s, state)
+			require.Eventually(t, func() bool {
+				tracker.mu.Lock()
+				defer tracker.mu.Unlock()
+				return tracker.total >= 4
+			}, 2*time.Second, time.Millisecond)
+			close(manager.stopChan)
+			<-state.done
+			state.scrapes.Wait()
+
+			assert.Equal(t, 1, tracker.max)
+		})
+	}
+}
+
+func TestManager_ScraperLoop_SlowScrapeOverlapsTicks(t *testing.T) {
+	tests := []struct {
+		policy  string
+		message string
+		level   logrus.Level
+		queued  bool
+	}{
+		{policy: "skip", message: "Skipping scrape, the previous scrape is still running", level: logrus.WarnLevel},
+		{policy: "queue", message: "Queueing scrape until the previous scrape finishes", level: logrus.DebugLevel, queued: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+			logger, hook := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+			manager := NewManager(&config.Config{}, logger)
+			s := newSlowScraper()
+			manager.scrapers = append(manager.scrapers, s)
+			// A second is the shortest interval whose skipped scrapes are logged as warnings
+			state := newScraperState(config.HealthcheckScraper{Name: "slow", Type: "fake", ScrapeInterval: "1s", OverlapPolicy: tt.policy})
+			manager.states[s] = state
+			manager.startScraper(s, state)
+			t.Cleanup(func() {
+				close(manager.stopChan)
+				<-state.done
+				state.cancel()
+				state.scrapes.Wait()
+			})
+
+			select {
+			case <-s.started:
+			case <-time.After(time.Second):
+				t.Fatal("Initial scrape should have started")
+			}
+			s.release <- struct{}{}
+
+			// The first tick's scrape is held, unlike the initial one it runs on the loop
+			select {
+			case <-s.started:
+			case <-time.After(2 * time.Second):
+				t.Fatal("Scrape of the first tick should have started")
+			}
+
+			// Two more ticks come due while the scrape of the first is held
+			require.Eventually(t, func() bool {
+				return countLogs(hook, tt.message) >= 2
+			}, 3*time.Second, 10*time.Millisecond)
+			for _, entry := range hook.AllEntries() {
+				if entry.Message == tt.message {
+					assert.Equal(t, tt.level, entry.Level)
+				}
+			}
+			assert.Empty(t, s.started, "no scrape should start while the previous one runs")
+
+			s.release <- struct{}{}
+
+			// A queued scrape runs as soon as the held one finishes, a skipped one waits for
+			// the next tick, half a second away at the earliest
+			select {
+			case <-s.started:
+				assert.True(t, tt.queued, "skipped scrape should not run before the next tick")
+			case <-time.After(300 * time.Millisecond):
+				assert.False(t, tt.queued, "queued scrape should run once the previous one finished")
+			}
+		})
+	}
+}
+
+func TestManager_OverlapQueue_DroppedOnStop(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{OverlapPolicy: "queue"}, true)
+	state := manager.states[s]
+
+	require.True(t, manager.beginScrape(s, state))
+	assert.False(t, manager.beginScrape(s, state))
+	close(state.stop)
+
+	assert.False(t, manager.finishScrape(state))
+	assert.False(t, state.running)
+}
+
+func TestParseOverlapPolicy(t *testing.T) {
+	policy, err := parseOverlapPolicy(config.HealthcheckScraper{})
+	require.NoError(t, err)
+	assert.Equal(t, "skip", policy)
+
+	policy, err = parseOverlapPolicy(config.HealthcheckScraper{OverlapPolicy: "queue"})
+	require.NoError(t, err)
+	assert.Equal(t, "queue", policy)
+
+	_, err = parseOverlapPolicy(config.HealthcheckScraper{Name: "api", OverlapPolicy: "parallel"})
+	assert.EqualError(t, err, `scraper api: invalid overlap_policy "parallel", expected skip or queue`)
+}
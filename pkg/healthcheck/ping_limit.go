@@ -0,0 +1,51 @@
+package healthcheck
+
+import (
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// throttledPingEvery is the reduced cadence of a scraper that exceeded max_consecutive_pings:
+// only every Nth healthy result is pinged until the scraper fails or its configuration changes
+const throttledPingEvery = 10
+
+// allowPing counts the scraper's consecutive pings and reports whether a result should ping.
+// Unhealthy results never ping and reset the count. Once more healthy results in a row than
+// max_consecutive_pings were pinged, the scraper is throttled to every throttledPingEvery-th
+// healthy result. A changed configuration creates a new state, which resets the count too.
+func (m *Manager) allowPing(s scraper.Scraper, state *scraperState, healthy bool) bool {
+	if state == nil || state.config.MaxConsecutivePings <= 0 {
+		return healthy
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !healthy {
+		if state.consecutivePings > state.config.MaxConsecutivePings {
+			m.logger.WithFields(logrus.Fields{
+				"name":         state.config.Name,
+				"scraper_type": s.Type(),
+			}).Info("Scraper failed, lifting ping throttling")
+		}
+		state.consecutivePings = 0
+		return false
+	}
+
+	state.consecutivePings++
+	over := state.consecutivePings - state.config.MaxConsecutivePings
+	if over <= 0 {
+		return true
+	}
+	if over == 1 {
+		m.logger.WithFields(logrus.Fields{
+			"name":                  state.config.Name,
+			"scraper_type":          s.Type(),
+			"max_consecutive_pings": state.config.MaxConsecutivePings,
+			"throttled_ping_every":  throttledPingEvery,
+		}).Warn("Scraper exceeded its maximum consecutive pings without a failure, throttling pings")
+	}
+	// The first result over the cap is throttled, and then every throttledPingEvery-th one pings
+	return over%throttledPingEvery == 0
+}
@@ -0,0 +1,84 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_AllowPing_ThrottlesAfterCap(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", MaxConsecutivePings: 3}, true)
+	state := manager.state(s)
+
+	var allowed []bool
+	for range 3 + 2*throttledPingEvery {
+		allowed = append(allowed, manager.allowPing(s, state, true))
+	}
+
+	// Every result up to the cap pings, then only every throttledPingEvery-th one
+	for i, ping := range allowed {
+		over := i + 1 - 3
+		assert.Equal(t, over <= 0 || over%throttledPingEvery == 0, ping, "result %d", i+1)
+	}
+	assert.Equal(t, 1, countLogs(hook, "Scraper exceeded its maximum consecutive pings without a failure, throttling pings"))
+}
+
+func TestManager_AllowPing_FailureLiftsThrottling(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", MaxConsecutivePings: 2}, true)
+	state := manager.state(s)
+
+	manager.allowPing(s, state, true)
+	manager.allowPing(s, state, true)
+	assert.False(t, manager.allowPing(s, state, true))
+
+	assert.False(t, manager.allowPing(s, state, false))
+	assert.Equal(t, 1, countLogs(hook, "Scraper failed, lifting ping throttling"))
+
+	// The count starts over after the failure
+	assert.True(t, manager.allowPing(s, state, true))
+	assert.True(t, manager.allowPing(s, state, true))
+	assert.False(t, manager.allowPing(s, state, true))
+}
+
+func TestManager_AllowPing_Unlimited(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	state := manager.state(s)
+
+	for range 100 {
+		assert.True(t, manager.allowPing(s, state, true))
+	}
+	assert.False(t, manager.allowPing(s, state, false))
+}
+
+func TestManager_MaxConsecutivePings(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", MaxConsecutivePings: 2}, true, true, true, true, false, true)
+	s.pingURL = server.URL
+	manager.dispatcher.start()
+
+	// Two pings reach the cap, two throttled results, a failure and a ping after recovery
+	for range 6 {
+		manager.runSingleHealthcheck(s)
+	}
+	manager.dispatcher.stop()
+
+	assert.Equal(t, int32(3), pings.Load())
+}
@@ -0,0 +1,139 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"healthcheck/pkg/scraper"
+)
+
+// CheckResult is the outcome of running a single scraper once. A skipped scraper was not
+// scraped, for example because its gate is closed, and the message says why.
+type CheckResult struct {
+	Name      string                 `json:"name"`
+	Type      string                 `json:"type"`
+	Healthy   bool                   `json:"healthy"`
+	Skipped   bool                   `json:"skipped,omitempty"`
+	Message   string                 `json:"message"`
+	LatencyMs int64                  `json:"latency_ms"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report is the aggregate outcome of a one-shot run
+type Report struct {
+	Healthy bool          `json:"healthy"`
+	Results []CheckResult `json:"results"`
+}
+
+// newReport aggregates the results, which are healthy unless a scraped one is unhealthy
+func newReport(results []CheckResult) Report {
+	report := Report{
+		Healthy: true,
+		Results: results,
+	}
+	for _, result := range results {
+		if !result.Healthy && !result.Skipped {
+			report.Healthy = false
+		}
+	}
+	return report
+}
+
+// RunOnce runs every scraper once concurrently and returns the results in configuration
+// order. It is a dry run for the check command, where the manager is not started: nothing is
+// recorded, pinged or notified, and active hours, dependencies and gates do not apply. At
+// most the configured maximum of scrapes run at once, or one in sequential mode.
+func (m *Manager) RunOnce(ctx context.Context) Report {
+	m.mu.RLock()
+	scrapers := append([]scraper.Scraper(nil), m.scrapers...)
+	names := make([]string, len(scrapers))
+	states := make([]*scraperState, len(scrapers))
+	for i, s := range scrapers {
+		states[i] = m.states[s]
+		names[i] = states[i].config.Name
+	}
+	m.mu.RUnlock()
+
+	results := make([]CheckResult, len(scrapers))
+
+	limit := m.config.MaxConcurrentScrapes
+	if m.config.Sequential {
+		limit = 1
+	}
+	if limit <= 0 {
+		limit = len(scrapers)
+	}
+	slots := make(chan struct{}, max(limit, 1))
+
+	var wg sync.WaitGroup
+	for i, s := range scrapers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			scrapeCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+
+			checkResult := CheckResult{
+				Name: names[i],
+				Type: s.Type(),
+			}
+
+			start := time.Now()
+			result, err := m.scrapeWithHooks(scrapeCtx, s, states[i])
+			checkResult.LatencyMs = time.Since(start).Milliseconds()
+
+			if err != nil {
+				checkResult.Message = err.Error()
+			} else {
+				checkResult.Healthy = result.Healthy
+				checkResult.Message = result.Message
+				checkResult.Details = result.Details
+			}
+
+			results[i] = checkResult
+		}()
+	}
+	wg.Wait()
+
+	return newReport(results)
+}
+
+// WriteJSON writes the report as indented JSON
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteTable writes the report as a human readable table
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tSTATUS\tLATENCY\tMESSAGE")
+	for _, result := range r.Results {
+		status := "FAIL"
+		switch {
+		case result.Skipped:
+			status = "SKIP"
+		case result.Healthy:
+			status = "PASS"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%dms\t%s\n", result.Name, result.Type, status, result.LatencyMs, result.Message)
+	}
+
+	overall := "FAIL"
+	if r.Healthy {
+		overall = "PASS"
+	}
+	fmt.Fprintf(tw, "\nOverall: %s\n", overall)
+
+	return tw.Flush()
+}
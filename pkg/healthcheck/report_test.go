@@ -0,0 +1,139 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_RunOnce(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	healthy := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	unhealthy := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false)
+	healthy.pingURL = "http://localhost:99999/ping"
+
+	report := manager.RunOnce(context.Background())
+
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "api", report.Results[0].Name)
+	assert.Equal(t, "fake", report.Results[0].Type)
+	assert.True(t, report.Results[0].Healthy)
+	assert.Equal(t, "db", report.Results[1].Name)
+	assert.False(t, report.Results[1].Healthy)
+	assert.Equal(t, 1, healthy.calls)
+	assert.Equal(t, 1, unhealthy.calls)
+}
+
+func TestManager_RunOnce_RecordsNothing(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	sink := &fakeSink{}
+	manager.AddSink(sink)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	manager.RunOnce(context.Background())
+	manager.sinks.stop()
+
+	assert.Equal(t, StatusUnknown, manager.Status()[0].Status)
+	assert.Empty(t, sink.published())
+}
+
+func TestManager_RunOnce_AllHealthy(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "web"}, true)
+
+	report := manager.RunOnce(context.Background())
+
+	assert.True(t, report.Healthy)
+}
+
+func TestManager_RunOnce_MaxConcurrentScrapes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		max  int
+	}{
+		{name: "limited", cfg: &config.Config{MaxConcurrentScrapes: 2}, max: 2},
+		{name: "sequential", cfg: &config.Config{Sequential: true, MaxConcurrentScrapes: 2}, max: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(tt.cfg, logrus.New())
+			tracker := &concurrencyTracker{}
+			for i := 0; i < 4; i++ {
+				s := &trackingScraper{fakeScraper: fakeScraper{healthy: []bool{true}}, tracker: tracker}
+				manager.scrapers = append(manager.scrapers, s)
+				manager.states[s] = newScraperState(config.HealthcheckScraper{Type: "fake"})
+			}
+
+			report := manager.RunOnce(context.Background())
+
+			assert.Len(t, report.Results, 4)
+			assert.Equal(t, 4, tracker.total)
+			assert.LessOrEqual(t, tracker.max, tt.max)
+		})
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	report := Report{
+		Healthy: false,
+		Results: []CheckResult{
+			{
+				Name:      "api",
+				Type:      "http",
+				Healthy:   false,
+				Message:   "HTTP status 503",
+				LatencyMs: 12,
+				Details:   map[string]interface{}{"status_code": 503},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, false, decoded["healthy"])
+
+	results := decoded["results"].([]interface{})
+	require.Len(t, results, 1)
+	result := results[0].(map[string]interface{})
+	assert.Equal(t, "api", result["name"])
+	assert.Equal(t, "http", result["type"])
+	assert.Equal(t, false, result["healthy"])
+	assert.Equal(t, "HTTP status 503", result["message"])
+	assert.Equal(t, float64(12), result["latency_ms"])
+	assert.Equal(t, map[string]interface{}{"status_code": float64(503)}, result["details"])
+}
+
+func TestReport_WriteTable(t *testing.T) {
+	report := Report{
+		Healthy: true,
+		Results: []CheckResult{
+			{Name: "api", Type: "http", Healthy: true, Message: "HTTP status 200", LatencyMs: 5},
+			{Name: "replica", Type: "http", Skipped: true, Message: "Scrape skipped outside active hours"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteTable(&buf))
+	assert.Contains(t, buf.String(), "SKIP")
+
+	output := buf.String()
+	assert.Contains(t, output, "NAME")
+	assert.Contains(t, output, "api")
+	assert.Contains(t, output, "PASS")
+	assert.Contains(t, output, "5ms")
+	assert.Contains(t, output, "Overall: PASS")
+}
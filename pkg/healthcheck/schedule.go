@@ -0,0 +1,67 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ScraperSchedule is when a scraper runs as served by the schedule endpoint
+type ScraperSchedule struct {
+	Name            string     `json:"name"`
+	Type            string     `json:"type"`
+	IntervalSeconds float64    `json:"interval_seconds"`
+	LastRun         *time.Time `json:"last_run,omitempty"`
+	NextRun         *time.Time `json:"next_run,omitempty"`
+}
+
+// setNextRun records when the scraper's loop is next due to schedule a scrape
+func (m *Manager) setNextRun(state *scraperState, next time.Time) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.nextRun = next
+}
+
+// Schedule returns the interval, last run and next scheduled run of every running scraper.
+// The last run is when the latest result was recorded, and the next run is unknown until the
+// scraper's loop started. A due scrape may still not run, for example when the previous one
+// is running and the overlap policy skips it, or while the scraper is pending or paused.
+func (m *Manager) Schedule() []ScraperSchedule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	schedules := make([]ScraperSchedule, 0, len(m.scrapers))
+	for _, s := range m.scrapers {
+		state := m.states[s]
+		schedule := ScraperSchedule{
+			Name: state.config.Name,
+			Type: s.Type(),
+		}
+
+		state.mu.Lock()
+		interval := state.interval
+		if interval <= 0 {
+			interval = scrapeInterval(s, state.config)
+		}
+		schedule.IntervalSeconds = interval.Seconds()
+		if lastRun := state.lastScrape; !lastRun.IsZero() {
+			schedule.LastRun = &lastRun
+		}
+		if nextRun := state.nextRun; !nextRun.IsZero() {
+			schedule.NextRun = &nextRun
+		}
+		state.mu.Unlock()
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules
+}
+
+// ScheduleHandler returns an HTTP handler serving the schedule of every scraper as JSON
+func (m *Manager) ScheduleHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(m.Schedule())
+	})
+}
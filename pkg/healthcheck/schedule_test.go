@@ -0,0 +1,67 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Schedule(t *testing.T) {
+	manager, clock := newStatusTestManager(0)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	schedules := manager.Schedule()
+	require.Len(t, schedules, 1)
+	assert.Equal(t, "api", schedules[0].Name)
+	assert.Equal(t, "fake", schedules[0].Type)
+	assert.Equal(t, float64(30), schedules[0].IntervalSeconds)
+	assert.Nil(t, schedules[0].LastRun)
+	assert.Nil(t, schedules[0].NextRun)
+
+	manager.runSingleHealthcheck(s)
+
+	schedules = manager.Schedule()
+	require.NotNil(t, schedules[0].LastRun)
+	assert.Equal(t, clock.now, *schedules[0].LastRun)
+	assert.Nil(t, schedules[0].NextRun)
+}
+
+func TestManager_Schedule_TracksNextRun(t *testing.T) {
+	manager, clock := newStatusTestManager(0)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	state := manager.states[s]
+
+	manager.startScraper(s, state)
+	defer func() {
+		close(state.stop)
+		<-state.done
+	}()
+
+	require.Eventually(t, func() bool {
+		schedule := manager.Schedule()[0]
+		return schedule.LastRun != nil && schedule.NextRun != nil && schedule.NextRun.After(clock.now)
+	}, time.Second, 5*time.Millisecond)
+
+	schedule := manager.Schedule()[0]
+	assert.Equal(t, float64(30), schedule.IntervalSeconds)
+	assert.Equal(t, clock.now.Add(30*time.Second), *schedule.NextRun)
+}
+
+func TestManager_ScheduleHandler(t *testing.T) {
+	manager, _ := newStatusTestManager(0)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	recorder := httptest.NewRecorder()
+	manager.ScheduleHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/schedule", nil))
+
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var schedules []map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &schedules))
+	assert.Equal(t, []map[string]interface{}{{"name": "api", "type": "fake", "interval_seconds": float64(30)}}, schedules)
+}
@@ -0,0 +1,81 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"healthcheck/pkg/scraper"
+)
+
+// ScrapeAllSyncHandler returns an HTTP handler that scrapes every scraper once, waits for
+// the results and responds with the report. Only one run is served at a time; requests
+// arriving while one is in progress are rejected rather than queued.
+func (m *Manager) ScrapeAllSyncHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !m.scrapeAll.TryLock() {
+			http.Error(w, "a scrape of all scrapers is already in progress", http.StatusTooManyRequests)
+			return
+		}
+		defer m.scrapeAll.Unlock()
+
+		m.logger.Info("Scraping all scrapers on request")
+		report := m.scrapeAllSync()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// scrapeAllSync scrapes every scraper once right away and returns the results in
+// configuration order. The scrapes take the same path as scheduled ones: they share the
+// worker pool, respect the overlap policy, active hours, dependencies and gates, and are
+// recorded, published and pinged like any other result.
+func (m *Manager) scrapeAllSync() Report {
+	m.mu.RLock()
+	scrapers := append([]scraper.Scraper(nil), m.scrapers...)
+	states := make([]*scraperState, len(scrapers))
+	for i, s := range scrapers {
+		states[i] = m.states[s]
+	}
+	m.mu.RUnlock()
+
+	results := make([]CheckResult, len(scrapers))
+	var wg sync.WaitGroup
+	for i, s := range scrapers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.scrapeNow(s, states[i])
+		}()
+	}
+	wg.Wait()
+
+	return newReport(results)
+}
+
+// scrapeNow runs a scrape of the scraper outside its schedule and returns its outcome,
+// skipped when the previous scrape is still running or the scraper is stopped first
+func (m *Manager) scrapeNow(s scraper.Scraper, state *scraperState) CheckResult {
+	skipped := CheckResult{Name: state.config.Name, Type: s.Type(), Skipped: true}
+	if !m.beginScrape(s, state) {
+		skipped.Message = "Scrape skipped, the previous scrape is still running"
+		return skipped
+	}
+
+	state.scrapes.Add(1)
+	defer state.scrapes.Done()
+
+	result, ran := m.runScrapes(s, state)
+	if !ran {
+		skipped.Message = "Scrape skipped, the scraper was stopped"
+		return skipped
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_ScrapeAllSyncHandler(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false)
+
+	recorder := httptest.NewRecorder()
+	manager.ScrapeAllSyncHandler().ServeHTTP(recorder, httptest.NewRequest("POST", "/scrape-all-sync", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "api", report.Results[0].Name)
+	assert.True(t, report.Results[0].Healthy)
+	assert.Equal(t, "db", report.Results[1].Name)
+	assert.False(t, report.Results[1].Healthy)
+	assert.Equal(t, 1, api.calls)
+	assert.Equal(t, 1, db.calls)
+}
+
+func TestManager_ScrapeAllSync_RecordsResults(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	sink := &fakeSink{}
+	manager.AddSink(sink)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false)
+
+	manager.scrapeAllSync()
+	manager.sinks.stop()
+
+	statuses := manager.Status()
+	assert.Equal(t, StatusHealthy, statuses[0].Status)
+	assert.Equal(t, StatusUnhealthy, statuses[1].Status)
+	assert.Len(t, sink.published(), 2)
+}
+
+func TestManager_ScrapeAllSync_SkipsLikeScheduledScrapes(t *testing.T) {
+	gate := newGateServer(t, http.StatusServiceUnavailable, "")
+	manager := NewManager(&config.Config{}, logrus.New())
+	gated := addFakeScraper(manager, config.HealthcheckScraper{Name: "replica", GateURL: gate.URL}, false)
+	busy := addFakeScraper(manager, config.HealthcheckScraper{Name: "busy"}, false)
+	require.True(t, manager.beginScrape(busy, manager.states[busy]))
+
+	report := manager.scrapeAllSync()
+
+	// Neither was scraped, so nothing failed
+	assert.True(t, report.Healthy)
+	require.Len(t, report.Results, 2)
+	assert.True(t, report.Results[0].Skipped)
+	assert.Contains(t, report.Results[0].Message, "gate")
+	assert.True(t, report.Results[1].Skipped)
+	assert.Equal(t, "Scrape skipped, the previous scrape is still running", report.Results[1].Message)
+	assert.Equal(t, 0, gated.calls)
+	assert.Equal(t, 0, busy.calls)
+}
+
+func TestManager_ScrapeAllSync_SharesWorkerPool(t *testing.T) {
+	manager := NewManager(&config.Config{MaxConcurrentScrapes: 2}, logrus.New())
+	tracker := &concurrencyTracker{}
+	for i := 0; i < 4; i++ {
+		s := &trackingScraper{fakeScraper: fakeScraper{healthy: []bool{true}}, tracker: tracker}
+		manager.scrapers = append(manager.scrapers, s)
+		manager.states[s] = newScraperState(config.HealthcheckScraper{Type: "fake"})
+	}
+	// A scheduled scrape holds one of the two workers throughout
+	require.True(t, manager.pool.acquire(manager.stopChan, nil))
+	report := manager.scrapeAllSync()
+	manager.pool.release()
+
+	assert.True(t, report.Healthy)
+	assert.Equal(t, 4, tracker.total)
+	assert.Equal(t, 1, tracker.max)
+}
+
+func TestManager_ScrapeAllSyncHandler_MethodNotAllowed(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	recorder := httptest.NewRecorder()
+	manager.ScrapeAllSyncHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/scrape-all-sync", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, "POST", recorder.Header().Get("Allow"))
+	assert.Equal(t, 0, s.calls)
+}
+
+func TestManager_ScrapeAllSyncHandler_RejectsConcurrentRuns(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	s := newSlowScraper()
+	manager.scrapers = append(manager.scrapers, s)
+	manager.states[s] = newScraperState(config.HealthcheckScraper{Name: "slow", Type: "fake"})
+	handler := manager.ScrapeAllSyncHandler()
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, httptest.NewRequest("POST", "/scrape-all-sync", nil))
+		close(done)
+	}()
+	<-s.started
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, httptest.NewRequest("POST", "/scrape-all-sync", nil))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+
+	close(s.release)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 1, s.calls)
+}
@@ -0,0 +1,77 @@
+package healthcheck
+
+import (
+	"sort"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// groupScraperType identifies group pings in the logs of the notification dispatcher
+const groupScraperType = "group"
+
+// scraperGroup returns the definition of the named scraper group
+func (m *Manager) scraperGroup(name string) (config.ScraperGroup, bool) {
+	for _, group := range m.config.Groups {
+		if group.Name == name {
+			return group, true
+		}
+	}
+	return config.ScraperGroup{}, false
+}
+
+// evaluateGroup re-evaluates the group of a scraper after one of its scrapes. The group is
+// healthy once the latest result of every member is healthy, so members without a result yet
+// keep it unhealthy. Its group_ping_url is pinged each time the group turns healthy, and
+// both transitions are logged. Members are scraped concurrently, so the scan and the
+// transition happen under groupHealthMu to keep a stale scan from overwriting a newer one.
+func (m *Manager) evaluateGroup(state *scraperState) {
+	if state == nil || state.config.Group == "" {
+		return
+	}
+	group, ok := m.scraperGroup(state.config.Group)
+	if !ok {
+		return
+	}
+
+	m.groupHealthMu.Lock()
+	var unhealthy []string
+	m.mu.RLock()
+	for _, member := range m.states {
+		if member.config.Group != group.Name {
+			continue
+		}
+		member.mu.Lock()
+		if member.lastScrape.IsZero() || !member.healthy {
+			unhealthy = append(unhealthy, member.config.Name)
+		}
+		member.mu.Unlock()
+	}
+	m.mu.RUnlock()
+	healthy := len(unhealthy) == 0
+	changed := m.groupHealthy[group.Name] != healthy
+	m.groupHealthy[group.Name] = healthy
+	m.groupHealthMu.Unlock()
+	if !changed {
+		return
+	}
+
+	if !healthy {
+		sort.Strings(unhealthy)
+		m.logger.WithFields(logrus.Fields{
+			"group":     group.Name,
+			"unhealthy": unhealthy,
+		}).Warn("Scraper group became unhealthy")
+		return
+	}
+
+	m.logger.WithField("group", group.Name).Info("Scraper group became healthy, pinging group URL")
+	m.dispatcher.dispatch(notification{
+		scraperType: groupScraperType,
+		url:         group.GroupPingURL,
+		deliver: func() {
+			m.pingSuccessURL(group.GroupPingURL, defaultPingTimeout)
+		},
+	})
+}
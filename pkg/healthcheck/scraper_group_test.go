@@ -0,0 +1,127 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGroupTestManager creates a manager with the checkout group pinging a server that counts
+// the pings
+func newGroupTestManager(t *testing.T, logger *logrus.Logger) (*Manager, *atomic.Int32) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	manager := NewManager(&config.Config{
+		Groups: []config.ScraperGroup{{Name: "checkout", GroupPingURL: server.URL}},
+	}, logger)
+	manager.dispatcher.start()
+	return manager, &pings
+}
+
+func TestManager_Group_FullyHealthy(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager, pings := newGroupTestManager(t, logger)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", Group: "checkout"}, true)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db", Group: "checkout"}, true)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "other"}, false)
+
+	// The group waits for a result of every member
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+	// Staying healthy does not ping again
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+	manager.dispatcher.stop()
+
+	assert.Equal(t, int32(1), pings.Load())
+	assert.Equal(t, 1, countLogs(hook, "Scraper group became healthy, pinging group URL"))
+}
+
+func TestManager_Group_PartiallyUnhealthy(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager, pings := newGroupTestManager(t, logger)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", Group: "checkout"}, true, true, true)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db", Group: "checkout"}, false, true, false)
+
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+	assert.Zero(t, pings.Load())
+
+	// db recovers and the group turns healthy, then db fails again
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+	manager.dispatcher.stop()
+
+	assert.Equal(t, int32(1), pings.Load())
+	require.Equal(t, 1, countLogs(hook, "Scraper group became unhealthy"))
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Scraper group became unhealthy" {
+			assert.Equal(t, "checkout", entry.Data["group"])
+			assert.Equal(t, []string{"db"}, entry.Data["unhealthy"])
+		}
+	}
+}
+
+func TestManager_Group_PingsOnEveryRecovery(t *testing.T) {
+	manager, pings := newGroupTestManager(t, logrus.New())
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api", Group: "checkout"}, true, false, true)
+
+	for range 3 {
+		manager.runSingleHealthcheck(api)
+	}
+	manager.dispatcher.stop()
+
+	assert.Equal(t, int32(2), pings.Load())
+}
+
+func TestManager_Group_ConcurrentMembers(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager, pings := newGroupTestManager(t, logger)
+	var members []*fakeScraper
+	for _, name := range []string{"api", "db", "cache", "queue"} {
+		members = append(members, addFakeScraper(manager, config.HealthcheckScraper{Name: name, Group: "checkout"}, true))
+	}
+
+	// Members finishing their scrapes at the same time turn the group healthy only once
+	var wg sync.WaitGroup
+	for _, member := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.runSingleHealthcheck(member)
+		}()
+	}
+	wg.Wait()
+	manager.dispatcher.stop()
+
+	assert.Equal(t, int32(1), pings.Load())
+	assert.Equal(t, 1, countLogs(hook, "Scraper group became healthy, pinging group URL"))
+}
+
+func TestManager_Reload_UnknownGroup(t *testing.T) {
+	manager := NewManager(&config.Config{
+		Groups: []config.ScraperGroup{{Name: "checkout", GroupPingURL: "http://example.com/ping"}},
+	}, logrus.New())
+
+	err := manager.Reload([]config.HealthcheckScraper{
+		{Name: "api", Type: "http", ScrapeURL: "http://localhost:8080/health", Group: "payments"},
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, "scraper api is in unknown group payments", err.Error())
+}
@@ -0,0 +1,248 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultSinkQueueSize is how many results are queued for each sink before results are dropped
+	defaultSinkQueueSize = 100
+	// DefaultSinkBatchSize is how many results a batch sink receives at once unless configured otherwise
+	DefaultSinkBatchSize = 100
+	// DefaultSinkFlushInterval is how long results wait for a batch to fill unless configured otherwise
+	DefaultSinkFlushInterval = time.Second
+	// sinkQueueBatches is how many batches of results are queued for a batch sink before results
+	// are dropped
+	sinkQueueBatches = 10
+)
+
+// ResultSink receives the result of every scrape, such as an exporter of metrics or a message
+// queue. Each sink is published to from a goroutine of its own in the order of the scrapes,
+// so a slow sink delays neither the scrapes nor the other sinks. Results must not be modified.
+type ResultSink interface {
+	Publish(name string, r scraper.ScrapeResult) error
+}
+
+// BatchResultSink receives the results of scrapes in batches, such as a database inserting
+// many rows in one statement or a message queue producing many messages in one request.
+// Results must not be modified.
+type BatchResultSink interface {
+	PublishBatch(results []PublishedResult) error
+}
+
+// PublishedResult is the result of a scrape of the named scraper
+type PublishedResult struct {
+	Name   string
+	Result scraper.ScrapeResult
+}
+
+// sinkQueue holds the results queued for one sink, which is either a sink of single results
+// or a batch sink
+type sinkQueue struct {
+	sink      ResultSink
+	batchSink BatchResultSink
+	// batchSize and flushInterval bound the batches of a batch sink
+	batchSize     int
+	flushInterval time.Duration
+	queue         chan PublishedResult
+}
+
+// sinkFanOut publishes every result to all sinks without blocking the scrapes, dropping
+// results for a sink whose queue is full
+type sinkFanOut struct {
+	logger *logrus.Logger
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	queues  []*sinkQueue
+	stopped bool
+}
+
+// newSinkFanOut creates a fan-out without sinks
+func newSinkFanOut(logger *logrus.Logger) *sinkFanOut {
+	return &sinkFanOut{logger: logger}
+}
+
+// add starts publishing to the sink
+func (f *sinkFanOut) add(sink ResultSink) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := &sinkQueue{sink: sink, queue: make(chan PublishedResult, defaultSinkQueueSize)}
+	f.queues = append(f.queues, q)
+	f.wg.Add(1)
+	go f.deliver(q)
+}
+
+// addBatch starts publishing to the batch sink in batches of up to batchSize results, at least
+// every flushInterval
+func (f *sinkFanOut) addBatch(sink BatchResultSink, batchSize int, flushInterval time.Duration) {
+	if batchSize <= 0 {
+		batchSize = DefaultSinkBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = DefaultSinkFlushInterval
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := &sinkQueue{
+		batchSink:     sink,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		queue:         make(chan PublishedResult, batchSize*sinkQueueBatches),
+	}
+	f.queues = append(f.queues, q)
+	f.wg.Add(1)
+	go f.deliverBatches(q)
+}
+
+// publish queues the result for every sink without blocking
+func (f *sinkFanOut) publish(name string, result scraper.ScrapeResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stopped {
+		return
+	}
+
+	for _, q := range f.queues {
+		select {
+		case q.queue <- PublishedResult{Name: name, Result: result}:
+		default:
+			f.logger.WithFields(logrus.Fields{
+				"name":       name,
+				"sink":       q.name(),
+				"queue_size": cap(q.queue),
+			}).Warn("Result sink queue full, dropping result")
+		}
+	}
+}
+
+// stop stops accepting results and waits for the queued ones to be published
+func (f *sinkFanOut) stop() {
+	f.mu.Lock()
+	if f.stopped {
+		f.mu.Unlock()
+		return
+	}
+	f.stopped = true
+	for _, q := range f.queues {
+		close(q.queue)
+	}
+	f.mu.Unlock()
+
+	f.wg.Wait()
+}
+
+// deliver publishes the queued results to the sink until its queue is closed
+func (f *sinkFanOut) deliver(q *sinkQueue) {
+	defer f.wg.Done()
+
+	for published := range q.queue {
+		f.publishTo(q.sink, published)
+	}
+}
+
+// deliverBatches publishes the queued results to the batch sink once a batch is full or the
+// flush interval elapses, and the last batch once the queue is closed
+func (f *sinkFanOut) deliverBatches(q *sinkQueue) {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(q.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]PublishedResult, 0, q.batchSize)
+	for {
+		select {
+		case published, ok := <-q.queue:
+			if !ok {
+				if len(batch) > 0 {
+					f.publishBatchTo(q.batchSink, batch)
+				}
+				return
+			}
+			batch = append(batch, published)
+			if len(batch) >= q.batchSize {
+				f.publishBatchTo(q.batchSink, batch)
+				batch = make([]PublishedResult, 0, q.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				f.publishBatchTo(q.batchSink, batch)
+				batch = make([]PublishedResult, 0, q.batchSize)
+			}
+		}
+	}
+}
+
+// publishTo publishes one result, logging errors and recovering from panics so a broken sink
+// cannot take the manager down
+func (f *sinkFanOut) publishTo(sink ResultSink, published PublishedResult) {
+	logger := f.logger.WithFields(logrus.Fields{
+		"name": published.Name,
+		"sink": sinkName(sink),
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.WithField("panic", fmt.Sprint(recovered)).Error("Result sink panicked")
+		}
+	}()
+
+	if err := sink.Publish(published.Name, published.Result); err != nil {
+		logger.WithError(err).Warn("Failed to publish result")
+	}
+}
+
+// publishBatchTo publishes a batch of results like publishTo publishes one
+func (f *sinkFanOut) publishBatchTo(sink BatchResultSink, batch []PublishedResult) {
+	logger := f.logger.WithFields(logrus.Fields{
+		"results": len(batch),
+		"sink":    sinkName(sink),
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.WithField("panic", fmt.Sprint(recovered)).Error("Result sink panicked")
+		}
+	}()
+
+	if err := sink.PublishBatch(batch); err != nil {
+		logger.WithError(err).Warn("Failed to publish results")
+	}
+}
+
+// publishResult publishes the outcome of a scrape to the sinks, as an unhealthy result with
+// the error as its message when the scrape failed
+func (m *Manager) publishResult(name string, s scraper.Scraper, result *scraper.ScrapeResult, err error, latency time.Duration) {
+	var published scraper.ScrapeResult
+	if err != nil {
+		published = scraper.ScrapeResult{Healthy: false, Message: err.Error(), Timestamp: m.now()}
+	} else {
+		published = *result
+	}
+	published.Type = s.Type()
+	published.Duration = latency
+	m.sinks.publish(name, published)
+}
+
+// name identifies the sink of the queue in logs
+func (q *sinkQueue) name() string {
+	if q.batchSink != nil {
+		return sinkName(q.batchSink)
+	}
+	return sinkName(q.sink)
+}
+
+// sinkName identifies a sink in logs by its type
+func sinkName(sink interface{}) string {
+	return fmt.Sprintf("%T", sink)
+}
@@ -0,0 +1,297 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSink captures published results, failing or panicking when configured to
+type fakeSink struct {
+	mu      sync.Mutex
+	names   []string
+	results []scraper.ScrapeResult
+	err     error
+	panics  bool
+	// block holds Publish until it is closed when set
+	block chan struct{}
+}
+
+func (f *fakeSink) Publish(name string, r scraper.ScrapeResult) error {
+	if f.block != nil {
+		<-f.block
+	}
+	if f.panics {
+		panic("sink exploded")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.names = append(f.names, name)
+	f.results = append(f.results, r)
+	return f.err
+}
+
+func (f *fakeSink) published() []scraper.ScrapeResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]scraper.ScrapeResult(nil), f.results...)
+}
+
+// fakeBatchSink captures published batches, panicking when configured to
+type fakeBatchSink struct {
+	mu      sync.Mutex
+	batches [][]PublishedResult
+	panics  bool
+	// block holds PublishBatch until it is closed when set
+	block chan struct{}
+}
+
+func (f *fakeBatchSink) PublishBatch(results []PublishedResult) error {
+	if f.block != nil {
+		<-f.block
+	}
+	if f.panics {
+		panic("sink exploded")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, append([]PublishedResult(nil), results...))
+	return nil
+}
+
+func (f *fakeBatchSink) published() [][]PublishedResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]PublishedResult(nil), f.batches...)
+}
+
+// failingScraper is a scraper whose scrapes fail with an error
+type failingScraper struct {
+	fakeScraper
+}
+
+func (f *failingScraper) Scrape(ctx context.Context) (*scraper.ScrapeResult, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestManager_FansOutResultsToSinks(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	first, second := &fakeSink{}, &fakeSink{}
+	manager.AddSink(first)
+	manager.AddSink(second)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true, false)
+	s.messages = []string{"up", "down"}
+
+	manager.runSingleHealthcheck(s)
+	manager.runSingleHealthcheck(s)
+	manager.sinks.stop()
+
+	for _, sink := range []*fakeSink{first, second} {
+		results := sink.published()
+		require.Len(t, results, 2)
+		assert.Equal(t, []string{"api", "api"}, sink.names)
+		assert.True(t, results[0].Healthy)
+		assert.Equal(t, "up", results[0].Message)
+		assert.Equal(t, "fake", results[0].Type)
+		assert.Positive(t, results[0].Duration)
+		assert.False(t, results[1].Healthy)
+		assert.Equal(t, "down", results[1].Message)
+	}
+}
+
+func TestManager_PublishesFailedScrapes(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	sink := &fakeSink{}
+	manager.AddSink(sink)
+	s := &failingScraper{}
+	manager.scrapers = append(manager.scrapers, s)
+	manager.states[s] = newScraperState(config.HealthcheckScraper{Name: "db"})
+
+	manager.runSingleHealthcheck(s)
+	manager.sinks.stop()
+
+	results := sink.published()
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Healthy)
+	assert.Equal(t, "connection refused", results[0].Message)
+	assert.Equal(t, "fake", results[0].Type)
+	assert.False(t, results[0].Timestamp.IsZero())
+}
+
+func TestSinkFanOut_SinkFailuresAreIsolated(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	fanOut := newSinkFanOut(logger)
+	panicking := &fakeSink{panics: true}
+	failing := &fakeSink{err: errors.New("disk full")}
+	healthy := &fakeSink{}
+	fanOut.add(panicking)
+	fanOut.add(failing)
+	fanOut.add(healthy)
+
+	fanOut.publish("api", scraper.ScrapeResult{Healthy: true})
+	fanOut.publish("api", scraper.ScrapeResult{Healthy: false})
+	fanOut.stop()
+
+	assert.Len(t, healthy.published(), 2)
+	assert.Len(t, failing.published(), 2)
+	assert.Equal(t, 2, countLogs(hook, "Result sink panicked"))
+	assert.Equal(t, 2, countLogs(hook, "Failed to publish result"))
+}
+
+func TestSinkFanOut_DropsWhenSinkQueueFull(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	fanOut := newSinkFanOut(logger)
+	slow := &fakeSink{block: make(chan struct{})}
+	fanOut.add(slow)
+
+	// Publishing never waits for the blocked sink, whose queue overflows
+	done := make(chan struct{})
+	go func() {
+		for range defaultSinkQueueSize + 10 {
+			fanOut.publish("api", scraper.ScrapeResult{Healthy: true})
+		}
+		close(done)
+	}()
+	assertClosed(t, done, "publishing blocked on a slow sink")
+
+	close(slow.block)
+	fanOut.stop()
+
+	// The queue and the result held by the blocked sink are published, the rest dropped
+	assert.LessOrEqual(t, len(slow.published()), defaultSinkQueueSize+1)
+	assert.Positive(t, countLogs(hook, "Result sink queue full, dropping result"))
+}
+
+func TestSinkFanOut_IgnoresResultsAfterStop(t *testing.T) {
+	fanOut := newSinkFanOut(logrus.New())
+	sink := &fakeSink{}
+	fanOut.add(sink)
+	fanOut.stop()
+
+	fanOut.publish("api", scraper.ScrapeResult{Healthy: true})
+	fanOut.stop()
+
+	assert.Empty(t, sink.published())
+}
+
+func TestManager_StopPublishesQueuedResults(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	sink := &fakeSink{block: make(chan struct{})}
+	manager.AddSink(sink)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+
+	manager.runSingleHealthcheck(s)
+	time.AfterFunc(20*time.Millisecond, func() { close(sink.block) })
+	manager.Stop()
+
+	assert.Len(t, sink.published(), 1)
+}
+
+func TestSinkFanOut_BatchesBySize(t *testing.T) {
+	fanOut := newSinkFanOut(logrus.New())
+	sink := &fakeBatchSink{}
+	fanOut.addBatch(sink, 2, time.Hour)
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		fanOut.publish(name, scraper.ScrapeResult{Healthy: true})
+	}
+	require.Eventually(t, func() bool { return len(sink.published()) == 2 }, time.Second, 5*time.Millisecond)
+
+	// The remainder is published on stop
+	fanOut.stop()
+	batches := sink.published()
+	require.Len(t, batches, 3)
+	assert.Equal(t, []PublishedResult{{Name: "a", Result: scraper.ScrapeResult{Healthy: true}}, {Name: "b", Result: scraper.ScrapeResult{Healthy: true}}}, batches[0])
+	assert.Len(t, batches[1], 2)
+	require.Len(t, batches[2], 1)
+	assert.Equal(t, "e", batches[2][0].Name)
+}
+
+func TestSinkFanOut_FlushesBatchOnInterval(t *testing.T) {
+	fanOut := newSinkFanOut(logrus.New())
+	sink := &fakeBatchSink{}
+	fanOut.addBatch(sink, 100, 10*time.Millisecond)
+	defer fanOut.stop()
+
+	fanOut.publish("api", scraper.ScrapeResult{Healthy: true})
+
+	require.Eventually(t, func() bool { return len(sink.published()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestSinkFanOut_BatchDefaults(t *testing.T) {
+	fanOut := newSinkFanOut(logrus.New())
+	fanOut.addBatch(&fakeBatchSink{}, 0, 0)
+	defer fanOut.stop()
+
+	q := fanOut.queues[0]
+	assert.Equal(t, DefaultSinkBatchSize, q.batchSize)
+	assert.Equal(t, DefaultSinkFlushInterval, q.flushInterval)
+	assert.Equal(t, DefaultSinkBatchSize*sinkQueueBatches, cap(q.queue))
+}
+
+func TestSinkFanOut_DropsWhenBatchQueueFull(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	fanOut := newSinkFanOut(logger)
+	slow := &fakeBatchSink{block: make(chan struct{})}
+	fanOut.addBatch(slow, 1, time.Hour)
+
+	// Publishing never waits for the blocked sink, so its queue stays bounded
+	done := make(chan struct{})
+	go func() {
+		for range sinkQueueBatches + 10 {
+			fanOut.publish("api", scraper.ScrapeResult{Healthy: true})
+		}
+		close(done)
+	}()
+	assertClosed(t, done, "publishing blocked on a slow batch sink")
+
+	close(slow.block)
+	fanOut.stop()
+
+	// The queue and the batch held by the blocked sink are published, the rest dropped
+	assert.LessOrEqual(t, len(slow.published()), sinkQueueBatches+1)
+	assert.Positive(t, countLogs(hook, "Result sink queue full, dropping result"))
+}
+
+func TestSinkFanOut_BatchSinkPanicIsRecovered(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	fanOut := newSinkFanOut(logger)
+	fanOut.addBatch(&fakeBatchSink{panics: true}, 1, time.Hour)
+	healthy := &fakeSink{}
+	fanOut.add(healthy)
+
+	fanOut.publish("api", scraper.ScrapeResult{Healthy: true})
+	fanOut.stop()
+
+	assert.Len(t, healthy.published(), 1)
+	assert.Equal(t, 1, countLogs(hook, "Result sink panicked"))
+}
+
+func TestManager_StopPublishesLastBatch(t *testing.T) {
+	manager := NewManager(&config.Config{}, logrus.New())
+	sink := &fakeBatchSink{}
+	manager.AddBatchSink(sink, 100, time.Hour)
+	s := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true, true)
+
+	manager.runSingleHealthcheck(s)
+	manager.runSingleHealthcheck(s)
+	manager.Stop()
+
+	batches := sink.published()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 2)
+	assert.Equal(t, "api", batches[0][0].Name)
+	assert.Equal(t, "fake", batches[0][1].Result.Type)
+}
@@ -0,0 +1,54 @@
+package healthcheck
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogStartup emits a single structured startup event describing what is running: the version,
+// the scrapers, the endpoints served and the selected global options
+func (m *Manager) LogStartup(version string) {
+	m.mu.RLock()
+	types := make(map[string]int)
+	for _, s := range m.scrapers {
+		types[s.Type()]++
+	}
+	scrapers := len(m.scrapers)
+	m.mu.RUnlock()
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// The status, history and synchronous scrape endpoints are served along with the metrics,
+	// and so is the dashboard if enabled
+	endpoints := []string{}
+	if m.config.MetricsAddress != "" {
+		if m.config.EnableStatusUI {
+			endpoints = append(endpoints, "/")
+		}
+		endpoints = append(endpoints, "/metrics", "/status", "/schedule", "/health", "/history.csv", "/scrape-all-sync")
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"event":                   "startup",
+		"version":                 version,
+		"scrapers":                scrapers,
+		"scraper_types":           names,
+		"metrics_address":         m.config.MetricsAddress,
+		"endpoints":               endpoints,
+		"otlp_endpoint":           m.config.OTLPEndpoint,
+		"kafka_topic":             m.config.KafkaTopic,
+		"sequential":              m.config.Sequential,
+		"max_concurrent_scrapes":  m.config.MaxConcurrentScrapes,
+		"notification_workers":    m.dispatcher.workers,
+		"notification_queue_size": cap(m.dispatcher.queue),
+		"initial_scrape_spread":   m.config.InitialScrapeSpread.String(),
+		"shutdown_timeout":        m.config.ShutdownTimeout.String(),
+		"scrape_size_metrics":     m.config.ScrapeSizeMetrics,
+		"discovery_url":           m.config.DiscoveryURL,
+	}).Info("Healthcheck started")
+}
@@ -0,0 +1,66 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_LogStartup(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{
+		MetricsAddress:       ":9090",
+		Sequential:           true,
+		ShutdownTimeout:      10 * time.Second,
+		NotificationWorkers:  2,
+		MaxConcurrentScrapes: 4,
+	}, logger)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, true)
+
+	manager.LogStartup("1.2.3")
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "Healthcheck started", entry.Message)
+	assert.Equal(t, "startup", entry.Data["event"])
+	assert.Equal(t, "1.2.3", entry.Data["version"])
+	assert.Equal(t, 2, entry.Data["scrapers"])
+	assert.Equal(t, []string{"fake"}, entry.Data["scraper_types"])
+	assert.Equal(t, ":9090", entry.Data["metrics_address"])
+	assert.Equal(t, []string{"/metrics", "/status", "/schedule", "/health", "/history.csv", "/scrape-all-sync"}, entry.Data["endpoints"])
+	assert.Equal(t, true, entry.Data["sequential"])
+	assert.Equal(t, 4, entry.Data["max_concurrent_scrapes"])
+	assert.Equal(t, 2, entry.Data["notification_workers"])
+	assert.Equal(t, "10s", entry.Data["shutdown_timeout"])
+}
+
+func TestManager_LogStartup_StatusUI(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{MetricsAddress: ":9090", EnableStatusUI: true}, logger)
+
+	manager.LogStartup("dev")
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, []string{"/", "/metrics", "/status", "/schedule", "/health", "/history.csv", "/scrape-all-sync"}, entry.Data["endpoints"])
+}
+
+func TestManager_LogStartup_NoEndpoints(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+
+	manager.LogStartup("dev")
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, 0, entry.Data["scrapers"])
+	assert.Equal(t, []string{}, entry.Data["endpoints"])
+}
@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"time"
+
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// checkStateChange notifies when a scraper flipped between healthy and unhealthy. With flap
+// dampening, a change is held until the new state has persisted for the dampening window, so
+// a momentary flap that reverts within the window is never notified. Persistence is judged on
+// the scrapes, so a held change fires with the first scrape after the window has passed.
+func (m *Manager) checkStateChange(s scraper.Scraper, healthy bool, message string, details map[string]interface{}) {
+	state := m.state(s)
+	if state == nil || !state.config.NotifyOnStateChange {
+		return
+	}
+
+	now := m.now()
+	window := time.Duration(state.config.FlapDampeningSeconds) * time.Second
+
+	state.mu.Lock()
+	// The first result sets the state changes are compared against
+	if state.notifiedHealthy == nil {
+		state.notifiedHealthy = &healthy
+		state.mu.Unlock()
+		return
+	}
+
+	if healthy == *state.notifiedHealthy {
+		held := !state.changeSince.IsZero()
+		state.changeSince = time.Time{}
+		state.mu.Unlock()
+		if held {
+			m.logger.WithFields(logrus.Fields{
+				"scraper_type": s.Type(),
+				"healthy":      healthy,
+			}).Info("Health change reverted within flap dampening window, not notifying")
+		}
+		return
+	}
+
+	if window > 0 {
+		if state.changeSince.IsZero() {
+			state.changeSince = now
+		}
+		if now.Sub(state.changeSince) < window {
+			state.mu.Unlock()
+			m.logger.WithFields(logrus.Fields{
+				"scraper_type": s.Type(),
+				"healthy":      healthy,
+				"until":        state.changeSince.Add(window),
+			}).Debug("Holding health change for flap dampening window")
+			return
+		}
+	}
+
+	state.notifiedHealthy = &healthy
+	state.changeSince = time.Time{}
+	state.mu.Unlock()
+
+	m.logger.WithFields(logrus.Fields{
+		"scraper_type": s.Type(),
+		"healthy":      healthy,
+		"message":      message,
+	}).Info("Scraper health changed")
+
+	if state.config.NotifyURL == "" {
+		return
+	}
+
+	event := NotificationEvent{
+		Event:       "state_change",
+		Name:        state.config.Name,
+		ScraperType: s.Type(),
+		Healthy:     healthy,
+		Message:     message,
+		Timestamp:   now,
+	}
+	if state.config.NotifyGroup != "" {
+		m.notifyGrouped(s, state, event, details)
+		return
+	}
+	m.notify(s, state, event, details)
+}
@@ -0,0 +1,103 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStateChangeTestServer records the state change events posted to it
+func newStateChangeTestServer(t *testing.T) (*httptest.Server, chan NotificationEvent) {
+	events := make(chan NotificationEvent, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event NotificationEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	t.Cleanup(server.Close)
+	return server, events
+}
+
+// runScrapes runs the scraper's healthchecks 10 seconds apart on the fake clock
+func runScrapes(manager *Manager, clock *fakeClock, s *fakeScraper, count int) {
+	for i := 0; i < count; i++ {
+		manager.runSingleHealthcheck(s)
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+}
+
+func TestManager_StateChange_Notified(t *testing.T) {
+	server, events := newStateChangeTestServer(t)
+	manager, clock := newStatusTestManager(0)
+	s := addFakeScraper(manager, config.HealthcheckScraper{
+		Name:                "api",
+		NotifyURL:           server.URL,
+		NotifyOnStateChange: true,
+	}, true, false)
+	manager.dispatcher.start()
+
+	runScrapes(manager, clock, s, 2)
+	manager.dispatcher.stop()
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, "state_change", event.Event)
+	assert.Equal(t, "api", event.Name)
+	assert.False(t, event.Healthy)
+}
+
+func TestManager_StateChange_FlapSelfResolves(t *testing.T) {
+	server, events := newStateChangeTestServer(t)
+	logger, hook := test.NewNullLogger()
+	manager := NewManager(&config.Config{}, logger)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	manager.now = clock.Now
+	// Unhealthy for 20 seconds, less than the 30 second window
+	s := addFakeScraper(manager, config.HealthcheckScraper{
+		Name:                 "api",
+		NotifyURL:            server.URL,
+		NotifyOnStateChange:  true,
+		FlapDampeningSeconds: 30,
+	}, true, false, false, true)
+	manager.dispatcher.start()
+
+	runScrapes(manager, clock, s, 4)
+	manager.dispatcher.stop()
+
+	assert.Empty(t, events)
+	assert.Equal(t, 1, countLogs(hook, "Health change reverted within flap dampening window, not notifying"))
+	assert.Equal(t, 0, countLogs(hook, "Scraper health changed"))
+}
+
+func TestManager_StateChange_FlapPersists(t *testing.T) {
+	server, events := newStateChangeTestServer(t)
+	manager, clock := newStatusTestManager(0)
+	s := addFakeScraper(manager, config.HealthcheckScraper{
+		Name:                 "api",
+		NotifyURL:            server.URL,
+		NotifyOnStateChange:  true,
+		FlapDampeningSeconds: 30,
+	}, true, false, false, false, false, false)
+	manager.dispatcher.start()
+
+	// Held while the change is younger than the window
+	runScrapes(manager, clock, s, 4)
+	assert.Empty(t, events)
+
+	// Fires once the unhealthy state persisted for the window, and only once
+	runScrapes(manager, clock, s, 2)
+	manager.dispatcher.stop()
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, "state_change", event.Event)
+	assert.False(t, event.Healthy)
+}
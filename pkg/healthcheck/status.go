@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Status values reported for a scraper
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+	// StatusStale is reported once the latest result is older than the scraper's result TTL
+	StatusStale = "stale"
+	// StatusUnknown is reported before the scraper's first result
+	StatusUnknown = "unknown"
+	// StatusInactive is reported while the scraper is outside its active hours
+	StatusInactive = "inactive"
+	// StatusSkipped is reported while the scraper's gate is closed
+	StatusSkipped = "skipped"
+)
+
+// ScraperStatus is the current status of a scraper as served by the status endpoint
+type ScraperStatus struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	ActiveHours string     `json:"active_hours,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	LastScrape  *time.Time `json:"last_scrape,omitempty"`
+	Flaps       int        `json:"flaps"`
+}
+
+// Status returns the status of every running scraper. A result is only reported until its
+// TTL of the scrape interval times the status TTL factor has passed, after which the
+// scraper is reported stale rather than keep showing its last outcome. Scrapers outside
+// their active hours are reported inactive, and those whose gate is closed skipped until their
+// latest skip outlives the TTL as well.
+func (m *Manager) Status() []ScraperStatus {
+	window := m.flapWindow()
+	factor := m.config.StatusTTLFactor
+	if factor <= 0 {
+		factor = defaultStatusTTLFactor
+	}
+	now := m.now()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]ScraperStatus, 0, len(m.scrapers))
+	for _, s := range m.scrapers {
+		state := m.states[s]
+		status := ScraperStatus{
+			Name:        state.config.Name,
+			Type:        s.Type(),
+			Status:      StatusUnknown,
+			ActiveHours: state.config.ActiveHours,
+		}
+
+		ttl := scrapeInterval(s, state.config) * time.Duration(factor)
+
+		state.mu.Lock()
+		status.Flaps = state.flaps.count(now, window)
+		lastScrape := state.lastScrape
+		if !lastScrape.IsZero() {
+			status.LastScrape = &lastScrape
+			status.Message = state.lastMessage
+		}
+		switch {
+		case state.activeHours != nil && !state.activeHours.contains(now):
+			status.Status = StatusInactive
+		case state.skipped && now.Sub(state.lastSkip) <= ttl:
+			status.Status = StatusSkipped
+			status.Message = state.skipMessage
+		case lastScrape.IsZero():
+			// Unknown until the first result
+		case now.Sub(lastScrape) > ttl:
+			status.Status = StatusStale
+		case state.healthy:
+			status.Status = StatusHealthy
+		default:
+			status.Status = StatusUnhealthy
+		}
+		state.mu.Unlock()
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// StatusHandler returns an HTTP handler serving the status of every scraper as JSON
+func (m *Manager) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(m.Status())
+	})
+}
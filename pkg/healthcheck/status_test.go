@@ -0,0 +1,97 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for the manager
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// newStatusTestManager creates a manager with a fake clock and the given TTL factor
+func newStatusTestManager(factor int) (*Manager, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	manager := NewManager(&config.Config{StatusTTLFactor: factor}, logrus.New())
+	manager.now = clock.Now
+	return manager, clock
+}
+
+func TestManager_Status(t *testing.T) {
+	manager, clock := newStatusTestManager(0)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	db := addFakeScraper(manager, config.HealthcheckScraper{Name: "db"}, false)
+	addFakeScraper(manager, config.HealthcheckScraper{Name: "queue"}, true)
+
+	manager.runSingleHealthcheck(api)
+	manager.runSingleHealthcheck(db)
+
+	statuses := manager.Status()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "api", statuses[0].Name)
+	assert.Equal(t, "fake", statuses[0].Type)
+	assert.Equal(t, StatusHealthy, statuses[0].Status)
+	assert.Equal(t, "fake result", statuses[0].Message)
+	assert.Equal(t, clock.now, *statuses[0].LastScrape)
+	assert.Equal(t, StatusUnhealthy, statuses[1].Status)
+	assert.Equal(t, StatusUnknown, statuses[2].Status)
+	assert.Nil(t, statuses[2].LastScrape)
+}
+
+func TestManager_Status_StaleAfterTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		factor  int
+		elapsed time.Duration
+		status  string
+	}{
+		{name: "within default TTL", elapsed: 90 * time.Second, status: StatusHealthy},
+		{name: "past default TTL", elapsed: 91 * time.Second, status: StatusStale},
+		{name: "within configured TTL", factor: 5, elapsed: 150 * time.Second, status: StatusHealthy},
+		{name: "past configured TTL", factor: 1, elapsed: 31 * time.Second, status: StatusStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, clock := newStatusTestManager(tt.factor)
+			api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+			manager.runSingleHealthcheck(api)
+
+			clock.now = clock.now.Add(tt.elapsed)
+
+			statuses := manager.Status()
+			require.Len(t, statuses, 1)
+			assert.Equal(t, tt.status, statuses[0].Status)
+			assert.Equal(t, "fake result", statuses[0].Message)
+		})
+	}
+}
+
+func TestManager_StatusHandler(t *testing.T) {
+	manager, _ := newStatusTestManager(0)
+	api := addFakeScraper(manager, config.HealthcheckScraper{Name: "api"}, true)
+	manager.runSingleHealthcheck(api)
+
+	recorder := httptest.NewRecorder()
+	manager.StatusHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/status", nil))
+
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var statuses []ScraperStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "api", statuses[0].Name)
+	assert.Equal(t, StatusHealthy, statuses[0].Status)
+}
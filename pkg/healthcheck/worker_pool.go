@@ -0,0 +1,86 @@
+package healthcheck
+
+import (
+	"healthcheck/pkg/metrics"
+)
+
+var (
+	workerPoolActive = metrics.DefaultRegistry.NewGauge(
+		"healthcheck_worker_pool_active",
+		"Scheduled scrapes currently running.",
+	)
+	workerPoolQueued = metrics.DefaultRegistry.NewGauge(
+		"healthcheck_worker_pool_queued",
+		"Scheduled scrapes waiting for a free worker.",
+	)
+	workerPoolWaited = metrics.DefaultRegistry.NewCounterVec(
+		"healthcheck_worker_pool_waited_total",
+		"Scheduled scrapes that had to wait for a free worker.",
+	)
+)
+
+// workerPool bounds the number of scheduled scrapes running at once to max_concurrent_scrapes
+// and reports its saturation
+type workerPool struct {
+	// slots holds a token per running scrape, nil when the pool is unbounded
+	slots  chan struct{}
+	active *metrics.Gauge
+	queued *metrics.Gauge
+	waited *metrics.CounterVec
+}
+
+// newWorkerPool creates a pool running up to limit scrapes at once, unbounded when limit is
+// not positive
+func newWorkerPool(limit int) *workerPool {
+	pool := &workerPool{
+		active: workerPoolActive,
+		queued: workerPoolQueued,
+		waited: workerPoolWaited,
+	}
+	if limit > 0 {
+		pool.slots = make(chan struct{}, limit)
+	}
+	return pool
+}
+
+// acquire waits for a free worker and returns true, or returns false when the manager
+// stops or the scraper is removed first
+func (p *workerPool) acquire(stop, removed <-chan struct{}) bool {
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			p.wait()
+			defer p.stopWaiting()
+
+			select {
+			case p.slots <- struct{}{}:
+			case <-stop:
+				return false
+			case <-removed:
+				return false
+			}
+		}
+	}
+	p.active.Inc()
+	return true
+}
+
+// release frees the worker of a scrape that acquired one
+func (p *workerPool) release() {
+	p.active.Dec()
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// wait records a scrape starting to wait for a free worker
+func (p *workerPool) wait() {
+	p.queued.Inc()
+	p.waited.Inc()
+}
+
+// stopWaiting records a scrape no longer waiting for a worker
+func (p *workerPool) stopWaiting() {
+	p.queued.Dec()
+}
@@ -0,0 +1,76 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWorkerPool creates a pool reporting to metrics of its own registry
+func newTestWorkerPool(limit int) *workerPool {
+	registry := metrics.NewRegistry()
+	pool := newWorkerPool(limit)
+	pool.active = registry.NewGauge("active", "Active.")
+	pool.queued = registry.NewGauge("queued", "Queued.")
+	pool.waited = registry.NewCounterVec("waited_total", "Waited.")
+	return pool
+}
+
+func TestWorkerPool_Saturated(t *testing.T) {
+	pool := newTestWorkerPool(1)
+	stop := make(chan struct{})
+
+	require.True(t, pool.acquire(stop, nil))
+	assert.Equal(t, 1.0, pool.active.Value())
+
+	acquired := make(chan bool)
+	go func() {
+		acquired <- pool.acquire(stop, nil)
+	}()
+
+	require.Eventually(t, func() bool {
+		return pool.queued.Value() == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 1.0, pool.waited.Value())
+
+	pool.release()
+	assert.True(t, <-acquired)
+	assert.Equal(t, 1.0, pool.active.Value())
+	assert.Equal(t, 0.0, pool.queued.Value())
+
+	pool.release()
+	assert.Equal(t, 0.0, pool.active.Value())
+}
+
+func TestWorkerPool_StopWhileWaiting(t *testing.T) {
+	pool := newTestWorkerPool(1)
+	removed := make(chan struct{})
+	require.True(t, pool.acquire(nil, removed))
+
+	close(removed)
+
+	assert.False(t, pool.acquire(nil, removed))
+	assert.Equal(t, 1.0, pool.active.Value())
+	assert.Equal(t, 0.0, pool.queued.Value())
+}
+
+func TestWorkerPool_Unbounded(t *testing.T) {
+	pool := newTestWorkerPool(0)
+
+	for i := 0; i < 10; i++ {
+		require.True(t, pool.acquire(nil, nil))
+	}
+	assert.Equal(t, 10.0, pool.active.Value())
+	assert.Equal(t, 0.0, pool.waited.Value())
+}
+
+func TestManager_MaxConcurrentScrapes(t *testing.T) {
+	tracker := runTrackedScrapers(t, &config.Config{MaxConcurrentScrapes: 2})
+
+	assert.Equal(t, 2, tracker.max)
+}
@@ -0,0 +1,137 @@
+// Package kafka publishes scrape results as JSON messages to a Kafka topic, keyed by scraper
+// name, using a minimal implementation of the Kafka producer protocol.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"healthcheck/pkg/healthcheck"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// produceTimeout bounds each produce request
+	produceTimeout = 10 * time.Second
+	// maxProduceAttempts is how often a batch is produced before it is dropped
+	maxProduceAttempts = 3
+	// retryBackoff is the wait between attempts to produce a batch
+	retryBackoff = 500 * time.Millisecond
+)
+
+// Message is a Kafka record
+type Message struct {
+	Key   []byte
+	Value []byte
+}
+
+// Producer writes batches of messages to a topic
+type Producer interface {
+	// Produce writes the messages to the topic, returning once they are acknowledged
+	Produce(ctx context.Context, topic string, messages []Message) error
+
+	// Close releases the connections of the producer
+	Close() error
+}
+
+// Event is the JSON value of a published scrape result
+type Event struct {
+	Name      string                 `json:"name"`
+	Type      string                 `json:"type"`
+	Healthy   bool                   `json:"healthy"`
+	Message   string                 `json:"message"`
+	Timestamp time.Time              `json:"timestamp"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Skipped   bool                   `json:"skipped,omitempty"`
+}
+
+// Sink produces batches of results to a topic, making it a batch result sink of the
+// healthcheck manager, which queues the results and decides the batches
+type Sink struct {
+	producer Producer
+	topic    string
+	logger   *logrus.Logger
+}
+
+// NewSink creates a sink producing the results to the topic
+func NewSink(producer Producer, topic string, logger *logrus.Logger) *Sink {
+	return &Sink{
+		producer: producer,
+		topic:    topic,
+		logger:   logger,
+	}
+}
+
+// PublishBatch produces the results as one batch of messages, skipping results that cannot
+// be encoded
+func (s *Sink) PublishBatch(results []healthcheck.PublishedResult) error {
+	batch := make([]Message, 0, len(results))
+	var errs []error
+	for _, published := range results {
+		message, err := encodeMessage(published.Name, published.Result)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		batch = append(batch, message)
+	}
+
+	if len(batch) > 0 {
+		s.flush(batch)
+	}
+	return errors.Join(errs...)
+}
+
+// Stop closes the producer once the manager published the last batch
+func (s *Sink) Stop() {
+	if err := s.producer.Close(); err != nil {
+		s.logger.WithError(err).Warn("Failed to close Kafka producer")
+	}
+}
+
+// encodeMessage encodes a result as a JSON message keyed by the scraper name
+func encodeMessage(name string, r scraper.ScrapeResult) (Message, error) {
+	value, err := json.Marshal(Event{
+		Name:      name,
+		Type:      r.Type,
+		Healthy:   r.Healthy,
+		Message:   r.Message,
+		Timestamp: r.Timestamp,
+		Details:   r.Details,
+		Skipped:   r.Skipped,
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode scrape result of %s for Kafka: %w", name, err)
+	}
+	return Message{Key: []byte(name), Value: value}, nil
+}
+
+// flush produces the batch, retrying failed attempts, and drops it once all attempts failed.
+// Results queue up in the manager while the batch is retried.
+func (s *Sink) flush(batch []Message) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"topic":    s.topic,
+		"messages": len(batch),
+	})
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), produceTimeout)
+		err := s.producer.Produce(ctx, s.topic, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt == maxProduceAttempts {
+			logger.WithError(err).Error("Failed to produce scrape results to Kafka, dropping them")
+			return
+		}
+		logger.WithError(err).WithField("attempt", attempt).Warn("Failed to produce scrape results to Kafka, retrying")
+		time.Sleep(retryBackoff)
+	}
+}
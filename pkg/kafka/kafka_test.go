@@ -0,0 +1,161 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/healthcheck"
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProducer records produced batches, failing the first failures attempts
+type mockProducer struct {
+	mu       sync.Mutex
+	batches  [][]Message
+	attempts int
+	failures int
+	closed   bool
+}
+
+func (p *mockProducer) Produce(ctx context.Context, topic string, messages []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.attempts++
+	if p.attempts <= p.failures {
+		return errors.New("broker unavailable")
+	}
+	p.batches = append(p.batches, append([]Message(nil), messages...))
+	return nil
+}
+
+func (p *mockProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	return nil
+}
+
+func (p *mockProducer) produced() [][]Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.batches
+}
+
+func TestSink_PublishesJSONKeyedByName(t *testing.T) {
+	producer := &mockProducer{}
+	sink := NewSink(producer, "health", logrus.New())
+
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	err := sink.PublishBatch([]healthcheck.PublishedResult{{
+		Name: "api",
+		Result: scraper.ScrapeResult{
+			Type:      "http",
+			Healthy:   true,
+			Message:   "HTTP 200",
+			Timestamp: timestamp,
+			Details:   map[string]interface{}{"status_code": 200},
+		},
+	}})
+	require.NoError(t, err)
+	sink.Stop()
+
+	batches := producer.produced()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "api", string(batches[0][0].Key))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(batches[0][0].Value, &event))
+	assert.Equal(t, "api", event.Name)
+	assert.Equal(t, "http", event.Type)
+	assert.True(t, event.Healthy)
+	assert.Equal(t, "HTTP 200", event.Message)
+	assert.True(t, timestamp.Equal(event.Timestamp))
+	assert.Equal(t, float64(200), event.Details["status_code"])
+	assert.True(t, producer.closed)
+}
+
+func TestSink_ProducesBatchInOneRequest(t *testing.T) {
+	producer := &mockProducer{}
+	sink := NewSink(producer, "health", logrus.New())
+
+	require.NoError(t, sink.PublishBatch([]healthcheck.PublishedResult{
+		{Name: "api", Result: scraper.ScrapeResult{Type: "http", Healthy: true}},
+		{Name: "db", Result: scraper.ScrapeResult{Type: "tls", Healthy: false}},
+		{Name: "api", Result: scraper.ScrapeResult{Type: "http", Healthy: false}},
+	}))
+
+	batches := producer.produced()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 3)
+	assert.Equal(t, "api", string(batches[0][0].Key))
+	assert.Equal(t, "db", string(batches[0][1].Key))
+	assert.Equal(t, "api", string(batches[0][2].Key))
+}
+
+func TestSink_RetriesFailedProduce(t *testing.T) {
+	producer := &mockProducer{failures: 1}
+	logger, hook := test.NewNullLogger()
+	sink := NewSink(producer, "health", logger)
+
+	sink.PublishBatch([]healthcheck.PublishedResult{{Name: "api", Result: scraper.ScrapeResult{Type: "http", Healthy: true}}})
+
+	assert.Len(t, producer.produced(), 1)
+	require.NotEmpty(t, hook.AllEntries())
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Failed to produce scrape results to Kafka, retrying", hook.LastEntry().Message)
+}
+
+func TestSink_DropsBatchAfterFailedAttempts(t *testing.T) {
+	producer := &mockProducer{failures: maxProduceAttempts}
+	logger, hook := test.NewNullLogger()
+	sink := NewSink(producer, "health", logger)
+
+	sink.PublishBatch([]healthcheck.PublishedResult{{Name: "api", Result: scraper.ScrapeResult{Type: "http", Healthy: true}}})
+
+	assert.Empty(t, producer.produced())
+	assert.Equal(t, maxProduceAttempts, producer.attempts)
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Failed to produce scrape results to Kafka, dropping them", hook.LastEntry().Message)
+}
+
+func TestSink_SkipsUnencodableResult(t *testing.T) {
+	producer := &mockProducer{}
+	sink := NewSink(producer, "health", logrus.New())
+
+	err := sink.PublishBatch([]healthcheck.PublishedResult{
+		{Name: "broken", Result: scraper.ScrapeResult{Details: map[string]interface{}{"bad": make(chan int)}}},
+		{Name: "api", Result: scraper.ScrapeResult{Type: "http", Healthy: true}},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to encode scrape result of broken for Kafka")
+	batches := producer.produced()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "api", string(batches[0][0].Key))
+}
+
+func TestSink_ProducesNothingWhenNoResultEncodes(t *testing.T) {
+	producer := &mockProducer{}
+	sink := NewSink(producer, "health", logrus.New())
+
+	err := sink.PublishBatch([]healthcheck.PublishedResult{
+		{Name: "broken", Result: scraper.ScrapeResult{Details: map[string]interface{}{"bad": make(chan int)}}},
+	})
+
+	require.Error(t, err)
+	assert.Zero(t, producer.attempts)
+}
@@ -0,0 +1,510 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// apiKeyProduce and apiKeyMetadata identify the requests of the Kafka protocol
+	apiKeyProduce  = 0
+	apiKeyMetadata = 3
+	// produceVersion is the Produce request version, the first using record batches
+	produceVersion = 3
+	// metadataVersion is the Metadata request version
+	metadataVersion = 1
+	// clientID identifies the producer to the brokers
+	clientID = "healthcheck"
+	// acksLeader waits for the partition leader to write the records
+	acksLeader = 1
+	// maxResponseSize guards against reading a corrupt response size
+	maxResponseSize = 64 << 20
+)
+
+// castagnoli is the CRC-32C table of record batch checksums
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// errorNames names the Kafka error codes a producer commonly receives
+var errorNames = map[int16]string{
+	2:  "CORRUPT_MESSAGE",
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	7:  "REQUEST_TIMED_OUT",
+	10: "MESSAGE_TOO_LARGE",
+	19: "NOT_ENOUGH_REPLICAS",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	87: "INVALID_RECORD",
+}
+
+// kafkaError describes a Kafka error code
+func kafkaError(code int16) error {
+	if name, ok := errorNames[code]; ok {
+		return fmt.Errorf("Kafka error %s (%d)", name, code)
+	}
+	return fmt.Errorf("Kafka error %d", code)
+}
+
+// BrokerProducer produces messages to the partition leaders of a topic, choosing the
+// partition from the murmur2 hash of the key like the Java client does. Partition leaders
+// are looked up from the bootstrap brokers on the first produce and again after a failure.
+type BrokerProducer struct {
+	brokers []string
+	dialer  net.Dialer
+
+	// mu serializes requests, which share the connections and metadata
+	mu            sync.Mutex
+	correlationID int32
+	conns         map[string]net.Conn
+	// topic is the topic the partition leaders were looked up for
+	topic   string
+	leaders []string
+}
+
+// NewBrokerProducer creates a producer bootstrapping from the host:port broker addresses
+func NewBrokerProducer(brokers []string) *BrokerProducer {
+	return &BrokerProducer{
+		brokers: brokers,
+		dialer:  net.Dialer{Timeout: 5 * time.Second},
+		conns:   make(map[string]net.Conn),
+	}
+}
+
+// Produce writes the messages to their partitions, returning once every leader acknowledged them
+func (p *BrokerProducer) Produce(ctx context.Context, topic string, messages []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.produce(ctx, topic, messages); err != nil {
+		// Look up the partition leaders again, as they may have moved
+		p.leaders = nil
+		return err
+	}
+	return nil
+}
+
+// Close closes the connections to the brokers
+func (p *BrokerProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for address, conn := range p.conns {
+		errs = append(errs, conn.Close())
+		delete(p.conns, address)
+	}
+	return errors.Join(errs...)
+}
+
+func (p *BrokerProducer) produce(ctx context.Context, topic string, messages []Message) error {
+	if p.leaders == nil || p.topic != topic {
+		leaders, err := p.lookupLeaders(ctx, topic)
+		if err != nil {
+			return err
+		}
+		p.topic, p.leaders = topic, leaders
+	}
+
+	// Group the messages by partition and the partitions by leader
+	byLeader := make(map[string]map[int32][]Message)
+	for _, message := range messages {
+		partition := int32(murmur2(message.Key)&0x7fffffff) % int32(len(p.leaders))
+		leader := p.leaders[partition]
+		if byLeader[leader] == nil {
+			byLeader[leader] = make(map[int32][]Message)
+		}
+		byLeader[leader][partition] = append(byLeader[leader][partition], message)
+	}
+
+	for leader, partitions := range byLeader {
+		if err := p.produceTo(ctx, leader, topic, partitions); err != nil {
+			return fmt.Errorf("broker %s: %w", leader, err)
+		}
+	}
+	return nil
+}
+
+// produceTo sends one Produce request with the messages of the leader's partitions
+func (p *BrokerProducer) produceTo(ctx context.Context, leader, topic string, partitions map[int32][]Message) error {
+	timeoutMs := int32(produceTimeout / time.Millisecond)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeoutMs = int32(max(time.Until(deadline), time.Millisecond) / time.Millisecond)
+	}
+
+	var e encoder
+	e.int16(-1) // transactional_id, null
+	e.int16(acksLeader)
+	e.int32(timeoutMs)
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(partitions)))
+	now := time.Now()
+	for partition, messages := range partitions {
+		e.int32(partition)
+		e.bytes(encodeRecordBatch(messages, now))
+	}
+
+	response, err := p.roundTrip(ctx, leader, apiKeyProduce, produceVersion, e.buf)
+	if err != nil {
+		return err
+	}
+
+	d := decoder{buf: response}
+	for range d.arrayLen() {
+		d.string()
+		for range d.arrayLen() {
+			partition := d.int32()
+			code := d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time
+			if d.err == nil && code != 0 {
+				return fmt.Errorf("partition %d: %w", partition, kafkaError(code))
+			}
+		}
+	}
+	return d.err
+}
+
+// lookupLeaders returns the address of each partition's leader, indexed by partition,
+// from the first bootstrap broker that answers
+func (p *BrokerProducer) lookupLeaders(ctx context.Context, topic string) ([]string, error) {
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+
+	var errs []error
+	for _, broker := range p.brokers {
+		response, err := p.roundTrip(ctx, broker, apiKeyMetadata, metadataVersion, e.buf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("broker %s: %w", broker, err))
+			continue
+		}
+		return decodeLeaders(response, topic)
+	}
+	return nil, fmt.Errorf("failed to look up partitions of topic %s: %w", topic, errors.Join(errs...))
+}
+
+// decodeLeaders reads the partition leaders of the topic from a Metadata response
+func decodeLeaders(response []byte, topic string) ([]string, error) {
+	d := decoder{buf: response}
+
+	addresses := make(map[int32]string)
+	for range d.arrayLen() {
+		nodeID := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		addresses[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller_id
+
+	var leaders []string
+	for range d.arrayLen() {
+		code := d.int16()
+		name := d.string()
+		d.int8() // is_internal
+		partitions := d.arrayLen()
+		if d.err != nil {
+			break
+		}
+		if name != topic {
+			return nil, fmt.Errorf("metadata for unexpected topic %s", name)
+		}
+		if code != 0 {
+			return nil, fmt.Errorf("topic %s: %w", topic, kafkaError(code))
+		}
+
+		leaders = make([]string, partitions)
+		for range partitions {
+			code := d.int16()
+			partition := d.int32()
+			leader := d.int32()
+			d.int32Array() // replica_nodes
+			d.int32Array() // isr_nodes
+			if d.err != nil {
+				break
+			}
+			if partition < 0 || int(partition) >= partitions {
+				return nil, fmt.Errorf("topic %s: partition %d out of range", topic, partition)
+			}
+			address, ok := addresses[leader]
+			if code != 0 || !ok {
+				return nil, fmt.Errorf("topic %s: partition %d has no leader", topic, partition)
+			}
+			leaders[partition] = address
+		}
+	}
+
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid metadata response: %w", d.err)
+	}
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", topic)
+	}
+	return leaders, nil
+}
+
+// roundTrip sends the request to the broker and returns the body of its response, closing the
+// connection on failure
+func (p *BrokerProducer) roundTrip(ctx context.Context, address string, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	conn, ok := p.conns[address]
+	if !ok {
+		var err error
+		conn, err = p.dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		p.conns[address] = conn
+	}
+
+	response, err := p.exchange(ctx, conn, apiKey, apiVersion, body)
+	if err != nil {
+		conn.Close()
+		delete(p.conns, address)
+		return nil, err
+	}
+	return response, nil
+}
+
+// exchange writes the request and reads its response on the connection
+func (p *BrokerProducer) exchange(ctx context.Context, conn net.Conn, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Time{})
+	}
+
+	p.correlationID++
+	var e encoder
+	e.int32(0) // size, filled in below
+	e.int16(apiKey)
+	e.int16(apiVersion)
+	e.int32(p.correlationID)
+	e.string(clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+
+	if _, err := conn.Write(e.buf); err != nil {
+		return nil, err
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(size[:])
+	if length < 4 || length > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", length)
+	}
+	response := make([]byte, length)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, err
+	}
+
+	if correlationID := int32(binary.BigEndian.Uint32(response)); correlationID != p.correlationID {
+		return nil, fmt.Errorf("response correlation ID %d does not match request %d", correlationID, p.correlationID)
+	}
+	return response[4:], nil
+}
+
+// encodeRecordBatch encodes the messages as an uncompressed v2 record batch
+func encodeRecordBatch(messages []Message, timestamp time.Time) []byte {
+	var records []byte
+	for i, message := range messages {
+		var record []byte
+		record = append(record, 0)                     // attributes
+		record = binary.AppendVarint(record, 0)        // timestamp_delta
+		record = binary.AppendVarint(record, int64(i)) // offset_delta
+		record = appendVarintBytes(record, message.Key)
+		record = appendVarintBytes(record, message.Value)
+		record = binary.AppendVarint(record, 0) // headers
+
+		records = binary.AppendVarint(records, int64(len(record)))
+		records = append(records, record...)
+	}
+
+	// The checksum covers everything from the attributes on
+	millis := uint64(timestamp.UnixMilli())
+	var e encoder
+	e.int16(0) // attributes
+	e.int32(int32(len(messages) - 1))
+	e.buf = binary.BigEndian.AppendUint64(e.buf, millis) // first_timestamp
+	e.buf = binary.BigEndian.AppendUint64(e.buf, millis) // max_timestamp
+	e.int64(-1)                                          // producer_id
+	e.int16(-1)                                          // producer_epoch
+	e.int32(-1)                                          // base_sequence
+	e.int32(int32(len(messages)))
+	e.buf = append(e.buf, records...)
+	checked := e.buf
+
+	var batch encoder
+	batch.int64(0)                               // base_offset
+	batch.int32(int32(4 + 1 + 4 + len(checked))) // batch_length
+	batch.int32(-1)                              // partition_leader_epoch
+	batch.int8(2)                                // magic
+	batch.buf = binary.BigEndian.AppendUint32(batch.buf, crc32.Checksum(checked, castagnoli))
+	batch.buf = append(batch.buf, checked...)
+	return batch.buf
+}
+
+// appendVarintBytes appends the length of b as a varint followed by b, or -1 if b is nil
+func appendVarintBytes(buf, b []byte) []byte {
+	if b == nil {
+		return binary.AppendVarint(buf, -1)
+	}
+	buf = binary.AppendVarint(buf, int64(len(b)))
+	return append(buf, b...)
+}
+
+// murmur2 is the hash the Java client partitions keys by
+func murmur2(data []byte) uint32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+
+	length := len(data)
+	h := uint32(seed) ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
+
+// encoder appends Kafka protocol primitives to a buffer
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int8(v int8) {
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *encoder) int16(v int16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+}
+
+func (e *encoder) int32(v int32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// decoder reads Kafka protocol primitives, remembering the first error and returning zero
+// values after it
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) int8() int8 {
+	if b := d.read(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.read(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.read(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.read(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string reads a string, returning "" for a null string
+func (d *decoder) string() string {
+	length := d.int16()
+	if length < 0 {
+		return ""
+	}
+	return string(d.read(int(length)))
+}
+
+// arrayLen reads the length of an array, returning 0 for a null array
+func (d *decoder) arrayLen() int {
+	length := d.int32()
+	if length < 0 || d.err != nil {
+		return 0
+	}
+	if int(length) > len(d.buf) {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	return int(length)
+}
+
+func (d *decoder) int32Array() {
+	for range d.arrayLen() {
+		d.int32()
+	}
+}
@@ -0,0 +1,290 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker is a single Kafka broker leading every partition of its topic
+type fakeBroker struct {
+	t          *testing.T
+	listener   net.Listener
+	topic      string
+	partitions int
+	// produceError is returned for every partition when set
+	produceError int16
+
+	mu       sync.Mutex
+	records  map[int32][]Message
+	requests []int16
+}
+
+// newFakeBroker starts a broker serving the topic with the given number of partitions
+func newFakeBroker(t *testing.T, topic string, partitions int) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	broker := &fakeBroker{t: t, listener: listener, topic: topic, partitions: partitions, records: make(map[int32][]Message)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+func (b *fakeBroker) address() string {
+	return b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		request := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+
+		d := decoder{buf: request}
+		apiKey := d.int16()
+		d.int16() // api_version
+		correlationID := d.int32()
+		assert.Equal(b.t, clientID, d.string())
+
+		b.mu.Lock()
+		b.requests = append(b.requests, apiKey)
+		b.mu.Unlock()
+
+		var e encoder
+		e.int32(0)
+		e.int32(correlationID)
+		switch apiKey {
+		case apiKeyMetadata:
+			b.metadata(&d, &e)
+		case apiKeyProduce:
+			b.produce(&d, &e)
+		}
+		require.NoError(b.t, d.err)
+		binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+		conn.Write(e.buf)
+	}
+}
+
+func (b *fakeBroker) metadata(d *decoder, e *encoder) {
+	require.Equal(b.t, 1, d.arrayLen())
+	assert.Equal(b.t, b.topic, d.string())
+
+	host, portText, _ := net.SplitHostPort(b.address())
+	port, _ := strconv.Atoi(portText)
+	e.int32(1)
+	e.int32(0) // node_id
+	e.string(host)
+	e.int32(int32(port))
+	e.int16(-1) // rack
+	e.int32(0)  // controller_id
+	e.int32(1)
+	e.int16(0)
+	e.string(b.topic)
+	e.int8(0)
+	e.int32(int32(b.partitions))
+	for partition := range b.partitions {
+		e.int16(0)
+		e.int32(int32(partition))
+		e.int32(0) // leader
+		e.int32(1)
+		e.int32(0)
+		e.int32(1)
+		e.int32(0)
+	}
+}
+
+func (b *fakeBroker) produce(d *decoder, e *encoder) {
+	assert.Equal(b.t, int16(-1), d.int16()) // transactional_id
+	assert.Equal(b.t, int16(acksLeader), d.int16())
+	d.int32() // timeout_ms
+	require.Equal(b.t, 1, d.arrayLen())
+	topic := d.string()
+	assert.Equal(b.t, b.topic, topic)
+
+	var partitions []int32
+	for range d.arrayLen() {
+		partition := d.int32()
+		batch := d.read(int(d.int32()))
+		messages := decodeRecordBatch(b.t, batch)
+
+		b.mu.Lock()
+		b.records[partition] = append(b.records[partition], messages...)
+		b.mu.Unlock()
+		partitions = append(partitions, partition)
+	}
+
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(partitions)))
+	for _, partition := range partitions {
+		e.int32(partition)
+		e.int16(b.produceError)
+		e.int64(0)
+		e.int64(-1)
+	}
+	e.int32(0) // throttle_time_ms
+}
+
+// decodeRecordBatch verifies the framing and checksum of a v2 record batch and returns its records
+func decodeRecordBatch(t *testing.T, batch []byte) []Message {
+	d := decoder{buf: batch}
+	assert.Equal(t, int64(0), d.int64())
+	assert.Equal(t, len(batch)-12, int(d.int32()))
+	d.int32() // partition_leader_epoch
+	assert.Equal(t, int8(2), d.int8())
+	checksum := uint32(d.int32())
+	assert.Equal(t, crc32.Checksum(d.buf, castagnoli), checksum)
+
+	assert.Equal(t, int16(0), d.int16()) // attributes
+	lastOffsetDelta := d.int32()
+	d.int64()
+	d.int64()
+	assert.Equal(t, int64(-1), d.int64())
+	d.int16()
+	d.int32()
+	count := d.int32()
+	assert.Equal(t, lastOffsetDelta+1, count)
+	require.NoError(t, d.err)
+
+	rest := d.buf
+	varint := func() int64 {
+		v, n := binary.Varint(rest)
+		require.Positive(t, n)
+		rest = rest[n:]
+		return v
+	}
+
+	var messages []Message
+	for i := range count {
+		length := varint()
+		end := len(rest) - int(length)
+		rest = rest[1:] // attributes
+		varint()        // timestamp_delta
+		assert.Equal(t, int64(i), varint())
+		key := rest[:varint()]
+		rest = rest[len(key):]
+		value := rest[:varint()]
+		rest = rest[len(value):]
+		assert.Equal(t, int64(0), varint())
+		assert.Equal(t, end, len(rest))
+		messages = append(messages, Message{Key: key, Value: value})
+	}
+	assert.Empty(t, rest)
+	return messages
+}
+
+func TestMurmur2(t *testing.T) {
+	// Reference values of the Java client's Utils.murmur2
+	tests := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, int32(murmur2([]byte(input))), input)
+	}
+}
+
+func TestBrokerProducer_Produce(t *testing.T) {
+	broker := newFakeBroker(t, "health", 3)
+	producer := NewBrokerProducer([]string{"127.0.0.1:1", broker.address()})
+	defer producer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	messages := []Message{
+		{Key: []byte("api"), Value: []byte(`{"name":"api"}`)},
+		{Key: []byte("db"), Value: []byte(`{"name":"db"}`)},
+		{Key: []byte("api"), Value: []byte(`{"name":"api","healthy":true}`)},
+	}
+	require.NoError(t, producer.Produce(ctx, "health", messages))
+	require.NoError(t, producer.Produce(ctx, "health", messages[:1]))
+
+	// Metadata is looked up once, falling back past the unreachable bootstrap broker
+	assert.Equal(t, []int16{apiKeyMetadata, apiKeyProduce, apiKeyProduce}, broker.requests)
+
+	// Messages of a key land on the same partition in order
+	apiPartition := int32(murmur2([]byte("api"))&0x7fffffff) % 3
+	api := broker.records[apiPartition]
+	require.GreaterOrEqual(t, len(api), 3)
+	var values []string
+	for _, message := range api {
+		if string(message.Key) == "api" {
+			values = append(values, string(message.Value))
+		}
+	}
+	assert.Equal(t, []string{`{"name":"api"}`, `{"name":"api","healthy":true}`, `{"name":"api"}`}, values)
+}
+
+func TestBrokerProducer_ProduceError(t *testing.T) {
+	broker := newFakeBroker(t, "health", 1)
+	broker.produceError = 6
+	producer := NewBrokerProducer([]string{broker.address()})
+	defer producer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := producer.Produce(ctx, "health", []Message{{Key: []byte("api"), Value: []byte("{}")}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "partition 0: Kafka error NOT_LEADER_OR_FOLLOWER (6)")
+
+	// The failure makes the next produce look up the leaders again
+	broker.produceError = 0
+	require.NoError(t, producer.Produce(ctx, "health", []Message{{Key: []byte("api"), Value: []byte("{}")}}))
+	assert.Equal(t, []int16{apiKeyMetadata, apiKeyProduce, apiKeyMetadata, apiKeyProduce}, broker.requests)
+}
+
+func TestBrokerProducer_UnknownTopic(t *testing.T) {
+	var e encoder
+	e.int32(0) // brokers
+	e.int32(0) // controller_id
+	e.int32(1)
+	e.int16(3)
+	e.string("health")
+	e.int8(0)
+	e.int32(0)
+
+	_, err := decodeLeaders(e.buf, "health")
+	require.Error(t, err)
+	assert.Equal(t, "topic health: Kafka error UNKNOWN_TOPIC_OR_PARTITION (3)", err.Error())
+}
+
+func TestBrokerProducer_NoReachableBroker(t *testing.T) {
+	producer := NewBrokerProducer([]string{"127.0.0.1:1"})
+	defer producer.Close()
+
+	err := producer.Produce(context.Background(), "health", []Message{{Key: []byte("api")}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to look up partitions of topic health: broker 127.0.0.1:1:")
+}
@@ -0,0 +1,304 @@
+// Package metrics implements a minimal registry of Prometheus style metrics exposed in
+// the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultRegistry is the registry served by the metrics endpoint
+var DefaultRegistry = NewRegistry()
+
+// Registry holds registered metric families in registration order
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// family is a metric family that can write itself in the text exposition format
+type family interface {
+	write(w io.Writer)
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewCounterVec creates a counter with the given label names and registers it
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	counter := &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]*sample),
+	}
+
+	r.mu.Lock()
+	r.families = append(r.families, counter)
+	r.mu.Unlock()
+
+	return counter
+}
+
+// NewGauge creates a gauge without labels and registers it
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{
+		name: name,
+		help: help,
+	}
+
+	r.mu.Lock()
+	r.families = append(r.families, gauge)
+	r.mu.Unlock()
+
+	return gauge
+}
+
+// NewGaugeVec creates a gauge with the given label names and registers it
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	gauge := &GaugeVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]*sample),
+	}
+
+	r.mu.Lock()
+	r.families = append(r.families, gauge)
+	r.mu.Unlock()
+
+	return gauge
+}
+
+// Write writes all registered metrics in the text exposition format
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+
+	for _, f := range families {
+		f.write(w)
+	}
+}
+
+// Handler returns an HTTP handler serving the registered metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// sample is the value of a single labelled series
+type sample struct {
+	labelValues []string
+	value       float64
+}
+
+// CounterVec is a monotonically increasing counter partitioned by label values
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]*sample
+}
+
+// Add increases the counter for the given label values, which must match the label names
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.name))
+	}
+	if len(labelValues) != len(c.labelNames) {
+		panic(fmt.Sprintf("counter %s expects %d label values, got %d", c.name, len(c.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.values[key]
+	if !ok {
+		s = &sample{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = s
+	}
+	s.value += value
+}
+
+// Inc increases the counter for the given label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the current counter value for the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.values[strings.Join(labelValues, "\xff")]; ok {
+		return s.value
+	}
+	return 0
+}
+
+// write writes the counter in the text exposition format, with series sorted by label values
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", c.name, c.help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", c.name)
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := c.values[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, s.labelValues), strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+}
+
+// Gauge is a value that can go up and down, such as the number of running scrapes
+type Gauge struct {
+	name string
+	help string
+
+	mu    sync.Mutex
+	value float64
+}
+
+// Set sets the gauge to the value
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = value
+}
+
+// Add changes the gauge by the value, which may be negative
+func (g *Gauge) Add(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value += value
+}
+
+// Inc increases the gauge by one
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec decreases the gauge by one
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Value returns the current gauge value
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+// write writes the gauge in the text exposition format
+func (g *Gauge) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", g.name, g.help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", g.name)
+	fmt.Fprintf(w, "%s %s\n", g.name, strconv.FormatFloat(g.value, 'g', -1, 64))
+}
+
+// GaugeVec is a gauge partitioned by label values, such as a value per scraper
+type GaugeVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]*sample
+}
+
+// Set sets the gauge for the given label values, which must match the label names
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	if len(labelValues) != len(g.labelNames) {
+		panic(fmt.Sprintf("gauge %s expects %d label values, got %d", g.name, len(g.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.values[key]
+	if !ok {
+		s = &sample{labelValues: append([]string(nil), labelValues...)}
+		g.values[key] = s
+	}
+	s.value = value
+}
+
+// Delete removes the series of the given label values, for example once its scraper is removed
+func (g *GaugeVec) Delete(labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.values, strings.Join(labelValues, "\xff"))
+}
+
+// Value returns the current gauge value for the given label values
+func (g *GaugeVec) Value(labelValues ...string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if s, ok := g.values[strings.Join(labelValues, "\xff")]; ok {
+		return s.value
+	}
+	return 0
+}
+
+// write writes the gauge in the text exposition format, with series sorted by label values
+func (g *GaugeVec) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", g.name, g.help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", g.name)
+
+	keys := make([]string, 0, len(g.values))
+	for key := range g.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := g.values[key]
+		fmt.Fprintf(w, "%s%s %s\n", g.name, formatLabels(g.labelNames, s.labelValues), strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+}
+
+// formatLabels formats label pairs as {name="value",...}, escaping the values
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(values[i]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelEscaper escapes label values for the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
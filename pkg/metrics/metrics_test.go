@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterVec_Add(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounterVec("bytes_total", "Bytes transferred.", "scraper")
+
+	counter.Add(10, "api")
+	counter.Add(5, "api")
+	counter.Inc("db")
+
+	assert.Equal(t, 15.0, counter.Value("api"))
+	assert.Equal(t, 1.0, counter.Value("db"))
+	assert.Equal(t, 0.0, counter.Value("unknown"))
+}
+
+func TestCounterVec_Add_Invalid(t *testing.T) {
+	counter := NewRegistry().NewCounterVec("bytes_total", "Bytes transferred.", "scraper")
+
+	assert.Panics(t, func() { counter.Add(-1, "api") })
+	assert.Panics(t, func() { counter.Add(1) })
+}
+
+func TestGauge(t *testing.T) {
+	gauge := NewRegistry().NewGauge("running", "Running scrapes.")
+
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+	gauge.Add(2.5)
+	assert.Equal(t, 3.5, gauge.Value())
+
+	gauge.Set(1)
+	assert.Equal(t, 1.0, gauge.Value())
+}
+
+func TestGaugeVec(t *testing.T) {
+	registry := NewRegistry()
+	gauge := registry.NewGaugeVec("flaps", "Flaps per scraper.", "scraper")
+
+	gauge.Set(3, "api")
+	gauge.Set(1, "db")
+	gauge.Set(2, "api")
+	gauge.Delete("db")
+
+	assert.Equal(t, 2.0, gauge.Value("api"))
+	assert.Equal(t, 0.0, gauge.Value("db"))
+	assert.Panics(t, func() { gauge.Set(1) })
+
+	var out strings.Builder
+	registry.Write(&out)
+	assert.Equal(t, `# HELP flaps Flaps per scraper.
+# TYPE flaps gauge
+flaps{scraper="api"} 2
+`, out.String())
+}
+
+func TestRegistry_Write(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("requests_total", "Requests sent.", "scraper", "code")
+	registry.NewCounterVec("empty_total", "Never incremented.")
+	registry.NewGauge("running", "Running scrapes.").Set(2)
+
+	requests.Inc("web", "200")
+	requests.Add(2, "api", "200")
+	requests.Inc(`quo"te`, "500")
+
+	var out strings.Builder
+	registry.Write(&out)
+
+	assert.Equal(t, `# HELP requests_total Requests sent.
+# TYPE requests_total counter
+requests_total{scraper="api",code="200"} 2
+requests_total{scraper="quo\"te",code="500"} 1
+requests_total{scraper="web",code="200"} 1
+# HELP empty_total Never incremented.
+# TYPE empty_total counter
+# HELP running Running scrapes.
+# TYPE running gauge
+running 2
+`, out.String())
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("up_total", "Times seen up.").Inc()
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), "up_total 1\n")
+}
@@ -0,0 +1,80 @@
+package otlp
+
+// The types below mirror the JSON encoding of the OTLP metrics protocol. As required by the
+// protobuf JSON mapping, 64 bit integers are encoded as strings.
+
+// MetricsData is the body of an OTLP metrics export request
+type MetricsData struct {
+	ResourceMetrics []ResourceMetrics `json:"resourceMetrics"`
+}
+
+// ResourceMetrics holds the metrics of a resource
+type ResourceMetrics struct {
+	Resource     Resource       `json:"resource"`
+	ScopeMetrics []ScopeMetrics `json:"scopeMetrics"`
+}
+
+// Resource describes the entity producing the metrics
+type Resource struct {
+	Attributes []KeyValue `json:"attributes"`
+}
+
+// ScopeMetrics holds the metrics of an instrumentation scope
+type ScopeMetrics struct {
+	Scope   Scope    `json:"scope"`
+	Metrics []Metric `json:"metrics"`
+}
+
+// Scope is the instrumentation scope producing the metrics
+type Scope struct {
+	Name string `json:"name"`
+}
+
+// Metric is a single metric with either gauge or histogram data
+type Metric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Unit        string     `json:"unit,omitempty"`
+	Gauge       *Gauge     `json:"gauge,omitempty"`
+	Histogram   *Histogram `json:"histogram,omitempty"`
+}
+
+// Gauge holds the data points of a gauge
+type Gauge struct {
+	DataPoints []NumberDataPoint `json:"dataPoints"`
+}
+
+// Histogram holds the data points of a histogram
+type Histogram struct {
+	DataPoints             []HistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+// NumberDataPoint is an integer value of a gauge at a point in time
+type NumberDataPoint struct {
+	Attributes   []KeyValue `json:"attributes"`
+	TimeUnixNano string     `json:"timeUnixNano"`
+	AsInt        string     `json:"asInt"`
+}
+
+// HistogramDataPoint is the distribution of a histogram's values since the start time
+type HistogramDataPoint struct {
+	Attributes        []KeyValue `json:"attributes"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	Count             string     `json:"count"`
+	Sum               float64    `json:"sum"`
+	BucketCounts      []string   `json:"bucketCounts"`
+	ExplicitBounds    []float64  `json:"explicitBounds"`
+}
+
+// KeyValue is an attribute
+type KeyValue struct {
+	Key   string   `json:"key"`
+	Value AnyValue `json:"value"`
+}
+
+// AnyValue is the value of an attribute
+type AnyValue struct {
+	StringValue string `json:"stringValue"`
+}
@@ -0,0 +1,259 @@
+// Package otlp exports scrape results as OpenTelemetry metrics to an OTLP/HTTP endpoint
+// using the JSON encoding of the OTLP protocol.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultExportInterval is how often metrics are exported unless configured otherwise
+	DefaultExportInterval = 60 * time.Second
+	// healthyMetric is the gauge of each scraper's latest health, 1 for healthy and 0 otherwise
+	healthyMetric = "healthcheck.scraper.healthy"
+	// durationMetric is the histogram of scrape durations in seconds
+	durationMetric = "healthcheck.scrape.duration"
+	// aggregationTemporalityCumulative marks histogram data points as cumulative since start
+	aggregationTemporalityCumulative = 2
+)
+
+// durationBounds are the explicit bucket boundaries of the scrape duration histogram in seconds
+var durationBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// series identifies the metrics of one scraper
+type series struct {
+	name        string
+	scraperType string
+}
+
+// seriesData is the aggregated state of one scraper's metrics
+type seriesData struct {
+	healthy      bool
+	healthyTime  time.Time
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+// Exporter aggregates recorded scrapes in memory and periodically exports them to an
+// OTLP/HTTP metrics endpoint
+type Exporter struct {
+	endpoint string
+	interval time.Duration
+	client   *http.Client
+	logger   *logrus.Logger
+	start    time.Time
+
+	mu     sync.Mutex
+	series map[series]*seriesData
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewExporter creates an exporter posting to the endpoint, e.g. http://collector:4318/v1/metrics,
+// every interval
+func NewExporter(endpoint string, interval time.Duration, logger *logrus.Logger) *Exporter {
+	if interval <= 0 {
+		interval = DefaultExportInterval
+	}
+
+	return &Exporter{
+		endpoint: endpoint,
+		interval: interval,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: logger,
+		start:  time.Now(),
+		series: make(map[series]*seriesData),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Publish records the result as a scrape of the named scraper, making the exporter a result
+// sink of the healthcheck manager. Skipped scrapes did not run and are not recorded.
+func (e *Exporter) Publish(name string, r scraper.ScrapeResult) error {
+	if r.Skipped {
+		return nil
+	}
+	e.RecordScrape(name, r.Type, r.Healthy, r.Duration)
+	return nil
+}
+
+// RecordScrape updates the health gauge and duration histogram of the scraper
+func (e *Exporter) RecordScrape(name, scraperType string, healthy bool, duration time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := series{name: name, scraperType: scraperType}
+	data, ok := e.series[key]
+	if !ok {
+		data = &seriesData{bucketCounts: make([]uint64, len(durationBounds)+1)}
+		e.series[key] = data
+	}
+
+	seconds := duration.Seconds()
+	data.healthy = healthy
+	data.healthyTime = time.Now()
+	data.count++
+	data.sum += seconds
+	data.bucketCounts[sort.SearchFloat64s(durationBounds, seconds)]++
+}
+
+// Start exports the metrics every interval until Stop is called
+func (e *Exporter) Start() {
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.exportAndLog()
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic export and exports the final state of the metrics
+func (e *Exporter) Stop() {
+	close(e.stop)
+	<-e.done
+	e.exportAndLog()
+}
+
+// exportAndLog exports the metrics, logging failures
+func (e *Exporter) exportAndLog() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.Export(ctx); err != nil {
+		e.logger.WithFields(logrus.Fields{
+			"endpoint": e.endpoint,
+			"error":    err.Error(),
+		}).Error("Failed to export OTLP metrics")
+	}
+}
+
+// Export posts the current state of the metrics to the endpoint
+func (e *Exporter) Export(ctx context.Context) error {
+	data := e.Collect()
+	if len(data.ResourceMetrics[0].ScopeMetrics[0].Metrics) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("HTTP status %d from %s: %s", resp.StatusCode, e.endpoint, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+// Collect returns the current state of the metrics as an OTLP export request
+func (e *Exporter) Collect() MetricsData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keys := make([]series, 0, len(e.series))
+	for key := range e.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name
+	})
+
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	start := strconv.FormatInt(e.start.UnixNano(), 10)
+
+	var gauge Gauge
+	var histogram Histogram
+	histogram.AggregationTemporality = aggregationTemporalityCumulative
+
+	for _, key := range keys {
+		data := e.series[key]
+		attributes := []KeyValue{
+			{Key: "scraper", Value: AnyValue{StringValue: key.name}},
+			{Key: "scraper_type", Value: AnyValue{StringValue: key.scraperType}},
+		}
+
+		healthy := "0"
+		if data.healthy {
+			healthy = "1"
+		}
+		gauge.DataPoints = append(gauge.DataPoints, NumberDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: strconv.FormatInt(data.healthyTime.UnixNano(), 10),
+			AsInt:        healthy,
+		})
+
+		bucketCounts := make([]string, len(data.bucketCounts))
+		for i, count := range data.bucketCounts {
+			bucketCounts[i] = strconv.FormatUint(count, 10)
+		}
+		histogram.DataPoints = append(histogram.DataPoints, HistogramDataPoint{
+			Attributes:        attributes,
+			StartTimeUnixNano: start,
+			TimeUnixNano:      now,
+			Count:             strconv.FormatUint(data.count, 10),
+			Sum:               data.sum,
+			BucketCounts:      bucketCounts,
+			ExplicitBounds:    durationBounds,
+		})
+	}
+
+	var metrics []Metric
+	if len(keys) > 0 {
+		metrics = []Metric{
+			{Name: healthyMetric, Description: "Whether the latest scrape of the scraper was healthy", Unit: "1", Gauge: &gauge},
+			{Name: durationMetric, Description: "Duration of scrapes", Unit: "s", Histogram: &histogram},
+		}
+	}
+
+	return MetricsData{
+		ResourceMetrics: []ResourceMetrics{{
+			Resource: Resource{Attributes: []KeyValue{{Key: "service.name", Value: AnyValue{StringValue: "healthcheck"}}}},
+			ScopeMetrics: []ScopeMetrics{{
+				Scope:   Scope{Name: "healthcheck"},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
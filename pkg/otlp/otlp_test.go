@@ -0,0 +1,147 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/scraper"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findMetric returns the metric with the given name from the export request
+func findMetric(t *testing.T, data MetricsData, name string) Metric {
+	for _, metric := range data.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return Metric{}
+}
+
+func TestExporter_Collect(t *testing.T) {
+	exporter := NewExporter("http://localhost:4318/v1/metrics", time.Minute, logrus.New())
+
+	exporter.RecordScrape("api", "http", true, 30*time.Millisecond)
+	exporter.RecordScrape("api", "http", false, 2*time.Second)
+	exporter.RecordScrape("db", "tls", true, 5*time.Millisecond)
+
+	data := exporter.Collect()
+
+	healthy := findMetric(t, data, "healthcheck.scraper.healthy")
+	require.Len(t, healthy.Gauge.DataPoints, 2)
+	assert.Equal(t, "api", healthy.Gauge.DataPoints[0].Attributes[0].Value.StringValue)
+	assert.Equal(t, "http", healthy.Gauge.DataPoints[0].Attributes[1].Value.StringValue)
+	assert.Equal(t, "0", healthy.Gauge.DataPoints[0].AsInt)
+	assert.Equal(t, "1", healthy.Gauge.DataPoints[1].AsInt)
+
+	duration := findMetric(t, data, "healthcheck.scrape.duration")
+	require.Len(t, duration.Histogram.DataPoints, 2)
+	api := duration.Histogram.DataPoints[0]
+	assert.Equal(t, "2", api.Count)
+	assert.InDelta(t, 2.03, api.Sum, 0.0001)
+	assert.Len(t, api.BucketCounts, len(api.ExplicitBounds)+1)
+	assert.Equal(t, "1", api.BucketCounts[3])  // (0.025, 0.05]
+	assert.Equal(t, "1", api.BucketCounts[10]) // (1, 2.5]
+	// The boundary belongs to the bucket it closes
+	assert.Equal(t, "1", duration.Histogram.DataPoints[1].BucketCounts[0])
+}
+
+func TestExporter_Publish(t *testing.T) {
+	exporter := NewExporter("http://localhost:4318/v1/metrics", time.Minute, logrus.New())
+
+	require.NoError(t, exporter.Publish("api", scraper.ScrapeResult{Healthy: true, Type: "http", Duration: 30 * time.Millisecond}))
+
+	data := exporter.Collect()
+	healthy := findMetric(t, data, "healthcheck.scraper.healthy")
+	require.Len(t, healthy.Gauge.DataPoints, 1)
+	assert.Equal(t, "http", healthy.Gauge.DataPoints[0].Attributes[1].Value.StringValue)
+	assert.Equal(t, "1", healthy.Gauge.DataPoints[0].AsInt)
+	duration := findMetric(t, data, "healthcheck.scrape.duration")
+	assert.Equal(t, "1", duration.Histogram.DataPoints[0].BucketCounts[3])
+}
+
+func TestExporter_Publish_IgnoresSkippedScrapes(t *testing.T) {
+	exporter := NewExporter("http://localhost:4318/v1/metrics", time.Minute, logrus.New())
+
+	require.NoError(t, exporter.Publish("api", scraper.ScrapeResult{Healthy: true, Type: "http"}))
+	require.NoError(t, exporter.Publish("api", scraper.ScrapeResult{Skipped: true, Type: "http"}))
+
+	healthy := findMetric(t, exporter.Collect(), "healthcheck.scraper.healthy")
+	require.Len(t, healthy.Gauge.DataPoints, 1)
+	assert.Equal(t, "1", healthy.Gauge.DataPoints[0].AsInt)
+}
+
+func TestExporter_Export(t *testing.T) {
+	requests := make(chan MetricsData, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var data MetricsData
+		json.NewDecoder(r.Body).Decode(&data)
+		requests <- data
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.URL, time.Minute, logrus.New())
+	exporter.RecordScrape("api", "http", true, 10*time.Millisecond)
+
+	require.NoError(t, exporter.Export(context.Background()))
+
+	data := <-requests
+	assert.Equal(t, "healthcheck", data.ResourceMetrics[0].Resource.Attributes[0].Value.StringValue)
+	assert.Equal(t, "1", findMetric(t, data, "healthcheck.scraper.healthy").Gauge.DataPoints[0].AsInt)
+}
+
+func TestExporter_Export_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.URL, time.Minute, logrus.New())
+	exporter.RecordScrape("api", "http", true, 10*time.Millisecond)
+
+	assert.ErrorContains(t, exporter.Export(context.Background()), "HTTP status 503")
+}
+
+func TestExporter_Export_NothingRecorded(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.URL, time.Minute, logrus.New())
+
+	require.NoError(t, exporter.Export(context.Background()))
+	assert.False(t, called)
+}
+
+func TestExporter_StartStop(t *testing.T) {
+	exports := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exports <- struct{}{}
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.URL, 20*time.Millisecond, logrus.New())
+	exporter.RecordScrape("api", "http", true, 10*time.Millisecond)
+	exporter.Start()
+
+	select {
+	case <-exports:
+	case <-time.After(time.Second):
+		t.Fatal("Metrics should have been exported periodically")
+	}
+
+	exporter.Stop()
+	// Stop exports the final state
+	assert.NotEmpty(t, exports)
+}
@@ -0,0 +1,29 @@
+package scraper
+
+import "net/http"
+
+// annotate copies the configured response headers into the result details under the
+// "annotations" key, omitting headers missing from the response
+func annotate(result *ScrapeResult, header http.Header, names []string) *ScrapeResult {
+	if len(names) == 0 {
+		return result
+	}
+
+	annotations := make(map[string]string)
+	for _, name := range names {
+		if value := header.Get(name); value != "" {
+			annotations[name] = value
+		}
+	}
+
+	if len(annotations) == 0 {
+		return result
+	}
+
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details["annotations"] = annotations
+
+	return result
+}
@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotate_CopiesConfiguredHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Deploy-Id", "build-42")
+	header.Set("X-Region", "eu-west-1")
+	header.Set("X-Other", "ignored")
+
+	result := annotate(&ScrapeResult{Details: map[string]interface{}{}}, header, []string{"X-Deploy-Id", "x-region"})
+
+	assert.Equal(t, map[string]string{"X-Deploy-Id": "build-42", "x-region": "eu-west-1"}, result.Details["annotations"])
+}
+
+func TestAnnotate_OmitsMissingHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Deploy-Id", "build-42")
+
+	result := annotate(&ScrapeResult{Details: map[string]interface{}{}}, header, []string{"X-Deploy-Id", "X-Missing"})
+
+	assert.Equal(t, map[string]string{"X-Deploy-Id": "build-42"}, result.Details["annotations"])
+}
+
+func TestAnnotate_NoMatchingHeaders(t *testing.T) {
+	result := annotate(&ScrapeResult{Details: map[string]interface{}{}}, http.Header{}, []string{"X-Missing"})
+
+	assert.NotContains(t, result.Details, "annotations")
+}
+
+func TestAnnotate_NilDetails(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Deploy-Id", "build-42")
+
+	result := annotate(&ScrapeResult{}, header, []string{"X-Deploy-Id"})
+
+	assert.Equal(t, map[string]string{"X-Deploy-Id": "build-42"}, result.Details["annotations"])
+}
+
+func TestCloudflaredTunnelScraper_Scrape_Annotations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Deploy-Id", "build-42")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewCloudflaredTunnelScraper(server.URL, "http://localhost:8081/ping", 30, logger)
+	scraper.annotationHeaders = []string{"X-Deploy-Id"}
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, map[string]string{"X-Deploy-Id": "build-42"}, result.Details["annotations"])
+}
+
+func TestHTTPScraper_Scrape_Annotations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Deploy-Id", "build-42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL:         server.URL,
+		AnnotationHeaders: []string{"X-Deploy-Id"},
+	}, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, map[string]string{"X-Deploy-Id": "build-42"}, result.Details["annotations"])
+}
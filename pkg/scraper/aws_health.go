@@ -0,0 +1,147 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// awsHealthMaxAttempts bounds how often a throttled alarm lookup is attempted per scrape
+	awsHealthMaxAttempts = 4
+	// defaultAWSHealthBackoff is the delay before the first retry of a throttled lookup,
+	// doubling with every further retry
+	defaultAWSHealthBackoff = 500 * time.Millisecond
+)
+
+// AWSHealthScraper implements the Scraper interface for checking the state of a CloudWatch alarm
+type AWSHealthScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	client                cloudWatchClient
+	backoff               time.Duration
+	logger                *logrus.Logger
+}
+
+// NewAWSHealthScraper creates a new AWS health scraper reading alarms with the given client
+func NewAWSHealthScraper(scraperConfig config.HealthcheckScraper, client cloudWatchClient, logger *logrus.Logger) *AWSHealthScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &AWSHealthScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		client:                client,
+		backoff:               defaultAWSHealthBackoff,
+		logger:                logger,
+	}
+}
+
+// Type returns the scraper type identifier
+func (a *AWSHealthScraper) Type() string {
+	return "aws-health"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (a *AWSHealthScraper) GetPingURL() string {
+	return a.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (a *AWSHealthScraper) GetScrapeInterval() int {
+	return a.scrapeIntervalSeconds
+}
+
+// Close closes the CloudWatch client if it holds connections
+func (a *AWSHealthScraper) Close() error {
+	if closer, ok := a.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Scrape reads the alarm state and is unhealthy while the alarm is in the ALARM state
+func (a *AWSHealthScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	name := a.config.AlarmName
+	a.logger.WithField("alarm_name", name).Debug("Starting AWS health healthcheck")
+
+	details := map[string]interface{}{
+		"alarm_name": name,
+	}
+
+	alarm, attempts, err := a.describeAlarm(ctx)
+	if attempts > 1 {
+		details["attempts"] = attempts
+	}
+	if err != nil {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to read CloudWatch alarm %s: %v", name, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	details["state"] = alarm.State
+	details["state_reason"] = alarm.StateReason
+	if !alarm.UpdatedAt.IsZero() {
+		details["state_updated"] = alarm.UpdatedAt
+	}
+
+	healthy := alarm.State != "ALARM"
+
+	a.logger.WithFields(logrus.Fields{
+		"alarm_name": name,
+		"state":      alarm.State,
+		"healthy":    healthy,
+	}).Info("AWS health healthcheck completed")
+
+	message := fmt.Sprintf("CloudWatch alarm %s is %s", name, alarm.State)
+	if !healthy {
+		message = fmt.Sprintf("%s: %s", message, alarm.StateReason)
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// describeAlarm reads the alarm, retrying throttled requests with exponential backoff,
+// and returns the number of attempts made
+func (a *AWSHealthScraper) describeAlarm(ctx context.Context) (*cloudWatchAlarm, int, error) {
+	delay := a.backoff
+	for attempt := 1; ; attempt++ {
+		alarm, err := a.client.DescribeAlarm(ctx, a.config.AlarmName)
+
+		var throttled *awsThrottlingError
+		if err == nil || !errors.As(err, &throttled) || attempt == awsHealthMaxAttempts {
+			return alarm, attempt, err
+		}
+
+		a.logger.WithFields(logrus.Fields{
+			"alarm_name": a.config.AlarmName,
+			"attempt":    attempt,
+			"backoff":    delay.String(),
+		}).Debug("CloudWatch request throttled, backing off")
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, attempt, ctx.Err()
+		}
+		delay *= 2
+	}
+}
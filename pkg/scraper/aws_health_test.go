@@ -0,0 +1,120 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCloudWatchClient returns scripted alarm lookups, repeating the last one
+type mockCloudWatchClient struct {
+	alarms []*cloudWatchAlarm
+	errs   []error
+	calls  int
+}
+
+func (m *mockCloudWatchClient) DescribeAlarm(ctx context.Context, name string) (*cloudWatchAlarm, error) {
+	i := min(m.calls, max(len(m.alarms), len(m.errs))-1)
+	m.calls++
+
+	var alarm *cloudWatchAlarm
+	if i < len(m.alarms) {
+		alarm = m.alarms[i]
+	}
+	var err error
+	if i < len(m.errs) {
+		err = m.errs[i]
+	}
+	return alarm, err
+}
+
+// newTestAWSHealthScraper creates an AWS health scraper for the alarm with a short backoff
+func newTestAWSHealthScraper(client cloudWatchClient) *AWSHealthScraper {
+	scraper := NewAWSHealthScraper(config.HealthcheckScraper{AlarmName: "api-5xx"}, client, logrus.New())
+	scraper.backoff = time.Millisecond
+	return scraper
+}
+
+func TestNewAWSHealthScraper(t *testing.T) {
+	scraper := NewAWSHealthScraper(config.HealthcheckScraper{
+		AlarmName: "api-5xx",
+		PingURL:   "http://localhost:8081/ping",
+	}, &mockCloudWatchClient{}, logrus.New())
+
+	assert.Equal(t, "aws-health", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestAWSHealthScraper_Scrape_States(t *testing.T) {
+	tests := []struct {
+		state   string
+		healthy bool
+	}{
+		{"OK", true},
+		{"INSUFFICIENT_DATA", true},
+		{"ALARM", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			scraper := newTestAWSHealthScraper(&mockCloudWatchClient{
+				alarms: []*cloudWatchAlarm{{Name: "api-5xx", State: tt.state, StateReason: "Threshold Crossed"}},
+			})
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy)
+			assert.Equal(t, tt.state, result.Details["state"])
+			assert.Equal(t, "Threshold Crossed", result.Details["state_reason"])
+		})
+	}
+}
+
+func TestAWSHealthScraper_Scrape_ThrottlingBackoff(t *testing.T) {
+	throttled := &awsThrottlingError{message: "Rate exceeded"}
+	client := &mockCloudWatchClient{
+		alarms: []*cloudWatchAlarm{nil, nil, {Name: "api-5xx", State: "OK"}},
+		errs:   []error{throttled, throttled, nil},
+	}
+	scraper := newTestAWSHealthScraper(client)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, 3, client.calls)
+	assert.Equal(t, 3, result.Details["attempts"])
+}
+
+func TestAWSHealthScraper_Scrape_ThrottlingExhausted(t *testing.T) {
+	client := &mockCloudWatchClient{errs: []error{&awsThrottlingError{message: "Rate exceeded"}}}
+	scraper := newTestAWSHealthScraper(client)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, awsHealthMaxAttempts, client.calls)
+	assert.Contains(t, result.Message, "throttled")
+}
+
+func TestAWSHealthScraper_Scrape_ErrorNotRetried(t *testing.T) {
+	client := &mockCloudWatchClient{errs: []error{fmt.Errorf("alarm api-5xx not found")}}
+	scraper := newTestAWSHealthScraper(client)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, 1, client.calls)
+	assert.Equal(t, "alarm api-5xx not found", result.Details["error"])
+}
@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAWSCredentials are the example credentials of the AWS Signature Version 4 test suite
+var testAWSCredentials = awsCredentials{
+	accessKeyID:     "AKIDEXAMPLE",
+	secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+func TestSignAWSRequest_TestSuiteVector(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	signAWSRequest(req, nil, testAWSCredentials, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+func TestSignAWSRequest_SessionToken(t *testing.T) {
+	req, err := http.NewRequest("POST", "https://sqs.eu-west-1.amazonaws.com/", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+
+	creds := testAWSCredentials
+	creds.sessionToken = "token"
+	signAWSRequest(req, []byte("{}"), creds, "eu-west-1", "sqs", time.Now())
+
+	assert.Equal(t, "token", req.Header.Get("X-Amz-Security-Token"))
+	assert.Contains(t, req.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,")
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://example.amazonaws.com/?b=2&a=x y&a=1", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "a=1&a=x%20y&b=2", canonicalQuery(req.URL.Query()))
+}
+
+func TestAWSCredentialsFromEnv(t *testing.T) {
+	os.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+	creds, err := awsCredentialsFromEnv()
+
+	require.NoError(t, err)
+	assert.Equal(t, "AKID", creds.accessKeyID)
+	assert.Equal(t, "secret", creds.secretAccessKey)
+
+	os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	_, err = awsCredentialsFromEnv()
+
+	assert.Error(t, err)
+}
+
+func TestAWSCredentialsFromConfig(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+
+	creds, err := awsCredentialsFromConfig(config.HealthcheckScraper{
+		AWSAccessKeyID:     "AKID",
+		AWSSecretAccessKey: "secret",
+		AWSSessionToken:    "token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, awsCredentials{accessKeyID: "AKID", secretAccessKey: "secret", sessionToken: "token"}, creds)
+
+	creds, err = awsCredentialsFromConfig(config.HealthcheckScraper{})
+	require.NoError(t, err)
+	assert.Equal(t, "AKIDENV", creds.accessKeyID)
+
+	_, err = awsCredentialsFromConfig(config.HealthcheckScraper{AWSAccessKeyID: "AKID"})
+	assert.EqualError(t, err, "aws_access_key_id requires an aws_secret_access_key")
+}
@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"healthcheck/pkg/config"
+)
+
+// newRootCAs returns the roots trusted by the scraper: the system roots plus the CA
+// certificates of ca_cert_pem or ca_cert_file, or nil for the system roots only
+func newRootCAs(scraperConfig config.HealthcheckScraper) (*x509.CertPool, error) {
+	name, data := "ca_cert_pem", []byte(scraperConfig.CACertPEM)
+	if scraperConfig.CACertFile != "" {
+		contents, err := os.ReadFile(scraperConfig.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+		name, data = "ca_cert_file", contents
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	certs, err := parsePEMCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// parsePEMCertificates parses every certificate of a PEM bundle, requiring at least one
+func parsePEMCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %s", block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
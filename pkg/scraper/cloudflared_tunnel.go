@@ -2,7 +2,6 @@ package scraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -19,6 +18,7 @@ type CloudflaredTunnelResponse struct {
 
 // CloudflaredTunnelScraper implements the Scraper interface for cloudflared tunnel healthchecks
 type CloudflaredTunnelScraper struct {
+	httpOptions
 	scrapeURL             string
 	pingURL               string
 	scrapeIntervalSeconds int
@@ -59,6 +59,12 @@ func (c *CloudflaredTunnelScraper) GetScrapeInterval() int {
 	return c.scrapeIntervalSeconds
 }
 
+// Close closes the idle connections of the scraper's HTTP client
+func (c *CloudflaredTunnelScraper) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
 // Scrape performs the healthcheck by calling the /ready endpoint
 func (c *CloudflaredTunnelScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
 	c.logger.WithField("url", c.scrapeURL).Debug("Starting cloudflared tunnel healthcheck")
@@ -70,40 +76,39 @@ func (c *CloudflaredTunnelScraper) Scrape(ctx context.Context) (*ScrapeResult, e
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return &ScrapeResult{
+		return c.decorate(&ScrapeResult{
 			Healthy:   false,
 			Message:   fmt.Sprintf("Failed to connect to %s: %v", c.scrapeURL, err),
 			Timestamp: time.Now(),
 			Details: map[string]interface{}{
 				"error": err.Error(),
 			},
-		}, nil
+		}, nil), nil
 	}
 	defer resp.Body.Close()
 
 	// Check if response status is not 200
 	if resp.StatusCode != http.StatusOK {
-		return &ScrapeResult{
+		return c.decorate(&ScrapeResult{
 			Healthy:   false,
 			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, c.scrapeURL),
 			Timestamp: time.Now(),
 			Details: map[string]interface{}{
-				"status_code": resp.StatusCode,
+				DetailStatusCode: resp.StatusCode,
 			},
-		}, nil
+		}, resp), nil
 	}
 
 	// Parse the response body
 	var tunnelResp CloudflaredTunnelResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tunnelResp); err != nil {
-		return &ScrapeResult{
+	if err := decodeJSON(resp.Body, &tunnelResp); err != nil {
+		details := map[string]interface{}{}
+		return c.decorate(&ScrapeResult{
 			Healthy:   false,
-			Message:   fmt.Sprintf("Failed to parse response from %s: %v", c.scrapeURL, err),
+			Message:   parseFailure(c.scrapeURL, err, details),
 			Timestamp: time.Now(),
-			Details: map[string]interface{}{
-				"error": err.Error(),
-			},
-		}, nil
+			Details:   details,
+		}, resp), nil
 	}
 
 	// Check if the tunnel response indicates unhealthy state
@@ -126,7 +131,7 @@ func (c *CloudflaredTunnelScraper) Scrape(ctx context.Context) (*ScrapeResult, e
 		"healthy":          healthy,
 	}).Info("Cloudflared tunnel healthcheck completed")
 
-	return &ScrapeResult{
+	return c.decorate(&ScrapeResult{
 		Healthy:   healthy,
 		Message:   message,
 		Timestamp: time.Now(),
@@ -135,5 +140,5 @@ func (c *CloudflaredTunnelScraper) Scrape(ctx context.Context) (*ScrapeResult, e
 			"readyConnections": tunnelResp.ReadyConnections,
 			"connectorId":      tunnelResp.ConnectorID,
 		},
-	}, nil
+	}, resp), nil
 }
@@ -128,6 +128,26 @@ func TestCloudflaredTunnelScraper_Scrape_InvalidJSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, result.Healthy)
 	assert.Contains(t, result.Message, "Failed to parse response")
+	assert.Equal(t, "parse", result.Details["error_type"])
+}
+
+func TestCloudflaredTunnelScraper_Scrape_EmptyResponse(t *testing.T) {
+	for _, body := range []string{"", " \n\t"} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(body))
+		}))
+
+		scraper := NewCloudflaredTunnelScraper(server.URL, "http://localhost:8081/ping", 30, logrus.New())
+		result, err := scraper.Scrape(context.Background())
+		server.Close()
+
+		require.NoError(t, err)
+		assert.False(t, result.Healthy)
+		assert.Equal(t, "Empty response from "+server.URL, result.Message)
+		assert.Equal(t, "parse", result.Details["error_type"])
+		assert.Equal(t, "empty response", result.Details["error"])
+	}
 }
 
 func TestCloudflaredTunnelScraper_Scrape_Timeout(t *testing.T) {
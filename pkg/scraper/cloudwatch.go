@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+// cloudWatchAlarm is the state of a CloudWatch metric or composite alarm
+type cloudWatchAlarm struct {
+	Name        string    `xml:"AlarmName"`
+	State       string    `xml:"StateValue"`
+	StateReason string    `xml:"StateReason"`
+	UpdatedAt   time.Time `xml:"StateUpdatedTimestamp"`
+}
+
+// cloudWatchClient reads the state of CloudWatch alarms
+type cloudWatchClient interface {
+	DescribeAlarm(ctx context.Context, name string) (*cloudWatchAlarm, error)
+}
+
+// awsThrottlingError is returned when AWS rejected a request because of its rate limits
+type awsThrottlingError struct {
+	message string
+}
+
+func (e *awsThrottlingError) Error() string {
+	return "request throttled: " + e.message
+}
+
+// cloudWatchAPI is a cloudWatchClient using the CloudWatch query API
+type cloudWatchAPI struct {
+	endpoint string
+	region   string
+	creds    awsCredentials
+	client   *http.Client
+}
+
+// newCloudWatchAPI creates a CloudWatch client for the configured region. The endpoint
+// defaults to the region's public endpoint unless the scrape URL overrides it. Requests are
+// signed with the configured credentials, or those from the standard AWS environment variables.
+func newCloudWatchAPI(scraperConfig config.HealthcheckScraper, client *http.Client) (*cloudWatchAPI, error) {
+	if scraperConfig.AWSRegion == "" {
+		return nil, fmt.Errorf("aws-health scraper requires an aws_region")
+	}
+
+	endpoint := scraperConfig.ScrapeURL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://monitoring.%s.amazonaws.com/", scraperConfig.AWSRegion)
+	}
+	if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid CloudWatch endpoint: %s", endpoint)
+	}
+
+	creds, err := awsCredentialsFromConfig(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloudWatchAPI{
+		endpoint: endpoint,
+		region:   scraperConfig.AWSRegion,
+		creds:    creds,
+		client:   client,
+	}, nil
+}
+
+// DescribeAlarm returns the state of the metric or composite alarm with the given name
+func (c *cloudWatchAPI) DescribeAlarm(ctx context.Context, name string) (*cloudWatchAlarm, error) {
+	form := url.Values{
+		"Action":              {"DescribeAlarms"},
+		"Version":             {"2010-08-01"},
+		"AlarmNames.member.1": {name},
+		"AlarmTypes.member.1": {"MetricAlarm"},
+		"AlarmTypes.member.2": {"CompositeAlarm"},
+		"MaxRecords":          {"1"},
+	}
+	body := []byte(form.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(req, body, c.creds, c.region, "monitoring", time.Now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, cloudWatchError(resp)
+	}
+
+	var result struct {
+		MetricAlarms    []cloudWatchAlarm `xml:"DescribeAlarmsResult>MetricAlarms>member"`
+		CompositeAlarms []cloudWatchAlarm `xml:"DescribeAlarmsResult>CompositeAlarms>member"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	alarms := append(result.MetricAlarms, result.CompositeAlarms...)
+	if len(alarms) == 0 {
+		return nil, fmt.Errorf("alarm %s not found", name)
+	}
+
+	return &alarms[0], nil
+}
+
+// Close closes the idle connections of the client's HTTP client
+func (c *cloudWatchAPI) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// cloudWatchError converts an error response to an error, distinguishing throttling
+func cloudWatchError(resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var errorResponse struct {
+		Code    string `xml:"Error>Code"`
+		Message string `xml:"Error>Message"`
+	}
+	xml.Unmarshal(message, &errorResponse)
+
+	if resp.StatusCode == http.StatusTooManyRequests || errorResponse.Code == "Throttling" || errorResponse.Code == "ThrottlingException" {
+		return &awsThrottlingError{message: errorResponse.Message}
+	}
+
+	if errorResponse.Code != "" {
+		return fmt.Errorf("HTTP status %d from CloudWatch: %s: %s", resp.StatusCode, errorResponse.Code, errorResponse.Message)
+	}
+	return fmt.Errorf("HTTP status %d from CloudWatch: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,133 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCloudWatchAPI creates a CloudWatch client for the test server with static credentials
+func newTestCloudWatchAPI(t *testing.T, server *httptest.Server) *cloudWatchAPI {
+	client, err := newCloudWatchAPI(config.HealthcheckScraper{
+		ScrapeURL:          server.URL,
+		AWSRegion:          "eu-west-1",
+		AWSAccessKeyID:     "AKIDEXAMPLE",
+		AWSSecretAccessKey: "secret",
+	}, server.Client())
+	require.NoError(t, err)
+	return client
+}
+
+func TestCloudWatchAPI_DescribeAlarm(t *testing.T) {
+	var form map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/monitoring/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`<DescribeAlarmsResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+  <DescribeAlarmsResult>
+    <MetricAlarms>
+      <member>
+        <AlarmName>api-5xx</AlarmName>
+        <StateValue>ALARM</StateValue>
+        <StateReason>Threshold Crossed: 1 datapoint [12.0] was greater than the threshold (5.0).</StateReason>
+        <StateUpdatedTimestamp>2024-05-01T12:30:00.000Z</StateUpdatedTimestamp>
+      </member>
+    </MetricAlarms>
+    <CompositeAlarms/>
+  </DescribeAlarmsResult>
+</DescribeAlarmsResponse>`))
+	}))
+	defer server.Close()
+
+	alarm, err := newTestCloudWatchAPI(t, server).DescribeAlarm(context.Background(), "api-5xx")
+
+	require.NoError(t, err)
+	assert.Equal(t, "DescribeAlarms", form["Action"][0])
+	assert.Equal(t, "api-5xx", form["AlarmNames.member.1"][0])
+	assert.Equal(t, "ALARM", alarm.State)
+	assert.Contains(t, alarm.StateReason, "Threshold Crossed")
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), alarm.UpdatedAt)
+}
+
+func TestCloudWatchAPI_DescribeAlarm_CompositeAlarm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<DescribeAlarmsResponse><DescribeAlarmsResult><MetricAlarms/><CompositeAlarms><member>
+<AlarmName>service</AlarmName><StateValue>OK</StateValue></member></CompositeAlarms></DescribeAlarmsResult></DescribeAlarmsResponse>`))
+	}))
+	defer server.Close()
+
+	alarm, err := newTestCloudWatchAPI(t, server).DescribeAlarm(context.Background(), "service")
+
+	require.NoError(t, err)
+	assert.Equal(t, "OK", alarm.State)
+}
+
+func TestCloudWatchAPI_DescribeAlarm_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<DescribeAlarmsResponse><DescribeAlarmsResult><MetricAlarms/></DescribeAlarmsResult></DescribeAlarmsResponse>`))
+	}))
+	defer server.Close()
+
+	_, err := newTestCloudWatchAPI(t, server).DescribeAlarm(context.Background(), "missing")
+
+	assert.ErrorContains(t, err, "alarm missing not found")
+}
+
+func TestCloudWatchAPI_DescribeAlarm_Throttled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`))
+	}))
+	defer server.Close()
+
+	_, err := newTestCloudWatchAPI(t, server).DescribeAlarm(context.Background(), "api-5xx")
+
+	var throttled *awsThrottlingError
+	require.ErrorAs(t, err, &throttled)
+	assert.Equal(t, "Rate exceeded", throttled.message)
+}
+
+func TestCloudWatchAPI_DescribeAlarm_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<ErrorResponse><Error><Code>AccessDenied</Code><Message>not authorized</Message></Error></ErrorResponse>`))
+	}))
+	defer server.Close()
+
+	_, err := newTestCloudWatchAPI(t, server).DescribeAlarm(context.Background(), "api-5xx")
+
+	assert.ErrorContains(t, err, "AccessDenied: not authorized")
+}
+
+func TestNewCloudWatchAPI(t *testing.T) {
+	client, err := newCloudWatchAPI(config.HealthcheckScraper{
+		AWSRegion:          "us-east-1",
+		AWSAccessKeyID:     "AKIDEXAMPLE",
+		AWSSecretAccessKey: "secret",
+	}, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "https://monitoring.us-east-1.amazonaws.com/", client.endpoint)
+
+	setTestAWSCredentials(t)
+	client, err = newCloudWatchAPI(config.HealthcheckScraper{AWSRegion: "us-east-1"}, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, testAWSCredentials.accessKeyID, client.creds.accessKeyID)
+
+	_, err = newCloudWatchAPI(config.HealthcheckScraper{}, http.DefaultClient)
+	assert.ErrorContains(t, err, "aws_region")
+
+	_, err = newCloudWatchAPI(config.HealthcheckScraper{AWSRegion: "us-east-1", AWSAccessKeyID: "AKIDEXAMPLE"}, http.DefaultClient)
+	assert.ErrorContains(t, err, "aws_secret_access_key")
+}
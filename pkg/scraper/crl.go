@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCRLSize is the largest CRL that is downloaded
+	maxCRLSize = 20 << 20
+	// defaultCRLCacheTTL is how long a CRL without a next update is cached
+	defaultCRLCacheTTL = time.Hour
+)
+
+// sharedCRLCache is shared by all TLS scrapers checking revocation via CRL
+var sharedCRLCache = newCRLCache(&http.Client{Timeout: 30 * time.Second})
+
+// crlCache holds downloaded CRLs keyed by their distribution point until their next update
+type crlCache struct {
+	client  *http.Client
+	mu      sync.Mutex
+	entries map[string]*x509.RevocationList
+}
+
+// newCRLCache creates an empty CRL cache downloading with the given client
+func newCRLCache(client *http.Client) *crlCache {
+	return &crlCache{client: client, entries: make(map[string]*x509.RevocationList)}
+}
+
+// get returns the CRL of the distribution point signed by the issuer, downloading it unless
+// a cached copy is still current, and whether the cached copy was used
+func (c *crlCache) get(ctx context.Context, url string, issuer *x509.Certificate) (*x509.RevocationList, bool, error) {
+	c.mu.Lock()
+	list, ok := c.entries[url]
+	c.mu.Unlock()
+	if ok && time.Now().Before(crlExpiry(list)) {
+		return list, true, nil
+	}
+
+	list, err := c.fetch(ctx, url)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := list.CheckSignatureFrom(issuer); err != nil {
+		return nil, false, fmt.Errorf("invalid CRL signature: %w", err)
+	}
+
+	c.mu.Lock()
+	c.entries[url] = list
+	c.mu.Unlock()
+
+	return list, false, nil
+}
+
+// fetch downloads and parses the CRL at the URL, refusing CRLs larger than maxCRLSize
+func (c *crlCache) fetch(ctx context.Context, url string) (*x509.RevocationList, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download CRL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to download CRL: HTTP status %d", resp.StatusCode)
+	}
+
+	der, err := io.ReadAll(io.LimitReader(resp.Body, maxCRLSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download CRL: %w", err)
+	}
+	if len(der) > maxCRLSize {
+		return nil, fmt.Errorf("CRL exceeds %d bytes", maxCRLSize)
+	}
+
+	list, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRL: %w", err)
+	}
+	return list, nil
+}
+
+// crlExpiry returns when a cached CRL must be downloaded again
+func crlExpiry(list *x509.RevocationList) time.Time {
+	if list.NextUpdate.IsZero() {
+		return list.ThisUpdate.Add(defaultCRLCacheTTL)
+	}
+	return list.NextUpdate
+}
+
+// crlStatus is the outcome of checking the leaf certificate against its CRLs
+type crlStatus struct {
+	revoked    bool
+	nextUpdate time.Time
+	cached     bool
+}
+
+// checkCRL checks the leaf certificate of a verified connection against the CRL of each
+// of its distribution points
+func checkCRL(ctx context.Context, cache *crlCache, state tls.ConnectionState) (crlStatus, error) {
+	var status crlStatus
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return status, fmt.Errorf("no verified certificate chain")
+	}
+
+	chain := state.VerifiedChains[0]
+	leaf, issuer := chain[0], chain[0]
+	if len(chain) > 1 {
+		issuer = chain[1]
+	}
+	if len(leaf.CRLDistributionPoints) == 0 {
+		return status, fmt.Errorf("certificate has no CRL distribution point")
+	}
+
+	status.cached = true
+	for _, url := range leaf.CRLDistributionPoints {
+		list, cached, err := cache.get(ctx, url, issuer)
+		if err != nil {
+			return status, fmt.Errorf("%s: %w", url, err)
+		}
+		status.cached = status.cached && cached
+		if !list.NextUpdate.IsZero() && (status.nextUpdate.IsZero() || list.NextUpdate.Before(status.nextUpdate)) {
+			status.nextUpdate = list.NextUpdate
+		}
+
+		for _, entry := range list.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				status.revoked = true
+				return status, nil
+			}
+		}
+	}
+
+	return status, nil
+}
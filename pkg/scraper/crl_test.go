@@ -0,0 +1,195 @@
+package scraper
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCRLCertificate creates a self-signed certificate for 127.0.0.1 that lists the
+// given CRL distribution point and can sign CRLs
+func newTestCRLCertificate(t *testing.T, crlURL string) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(42),
+		Subject:               pkix.Name{CommonName: "healthcheck test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(90 * 24 * time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		CRLDistributionPoints: []string{crlURL},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+// newTestCRL creates a CRL signed by the certificate revoking the given serial numbers
+func newTestCRL(t *testing.T, issuer tls.Certificate, nextUpdate time.Time, revoked ...int64) []byte {
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	for _, serial := range revoked {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                time.Now().Add(-time.Minute),
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, issuer.Leaf, issuer.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	return der
+}
+
+// crlTestServer serves a CRL that is set once the certificate listing it exists
+type crlTestServer struct {
+	*httptest.Server
+	crl       atomic.Value
+	downloads atomic.Int32
+}
+
+// newCRLTestServer starts a server serving the CRL set on it
+func newCRLTestServer(t *testing.T) *crlTestServer {
+	server := &crlTestServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.downloads.Add(1)
+		crl, _ := server.crl.Load().([]byte)
+		if crl == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(crl)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTLSScraper_Scrape_CRL(t *testing.T) {
+	tests := []struct {
+		name    string
+		revoked []int64
+		healthy bool
+	}{
+		{name: "not revoked", revoked: []int64{7}, healthy: true},
+		{name: "revoked", revoked: []int64{7, 42}, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crlServer := newCRLTestServer(t)
+			cert, roots := newTestCRLCertificate(t, crlServer.URL+"/ca.crl")
+			nextUpdate := time.Now().Add(time.Hour).Truncate(time.Second)
+			crlServer.crl.Store(newTestCRL(t, cert, nextUpdate, tt.revoked...))
+
+			scraper := newTestTLSScraper(t, startTLSServer(t, cert), roots, config.HealthcheckScraper{CheckCRL: true})
+			scraper.crls = newCRLCache(crlServer.Client())
+
+			result, err := scraper.Scrape(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, !tt.healthy, result.Details["revoked"])
+			assert.Equal(t, false, result.Details["crl_cached"])
+			assert.True(t, nextUpdate.Equal(result.Details["crl_next_update"].(time.Time)))
+		})
+	}
+}
+
+func TestTLSScraper_Scrape_CRLCached(t *testing.T) {
+	crlServer := newCRLTestServer(t)
+	cert, roots := newTestCRLCertificate(t, crlServer.URL+"/ca.crl")
+	crlServer.crl.Store(newTestCRL(t, cert, time.Now().Add(time.Hour)))
+
+	scraper := newTestTLSScraper(t, startTLSServer(t, cert), roots, config.HealthcheckScraper{CheckCRL: true})
+	scraper.crls = newCRLCache(crlServer.Client())
+
+	for _, cached := range []bool{false, true} {
+		result, err := scraper.Scrape(context.Background())
+		require.NoError(t, err)
+		assert.True(t, result.Healthy, result.Message)
+		assert.Equal(t, cached, result.Details["crl_cached"])
+	}
+	assert.Equal(t, int32(1), crlServer.downloads.Load())
+}
+
+func TestTLSScraper_Scrape_CRLExpiredIsDownloadedAgain(t *testing.T) {
+	crlServer := newCRLTestServer(t)
+	cert, roots := newTestCRLCertificate(t, crlServer.URL+"/ca.crl")
+	crlServer.crl.Store(newTestCRL(t, cert, time.Now().Add(-time.Second)))
+
+	scraper := newTestTLSScraper(t, startTLSServer(t, cert), roots, config.HealthcheckScraper{CheckCRL: true})
+	scraper.crls = newCRLCache(crlServer.Client())
+
+	for i := 0; i < 2; i++ {
+		result, err := scraper.Scrape(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, false, result.Details["crl_cached"])
+	}
+	assert.Equal(t, int32(2), crlServer.downloads.Load())
+}
+
+func TestTLSScraper_Scrape_CRLFetchFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		hardFail bool
+		healthy  bool
+	}{
+		{name: "soft-fail", hardFail: false, healthy: true},
+		{name: "hard-fail", hardFail: true, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crlServer := newCRLTestServer(t)
+			cert, roots := newTestCRLCertificate(t, crlServer.URL+"/ca.crl")
+
+			scraper := newTestTLSScraper(t, startTLSServer(t, cert), roots, config.HealthcheckScraper{
+				CheckCRL:    true,
+				CRLHardFail: tt.hardFail,
+			})
+			scraper.crls = newCRLCache(crlServer.Client())
+
+			result, err := scraper.Scrape(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Contains(t, result.Details["crl_error"], "HTTP status 404")
+		})
+	}
+}
+
+func TestCRLCache_RejectsWrongIssuer(t *testing.T) {
+	crlServer := newCRLTestServer(t)
+	cert, _ := newTestCRLCertificate(t, crlServer.URL+"/ca.crl")
+	other, _ := newTestCRLCertificate(t, crlServer.URL+"/ca.crl")
+	crlServer.crl.Store(newTestCRL(t, other, time.Now().Add(time.Hour)))
+
+	_, _, err := newCRLCache(crlServer.Client()).get(context.Background(), crlServer.URL+"/ca.crl", cert.Leaf)
+	assert.ErrorContains(t, err, "invalid CRL signature")
+}
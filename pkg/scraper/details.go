@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"time"
+)
+
+// Standard detail keys that mean the same for every scraper reporting them, so the status,
+// metrics and notification layers can rely on their names and types
+const (
+	// DetailLatencyMs is the duration of the checked operation in milliseconds, as an int64
+	DetailLatencyMs = "latency_ms"
+	// DetailStatusCode is the HTTP status code of the checked response, as an int
+	DetailStatusCode = "status_code"
+	// DetailRemoteAddr is the address of the server that answered, as a string
+	DetailRemoteAddr = "remote_addr"
+)
+
+// detailAliases maps keys some scrapers report for historical reasons to the standard key
+// they duplicate
+var detailAliases = map[string]string{
+	"total_ms":         DetailLatencyMs,
+	"total_latency_ms": DetailLatencyMs,
+	"response_time_ms": DetailLatencyMs,
+	"http_status":      DetailStatusCode,
+	"remote_address":   DetailRemoteAddr,
+}
+
+// setLatency sets the standard latency key
+func setLatency(details map[string]interface{}, latency time.Duration) {
+	details[DetailLatencyMs] = latency.Milliseconds()
+}
+
+// setStatusCode sets the standard status code key
+func setStatusCode(details map[string]interface{}, code int) {
+	details[DetailStatusCode] = code
+}
+
+// setRemoteAddr sets the standard remote address key
+func setRemoteAddr(details map[string]interface{}, addr string) {
+	details[DetailRemoteAddr] = addr
+}
+
+// NormalizeDetails fills in the standard keys from their aliases when a scraper did not set
+// them and converts their values to the standard types. The aliases are kept, as templates
+// and dashboards may still refer to them.
+func NormalizeDetails(details map[string]interface{}) {
+	if details == nil {
+		return
+	}
+
+	for alias, key := range detailAliases {
+		if _, ok := details[key]; ok {
+			continue
+		}
+		if value, ok := details[alias]; ok {
+			details[key] = value
+		}
+	}
+
+	if value, ok := details[DetailLatencyMs]; ok {
+		if number, ok := toInt64(value); ok {
+			details[DetailLatencyMs] = number
+		}
+	}
+	if value, ok := details[DetailStatusCode]; ok {
+		if number, ok := toInt64(value); ok {
+			details[DetailStatusCode] = int(number)
+		}
+	}
+}
+
+// toInt64 converts the numeric types scrapers and decoded JSON use to an int64
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	case time.Duration:
+		return v.Milliseconds(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDetails(t *testing.T) {
+	details := map[string]interface{}{
+		"total_latency_ms": int64(250),
+		"status_code":      503.0,
+		"remote_address":   "10.0.0.1:443",
+	}
+
+	NormalizeDetails(details)
+
+	assert.Equal(t, int64(250), details[DetailLatencyMs])
+	assert.Equal(t, 503, details[DetailStatusCode])
+	assert.Equal(t, "10.0.0.1:443", details[DetailRemoteAddr])
+	assert.Equal(t, int64(250), details["total_latency_ms"])
+}
+
+func TestNormalizeDetails_KeepsStandardKeys(t *testing.T) {
+	details := map[string]interface{}{
+		"latency_ms": int64(40),
+		"total_ms":   int64(90),
+	}
+
+	NormalizeDetails(details)
+	NormalizeDetails(nil)
+
+	assert.Equal(t, int64(40), details[DetailLatencyMs])
+}
+
+func TestSetDetailHelpers(t *testing.T) {
+	details := map[string]interface{}{}
+
+	setLatency(details, 1500*time.Millisecond)
+	setStatusCode(details, 200)
+	setRemoteAddr(details, "127.0.0.1:8080")
+
+	assert.Equal(t, map[string]interface{}{
+		"latency_ms":  int64(1500),
+		"status_code": 200,
+		"remote_addr": "127.0.0.1:8080",
+	}, details)
+}
+
+// TestStandardDetails_AcrossScraperTypes checks scrapers of different types report the
+// standard keys under the same names and with the same types
+func TestStandardDetails_AcrossScraperTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Grpc-Web") == "1" {
+			w.Header().Set("Content-Type", "application/grpc-web+proto")
+			w.Write(grpcWebFrame(grpcWebTrailerFlag, []byte("grpc-status:0\r\n")))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	factory := NewFactory(logrus.New())
+	configs := []config.HealthcheckScraper{
+		{Type: "http", ScrapeURL: server.URL},
+		{Type: "grpc-web", ScrapeURL: server.URL, GRPCMethod: "/grpc.health.v1.Health/Check"},
+	}
+
+	for _, scraperConfig := range configs {
+		t.Run(scraperConfig.Type, func(t *testing.T) {
+			s, err := factory.CreateScraper(scraperConfig)
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+			require.NoError(t, err)
+			require.True(t, result.Healthy, result.Message)
+			NormalizeDetails(result.Details)
+
+			assert.IsType(t, int64(0), result.Details[DetailLatencyMs])
+			assert.Equal(t, http.StatusOK, result.Details[DetailStatusCode])
+			assert.Equal(t, server.Listener.Addr().String(), result.Details[DetailRemoteAddr])
+		})
+	}
+}
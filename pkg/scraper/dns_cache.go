@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// dnsCacheEntry holds the resolved addresses for a host and their expiry
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsCache is an in-process DNS cache that reuses resolved addresses for a configured TTL
+type dnsCache struct {
+	ttl        time.Duration
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	dialer     *net.Dialer
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+// newDNSCache creates a DNS cache backed by the default resolver that dials using dialer
+func newDNSCache(ttl time.Duration, dialer *net.Dialer) *dnsCache {
+	return &dnsCache{
+		ttl:        ttl,
+		lookupHost: net.DefaultResolver.LookupHost,
+		dialer:     dialer,
+		now:        time.Now,
+		entries:    make(map[string]dnsCacheEntry),
+	}
+}
+
+// resolve returns the cached addresses for host, resolving it again if the entry expired
+func (d *dnsCache) resolve(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+
+	if ok && d.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := d.lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+
+	d.mu.Lock()
+	d.entries[host] = dnsCacheEntry{
+		addrs:   addrs,
+		expires: d.now().Add(d.ttl),
+	}
+	d.mu.Unlock()
+
+	return addrs, nil
+}
+
+// invalidate drops the cached entry for host so the next dial resolves it again
+func (d *dnsCache) invalidate(host string) {
+	d.mu.Lock()
+	delete(d.entries, host)
+	d.mu.Unlock()
+}
+
+// DialContext dials addr using the cached addresses of its host
+func (d *dnsCache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Literal IPs don't need resolving
+	if net.ParseIP(host) != nil {
+		return d.dialer.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, ip := range addrs {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+
+	// The cached addresses may be stale, resolve again on the next attempt
+	d.invalidate(host)
+	return nil, lastErr
+}
@@ -0,0 +1,100 @@
+package scraper
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDNSCache creates a DNS cache with a fake resolver and a controllable clock
+func newTestDNSCache(ttl time.Duration, addrs []string) (*dnsCache, *int, *time.Time) {
+	lookups := 0
+	now := time.Now()
+
+	cache := newDNSCache(ttl, &net.Dialer{})
+	cache.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return addrs, nil
+	}
+	cache.now = func() time.Time {
+		return now
+	}
+
+	return cache, &lookups, &now
+}
+
+func TestDNSCache_ReusesAddressesWithinTTL(t *testing.T) {
+	cache, lookups, now := newTestDNSCache(time.Minute, []string{"127.0.0.1"})
+
+	for i := 0; i < 3; i++ {
+		addrs, err := cache.resolve(context.Background(), "example.internal")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"127.0.0.1"}, addrs)
+		*now = now.Add(10 * time.Second)
+	}
+
+	assert.Equal(t, 1, *lookups)
+}
+
+func TestDNSCache_ResolvesAgainAfterExpiry(t *testing.T) {
+	cache, lookups, now := newTestDNSCache(time.Minute, []string{"127.0.0.1"})
+
+	_, err := cache.resolve(context.Background(), "example.internal")
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Minute)
+
+	_, err = cache.resolve(context.Background(), "example.internal")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, *lookups)
+}
+
+func TestDNSCache_DialContext_UsesCachedAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	cache, lookups, _ := newTestDNSCache(time.Minute, []string{"127.0.0.1"})
+	client := &http.Client{
+		Transport: &http.Transport{DialContext: cache.DialContext},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get("http://example.internal:" + port)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	assert.Equal(t, 1, *lookups)
+}
+
+func TestDNSCache_DialContext_InvalidatesOnConnectionFailure(t *testing.T) {
+	// Grab a free port and close the listener so nothing accepts connections on it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	listener.Close()
+
+	cache, lookups, _ := newTestDNSCache(time.Minute, []string{"127.0.0.1"})
+
+	_, err = cache.DialContext(context.Background(), "tcp", "example.internal:"+port)
+	assert.Error(t, err)
+
+	_, err = cache.DialContext(context.Background(), "tcp", "example.internal:"+port)
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, *lookups)
+}
@@ -0,0 +1,133 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DNSConsistencyScraper implements the Scraper interface for checking that several
+// resolvers agree on the addresses of a hostname
+type DNSConsistencyScraper struct {
+	hostname              string
+	resolvers             []string
+	pingURL               string
+	scrapeIntervalSeconds int
+	logger                *logrus.Logger
+	lookup                func(ctx context.Context, resolver, hostname string) ([]string, error)
+}
+
+// NewDNSConsistencyScraper creates a new DNS consistency scraper
+func NewDNSConsistencyScraper(hostname string, resolvers []string, pingURL string, scrapeIntervalSeconds int, logger *logrus.Logger) *DNSConsistencyScraper {
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &DNSConsistencyScraper{
+		hostname:              hostname,
+		resolvers:             resolvers,
+		pingURL:               pingURL,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		logger:                logger,
+		lookup:                lookupWithResolver,
+	}
+}
+
+// Type returns the scraper type identifier
+func (d *DNSConsistencyScraper) Type() string {
+	return "dns-consistency"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (d *DNSConsistencyScraper) GetPingURL() string {
+	return d.pingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (d *DNSConsistencyScraper) GetScrapeInterval() int {
+	return d.scrapeIntervalSeconds
+}
+
+// Scrape resolves the hostname against every resolver and compares the answers
+func (d *DNSConsistencyScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	d.logger.WithFields(logrus.Fields{
+		"hostname":  d.hostname,
+		"resolvers": d.resolvers,
+	}).Debug("Starting DNS consistency healthcheck")
+
+	answers := make(map[string]interface{}, len(d.resolvers))
+	var failed []string
+	var reference string
+	consistent := true
+
+	for _, resolver := range d.resolvers {
+		addrs, err := d.lookup(ctx, resolver, d.hostname)
+		if err != nil {
+			answers[resolver] = err.Error()
+			failed = append(failed, resolver)
+			continue
+		}
+
+		sort.Strings(addrs)
+		answers[resolver] = addrs
+
+		answer := strings.Join(addrs, ",")
+		if reference == "" {
+			reference = answer
+		} else if answer != reference {
+			consistent = false
+		}
+	}
+
+	healthy := len(failed) == 0 && consistent
+
+	var message string
+	switch {
+	case len(failed) > 0:
+		message = fmt.Sprintf("Failed to resolve %s via %s", d.hostname, strings.Join(failed, ", "))
+	case !consistent:
+		message = fmt.Sprintf("Resolvers disagree on the addresses of %s", d.hostname)
+	default:
+		message = fmt.Sprintf("All %d resolvers agree on the addresses of %s", len(d.resolvers), d.hostname)
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"hostname": d.hostname,
+		"answers":  answers,
+		"healthy":  healthy,
+	}).Info("DNS consistency healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details: map[string]interface{}{
+			"hostname": d.hostname,
+			"answers":  answers,
+		},
+	}, nil
+}
+
+// lookupWithResolver resolves hostname by querying the given DNS server directly,
+// defaulting to port 53 when the resolver address has no port
+func lookupWithResolver(ctx context.Context, resolver, hostname string) ([]string, error) {
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(resolver, "53")
+	}
+
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialer := &net.Dialer{Timeout: 5 * time.Second}
+			return dialer.DialContext(ctx, network, resolver)
+		},
+	}
+
+	return r.LookupHost(ctx, hostname)
+}
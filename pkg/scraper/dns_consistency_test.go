@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDNSConsistencyTestScraper creates a scraper whose resolvers answer from the given map
+func newDNSConsistencyTestScraper(answers map[string][]string) *DNSConsistencyScraper {
+	resolvers := make([]string, 0, len(answers))
+	for resolver := range answers {
+		resolvers = append(resolvers, resolver)
+	}
+
+	scraper := NewDNSConsistencyScraper("app.example.com", resolvers, "http://localhost:8081/ping", 30, logrus.New())
+	scraper.lookup = func(ctx context.Context, resolver, hostname string) ([]string, error) {
+		addrs, ok := answers[resolver]
+		if !ok || addrs == nil {
+			return nil, fmt.Errorf("lookup %s on %s: no such host", hostname, resolver)
+		}
+		return append([]string(nil), addrs...), nil
+	}
+
+	return scraper
+}
+
+func TestNewDNSConsistencyScraper(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewDNSConsistencyScraper("app.example.com", []string{"1.1.1.1", "8.8.8.8"}, "http://localhost:8081/ping", 0, logger)
+
+	assert.Equal(t, "dns-consistency", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestDNSConsistencyScraper_Scrape_Consistent(t *testing.T) {
+	scraper := newDNSConsistencyTestScraper(map[string][]string{
+		"1.1.1.1": {"10.0.0.2", "10.0.0.1"},
+		"8.8.8.8": {"10.0.0.1", "10.0.0.2"},
+	})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Contains(t, result.Message, "All 2 resolvers agree")
+	answers := result.Details["answers"].(map[string]interface{})
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, answers["1.1.1.1"])
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, answers["8.8.8.8"])
+}
+
+func TestDNSConsistencyScraper_Scrape_Divergent(t *testing.T) {
+	scraper := newDNSConsistencyTestScraper(map[string][]string{
+		"1.1.1.1": {"10.0.0.1"},
+		"8.8.8.8": {"10.0.0.9"},
+	})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Resolvers disagree")
+}
+
+func TestDNSConsistencyScraper_Scrape_ResolverFailure(t *testing.T) {
+	scraper := newDNSConsistencyTestScraper(map[string][]string{
+		"1.1.1.1": {"10.0.0.1"},
+		"8.8.8.8": nil,
+	})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to resolve app.example.com via 8.8.8.8")
+	answers := result.Details["answers"].(map[string]interface{})
+	assert.Contains(t, answers["8.8.8.8"], "no such host")
+}
+
+func TestFactory_CreateScraper_DNSConsistency_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "dns-consistency",
+		Resolvers: []string{"1.1.1.1", "8.8.8.8"},
+	})
+	assert.Contains(t, err.Error(), "requires a hostname")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "dns-consistency",
+		Hostname:  "app.example.com",
+		Resolvers: []string{"1.1.1.1"},
+	})
+	assert.Contains(t, err.Error(), "requires at least 2 resolvers")
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "dns-consistency",
+		Hostname:  "app.example.com",
+		Resolvers: []string{"1.1.1.1", "8.8.8.8:53"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dns-consistency", scraper.Type())
+}
@@ -0,0 +1,284 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+const (
+	// defaultDoHCacheTTL is how long DoH resolutions are reused when dns_cache_ttl_seconds is unset
+	defaultDoHCacheTTL = 30 * time.Second
+	// dohTimeout bounds a single DoH query
+	dohTimeout = 5 * time.Second
+	// dohMessageType is the media type of DNS wire format messages (RFC 8484)
+	dohMessageType = "application/dns-message"
+	// maxDoHResponseSize is the largest DNS message a DoH response may carry
+	maxDoHResponseSize = 65535
+)
+
+// DNS record types and response codes used by the DoH resolver
+const (
+	dnsTypeA         = 1
+	dnsTypeAAAA      = 28
+	dnsClassIN       = 1
+	dnsRcodeNXDomain = 3
+)
+
+// dohResolver resolves hostnames over DNS-over-HTTPS (RFC 8484). Its own requests use the
+// system resolver, so the resolver URL should name an IP address or a host the system can
+// resolve.
+type dohResolver struct {
+	url    string
+	client *http.Client
+}
+
+// newDoHResolver creates a DoH resolver for the scraper's doh_resolver_url, trusting the
+// scraper's custom CA certificates in addition to the system roots
+func newDoHResolver(scraperConfig config.HealthcheckScraper) (*dohResolver, error) {
+	parsed, err := url.Parse(scraperConfig.DoHResolverURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid doh_resolver_url %q, expected an https URL", scraperConfig.DoHResolverURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	rootCAs, err := newRootCAs(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+	if rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+
+	return &dohResolver{
+		url:    scraperConfig.DoHResolverURL,
+		client: &http.Client{Timeout: dohTimeout, Transport: transport},
+	}, nil
+}
+
+// lookupHost resolves the IPv4 and IPv6 addresses of host, IPv4 first, and records the
+// lookup for the request being dialed
+func (r *dohResolver) lookupHost(ctx context.Context, host string) ([]string, error) {
+	start := time.Now()
+	var addrs []string
+	var errs []error
+	nxdomain := 0
+	for _, qtype := range []uint16{dnsTypeA, dnsTypeAAAA} {
+		answers, err := r.query(ctx, host, qtype)
+		if errors.Is(err, errNXDomain) {
+			nxdomain++
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		addrs = append(addrs, answers...)
+	}
+	latency := time.Since(start)
+
+	if len(addrs) == 0 {
+		err := errors.Join(errs...)
+		if err == nil {
+			err = fmt.Errorf("no addresses found")
+			if nxdomain > 0 {
+				err = errNXDomain
+			}
+		}
+		return nil, fmt.Errorf("DoH lookup of %s via %s failed: %w", host, r.url, err)
+	}
+
+	if lookup, ok := ctx.Value(dohLookupKey{}).(*dohLookup); ok {
+		lookup.record(addrs, latency)
+	}
+	return addrs, nil
+}
+
+// errNXDomain reports that the queried name does not exist
+var errNXDomain = errors.New("no such host")
+
+// query sends a DoH GET request for the records of type qtype of host and returns the
+// addresses of the answer
+func (r *dohResolver) query(ctx context.Context, host string, qtype uint16) ([]string, error) {
+	message, err := encodeDNSQuery(host, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("dns", base64.RawURLEncoding.EncodeToString(message))
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Accept", dohMessageType)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned HTTP status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDoHResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	return decodeDNSAnswers(body, qtype)
+}
+
+// encodeDNSQuery encodes a recursive query for the records of type qtype of host. The ID is 0
+// as RFC 8484 recommends, which keeps the GET requests cacheable.
+func encodeDNSQuery(host string, qtype uint16) ([]byte, error) {
+	message := []byte{
+		0, 0, // ID
+		1, 0, // Flags: recursion desired
+		0, 1, // QDCOUNT
+		0, 0, 0, 0, 0, 0, // ANCOUNT, NSCOUNT, ARCOUNT
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("invalid hostname %q", host)
+		}
+		message = append(message, byte(len(label)))
+		message = append(message, label...)
+	}
+	message = append(message, 0)
+	message = binary.BigEndian.AppendUint16(message, qtype)
+	message = binary.BigEndian.AppendUint16(message, dnsClassIN)
+	return message, nil
+}
+
+// decodeDNSAnswers returns the addresses of the answer records of type qtype in a DNS
+// response, skipping others such as the CNAMEs leading to them
+func decodeDNSAnswers(message []byte, qtype uint16) ([]string, error) {
+	if len(message) < 12 {
+		return nil, fmt.Errorf("truncated DNS response")
+	}
+	switch rcode := message[3] & 0x0f; rcode {
+	case 0:
+	case dnsRcodeNXDomain:
+		return nil, errNXDomain
+	default:
+		return nil, fmt.Errorf("DNS response code %d", rcode)
+	}
+
+	questions := int(binary.BigEndian.Uint16(message[4:6]))
+	answers := int(binary.BigEndian.Uint16(message[6:8]))
+	offset := 12
+	var err error
+	for range questions {
+		if offset, err = skipDNSName(message, offset); err != nil {
+			return nil, err
+		}
+		offset += 4 // QTYPE, QCLASS
+	}
+
+	var addrs []string
+	for range answers {
+		if offset, err = skipDNSName(message, offset); err != nil {
+			return nil, err
+		}
+		if offset+10 > len(message) {
+			return nil, fmt.Errorf("truncated DNS response")
+		}
+		rtype := binary.BigEndian.Uint16(message[offset:])
+		length := int(binary.BigEndian.Uint16(message[offset+8:]))
+		offset += 10
+		if offset+length > len(message) {
+			return nil, fmt.Errorf("truncated DNS response")
+		}
+		data := message[offset : offset+length]
+		offset += length
+
+		if rtype != qtype || (rtype == dnsTypeA && length != net.IPv4len) || (rtype == dnsTypeAAAA && length != net.IPv6len) {
+			continue
+		}
+		addrs = append(addrs, net.IP(data).String())
+	}
+	return addrs, nil
+}
+
+// skipDNSName returns the offset following the possibly compressed name at offset
+func skipDNSName(message []byte, offset int) (int, error) {
+	for offset < len(message) {
+		length := int(message[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			// A pointer ends the name
+			return offset + 2, nil
+		default:
+			offset += 1 + length
+		}
+	}
+	return 0, fmt.Errorf("truncated DNS response")
+}
+
+// dohLookupKey is the request context key of the DoH lookup made to dial the request
+type dohLookupKey struct{}
+
+// dohLookup holds the DoH lookup made while dialing a request, if any
+type dohLookup struct {
+	mu       sync.Mutex
+	resolved bool
+	addrs    []string
+	latency  time.Duration
+}
+
+// record stores the addresses a lookup resolved and how long it took
+func (l *dohLookup) record(addrs []string, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.resolved = true
+	l.addrs = addrs
+	l.latency = latency
+}
+
+// dohTransport lets the requests of a scraper resolving over DoH record the lookup made to
+// dial them, which decorate reports in the details
+type dohTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip sends the request with a holder for the DoH lookup of its connection
+func (t *dohTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := context.WithValue(req.Context(), dohLookupKey{}, &dohLookup{})
+	return t.next.RoundTrip(req.WithContext(ctx))
+}
+
+// recordDoHLookup adds the DoH lookup made to dial the response's connection to details.
+// Connections that were reused or dialed with a cached resolution made no lookup.
+func recordDoHLookup(details map[string]interface{}, resp *http.Response) {
+	if resp.Request == nil {
+		return
+	}
+	lookup, ok := resp.Request.Context().Value(dohLookupKey{}).(*dohLookup)
+	if !ok {
+		return
+	}
+
+	lookup.mu.Lock()
+	defer lookup.mu.Unlock()
+	if !lookup.resolved {
+		return
+	}
+	details["doh_resolved_addrs"] = lookup.addrs
+	details["doh_latency_ms"] = lookup.latency.Milliseconds()
+}
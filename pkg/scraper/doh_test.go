@@ -0,0 +1,160 @@
+package scraper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDoHTestServer starts a DoH server answering A queries from records, NXDOMAIN for other
+// names, and returns it with its certificate in PEM and its query count
+func newDoHTestServer(t *testing.T, records map[string]string) (*httptest.Server, string, *atomic.Int32) {
+	var queries atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+		assert.Equal(t, dohMessageType, r.Header.Get("Accept"))
+		query, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		require.NoError(t, err)
+
+		name, qtype := decodeTestQuestion(query)
+		response := append([]byte(nil), query...)
+		response[2] |= 0x80 // QR
+		addr, ok := records[name]
+		if !ok {
+			response[3] |= dnsRcodeNXDomain
+		} else if qtype == dnsTypeA {
+			binary.BigEndian.PutUint16(response[6:], 2)
+			// A CNAME the resolver skips, then the address, both named by a pointer to the question
+			response = append(response, 0xc0, 12, 0, 5, 0, 1, 0, 0, 0, 60, 0, 2, 0xc0, 12)
+			response = append(response, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+			response = append(response, net.ParseIP(addr).To4()...)
+		}
+		w.Header().Set("Content-Type", dohMessageType)
+		w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, string(certPEM), &queries
+}
+
+// decodeTestQuestion returns the name and type of the question of a DNS query
+func decodeTestQuestion(query []byte) (string, uint16) {
+	var name string
+	offset := 12
+	for query[offset] != 0 {
+		length := int(query[offset])
+		if name != "" {
+			name += "."
+		}
+		name += string(query[offset+1 : offset+1+length])
+		offset += 1 + length
+	}
+	return name, binary.BigEndian.Uint16(query[offset+1:])
+}
+
+func TestHTTPScraper_Scrape_DoHResolver(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+
+	doh, certPEM, queries := newDoHTestServer(t, map[string]string{"api.test": "127.0.0.1"})
+	s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:           "http",
+		ScrapeURL:      "http://api.test:" + targetURL.Port() + "/health",
+		DoHResolverURL: doh.URL + "/dns-query",
+		CACertPEM:      certPEM,
+	})
+	require.NoError(t, err)
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, []string{"127.0.0.1"}, result.Details["doh_resolved_addrs"])
+	assert.Contains(t, result.Details, "doh_latency_ms")
+	assert.Equal(t, target.Listener.Addr().String(), result.Details["remote_addr"])
+	// One query for the A and one for the AAAA records
+	assert.Equal(t, int32(2), queries.Load())
+
+	// The resolution is cached, so a new connection makes no further lookup
+	s.(*HTTPScraper).client.CloseIdleConnections()
+	result, err = s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.NotContains(t, result.Details, "doh_latency_ms")
+	assert.Equal(t, int32(2), queries.Load())
+}
+
+func TestHTTPScraper_Scrape_DoHFailures(t *testing.T) {
+	doh, certPEM, _ := newDoHTestServer(t, nil)
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	failingPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: failing.Certificate().Raw})
+
+	tests := []struct {
+		name     string
+		resolver string
+		certPEM  string
+		message  string
+	}{
+		{name: "unknown host", resolver: doh.URL, certPEM: certPEM, message: "DoH lookup of api.test via " + doh.URL + " failed: no such host"},
+		{name: "resolver error", resolver: failing.URL, certPEM: string(failingPEM), message: "DoH server returned HTTP status 502"},
+		{name: "untrusted resolver", resolver: doh.URL, message: "certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:           "http",
+				ScrapeURL:      "http://api.test/health",
+				DoHResolverURL: tt.resolver,
+				CACertPEM:      tt.certPEM,
+			})
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, "Failed to connect to http://api.test/health")
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestFactory_CreateScraper_InvalidDoHResolverURL(t *testing.T) {
+	_, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:           "http",
+		ScrapeURL:      "http://api.test/health",
+		DoHResolverURL: "http://1.1.1.1/dns-query",
+	})
+
+	assert.EqualError(t, err, `invalid doh_resolver_url "http://1.1.1.1/dns-query", expected an https URL`)
+}
+
+func TestDecodeDNSAnswers_Truncated(t *testing.T) {
+	query, err := encodeDNSQuery("api.test", dnsTypeA)
+	require.NoError(t, err)
+	binary.BigEndian.PutUint16(query[6:], 1)
+
+	_, err = decodeDNSAnswers(query, dnsTypeA)
+
+	assert.EqualError(t, err, "truncated DNS response")
+}
@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// errEmptyResponse reports a response body that is empty or only whitespace, such as the
+// 200 of an endpoint that is still starting or a proxy swallowing the body
+var errEmptyResponse = errors.New("empty response")
+
+// decodeJSON decodes a JSON response body into v, returning errEmptyResponse for a body that
+// is empty or only whitespace instead of the decoder's bare EOF
+func decodeJSON(body io.Reader, v interface{}) error {
+	err := json.NewDecoder(body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return errEmptyResponse
+	}
+	return err
+}
+
+// parseFailure records a response that could not be parsed in details, with error_type parse,
+// and returns the message describing it
+func parseFailure(scrapeURL string, err error, details map[string]interface{}) string {
+	details["error"] = err.Error()
+	details["error_type"] = "parse"
+	if errors.Is(err, errEmptyResponse) {
+		return fmt.Sprintf("Empty response from %s", scrapeURL)
+	}
+	return fmt.Sprintf("Failed to parse response from %s: %v", scrapeURL, err)
+}
+
+// checkNotEmpty asserts the response body holds more than whitespace, for fail_on_empty_response,
+// reading only up to the first byte that is not whitespace
+func (h *HTTPScraper) checkNotEmpty(resp *http.Response, details map[string]interface{}) (bool, string) {
+	buf := make([]byte, 512)
+	for {
+		n, err := resp.Body.Read(buf)
+		if len(bytes.TrimSpace(buf[:n])) > 0 {
+			return true, fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, h.config.ScrapeURL)
+		}
+		if errors.Is(err, io.EOF) {
+			return false, parseFailure(h.config.ScrapeURL, errEmptyResponse, details)
+		}
+		if err != nil {
+			details["error"] = err.Error()
+			return false, fmt.Sprintf("Failed to read response from %s: %v", h.config.ScrapeURL, err)
+		}
+	}
+}
@@ -0,0 +1,825 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expressionVariables are the identifiers an expression can refer to: the decoded JSON body
+// of the response and its HTTP status code
+var expressionVariables = []string{"body", "status"}
+
+// errMissingField is returned when an expression selects a key or index that does not exist,
+// which has() turns into false
+var errMissingField = errors.New("missing field")
+
+// expression is a compiled expression over a decoded JSON document, a small subset of CEL:
+// literals and lists, field selection (body.a.b, body["a"], body.items[0]), arithmetic,
+// comparisons, && || !, the conditional operator, "in" and the functions size, has, string,
+// contains, startsWith, endsWith and matches
+type expression struct {
+	source string
+	eval   evalFunc
+}
+
+// evalFunc evaluates a node of a compiled expression against the variables
+type evalFunc func(vars map[string]interface{}) (interface{}, error)
+
+// compileExpression parses the source into an expression, failing on syntax errors, unknown
+// variables or functions and invalid regular expression literals
+func compileExpression(source string) (*expression, error) {
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &expressionParser{tokens: tokens}
+	eval, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+
+	return &expression{source: source, eval: eval}, nil
+}
+
+// Evaluate evaluates the expression against the variables
+func (e *expression) Evaluate(vars map[string]interface{}) (interface{}, error) {
+	return e.eval(vars)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+// expressionToken is a lexical token of an expression with its offset in the source
+type expressionToken struct {
+	kind  tokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+func (t expressionToken) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// expressionOperators are the operator tokens, longest first so "==" is not read as "=" "="
+var expressionOperators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ".", ",", "?", ":",
+}
+
+// tokenizeExpression splits the source into tokens
+func tokenizeExpression(source string) ([]expressionToken, error) {
+	var tokens []expressionToken
+	i := 0
+	for i < len(source) {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(source) && (source[i] >= '0' && source[i] <= '9' || source[i] == '.' || source[i] == 'e' || source[i] == 'E' ||
+				(source[i] == '-' || source[i] == '+') && (source[i-1] == 'e' || source[i-1] == 'E')) {
+				i++
+			}
+			number, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at offset %d", source[start:i], start)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenNumber, text: source[start:i], value: number, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(source) {
+					return nil, fmt.Errorf("unterminated string at offset %d", start)
+				}
+				if source[i] == c {
+					i++
+					break
+				}
+				if source[i] == '\\' && i+1 < len(source) {
+					i++
+					switch source[i] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(source[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteByte(source[i])
+				i++
+			}
+			tokens = append(tokens, expressionToken{kind: tokenString, text: source[start:i], value: sb.String(), pos: start})
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(source) && (source[i] == '_' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, expressionToken{kind: tokenIdent, text: source[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range expressionOperators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, expressionToken{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+		}
+	}
+	return append(tokens, expressionToken{kind: tokenEOF, pos: len(source)}), nil
+}
+
+// expressionParser is a recursive descent parser building evalFuncs, one method per
+// precedence level from the conditional operator down to primary expressions
+type expressionParser struct {
+	tokens []expressionToken
+	pos    int
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() expressionToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the operators or keywords
+func (p *expressionParser) accept(texts ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOperator && tok.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *expressionParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected %q, got %s at offset %d", text, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *expressionParser) parseConditional() (evalFunc, error) {
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+
+	then, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(vars map[string]interface{}) (interface{}, error) {
+		ok, err := evalBool(cond, vars, "?")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return then(vars)
+		}
+		return otherwise(vars)
+	}, nil
+}
+
+func (p *expressionParser) parseOr() (evalFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalOperator(left, right, true)
+	}
+}
+
+func (p *expressionParser) parseAnd() (evalFunc, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalOperator(left, right, false)
+	}
+}
+
+// logicalOperator short-circuits: || returns true once a side is true, && false once a side is false
+func logicalOperator(left, right evalFunc, or bool) evalFunc {
+	op := "&&"
+	if or {
+		op = "||"
+	}
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, err := evalBool(left, vars, op)
+		if err != nil {
+			return nil, err
+		}
+		if l == or {
+			return l, nil
+		}
+		return evalBool(right, vars, op)
+	}
+}
+
+func (p *expressionParser) parseComparison() (evalFunc, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, err := left(vars)
+		if err != nil {
+			return nil, err
+		}
+		r, err := right(vars)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "==":
+			return reflect.DeepEqual(l, r), nil
+		case "!=":
+			return !reflect.DeepEqual(l, r), nil
+		case "in":
+			return evalIn(l, r)
+		}
+		return compareValues(op, l, r)
+	}, nil
+}
+
+func (p *expressionParser) parseAdditive() (evalFunc, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticOperator(op, left, right)
+	}
+}
+
+func (p *expressionParser) parseMultiplicative() (evalFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticOperator(op, left, right)
+	}
+}
+
+func (p *expressionParser) parseUnary() (evalFunc, error) {
+	op, ok := p.accept("!", "-")
+	if !ok {
+		return p.parsePostfix()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	if op == "!" {
+		return func(vars map[string]interface{}) (interface{}, error) {
+			value, err := evalBool(operand, vars, "!")
+			if err != nil {
+				return nil, err
+			}
+			return !value, nil
+		}, nil
+	}
+	return func(vars map[string]interface{}) (interface{}, error) {
+		value, err := operand(vars)
+		if err != nil {
+			return nil, err
+		}
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot negate %s", typeName(value))
+		}
+		return -number, nil
+	}, nil
+}
+
+// parsePostfix parses a primary expression followed by any number of .field and [index] selections
+func (p *expressionParser) parsePostfix() (evalFunc, error) {
+	eval, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peek().text == "." && p.peek().kind == tokenOperator:
+			p.next()
+			tok := p.next()
+			if tok.kind != tokenIdent {
+				return nil, fmt.Errorf("expected field name after \".\", got %s at offset %d", tok, tok.pos)
+			}
+			eval = selectField(eval, constant(tok.text))
+		case p.peek().text == "[" && p.peek().kind == tokenOperator:
+			p.next()
+			index, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			eval = selectField(eval, index)
+		default:
+			return eval, nil
+		}
+	}
+}
+
+func (p *expressionParser) parsePrimary() (evalFunc, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber, tokenString:
+		return constant(tok.value), nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return constant(true), nil
+		case "false":
+			return constant(false), nil
+		case "null":
+			return constant(nil), nil
+		}
+		if p.peek().text == "(" && p.peek().kind == tokenOperator {
+			return p.parseCall(tok)
+		}
+		for _, name := range expressionVariables {
+			if tok.text == name {
+				return func(vars map[string]interface{}) (interface{}, error) {
+					return vars[name], nil
+				}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown variable %q at offset %d, expected one of %s", tok.text, tok.pos, strings.Join(expressionVariables, ", "))
+	case tokenOperator:
+		if tok.text == "[" {
+			return p.parseList()
+		}
+		if tok.text == "(" {
+			eval, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return eval, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+}
+
+// parseList parses the elements of a list literal such as [200, 204]
+func (p *expressionParser) parseList() (evalFunc, error) {
+	var elements []evalFunc
+	if _, ok := p.accept("]"); !ok {
+		for {
+			element, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(vars map[string]interface{}) (interface{}, error) {
+		list := make([]interface{}, len(elements))
+		for i, element := range elements {
+			value, err := element(vars)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	}, nil
+}
+
+// expressionFunctions maps the function names to the number of arguments they take
+var expressionFunctions = map[string]int{
+	"size":       1,
+	"has":        1,
+	"string":     1,
+	"contains":   2,
+	"startsWith": 2,
+	"endsWith":   2,
+	"matches":    2,
+}
+
+// parseCall parses the arguments of a function call and builds the function
+func (p *expressionParser) parseCall(name expressionToken) (evalFunc, error) {
+	arity, ok := expressionFunctions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at offset %d", name.text, name.pos)
+	}
+	p.next() // (
+
+	var args []evalFunc
+	// literals holds the value of arguments that are a single string literal
+	var literals []interface{}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			start := p.pos
+			arg, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			var literal interface{}
+			if p.pos-start == 1 && p.tokens[start].kind == tokenString {
+				literal = p.tokens[start].value
+			}
+			literals = append(literals, literal)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s() takes %d argument(s), got %d", name.text, arity, len(args))
+	}
+
+	switch name.text {
+	case "size":
+		return func(vars map[string]interface{}) (interface{}, error) {
+			value, err := args[0](vars)
+			if err != nil {
+				return nil, err
+			}
+			switch v := value.(type) {
+			case string:
+				return float64(len([]rune(v))), nil
+			case []interface{}:
+				return float64(len(v)), nil
+			case map[string]interface{}:
+				return float64(len(v)), nil
+			}
+			return nil, fmt.Errorf("size() of %s", typeName(value))
+		}, nil
+	case "has":
+		return func(vars map[string]interface{}) (interface{}, error) {
+			_, err := args[0](vars)
+			if errors.Is(err, errMissingField) {
+				return false, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return true, nil
+		}, nil
+	case "string":
+		return func(vars map[string]interface{}) (interface{}, error) {
+			value, err := args[0](vars)
+			if err != nil {
+				return nil, err
+			}
+			return formatValue(value), nil
+		}, nil
+	case "matches":
+		// The pattern is usually a literal, so compile it up front to fail at startup
+		var pattern *regexp.Regexp
+		if lit, ok := literals[1].(string); ok {
+			re, err := regexp.Compile(lit)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern in matches(): %w", err)
+			}
+			pattern = re
+		}
+		return stringFunction(args, func(s, arg string) (interface{}, error) {
+			re := pattern
+			if re == nil {
+				var err error
+				if re, err = regexp.Compile(arg); err != nil {
+					return nil, fmt.Errorf("invalid pattern in matches(): %w", err)
+				}
+			}
+			return re.MatchString(s), nil
+		}), nil
+	}
+
+	var test func(s, substr string) bool
+	switch name.text {
+	case "contains":
+		test = strings.Contains
+	case "startsWith":
+		test = strings.HasPrefix
+	default:
+		test = strings.HasSuffix
+	}
+	return stringFunction(args, func(s, arg string) (interface{}, error) {
+		return test(s, arg), nil
+	}), nil
+}
+
+// stringFunction builds a function of two string arguments
+func stringFunction(args []evalFunc, fn func(s, arg string) (interface{}, error)) evalFunc {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		var values [2]string
+		for i, arg := range args {
+			value, err := arg(vars)
+			if err != nil {
+				return nil, err
+			}
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string argument, got %s", typeName(value))
+			}
+			values[i] = s
+		}
+		return fn(values[0], values[1])
+	}
+}
+
+func constant(value interface{}) evalFunc {
+	return func(map[string]interface{}) (interface{}, error) {
+		return value, nil
+	}
+}
+
+// selectField selects a key of an object or an element of an array
+func selectField(target, key evalFunc) evalFunc {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		container, err := target(vars)
+		if err != nil {
+			return nil, err
+		}
+		k, err := key(vars)
+		if err != nil {
+			return nil, err
+		}
+
+		switch c := container.(type) {
+		case map[string]interface{}:
+			name, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot index an object with %s", typeName(k))
+			}
+			value, ok := c[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: key %q not found", errMissingField, name)
+			}
+			return value, nil
+		case []interface{}:
+			number, ok := k.(float64)
+			if !ok || number != float64(int(number)) {
+				return nil, fmt.Errorf("cannot index an array with %s", formatValue(k))
+			}
+			index := int(number)
+			if index < 0 || index >= len(c) {
+				return nil, fmt.Errorf("%w: index %d out of range for array of length %d", errMissingField, index, len(c))
+			}
+			return c[index], nil
+		}
+		return nil, fmt.Errorf("%w: cannot select %s from %s", errMissingField, formatValue(k), typeName(container))
+	}
+}
+
+func arithmeticOperator(op string, left, right evalFunc) evalFunc {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, err := left(vars)
+		if err != nil {
+			return nil, err
+		}
+		r, err := right(vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if ls, ok := l.(string); ok && op == "+" {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+		ln, lok := l.(float64)
+		rn, rok := r.(float64)
+		if !lok || !rok {
+			return nil, fmt.Errorf("operator %s cannot be applied to %s and %s", op, typeName(l), typeName(r))
+		}
+		switch op {
+		case "+":
+			return ln + rn, nil
+		case "-":
+			return ln - rn, nil
+		case "*":
+			return ln * rn, nil
+		}
+		if op == "/" {
+			if rn == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return ln / rn, nil
+		}
+		// % works on the truncated integers, so any divisor in (-1, 1) is zero
+		divisor := int64(rn)
+		if divisor == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return float64(int64(ln) % divisor), nil
+	}
+}
+
+// compareValues orders two numbers or two strings
+func compareValues(op string, l, r interface{}) (interface{}, error) {
+	var cmp int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be applied to %s and %s", op, typeName(l), typeName(r))
+		}
+		switch {
+		case lv < rv:
+			cmp = -1
+		case lv > rv:
+			cmp = 1
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be applied to %s and %s", op, typeName(l), typeName(r))
+		}
+		cmp = strings.Compare(lv, rv)
+	default:
+		return nil, fmt.Errorf("operator %s cannot be applied to %s and %s", op, typeName(l), typeName(r))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+// evalIn reports whether a value is an element of an array or a key of an object
+func evalIn(value, container interface{}) (interface{}, error) {
+	switch c := container.(type) {
+	case []interface{}:
+		for _, element := range c {
+			if reflect.DeepEqual(element, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator in cannot look up %s in an object", typeName(value))
+		}
+		_, found := c[key]
+		return found, nil
+	}
+	return nil, fmt.Errorf("operator in cannot be applied to %s", typeName(container))
+}
+
+// evalBool evaluates a node that must produce a boolean
+func evalBool(eval evalFunc, vars map[string]interface{}, op string) (bool, error) {
+	value, err := eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("operator %s expects a boolean, got %s", op, typeName(value))
+	}
+	return b, nil
+}
+
+// typeName names the JSON type of a value for error messages
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// formatValue formats a value for messages, numbers without a trailing ".0" and
+// arrays and objects in Go syntax
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
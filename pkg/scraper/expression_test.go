@@ -0,0 +1,356 @@
+package scraper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expressionVars(t *testing.T, body string) map[string]interface{} {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &doc))
+	return map[string]interface{}{"body": doc, "status": 200.0}
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	vars := expressionVars(t, `{"status":"ok","checks":{"db":{"up":true},"cache":{"up":false}},"replicas":[{"lag":2},{"lag":12}],"version":"1.4.2"}`)
+
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{`body.status == "ok"`, true},
+		{`body.checks.db.up && !body.checks.cache.up`, true},
+		{`body["checks"]["cache"].up || status == 200`, true},
+		{`size(body.replicas) >= 2 && body.replicas[1].lag < 10`, false},
+		{`body.replicas[0].lag * 2 + 1`, 5.0},
+		{`-body.replicas[1].lag % 5`, -2.0},
+		{`7.9 % 2.5`, 1.0},
+		{`has(body.checks.queue) ? body.checks.queue.up : true`, true},
+		{`has(body.checks.db)`, true},
+		{`"db" in body.checks && status in [200, 204]`, true},
+		{`startsWith(body.version, "1.") && matches(body.version, "^1\\.[0-9]+\\.[0-9]+$")`, true},
+		{`contains(body.status, "o") && endsWith(body.version, ".2")`, true},
+		{`"replicas: " + string(size(body.replicas)) + ", version " + body.version`, "replicas: 2, version 1.4.2"},
+		{`body.missing == null || true`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := compileExpression(tt.source)
+			require.NoError(t, err)
+
+			value, err := expr.Evaluate(vars)
+			if tt.want == nil {
+				assert.ErrorContains(t, err, `key "missing" not found`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+// evaluateExpression compiles and evaluates the source, failing the test on syntax errors
+func evaluateExpression(t *testing.T, source string, vars map[string]interface{}) (interface{}, error) {
+	expr, err := compileExpression(source)
+	require.NoError(t, err)
+	return expr.Evaluate(vars)
+}
+
+func TestExpression_PrecedenceAndAssociativity(t *testing.T) {
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{`2 + 3 * 4`, 14.0},
+		{`(2 + 3) * 4`, 20.0},
+		{`2 * 3 + 4 * 5`, 26.0},
+		{`10 - 2 - 3`, 5.0},
+		{`16 / 4 / 2`, 2.0},
+		{`10 % 4 * 3`, 6.0},
+		{`-2 * 3`, -6.0},
+		{`-(2 + 3)`, -5.0},
+		{`- -3`, 3.0},
+		{`2 - -3`, 5.0},
+		{`-7 % 3`, -1.0},
+		{`1 + 2 < 4`, true},
+		{`!true && false`, false},
+		{`!(true && false)`, true},
+		{`!!true`, true},
+		{`!false == true`, true},
+		{`true || true && false`, true},
+		{`false && true || true`, true},
+		{`false || false && true`, false},
+		{`true ? 1 : 2 + 3`, 1.0},
+		{`false ? 1 : 2 + 3`, 5.0},
+		{`false ? 1 : false ? 2 : 3`, 3.0},
+		{`true ? false ? 1 : 2 : 3`, 2.0},
+		{`1 < 2 ? "yes" : "no"`, "yes"},
+		{`"a" + "b" + "c"`, "abc"},
+		{`"b" > "a" && "a" <= "a"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestExpression_ShortCircuit(t *testing.T) {
+	vars := expressionVars(t, `{"ok":true,"checks":{"db":{"up":true}}}`)
+
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{`false && body.missing.up`, false},
+		{`body.ok || body.missing.up`, true},
+		{`has(body.checks.queue) && body.checks.queue.up`, false},
+		{`!has(body.checks.queue) || body.checks.queue.up`, true},
+		{`has(body.checks.queue) ? body.checks.queue.up : "skipped"`, "skipped"},
+		{`has(body.checks.db) ? body.checks.db.up : body.missing`, true},
+		{`false && 1`, false},
+		{`true || "not a boolean"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestExpression_In(t *testing.T) {
+	vars := expressionVars(t, `{"checks":{"db":{"up":true}},"codes":[200,204],"tags":["a",null,[1]]}`)
+
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{`2 in [1, 2, 3]`, true},
+		{`4 in [1, 2, 3]`, false},
+		{`"x" in []`, false},
+		{`status in body.codes`, true},
+		{`"a" in body.tags`, true},
+		{`null in body.tags`, true},
+		{`[1] in body.tags`, true},
+		{`"1" in [1]`, false},
+		{`"db" in body.checks`, true},
+		{`"queue" in body.checks`, false},
+		{`!("queue" in body.checks)`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestExpression_Strings(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"plain"`, "plain"},
+		{`'single'`, "single"},
+		{`"it's"`, "it's"},
+		{`'say "hi"'`, `say "hi"`},
+		{`"quote \" inside"`, `quote " inside`},
+		{`'it\'s'`, "it's"},
+		{`"line\nbreak"`, "line\nbreak"},
+		{`"tab\there"`, "tab\there"},
+		{`"back\\slash"`, `back\slash`},
+		{`"unknown \x escape"`, "unknown x escape"},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestExpression_Numbers(t *testing.T) {
+	tests := []struct {
+		source string
+		want   float64
+	}{
+		{`0`, 0},
+		{`42`, 42},
+		{`2.5`, 2.5},
+		{`1e3`, 1000},
+		{`2.5E-1`, 0.25},
+		{`1e+2`, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestExpression_Modulo(t *testing.T) {
+	tests := []struct {
+		source string
+		want   float64
+	}{
+		{`7 % 3`, 1},
+		{`-7 % 3`, -1},
+		{`7 % -3`, 1},
+		{`-7 % -3`, -1},
+		{`7.9 % 2.5`, 1},
+		{`-7.5 % 2`, -1},
+		{`7 % 2.9`, 1},
+		{`0.5 % 3`, 0},
+		{`6 % 1.5`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestExpression_Has(t *testing.T) {
+	vars := expressionVars(t, `{"items":[{"id":1}],"name":"api","empty":null}`)
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{`has(body.items)`, true},
+		{`has(body.items[0])`, true},
+		{`has(body.items[0].id)`, true},
+		{`has(body.items[1])`, false},
+		{`has(body.items[-1])`, false},
+		{`has(body.items[0].missing)`, false},
+		{`has(body.a.b.c.d)`, false},
+		{`has(body["a"]["b"])`, false},
+		{`has(body.name.first)`, false},
+		{`has(body.empty)`, true},
+		{`has(body.empty.value)`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			value, err := evaluateExpression(t, tt.source, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestCompileExpression_Errors(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{`body.status ==`, "unexpected end of expression"},
+		{`body.status = "ok"`, "unexpected character"},
+		{`response.ok`, `unknown variable "response"`},
+		{`len(body.items) > 0`, `unknown function "len"`},
+		{`size(body.a, body.b)`, "size() takes 1 argument(s), got 2"},
+		{`(body.ok`, `expected ")"`},
+		{`body.`, "expected field name"},
+		{`body.name == "ok`, "unterminated string"},
+		{`matches(body.name, "[")`, "invalid pattern"},
+		{`body.ok body.ready`, `unexpected "body"`},
+		{`1 2`, `unexpected "2" at offset 2`},
+		{`body.ok )`, `unexpected ")"`},
+		{`true ? 1 : 2 3`, `unexpected "3"`},
+		{`1 < 2 < 3`, `unexpected "<"`},
+		{`size(body) ]`, `unexpected "]"`},
+		{`true ? 1`, `expected ":"`},
+		{`[1, 2`, `expected "]"`},
+		{`body.items[0`, `expected "]"`},
+		{`"abc`, "unterminated string at offset 0"},
+		{`'abc"`, "unterminated string"},
+		{`"abc\"`, "unterminated string"},
+		{`"abc\`, "unterminated string"},
+		{`1e`, `invalid number "1e"`},
+		{`1.2.3`, `invalid number "1.2.3"`},
+		{`1e5e`, `invalid number "1e5e"`},
+		{`.5`, `unexpected "."`},
+		{`body.5`, "expected field name"},
+		{`-`, "unexpected end of expression"},
+		{`!`, "unexpected end of expression"},
+		{`has()`, "has() takes 1 argument(s), got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := compileExpression(tt.source)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestExpression_EvaluateErrors(t *testing.T) {
+	vars := expressionVars(t, `{"count":"3","items":[1],"ok":"yes"}`)
+
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{`body.count > 2`, "operator > cannot be applied to string and number"},
+		{`body.ok && true`, "operator && expects a boolean, got string"},
+		{`body.items[3] == 1`, "index 3 out of range"},
+		{`body.items.first`, "cannot index an array with first"},
+		{`1 / 0`, "division by zero"},
+		{`5 % 0`, "division by zero"},
+		{`5 % 0.5`, "division by zero"},
+		{`5 % -0.9`, "division by zero"},
+		{`size(body.items) % 0.5`, "division by zero"},
+		{`size(1)`, "size() of number"},
+		{`body.items[0.5]`, "cannot index an array with 0.5"},
+		{`body.items["0"]`, "cannot index an array with 0"},
+		{`body.items[true]`, "cannot index an array with true"},
+		{`body.items[-1]`, "index -1 out of range for array of length 1"},
+		{`body.items[1]`, "index 1 out of range for array of length 1"},
+		{`body[0]`, "cannot index an object with number"},
+		{`body.count.first`, "cannot select first from string"},
+		{`body.missing.first`, `key "missing" not found`},
+		{`has(body.items[0.5])`, "cannot index an array with 0.5"},
+		{`-body.ok`, "cannot negate string"},
+		{`!body.count`, "operator ! expects a boolean, got string"},
+		{`1 ? 2 : 3`, "operator ? expects a boolean, got number"},
+		{`true && body.ok`, "operator && expects a boolean, got string"},
+		{`false || 1`, "operator || expects a boolean, got number"},
+		{`1 in body`, "operator in cannot look up number in an object"},
+		{`"o" in body.ok`, "operator in cannot be applied to string"},
+		{`"a" + 1`, "operator + cannot be applied to string and number"},
+		{`"a" - "b"`, "operator - cannot be applied to string and string"},
+		{`body.ok % 2`, "operator % cannot be applied to string and number"},
+		{`null < 1`, "operator < cannot be applied to null and number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := compileExpression(tt.source)
+			require.NoError(t, err)
+
+			_, err = expr.Evaluate(vars)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -22,10 +22,10 @@ func NewFactory(logger *logrus.Logger) *Factory {
 
 // CreateScraper creates a scraper based on the configuration
 func (f *Factory) CreateScraper(scraperConfig config.HealthcheckScraper) (Scraper, error) {
-	switch scraperConfig.Type {
-	case "cloudflared-tunnel-connector":
-		return NewCloudflaredTunnelScraper(scraperConfig.ScrapeURL, scraperConfig.PingURL, scraperConfig.ScrapeIntervalSeconds, f.logger), nil
-	default:
+	reg, ok := registry[scraperConfig.Type]
+	if !ok {
 		return nil, fmt.Errorf("unknown scraper type: %s", scraperConfig.Type)
 	}
+
+	return reg.create(scraperConfig, f.logger)
 }
@@ -53,3 +53,280 @@ func TestFactory_CreateScraper_UnknownType(t *testing.T) {
 	assert.Nil(t, scraper)
 	assert.Contains(t, err.Error(), "unknown scraper type: unknown-scraper-type")
 }
+
+func TestFactory_CreateScraper_Vault(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraperConfig := config.HealthcheckScraper{
+		Type:                  "vault",
+		ScrapeURL:             "http://localhost:8200/v1/sys/health",
+		PingURL:               "http://localhost:8081/ping",
+		ScrapeIntervalSeconds: 60,
+		VaultNamespace:        "team-a",
+		VaultStandbyHealthy:   true,
+	}
+
+	scraper, err := factory.CreateScraper(scraperConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vault", scraper.Type())
+	assert.Equal(t, 60, scraper.GetScrapeInterval())
+}
+
+func TestFactory_CreateScraper_HTTP(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraperConfig := config.HealthcheckScraper{
+		Type:      "http",
+		ScrapeURL: "http://localhost:8080/health",
+		PingURL:   "http://localhost:8081/ping",
+	}
+
+	scraper, err := factory.CreateScraper(scraperConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+}
+
+func TestFactory_CreateScraper_VaultSeal(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "vault-seal",
+		ScrapeURL: "http://localhost:8200/v1/sys/seal-status",
+		PingURL:   "http://localhost:8081/ping",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vault-seal", scraper.Type())
+}
+
+func TestFactory_CreateScraper_GRPCStream(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:       "grpc-stream",
+		ScrapeURL:  "http://localhost:50051",
+		GRPCMethod: "/events.Events/Subscribe",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "grpc-stream", scraper.Type())
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "grpc-stream",
+		ScrapeURL: "http://localhost:50051",
+	})
+
+	assert.Error(t, err)
+}
+
+func TestFactory_CreateScraper_GRPCWeb(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:       "grpc-web",
+		ScrapeURL:  "https://gateway.example.com",
+		GRPCMethod: "/grpc.health.v1.Health/Check",
+		AuthHeader: "Bearer token",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "grpc-web", scraper.Type())
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:               "grpc-web",
+		ScrapeURL:          "https://gateway.example.com",
+		GRPCMethod:         "/grpc.health.v1.Health/Check",
+		GRPCRequestMessage: "not base64!",
+	})
+
+	assert.ErrorContains(t, err, "invalid grpc_request_message")
+}
+
+func TestFactory_CreateScraper_PrometheusMetric(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+	threshold := 1.0
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:       "prometheus-metric",
+		ScrapeURL:  "http://localhost:8080/metrics",
+		MetricName: "up",
+		Operator:   "==",
+		Threshold:  &threshold,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "prometheus-metric", scraper.Type())
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:       "prometheus-metric",
+		ScrapeURL:  "http://localhost:8080/metrics",
+		MetricName: "up",
+		Operator:   "~=",
+		Threshold:  &threshold,
+	})
+
+	assert.Error(t, err)
+}
+
+func TestFactory_CreateScraper_QueueDepth(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:         "queue-depth",
+		ScrapeURL:    "redis://localhost:6379",
+		QueueBackend: "redis",
+		QueueName:    "jobs",
+		MaxDepth:     1000,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "queue-depth", scraper.Type())
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:         "queue-depth",
+		QueueBackend: "kafka",
+	})
+
+	assert.ErrorContains(t, err, "rabbitmq, redis, sqs")
+}
+
+func TestFactory_CreateScraper_HTTPBurstValidation(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:        "http",
+		ScrapeURL:   "http://localhost:8080/health",
+		Burst:       3,
+		BurstQuorum: 4,
+	})
+
+	assert.ErrorContains(t, err, "burst_quorum")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:              "http",
+		ScrapeURL:         "http://localhost:8080/health",
+		Burst:             3,
+		EnableScrapeCache: true,
+	})
+
+	assert.ErrorContains(t, err, "enable_scrape_cache")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:       "http",
+		ScrapeURL:  "http://localhost:8080/health",
+		Burst:      3,
+		TrailerKey: "grpc-status",
+	})
+
+	assert.ErrorContains(t, err, "trailer_key")
+}
+
+func TestFactory_CreateScraper_HTTPProbesValidation(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:            "http",
+		ScrapeURL:       "http://localhost:8080/health",
+		Probes:          3,
+		MinSuccessRatio: 1.5,
+	})
+
+	assert.ErrorContains(t, err, "min_success_ratio 1.5 exceeds 1")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:               "http",
+		ScrapeURL:          "http://localhost:8080/health",
+		Probes:             3,
+		ProbeWindowSeconds: 60,
+	})
+
+	assert.ErrorContains(t, err, "probe_window_seconds 60 exceeds the maximum of 25s")
+}
+
+func TestFactory_CreateScraper_HTTPExpressionValidation(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:             "http",
+		ScrapeURL:        "http://localhost:8080/health",
+		HealthExpression: `body.status = "ok"`,
+	})
+
+	assert.ErrorContains(t, err, "invalid health_expression")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:              "http",
+		ScrapeURL:         "http://localhost:8080/health",
+		HealthExpression:  `body.ok`,
+		MessageExpression: `format(body.reason)`,
+	})
+
+	assert.ErrorContains(t, err, "invalid message_expression")
+}
+
+func TestFactory_CreateScraper_TLS(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:       "tls",
+		ScrapeURL:  "example.com:443",
+		RequireSCT: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tls", scraper.Type())
+}
+
+func TestFactory_CreateScraper_Mount(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "mount",
+		MountPath: "/mnt/data",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mount", scraper.Type())
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "mount"})
+
+	assert.ErrorContains(t, err, "mount_path")
+}
+
+func TestFactory_CreateScraper_AWSHealth(t *testing.T) {
+	logger := logrus.New()
+	factory := NewFactory(logger)
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:               "aws-health",
+		AlarmName:          "api-5xx",
+		AWSRegion:          "eu-west-1",
+		AWSAccessKeyID:     "AKIDEXAMPLE",
+		AWSSecretAccessKey: "secret",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "aws-health", scraper.Type())
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "aws-health",
+		AWSRegion: "eu-west-1",
+	})
+
+	assert.ErrorContains(t, err, "alarm_name")
+}
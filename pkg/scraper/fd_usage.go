@@ -0,0 +1,183 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultMaxFDPercent is the share of the soft open files limit a process may use by default
+const defaultMaxFDPercent = 80
+
+// fdUsage is the number of open file descriptors of a process and its open files limits,
+// where a limit of -1 is unlimited
+type fdUsage struct {
+	Open      int
+	SoftLimit int
+	HardLimit int
+}
+
+// percent returns the share of the soft limit in use, 0 when unlimited
+func (u fdUsage) percent() float64 {
+	if u.SoftLimit <= 0 {
+		return 0
+	}
+	return float64(u.Open) / float64(u.SoftLimit) * 100
+}
+
+// FDUsageScraper implements the Scraper interface for checking a process is not running out
+// of file descriptors
+type FDUsageScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	maxPercent            float64
+	logger                *logrus.Logger
+	list                  func() ([]processInfo, error)
+	usage                 func(pid int) (fdUsage, error)
+}
+
+// NewFDUsageScraper creates a new file descriptor usage scraper for the process with the
+// configured pid or name
+func NewFDUsageScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (*FDUsageScraper, error) {
+	if (scraperConfig.PID == 0) == (scraperConfig.ProcessName == "") {
+		return nil, fmt.Errorf("fd-usage scraper requires either a pid or a process_name")
+	}
+
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	maxPercent := scraperConfig.MaxFDPercent
+	if maxPercent <= 0 {
+		maxPercent = defaultMaxFDPercent
+	}
+
+	return &FDUsageScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		maxPercent:            maxPercent,
+		logger:                logger,
+		list:                  listProcesses,
+		usage:                 readFDUsage,
+	}, nil
+}
+
+// Type returns the scraper type identifier
+func (f *FDUsageScraper) Type() string {
+	return "fd-usage"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (f *FDUsageScraper) GetPingURL() string {
+	return f.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (f *FDUsageScraper) GetScrapeInterval() int {
+	return f.scrapeIntervalSeconds
+}
+
+// Scrape reads the open file descriptors and limits of the process, or of every process with
+// the configured name, and is unhealthy when any of them uses more than max_fd_percent of its
+// soft limit. The details report the process with the highest usage.
+func (f *FDUsageScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	f.logger.WithFields(logrus.Fields{
+		"pid":          f.config.PID,
+		"process_name": f.config.ProcessName,
+	}).Debug("Starting fd usage healthcheck")
+
+	details := map[string]interface{}{
+		"max_fd_percent": f.maxPercent,
+	}
+
+	pids, err := f.pids()
+	if err != nil {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to find process: %v", err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	worstPID := 0
+	var worst fdUsage
+	for _, pid := range pids {
+		usage, err := f.usage(pid)
+		if err != nil {
+			details["pid"] = pid
+			details["error"] = err.Error()
+			return &ScrapeResult{
+				Healthy:   false,
+				Message:   fmt.Sprintf("Failed to read file descriptors of process %d: %v", pid, err),
+				Timestamp: time.Now(),
+				Details:   details,
+			}, nil
+		}
+		if worstPID == 0 || usage.percent() > worst.percent() {
+			worstPID, worst = pid, usage
+		}
+	}
+
+	percent := worst.percent()
+	details["pid"] = worstPID
+	details["open_fds"] = worst.Open
+	details["fd_soft_limit"] = worst.SoftLimit
+	details["fd_hard_limit"] = worst.HardLimit
+	details["fd_usage_percent"] = percent
+	healthy := percent <= f.maxPercent
+
+	f.logger.WithFields(logrus.Fields{
+		"pid":     worstPID,
+		"open":    worst.Open,
+		"limit":   worst.SoftLimit,
+		"healthy": healthy,
+	}).Info("FD usage healthcheck completed")
+
+	message := fmt.Sprintf("Process %d has %d/%d file descriptors open (%.1f%%)", worstPID, worst.Open, worst.SoftLimit, percent)
+	if worst.SoftLimit < 0 {
+		message = fmt.Sprintf("Process %d has %d file descriptors open without a limit", worstPID, worst.Open)
+	}
+	if !healthy {
+		message = fmt.Sprintf("%s, expected at most %.1f%%", message, f.maxPercent)
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// pids returns the configured pid, or those of the processes with the configured name
+func (f *FDUsageScraper) pids() ([]int, error) {
+	if f.config.PID != 0 {
+		return []int{f.config.PID}, nil
+	}
+
+	processes, err := f.list()
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	self := os.Getpid()
+	for _, process := range processes {
+		if process.PID != self && processNameMatches(process, f.config.ProcessName) {
+			pids = append(pids, process.PID)
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no process named %s is running", f.config.ProcessName)
+	}
+	return pids, nil
+}
@@ -0,0 +1,75 @@
+//go:build linux
+
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readFDUsage counts the open file descriptors of the process in its fd directory and reads
+// its open files limits
+func readFDUsage(pid int) (fdUsage, error) {
+	dir := filepath.Join(procDir, strconv.Itoa(pid))
+
+	fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return fdUsage{}, err
+	}
+
+	limits, err := os.ReadFile(filepath.Join(dir, "limits"))
+	if err != nil {
+		return fdUsage{}, err
+	}
+	soft, hard, err := parseOpenFilesLimit(limits)
+	if err != nil {
+		return fdUsage{}, err
+	}
+
+	return fdUsage{Open: len(fds), SoftLimit: soft, HardLimit: hard}, nil
+}
+
+// parseOpenFilesLimit returns the soft and hard limits of the "Max open files" row of a
+// limits file, -1 for unlimited
+func parseOpenFilesLimit(limits []byte) (int, int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(limits))
+	for scanner.Scan() {
+		rest, ok := strings.CutPrefix(scanner.Text(), "Max open files")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) < 2 {
+			return 0, 0, fmt.Errorf("invalid open files limit: %q", scanner.Text())
+		}
+		soft, err := parseLimit(fields[0])
+		if err != nil {
+			return 0, 0, err
+		}
+		hard, err := parseLimit(fields[1])
+		if err != nil {
+			return 0, 0, err
+		}
+		return soft, hard, nil
+	}
+
+	return 0, 0, fmt.Errorf("open files limit not found")
+}
+
+// parseLimit parses a limit value, -1 for unlimited
+func parseLimit(value string) (int, error) {
+	if value == "unlimited" {
+		return -1, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid limit %q: %w", value, err)
+	}
+	return limit, nil
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package scraper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLimits is a limits file as found in /proc/<pid>/limits
+const testLimits = `Limit                     Soft Limit           Hard Limit           Units     
+Max cpu time              unlimited            unlimited            seconds   
+Max processes             63704                63704                processes 
+Max open files            %s                 %s              files     
+Max locked memory         8388608              8388608              bytes     
+`
+
+func TestReadFDUsage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "42", "fd"), 0o755))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "42", "fd", fmt.Sprint(i)), nil, 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "42", "limits"), []byte(fmt.Sprintf(testLimits, "1024", "1048576")), 0o644))
+
+	original := procDir
+	procDir = dir
+	t.Cleanup(func() { procDir = original })
+
+	usage, err := readFDUsage(42)
+
+	require.NoError(t, err)
+	assert.Equal(t, fdUsage{Open: 3, SoftLimit: 1024, HardLimit: 1048576}, usage)
+
+	_, err = readFDUsage(43)
+	assert.Error(t, err)
+}
+
+func TestParseOpenFilesLimit(t *testing.T) {
+	soft, hard, err := parseOpenFilesLimit([]byte(fmt.Sprintf(testLimits, "unlimited", "unlimited")))
+	require.NoError(t, err)
+	assert.Equal(t, -1, soft)
+	assert.Equal(t, -1, hard)
+
+	_, _, err = parseOpenFilesLimit([]byte("Limit Soft Limit Hard Limit Units\n"))
+	assert.ErrorContains(t, err, "open files limit not found")
+}
+
+func TestReadFDUsage_Self(t *testing.T) {
+	usage, err := readFDUsage(os.Getpid())
+
+	require.NoError(t, err)
+	assert.Positive(t, usage.Open)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package scraper
+
+import "fmt"
+
+// readFDUsage is only supported on Linux, where file descriptors are read from /proc
+func readFDUsage(pid int) (fdUsage, error) {
+	return fdUsage{}, fmt.Errorf("reading file descriptors is only supported on Linux")
+}
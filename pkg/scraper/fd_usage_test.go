@@ -0,0 +1,106 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFDUsageScraper creates a fd usage scraper reading the given usage of each pid
+func newTestFDUsageScraper(t *testing.T, scraperConfig config.HealthcheckScraper, usages map[int]fdUsage) *FDUsageScraper {
+	scraper, err := NewFDUsageScraper(scraperConfig, logrus.New())
+	require.NoError(t, err)
+	scraper.list = func() ([]processInfo, error) {
+		return testProcesses, nil
+	}
+	scraper.usage = func(pid int) (fdUsage, error) {
+		usage, ok := usages[pid]
+		if !ok {
+			return fdUsage{}, fmt.Errorf("no such process")
+		}
+		return usage, nil
+	}
+	return scraper
+}
+
+func TestNewFDUsageScraper(t *testing.T) {
+	scraper, err := NewFDUsageScraper(config.HealthcheckScraper{
+		PID:     42,
+		PingURL: "http://localhost:8081/ping",
+	}, logrus.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, "fd-usage", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+	assert.Equal(t, float64(defaultMaxFDPercent), scraper.maxPercent)
+
+	_, err = NewFDUsageScraper(config.HealthcheckScraper{}, logrus.New())
+	assert.ErrorContains(t, err, "requires either a pid or a process_name")
+}
+
+func TestFDUsageScraper_Scrape(t *testing.T) {
+	tests := []struct {
+		name       string
+		usage      fdUsage
+		maxPercent float64
+		healthy    bool
+	}{
+		{name: "below default maximum", usage: fdUsage{Open: 500, SoftLimit: 1024, HardLimit: 4096}, healthy: true},
+		{name: "above default maximum", usage: fdUsage{Open: 900, SoftLimit: 1024, HardLimit: 4096}, healthy: false},
+		{name: "above configured maximum", usage: fdUsage{Open: 600, SoftLimit: 1024, HardLimit: 4096}, maxPercent: 50, healthy: false},
+		{name: "unlimited", usage: fdUsage{Open: 100000, SoftLimit: -1, HardLimit: -1}, healthy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := newTestFDUsageScraper(t, config.HealthcheckScraper{PID: 42, MaxFDPercent: tt.maxPercent}, map[int]fdUsage{42: tt.usage})
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, 42, result.Details["pid"])
+			assert.Equal(t, tt.usage.Open, result.Details["open_fds"])
+			assert.Equal(t, tt.usage.SoftLimit, result.Details["fd_soft_limit"])
+		})
+	}
+}
+
+func TestFDUsageScraper_Scrape_ByName(t *testing.T) {
+	scraper := newTestFDUsageScraper(t, config.HealthcheckScraper{ProcessName: "nginx"}, map[int]fdUsage{
+		42: {Open: 100, SoftLimit: 1024},
+		43: {Open: 1000, SoftLimit: 1024},
+		44: {Open: 200, SoftLimit: 1024},
+	})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, 43, result.Details["pid"], "the process with the highest usage should be reported")
+}
+
+func TestFDUsageScraper_Scrape_Errors(t *testing.T) {
+	notRunning := newTestFDUsageScraper(t, config.HealthcheckScraper{ProcessName: "redis-server"}, nil)
+
+	result, err := notRunning.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "no process named redis-server is running")
+
+	exited := newTestFDUsageScraper(t, config.HealthcheckScraper{PID: 99}, nil)
+
+	result, err = exited.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to read file descriptors of process 99")
+}
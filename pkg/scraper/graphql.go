@@ -0,0 +1,206 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// graphQLIntrospectionQuery is the minimal introspection query posted when no query is
+// configured, only answered with the name of the query type once the schema has loaded
+const graphQLIntrospectionQuery = "{ __schema { queryType { name } } }"
+
+// graphQLIntrospectionDataPath is the path checked in the response to the introspection query
+const graphQLIntrospectionDataPath = "__schema.queryType.name"
+
+// GraphQLError represents a single entry of a GraphQL response's errors field
+type GraphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// GraphQLResponse represents a GraphQL response body
+type GraphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []GraphQLError `json:"errors"`
+}
+
+// GraphQLScraper implements the Scraper interface for GraphQL health queries
+type GraphQLScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewGraphQLScraper creates a new GraphQL scraper, which posts a minimal introspection query
+// unless a query is configured
+func NewGraphQLScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *GraphQLScraper {
+	if scraperConfig.GraphQLQuery == "" {
+		scraperConfig.GraphQLQuery = graphQLIntrospectionQuery
+		if scraperConfig.GraphQLDataPath == "" {
+			scraperConfig.GraphQLDataPath = graphQLIntrospectionDataPath
+		}
+	}
+
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &GraphQLScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Type returns the scraper type identifier
+func (g *GraphQLScraper) Type() string {
+	return "graphql"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (g *GraphQLScraper) GetPingURL() string {
+	return g.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (g *GraphQLScraper) GetScrapeInterval() int {
+	return g.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (g *GraphQLScraper) Close() error {
+	g.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape performs the healthcheck by posting the configured query to the scrape URL
+func (g *GraphQLScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	scrapeURL := g.config.ScrapeURL
+	g.logger.WithField("url", scrapeURL).Debug("Starting GraphQL healthcheck")
+
+	body, err := json.Marshal(map[string]string{"query": g.config.GraphQLQuery})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", scrapeURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to connect to %s: %v", scrapeURL, err),
+			Timestamp: time.Now(),
+			Details: map[string]interface{}{
+				"error":      err.Error(),
+				"error_type": "transport",
+			},
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	details := map[string]interface{}{
+		DetailStatusCode: resp.StatusCode,
+	}
+
+	// GraphQL servers may report errors with a non-2xx status, so try to decode the body first
+	var graphqlResp GraphQLResponse
+	decodeErr := decodeJSON(resp.Body, &graphqlResp)
+
+	if decodeErr == nil && len(graphqlResp.Errors) > 0 {
+		messages := make([]string, 0, len(graphqlResp.Errors))
+		for _, graphqlErr := range graphqlResp.Errors {
+			messages = append(messages, graphqlErr.Message)
+		}
+		details["errors"] = graphqlResp.Errors
+		details["error_type"] = "graphql"
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("GraphQL errors from %s: %s", scrapeURL, strings.Join(messages, "; ")),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		details["error_type"] = "transport"
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, scrapeURL),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	if decodeErr != nil {
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   parseFailure(scrapeURL, decodeErr, details),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	healthy, message := g.checkData(graphqlResp.Data, details)
+
+	g.logger.WithFields(logrus.Fields{
+		"url":         scrapeURL,
+		"status_code": resp.StatusCode,
+		"healthy":     healthy,
+	}).Info("GraphQL healthcheck completed")
+
+	return g.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
+
+// checkData asserts the optional value at the configured path of the response data
+func (g *GraphQLScraper) checkData(data interface{}, details map[string]interface{}) (bool, string) {
+	if data == nil {
+		details["error_type"] = "graphql"
+		return false, fmt.Sprintf("GraphQL response from %s has no data", g.config.ScrapeURL)
+	}
+
+	if g.config.GraphQLDataPath == "" {
+		return true, fmt.Sprintf("GraphQL query to %s succeeded", g.config.ScrapeURL)
+	}
+
+	value, err := lookupJSONPath(data, g.config.GraphQLDataPath)
+	if err != nil || value == nil {
+		details["error_type"] = "graphql"
+		return false, fmt.Sprintf("GraphQL data path %s not found", g.config.GraphQLDataPath)
+	}
+	details["value"] = value
+
+	if g.config.GraphQLExpectedValue != "" && fmt.Sprint(value) != g.config.GraphQLExpectedValue {
+		details["error_type"] = "graphql"
+		return false, fmt.Sprintf("GraphQL data %s is %v, expected %s", g.config.GraphQLDataPath, value, g.config.GraphQLExpectedValue)
+	}
+
+	return true, fmt.Sprintf("GraphQL data %s is %v", g.config.GraphQLDataPath, value)
+}
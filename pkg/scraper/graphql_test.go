@@ -0,0 +1,177 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answerQueries returns a handler answering every POSTed query with the status and body,
+// rejecting requests without a query
+func answerQueries(statusCode int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.Method != "POST" || req["query"] == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		respond(statusCode, "application/json", body)(w, r)
+	}
+}
+
+func TestNewGraphQLScraper(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:    "http://localhost:8080/graphql",
+		PingURL:      "http://localhost:8081/ping",
+		GraphQLQuery: "{ health }",
+	}, logger)
+
+	assert.Equal(t, "graphql", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestGraphQLScraper_Scrape_Success(t *testing.T) {
+	server := newTestServer(t, answerQueries(http.StatusOK, `{"data":{"health":{"status":"UP"}}}`))
+
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:            server.URL,
+		GraphQLQuery:         "{ health { status } }",
+		GraphQLDataPath:      "health.status",
+		GraphQLExpectedValue: "UP",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, "UP", result.Details["value"])
+}
+
+func TestGraphQLScraper_Scrape_UnexpectedValue(t *testing.T) {
+	server := newTestServer(t, answerQueries(http.StatusOK, `{"data":{"health":{"status":"DOWN"}}}`))
+
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:            server.URL,
+		GraphQLQuery:         "{ health { status } }",
+		GraphQLDataPath:      "health.status",
+		GraphQLExpectedValue: "UP",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "expected UP")
+	assert.Equal(t, "graphql", result.Details["error_type"])
+}
+
+func TestGraphQLScraper_Scrape_GraphQLErrors(t *testing.T) {
+	server := newTestServer(t, answerQueries(http.StatusOK, `{"data":null,"errors":[{"message":"resolver failed","path":["health"]}]}`))
+
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:    server.URL,
+		GraphQLQuery: "{ health }",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "resolver failed")
+	assert.Equal(t, "graphql", result.Details["error_type"])
+	assert.Len(t, result.Details["errors"], 1)
+}
+
+func TestGraphQLScraper_Scrape_GraphQLErrorsWithBadRequestStatus(t *testing.T) {
+	server := newTestServer(t, answerQueries(http.StatusBadRequest, `{"errors":[{"message":"Cannot query field \"health\""}]}`))
+
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:    server.URL,
+		GraphQLQuery: "{ health }",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "graphql", result.Details["error_type"])
+}
+
+func TestGraphQLScraper_Scrape_HTTPError(t *testing.T) {
+	server := newTestServer(t, answerQueries(http.StatusBadGateway, `<html>Bad Gateway</html>`))
+
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:    server.URL,
+		GraphQLQuery: "{ health }",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "HTTP status 502")
+	assert.Equal(t, "transport", result.Details["error_type"])
+}
+
+func TestGraphQLScraper_Scrape_ConnectionError(t *testing.T) {
+	scraper := NewGraphQLScraper(config.HealthcheckScraper{
+		ScrapeURL:    "http://localhost:99999/graphql",
+		GraphQLQuery: "{ health }",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to connect to")
+	assert.Equal(t, "transport", result.Details["error_type"])
+}
+
+func TestGraphQLScraper_Scrape_Introspection(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		healthy bool
+	}{
+		{name: "schema loaded", body: `{"data":{"__schema":{"queryType":{"name":"Query"}}}}`, healthy: true},
+		{name: "schema failed to load", body: `{"data":{"__schema":null}}`, healthy: false},
+		{name: "schema errors", body: `{"errors":[{"message":"Schema is not configured"}]}`, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]string
+				json.NewDecoder(r.Body).Decode(&req)
+				queries <- req["query"]
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			factory := NewFactory(logrus.New())
+			scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+				Type:      "graphql",
+				ScrapeURL: server.URL,
+			})
+			require.NoError(t, err)
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, graphQLIntrospectionQuery, <-queries)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultFirstMessageTimeout bounds how long the scraper waits for the first stream message
+	defaultFirstMessageTimeout = 10 * time.Second
+	// maxGRPCMessageSize caps the size of the first response message that is read
+	maxGRPCMessageSize = 4 << 20
+)
+
+// GRPCStreamScraper implements the Scraper interface for verifying a gRPC server-streaming
+// or bidirectional streaming RPC delivers its first message. It speaks the gRPC wire format
+// directly over HTTP/2 so the request message must be provided already protobuf encoded.
+type GRPCStreamScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	firstMessageTimeout   time.Duration
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewGRPCStreamScraper creates a new gRPC streaming scraper
+func NewGRPCStreamScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *GRPCStreamScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &GRPCStreamScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		firstMessageTimeout:   defaultFirstMessageTimeout,
+		logger:                logger,
+		client: &http.Client{
+			Transport: newGRPCTransport(http.DefaultTransport.(*http.Transport).Clone()),
+		},
+	}
+}
+
+// newGRPCTransport restricts a transport to HTTP/2, allowing h2c for plain http:// URLs
+func newGRPCTransport(transport *http.Transport) *http.Transport {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	transport.Protocols = protocols
+	return transport
+}
+
+// Type returns the scraper type identifier
+func (g *GRPCStreamScraper) Type() string {
+	return "grpc-stream"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (g *GRPCStreamScraper) GetPingURL() string {
+	return g.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (g *GRPCStreamScraper) GetScrapeInterval() int {
+	return g.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (g *GRPCStreamScraper) Close() error {
+	g.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape opens the configured stream, sends the initial message and waits for the first
+// response message before cancelling the stream
+func (g *GRPCStreamScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	target := strings.TrimSuffix(g.config.ScrapeURL, "/") + "/" + strings.TrimPrefix(g.config.GRPCMethod, "/")
+	g.logger.WithField("url", target).Debug("Starting gRPC stream healthcheck")
+
+	message, err := base64.StdEncoding.DecodeString(g.config.GRPCRequestMessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode grpc_request_message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.firstMessageTimeout)
+	// Cancelling resets the stream once the first message was received
+	defer cancel()
+
+	frame := make([]byte, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(message)))
+	copy(frame[5:], message)
+
+	// Server-streaming calls close the request after the message, bidirectional calls
+	// keep it open like a real client would until the stream is cancelled
+	var body io.Reader = bytes.NewReader(frame)
+	if g.config.GRPCBidi {
+		reader, writer := io.Pipe()
+		go func() {
+			writer.Write(frame)
+			<-ctx.Done()
+			writer.Close()
+		}()
+		body = reader
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", g.firstMessageTimeout.Milliseconds()))
+
+	details := map[string]interface{}{
+		"grpc_method": g.config.GRPCMethod,
+	}
+
+	start := time.Now()
+	resp, err := g.client.Do(req)
+	if err != nil {
+		details["error"] = err.Error()
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to open stream %s: %v", target, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	setStatusCode(details, resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, target),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	// A trailers-only response carries the status in the headers and has no messages
+	if status := resp.Header.Get("Grpc-Status"); status != "" && status != "0" {
+		details["grpc_status"] = status
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Stream %s failed with grpc-status %s: %s", g.config.GRPCMethod, status, resp.Header.Get("Grpc-Message")),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	size, err := readGRPCMessage(resp.Body)
+	if err != nil {
+		if status := resp.Trailer.Get("Grpc-Status"); status != "" {
+			details["grpc_status"] = status
+		}
+		details["error"] = err.Error()
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("No message received from stream %s: %v", g.config.GRPCMethod, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	timeToFirstMessage := time.Since(start)
+	details["time_to_first_message_ms"] = timeToFirstMessage.Milliseconds()
+	details["message_bytes"] = size
+
+	g.logger.WithFields(logrus.Fields{
+		"url":                      target,
+		"time_to_first_message_ms": timeToFirstMessage.Milliseconds(),
+		"healthy":                  true,
+	}).Info("gRPC stream healthcheck completed")
+
+	return g.decorate(&ScrapeResult{
+		Healthy:   true,
+		Message:   fmt.Sprintf("Stream %s delivered its first message in %s", g.config.GRPCMethod, timeToFirstMessage.Round(time.Millisecond)),
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
+
+// readGRPCMessage reads one length-prefixed gRPC message and returns its size
+func readGRPCMessage(r io.Reader) (int, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF {
+			return 0, fmt.Errorf("stream ended before the first message")
+		}
+		return 0, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:5])
+	if size > maxGRPCMessageSize {
+		return 0, fmt.Errorf("message of %d bytes exceeds the %d byte limit", size, maxGRPCMessageSize)
+	}
+
+	if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+		return 0, err
+	}
+
+	return int(size), nil
+}
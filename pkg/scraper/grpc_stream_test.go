@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGRPCMessage writes a length-prefixed gRPC message and flushes it to the client
+func writeGRPCMessage(w http.ResponseWriter, message []byte) {
+	header := make([]byte, 5)
+	binary.BigEndian.PutUint32(header[1:5], uint32(len(message)))
+	w.Write(header)
+	w.Write(message)
+	w.(http.Flusher).Flush()
+}
+
+func TestNewGRPCStreamScraper(t *testing.T) {
+	scraper := NewGRPCStreamScraper(config.HealthcheckScraper{
+		ScrapeURL:  "http://localhost:50051",
+		PingURL:    "http://localhost:8081/ping",
+		GRPCMethod: "/events.Events/Subscribe",
+	}, logrus.New())
+
+	assert.Equal(t, "grpc-stream", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestGRPCStreamScraper_Scrape_FirstMessage(t *testing.T) {
+	var gotMessage []byte
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || r.URL.Path != "/events.Events/Subscribe" || r.Header.Get("Content-Type") != "application/grpc" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotMessage, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/grpc")
+		writeGRPCMessage(w, []byte{0x08, 0x01})
+		// Keep streaming until the client cancels
+		<-r.Context().Done()
+	})
+
+	scraper := NewGRPCStreamScraper(config.HealthcheckScraper{
+		ScrapeURL:          server.URL,
+		GRPCMethod:         "/events.Events/Subscribe",
+		GRPCRequestMessage: base64.StdEncoding.EncodeToString([]byte{0x0a, 0x01, 0x78}),
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, []byte{0, 0, 0, 0, 3, 0x0a, 0x01, 0x78}, gotMessage)
+	assert.Equal(t, 2, result.Details["message_bytes"])
+	assert.Contains(t, result.Details, "time_to_first_message_ms")
+}
+
+func TestGRPCStreamScraper_Scrape_Bidi(t *testing.T) {
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// The request stream stays open, so only the first message can be read
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(r.Body, header); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/grpc")
+		writeGRPCMessage(w, nil)
+		<-r.Context().Done()
+	})
+
+	scraper := NewGRPCStreamScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		GRPCMethod: "/chat.Chat/Connect",
+		GRPCBidi:   true,
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, 0, result.Details["message_bytes"])
+}
+
+func TestGRPCStreamScraper_Scrape_TrailersOnlyError(t *testing.T) {
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "12")
+		w.Header().Set("Grpc-Message", "unknown method")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	scraper := NewGRPCStreamScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		GRPCMethod: "/events.Events/Missing",
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "12", result.Details["grpc_status"])
+	assert.Contains(t, result.Message, "unknown method")
+}
+
+func TestGRPCStreamScraper_Scrape_NoMessageWithinDeadline(t *testing.T) {
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	scraper := NewGRPCStreamScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		GRPCMethod: "/events.Events/Subscribe",
+	}, logrus.New())
+	scraper.client = server.Client()
+	scraper.firstMessageTimeout = 100 * time.Millisecond
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "No message received")
+}
+
+func TestGRPCStreamScraper_Scrape_InvalidRequestMessage(t *testing.T) {
+	scraper := NewGRPCStreamScraper(config.HealthcheckScraper{
+		ScrapeURL:          "http://localhost:50051",
+		GRPCMethod:         "/events.Events/Subscribe",
+		GRPCRequestMessage: "not base64!",
+	}, logrus.New())
+
+	_, err := scraper.Scrape(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,257 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// grpcWebTrailerFlag marks the frame of a gRPC-web response that carries the trailers
+const grpcWebTrailerFlag = 0x80
+
+// grpcStatusNames names the gRPC status codes for result messages
+var grpcStatusNames = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED",
+	"OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+// GRPCWebScraper implements the Scraper interface for calling a unary RPC through a gRPC-web
+// gateway or proxy, such as Envoy's grpc_web filter. Unlike native gRPC, gRPC-web works over
+// HTTP/1.1 and HTTP/2 and carries the trailers in the last frame of the response body.
+type GRPCWebScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewGRPCWebScraper creates a new gRPC-web scraper
+func NewGRPCWebScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *GRPCWebScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &GRPCWebScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		logger:                logger,
+		client:                &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Type returns the scraper type identifier
+func (g *GRPCWebScraper) Type() string {
+	return "grpc-web"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (g *GRPCWebScraper) GetPingURL() string {
+	return g.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (g *GRPCWebScraper) GetScrapeInterval() int {
+	return g.scrapeIntervalSeconds
+}
+
+// Scrape posts the framed request message to the configured method and checks the
+// grpc-status of the response is OK
+func (g *GRPCWebScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	target := strings.TrimSuffix(g.config.ScrapeURL, "/") + "/" + strings.TrimPrefix(g.config.GRPCMethod, "/")
+	g.logger.WithField("url", target).Debug("Starting gRPC-web healthcheck")
+
+	requestMessage, err := base64.StdEncoding.DecodeString(g.config.GRPCRequestMessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode grpc_request_message: %w", err)
+	}
+
+	frame := make([]byte, 5+len(requestMessage))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(requestMessage)))
+	copy(frame[5:], requestMessage)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewReader(frame))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range g.config.RequestHeaders {
+		req.Header.Set(name, value)
+	}
+	if g.config.AuthHeader != "" {
+		req.Header.Set("Authorization", g.config.AuthHeader)
+	}
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("Accept", "application/grpc-web+proto")
+	req.Header.Set("X-Grpc-Web", "1")
+
+	details := map[string]interface{}{
+		"grpc_method": g.config.GRPCMethod,
+	}
+
+	start := time.Now()
+	resp, err := g.client.Do(req)
+	if err != nil {
+		details["error"] = err.Error()
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to call %s: %v", target, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	setStatusCode(details, resp.StatusCode)
+	details["protocol"] = resp.Proto
+
+	if resp.StatusCode != http.StatusOK {
+		message := fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, target)
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			message = fmt.Sprintf("HTTP status %d from %s, check auth_header and request_headers", resp.StatusCode, target)
+		}
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	// The text variant base64 encodes the frames for clients that cannot read binary bodies
+	var body io.Reader = resp.Body
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/grpc-web-text") {
+		body = base64.NewDecoder(base64.StdEncoding, resp.Body)
+	}
+
+	messages, trailers, err := readGRPCWebResponse(body)
+	setLatency(details, time.Since(start))
+	if err != nil {
+		details["error"] = err.Error()
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to read gRPC-web response from %s: %v", target, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+	details["message_count"] = messages
+
+	// A trailers-only response carries the status in the headers, and some proxies forward
+	// the HTTP/2 trailers as they are instead of framing them
+	status, ok := trailers["grpc-status"]
+	grpcMessage := trailers["grpc-message"]
+	for _, header := range []http.Header{resp.Header, resp.Trailer} {
+		if !ok && header.Get("Grpc-Status") != "" {
+			status, ok = header.Get("Grpc-Status"), true
+			grpcMessage = header.Get("Grpc-Message")
+		}
+	}
+	if !ok {
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Response from %s has no grpc-status", target),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		details["error"] = fmt.Sprintf("invalid grpc-status %q", status)
+		return g.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Response from %s has an invalid grpc-status %q", target, status),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+	details["grpc_status"] = code
+	if grpcMessage != "" {
+		details["grpc_message"] = grpcMessage
+	}
+
+	healthy := code == 0
+	message := fmt.Sprintf("%s returned grpc-status %d (%s)", g.config.GRPCMethod, code, grpcStatusName(code))
+	if grpcMessage != "" {
+		message += ": " + grpcMessage
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"url":         target,
+		"grpc_status": code,
+		"healthy":     healthy,
+	}).Info("gRPC-web healthcheck completed")
+
+	return g.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
+
+// readGRPCWebResponse reads the frames of a gRPC-web response, counting the message frames
+// and parsing the trailer frame, whose payload is a block of HTTP/1 style header lines
+func readGRPCWebResponse(r io.Reader) (int, map[string]string, error) {
+	messages := 0
+	for {
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return messages, nil, nil
+			}
+			return messages, nil, err
+		}
+
+		size := binary.BigEndian.Uint32(header[1:5])
+		if size > maxGRPCMessageSize {
+			return messages, nil, fmt.Errorf("frame of %d bytes exceeds the %d byte limit", size, maxGRPCMessageSize)
+		}
+
+		if header[0]&grpcWebTrailerFlag == 0 {
+			if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+				return messages, nil, err
+			}
+			messages++
+			continue
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return messages, nil, err
+		}
+		trailers := make(map[string]string)
+		scanner := bufio.NewScanner(bytes.NewReader(payload))
+		for scanner.Scan() {
+			name, value, ok := strings.Cut(scanner.Text(), ":")
+			if ok {
+				trailers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+			}
+		}
+		return messages, trailers, nil
+	}
+}
+
+// grpcStatusName returns the name of a gRPC status code
+func grpcStatusName(code int) string {
+	if code >= 0 && code < len(grpcStatusNames) {
+		return grpcStatusNames[code]
+	}
+	return "UNKNOWN"
+}
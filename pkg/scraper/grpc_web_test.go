@@ -0,0 +1,204 @@
+package scraper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grpcWebFrame builds a gRPC-web frame with the given flags
+func grpcWebFrame(flags byte, payload []byte) []byte {
+	frame := make([]byte, 5+len(payload))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+	return frame
+}
+
+func TestNewGRPCWebScraper(t *testing.T) {
+	scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+		ScrapeURL:  "https://gateway.example.com",
+		PingURL:    "http://localhost:8081/ping",
+		GRPCMethod: "/grpc.health.v1.Health/Check",
+	}, logrus.New())
+
+	assert.Equal(t, "grpc-web", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestGRPCWebScraper_Scrape_OK(t *testing.T) {
+	var gotMessage []byte
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || r.URL.Path != "/grpc.health.v1.Health/Check" ||
+			r.Header.Get("Content-Type") != "application/grpc-web+proto" || r.Header.Get("X-Grpc-Web") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotMessage = body[5:]
+
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		w.Write(grpcWebFrame(0, []byte("\x08\x01")))
+		w.Write(grpcWebFrame(grpcWebTrailerFlag, []byte("grpc-status:0\r\ngrpc-message:\r\n")))
+	})
+
+	scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+		ScrapeURL:          server.URL,
+		GRPCMethod:         "/grpc.health.v1.Health/Check",
+		GRPCRequestMessage: base64.StdEncoding.EncodeToString([]byte("\x0a\x03api")),
+		AuthHeader:         "Bearer secret",
+		RequestHeaders:     map[string]string{"X-Api-Key": "key"},
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, []byte("\x0a\x03api"), gotMessage)
+	assert.Equal(t, 0, result.Details["grpc_status"])
+	assert.Equal(t, 1, result.Details["message_count"])
+	assert.Equal(t, "HTTP/2.0", result.Details["protocol"])
+	assert.Equal(t, "/grpc.health.v1.Health/Check returned grpc-status 0 (OK)", result.Message)
+}
+
+func TestGRPCWebScraper_Scrape_ErrorStatus(t *testing.T) {
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		w.Write(grpcWebFrame(grpcWebTrailerFlag, []byte("Grpc-Status: 14\r\nGrpc-Message: backend down\r\n")))
+	})
+
+	scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		GRPCMethod: "/grpc.health.v1.Health/Check",
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, 14, result.Details["grpc_status"])
+	assert.Equal(t, "backend down", result.Details["grpc_message"])
+	assert.Contains(t, result.Message, "grpc-status 14 (UNAVAILABLE): backend down")
+}
+
+func TestGRPCWebScraper_Scrape_TrailersOnly(t *testing.T) {
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		w.Header().Set("Grpc-Status", "16")
+		w.Header().Set("Grpc-Message", "missing token")
+	})
+
+	scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		GRPCMethod: "/grpc.health.v1.Health/Check",
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, 16, result.Details["grpc_status"])
+	assert.Contains(t, result.Message, "UNAUTHENTICATED")
+}
+
+func TestGRPCWebScraper_Scrape_Text(t *testing.T) {
+	server := newHTTP2TestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc-web-text+proto")
+		frames := append(grpcWebFrame(0, []byte("\x08\x01")), grpcWebFrame(grpcWebTrailerFlag, []byte("grpc-status:0\r\n"))...)
+		w.Write([]byte(base64.StdEncoding.EncodeToString(frames)))
+	})
+
+	scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		GRPCMethod: "/grpc.health.v1.Health/Check",
+	}, logrus.New())
+	scraper.client = server.Client()
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, 1, result.Details["message_count"])
+}
+
+func TestGRPCWebScraper_Scrape_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		message string
+	}{
+		{
+			name:    "unauthorized",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) },
+			message: "HTTP status 401",
+		},
+		{
+			name: "no status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(grpcWebFrame(0, []byte("\x08\x01")))
+			},
+			message: "has no grpc-status",
+		},
+		{
+			name: "truncated frame",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(grpcWebFrame(0, []byte("\x08\x01"))[:4])
+			},
+			message: "Failed to read gRPC-web response",
+		},
+		{
+			name: "invalid status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(grpcWebFrame(grpcWebTrailerFlag, []byte("grpc-status:ok\r\n")))
+			},
+			message: `invalid grpc-status "ok"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newHTTP2TestServer(t, tt.handler)
+			scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+				ScrapeURL:  server.URL,
+				GRPCMethod: "/grpc.health.v1.Health/Check",
+			}, logrus.New())
+			scraper.client = server.Client()
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestGRPCWebScraper_Scrape_ConnectionError(t *testing.T) {
+	scraper := NewGRPCWebScraper(config.HealthcheckScraper{
+		ScrapeURL:  "http://127.0.0.1:1",
+		GRPCMethod: "/grpc.health.v1.Health/Check",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to call")
+}
@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer starts a test server with the handler, closed when the test ends
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newHTTP2TestServer starts a TLS test server speaking HTTP/2 with the handler, closed when
+// the test ends. Clients must trust it through server.Client().
+func newHTTP2TestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// respond returns a handler answering every request with the status and body, and the
+// content type unless it is empty
+func respond(status int, contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// newTestScraper creates the scraper through the factory, as the manager does
+func newTestScraper(t *testing.T, scraperConfig config.HealthcheckScraper) Scraper {
+	s, err := NewFactory(logrus.New()).CreateScraper(scraperConfig)
+	require.NoError(t, err)
+	return s
+}
@@ -0,0 +1,566 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultFirstLineTimeout bounds how long the read_first_line mode waits for the first line
+const defaultFirstLineTimeout = 5 * time.Second
+
+// HTTPScraper implements the Scraper interface for generic HTTP endpoints
+type HTTPScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	firstLineTimeout      time.Duration
+	logger                *logrus.Logger
+	client                *http.Client
+	// healthExpression and messageExpression are compiled from the configuration by the registry
+	healthExpression  *expression
+	messageExpression *expression
+	// badPagePatterns are compiled from bad_page_patterns by the registry
+	badPagePatterns []*regexp.Regexp
+	// requestURL is the URL requests are sent to, which differs from the scrape URL for Unix
+	// sockets, whose path socketPath is set then
+	requestURL string
+	socketPath string
+}
+
+// NewHTTPScraper creates a new generic HTTP scraper
+func NewHTTPScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *HTTPScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	// The registry rejects invalid Unix socket URLs before creating the scraper
+	requestURL := scraperConfig.ScrapeURL
+	socketPath, unixURL, ok, _ := parseUnixScrapeURL(scraperConfig.ScrapeURL)
+	if ok {
+		requestURL = unixURL
+	}
+
+	return &HTTPScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		requestURL:            requestURL,
+		socketPath:            socketPath,
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		firstLineTimeout:      defaultFirstLineTimeout,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: clientTimeout(scraperConfig),
+		},
+	}
+}
+
+// Type returns the scraper type identifier
+func (h *HTTPScraper) Type() string {
+	return "http"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (h *HTTPScraper) GetPingURL() string {
+	return h.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (h *HTTPScraper) GetScrapeInterval() int {
+	return h.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (h *HTTPScraper) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape performs the healthcheck by sending a GET request to the scrape URL
+func (h *HTTPScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	if h.config.Burst > 1 {
+		return h.scrapeBurst(ctx)
+	}
+	if h.config.Probes > 1 {
+		return h.scrapeProbes(ctx)
+	}
+
+	scrapeURL := h.config.ScrapeURL
+	h.logger.WithField("url", scrapeURL).Debug("Starting HTTP healthcheck")
+
+	var warmup map[string]interface{}
+	if h.config.Warmup {
+		warmup = h.warmup(ctx)
+	}
+
+	// Streaming endpoints never finish their body, so bound the whole request
+	// including the first line read by a shorter sub-timeout
+	if h.config.ReadFirstLine {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.firstLineTimeout)
+		defer cancel()
+	}
+
+	attemptCtx, cancel := h.attemptContext(ctx)
+	defer cancel()
+
+	start := time.Now()
+	var ttfb time.Duration
+	// Informational responses such as 103 Early Hints precede the final response and
+	// are only recorded, the health is always judged on the final status
+	var informational []int
+	var reused bool
+	traceCtx := httptrace.WithClientTrace(attemptCtx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			ttfb = time.Since(start)
+		},
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			informational = append(informational, code)
+			return nil
+		},
+	})
+
+	req, err := http.NewRequestWithContext(traceCtx, "GET", h.requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// gRPC and gRPC-web servers and proxies only send trailers to clients announcing support
+	if h.config.TrailerKey != "" {
+		req.Header.Set("TE", "trailers")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		details := map[string]interface{}{
+			"error": err.Error(),
+		}
+		maps.Copy(details, warmup)
+		message := fmt.Sprintf("Failed to connect to %s: %v", scrapeURL, err)
+		if attemptTimedOut(ctx, attemptCtx) {
+			details["request_timeout_ms"] = h.config.RequestTimeoutMs
+			message = fmt.Sprintf("Request to %s timed out after %dms", scrapeURL, h.config.RequestTimeoutMs)
+		}
+		return h.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	// Counted beneath every other check so the whole body is accounted for
+	var body *countingBody
+	if h.config.VerifyContentLength {
+		body = countBody(resp)
+	}
+
+	details := map[string]interface{}{
+		DetailStatusCode: resp.StatusCode,
+	}
+	if len(informational) > 0 {
+		details["informational_status_codes"] = informational
+	}
+	if warmup != nil {
+		maps.Copy(details, warmup)
+		details["connection_reused"] = reused
+	}
+
+	// A redirect that was not followed would otherwise only show as a 3xx status
+	if len(h.config.AllowedRedirectHosts) > 0 {
+		if allowed, message := h.checkRedirects(resp, details); !allowed {
+			h.recordTimings(resp, details, start, ttfb)
+			return h.decorate(&ScrapeResult{
+				Healthy:   false,
+				Message:   message,
+				Timestamp: time.Now(),
+				Details:   details,
+			}, resp), nil
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		h.recordTimings(resp, details, start, ttfb)
+		return h.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, scrapeURL),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	healthy := true
+	message := fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, scrapeURL)
+
+	// A maintenance page served with a 2xx status is down no matter what else the body says
+	if len(h.badPagePatterns) > 0 || len(h.config.BadPageTitles) > 0 {
+		if ok, badMessage := h.checkBadPage(resp, details); !ok {
+			h.recordTimings(resp, details, start, ttfb)
+			return h.decorate(&ScrapeResult{
+				Healthy:   false,
+				Message:   badMessage,
+				Timestamp: time.Now(),
+				Details:   details,
+			}, resp), nil
+		}
+	}
+
+	if h.config.ReadFirstLine {
+		line, err := readFirstLine(resp)
+		if err != nil {
+			details["error"] = err.Error()
+			lineMessage := fmt.Sprintf("Failed to read first line from %s: %v", scrapeURL, err)
+			// A stream ending without a line is as empty as a body without any content
+			if errors.Is(err, errEmptyResponse) {
+				lineMessage = parseFailure(scrapeURL, err, details)
+			}
+			h.recordTimings(resp, details, start, ttfb)
+			return h.decorate(&ScrapeResult{
+				Healthy:   false,
+				Message:   lineMessage,
+				Timestamp: time.Now(),
+				Details:   details,
+			}, resp), nil
+		}
+		details["first_line"] = line
+		message = fmt.Sprintf("Received first line from %s: %s", scrapeURL, line)
+		// A stream reports its health in its events, so a healthy status alone proves little
+		if h.healthExpression != nil {
+			healthy, message = h.evaluateDocument(firstLineDocument(line), resp.StatusCode, details)
+		}
+	} else if h.config.JSONPath != "" {
+		healthy, message = h.checkJSONArrayLength(resp, details)
+	} else if h.healthExpression != nil {
+		healthy, message = h.evaluateExpressions(resp, details)
+	} else if h.config.FailOnEmptyResponse {
+		healthy, message = h.checkNotEmpty(resp, details)
+	}
+
+	// A stale deploy still answers with a healthy 200, so the content is judged as well
+	if healthy && (h.config.MaxAgeSeconds > 0 || h.config.ExpectedETag != "") {
+		if fresh, staleMessage := h.checkFreshness(resp, details, time.Now()); !fresh {
+			healthy, message = false, staleMessage
+		}
+	}
+	if healthy && (h.config.MinQuotaRemaining > 0 || h.config.MinQuotaPercent > 0) {
+		healthy, message = h.checkRateLimit(resp, details, time.Now())
+	}
+
+	h.recordTimings(resp, details, start, ttfb)
+	// A truncated body is the cause of whatever the checks of the body made of it
+	if body != nil {
+		if ok, truncatedMessage := h.checkContentLength(resp, body, details); !ok {
+			healthy, message = false, truncatedMessage
+		}
+	}
+	if healthy && h.config.TrailerKey != "" {
+		healthy, message = h.checkTrailer(resp, details)
+	}
+	if healthy && h.config.MaxTTFBMs > 0 && ttfb > time.Duration(h.config.MaxTTFBMs)*time.Millisecond {
+		healthy = false
+		message = fmt.Sprintf("Time to first byte from %s was %dms, expected at most %dms", scrapeURL, ttfb.Milliseconds(), h.config.MaxTTFBMs)
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"url":         scrapeURL,
+		"status_code": resp.StatusCode,
+		"healthy":     healthy,
+	}).Info("HTTP healthcheck completed")
+
+	return h.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
+
+// recordTimings records the time to first byte and the total time of the request in details,
+// reading the rest of the body first unless only the first line of a stream is read
+func (h *HTTPScraper) recordTimings(resp *http.Response, details map[string]interface{}, start time.Time, ttfb time.Duration) {
+	// Responses served from the scrape cache never reach the server, so they have no first byte
+	if ttfb > 0 {
+		details["ttfb_ms"] = ttfb.Milliseconds()
+	}
+	if !h.config.ReadFirstLine {
+		io.Copy(io.Discard, resp.Body)
+	}
+	details["total_ms"] = time.Since(start).Milliseconds()
+}
+
+// checkTrailer asserts the configured trailer was sent, and has the expected value if one is
+// configured, recording its value and all observed trailers in details. Trailers are only
+// known once the body was read.
+func (h *HTTPScraper) checkTrailer(resp *http.Response, details map[string]interface{}) (bool, string) {
+	observed := make(map[string]string, len(resp.Trailer))
+	for name, values := range resp.Trailer {
+		if len(values) > 0 {
+			observed[name] = values[0]
+		}
+	}
+	details["trailers"] = observed
+	details["protocol"] = resp.Proto
+
+	key := h.config.TrailerKey
+	values, ok := resp.Trailer[http.CanonicalHeaderKey(key)]
+	if !ok || len(values) == 0 {
+		return false, fmt.Sprintf("Trailer %s missing from %s", key, h.config.ScrapeURL)
+	}
+
+	value := values[0]
+	details["trailer"] = value
+
+	if h.config.ExpectedTrailerValue != "" && value != h.config.ExpectedTrailerValue {
+		return false, fmt.Sprintf("Trailer %s from %s is %s, expected %s", key, h.config.ScrapeURL, value, h.config.ExpectedTrailerValue)
+	}
+
+	return true, fmt.Sprintf("Trailer %s from %s is %s", key, h.config.ScrapeURL, value)
+}
+
+// scrapeBurst sends the configured number of concurrent GET requests to the scrape URL and
+// is healthy if at least the quorum of them returned a 2xx status
+func (h *HTTPScraper) scrapeBurst(ctx context.Context) (*ScrapeResult, error) {
+	scrapeURL := h.config.ScrapeURL
+	h.logger.WithFields(logrus.Fields{
+		"url":   scrapeURL,
+		"burst": h.config.Burst,
+	}).Debug("Starting HTTP burst healthcheck")
+
+	quorum := h.config.BurstQuorum
+	if quorum <= 0 {
+		quorum = h.config.Burst
+	}
+
+	outcomes := make([]map[string]interface{}, h.config.Burst)
+	latencies := make([]time.Duration, h.config.Burst)
+	succeeded := make([]bool, h.config.Burst)
+
+	var wg sync.WaitGroup
+	for i := range outcomes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			outcomes[i], succeeded[i] = h.probeRequest(ctx)
+			latencies[i] = time.Since(start)
+			outcomes[i]["latency_ms"] = latencies[i].Milliseconds()
+		}()
+	}
+	wg.Wait()
+
+	successes := 0
+	var maxLatency time.Duration
+	for i := range outcomes {
+		if succeeded[i] {
+			successes++
+		}
+		maxLatency = max(maxLatency, latencies[i])
+	}
+
+	healthy := successes >= quorum
+	details := map[string]interface{}{
+		"requests":       outcomes,
+		"successes":      successes,
+		"quorum":         quorum,
+		"max_latency_ms": maxLatency.Milliseconds(),
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"url":       scrapeURL,
+		"successes": successes,
+		"burst":     h.config.Burst,
+		"healthy":   healthy,
+	}).Info("HTTP burst healthcheck completed")
+
+	message := fmt.Sprintf("%d of %d concurrent requests to %s succeeded", successes, h.config.Burst, scrapeURL)
+	if !healthy {
+		message = fmt.Sprintf("%s, expected at least %d", message, quorum)
+	}
+
+	return h.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil), nil
+}
+
+// attemptContext bounds a single request by request_timeout_ms if configured, nested within
+// the scrape's overall context
+func (h *HTTPScraper) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.config.RequestTimeoutMs <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(h.config.RequestTimeoutMs)*time.Millisecond)
+}
+
+// attemptTimedOut reports whether a request failed because its own timeout elapsed while
+// the scrape's overall context was still live
+func attemptTimedOut(scrapeCtx, attemptCtx context.Context) bool {
+	return scrapeCtx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded)
+}
+
+// probeRequest sends a single request of a burst or probe window and returns its outcome and
+// whether it succeeded
+func (h *HTTPScraper) probeRequest(ctx context.Context) (map[string]interface{}, bool) {
+	attemptCtx, cancel := h.attemptContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(attemptCtx, "GET", h.requestURL, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, false
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		outcome := map[string]interface{}{"error": err.Error()}
+		if attemptTimedOut(ctx, attemptCtx) {
+			outcome["request_timeout_ms"] = h.config.RequestTimeoutMs
+		}
+		return outcome, false
+	}
+	defer resp.Body.Close()
+
+	// Read the body so a server failing mid-response counts as a failure
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return map[string]interface{}{DetailStatusCode: resp.StatusCode, "error": err.Error()}, false
+	}
+
+	return map[string]interface{}{DetailStatusCode: resp.StatusCode}, resp.StatusCode >= 200 && resp.StatusCode <= 299
+}
+
+// checkJSONArrayLength asserts the array at the configured JSON path has a length within
+// the configured bounds, recording the observed length in details
+func (h *HTTPScraper) checkJSONArrayLength(resp *http.Response, details map[string]interface{}) (bool, string) {
+	var doc interface{}
+	if err := decodeJSON(resp.Body, &doc); err != nil {
+		return false, parseFailure(h.config.ScrapeURL, err, details)
+	}
+
+	value, err := lookupJSONPath(doc, h.config.JSONPath)
+	if err != nil {
+		details["error"] = err.Error()
+		return false, fmt.Sprintf("JSON path %s not found: %v", h.config.JSONPath, err)
+	}
+
+	array, ok := value.([]interface{})
+	if !ok {
+		return false, fmt.Sprintf("JSON path %s is not an array", h.config.JSONPath)
+	}
+
+	length := len(array)
+	details["length"] = length
+
+	if h.config.MinLength != nil && length < *h.config.MinLength {
+		return false, fmt.Sprintf("Array at %s has %d items, expected at least %d", h.config.JSONPath, length, *h.config.MinLength)
+	}
+	if h.config.MaxLength != nil && length > *h.config.MaxLength {
+		return false, fmt.Sprintf("Array at %s has %d items, expected at most %d", h.config.JSONPath, length, *h.config.MaxLength)
+	}
+
+	return true, fmt.Sprintf("Array at %s has %d items", h.config.JSONPath, length)
+}
+
+// evaluateExpressions decodes the JSON response and evaluates the expressions against it
+func (h *HTTPScraper) evaluateExpressions(resp *http.Response, details map[string]interface{}) (bool, string) {
+	var doc interface{}
+	if err := decodeJSON(resp.Body, &doc); err != nil {
+		return false, parseFailure(h.config.ScrapeURL, err, details)
+	}
+	return h.evaluateDocument(doc, resp.StatusCode, details)
+}
+
+// evaluateDocument evaluates the health expression, and the message expression if configured,
+// with the document as body and the status code as status
+func (h *HTTPScraper) evaluateDocument(doc interface{}, statusCode int, details map[string]interface{}) (bool, string) {
+	vars := map[string]interface{}{
+		"body":   doc,
+		"status": float64(statusCode),
+	}
+
+	value, err := h.healthExpression.Evaluate(vars)
+	if err != nil {
+		details["error"] = err.Error()
+		return false, fmt.Sprintf("Failed to evaluate health_expression for %s: %v", h.config.ScrapeURL, err)
+	}
+	healthy, ok := value.(bool)
+	if !ok {
+		return false, fmt.Sprintf("health_expression returned a %s for %s, expected a boolean", typeName(value), h.config.ScrapeURL)
+	}
+	details["expression_result"] = healthy
+
+	message := fmt.Sprintf("health_expression is %t for %s", healthy, h.config.ScrapeURL)
+	if h.messageExpression != nil {
+		// A broken message expression must not hide the health the expression computed
+		value, err := h.messageExpression.Evaluate(vars)
+		if err != nil {
+			details["message_expression_error"] = err.Error()
+		} else {
+			message = formatValue(value)
+		}
+	}
+
+	return healthy, message
+}
+
+// sseFields are the prefixes of the server-sent event fields other than data, which describe
+// an event rather than carry it
+var sseFields = []string{"event:", "id:", "retry:"}
+
+// readFirstLine returns the first non-empty line or server-sent event data of the response body,
+// skipping event-stream comments used as keep-alives and the other fields of an event
+func readFirstLine(resp *http.Response) (string, error) {
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ":") || isEventField(line) {
+			continue
+		}
+		return strings.TrimSpace(strings.TrimPrefix(line, "data:")), nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errEmptyResponse
+}
+
+// isEventField reports whether the line is a server-sent event field other than data
+func isEventField(line string) bool {
+	for _, field := range sseFields {
+		if strings.HasPrefix(line, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLineDocument returns the first line decoded as JSON for the health expression, or the
+// line itself as a string when it is plain text
+func firstLineDocument(line string) interface{} {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(line), &doc); err != nil {
+		return line
+	}
+	return doc
+}
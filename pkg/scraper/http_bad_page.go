@@ -0,0 +1,67 @@
+package scraper
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// maxBadPageBytes caps how much of the body is searched for bad page signatures, as
+// maintenance and error pages are small and their signature is near the top
+const maxBadPageBytes = 1 << 20
+
+// titlePattern extracts the title of an HTML page
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// compileBadPagePatterns compiles the bad_page_patterns so an invalid expression fails
+// when the scraper is created
+func compileBadPagePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bad_page_patterns entry %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// checkBadPage searches the start of the body for the configured bad page signatures, such
+// as a CDN maintenance page served with a 200 status. The body read is put back in front of
+// the rest so later checks and the trailers still see the whole response.
+func (h *HTTPScraper) checkBadPage(resp *http.Response, details map[string]interface{}) (bool, string) {
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxBadPageBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(page), resp.Body), resp.Body}
+	if err != nil {
+		details["error"] = err.Error()
+		return false, fmt.Sprintf("Failed to read response from %s: %v", h.config.ScrapeURL, err)
+	}
+
+	if match := titlePattern.FindSubmatch(page); match != nil {
+		title := strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " ")
+		details["page_title"] = title
+		for _, bad := range h.config.BadPageTitles {
+			if strings.Contains(strings.ToLower(title), strings.ToLower(bad)) {
+				details["bad_page_signature"] = "title:" + bad
+				return false, fmt.Sprintf("%s served a bad page titled %q", h.config.ScrapeURL, title)
+			}
+		}
+	}
+
+	for _, re := range h.badPagePatterns {
+		if re.Match(page) {
+			details["bad_page_signature"] = "pattern:" + re.String()
+			return false, fmt.Sprintf("%s served a bad page matching %s", h.config.ScrapeURL, re)
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,82 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const maintenancePage = `<!DOCTYPE html>
+<html><head><title>
+  Site Under Maintenance &amp; Upgrade
+</title></head>
+<body><h1>We'll be back soon</h1><p>Ray ID: 7d1c2a</p></body></html>`
+
+func TestHTTPScraper_Scrape_BadPageTitle(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "", maintenancePage))
+	s := newTestScraper(t, config.HealthcheckScraper{
+		Type:          "http",
+		ScrapeURL:     server.URL,
+		BadPageTitles: []string{"maintenance"},
+	})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "title:maintenance", result.Details["bad_page_signature"])
+	assert.Equal(t, "Site Under Maintenance & Upgrade", result.Details["page_title"])
+	assert.Contains(t, result.Message, `served a bad page titled "Site Under Maintenance & Upgrade"`)
+}
+
+func TestHTTPScraper_Scrape_BadPagePattern(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "", maintenancePage))
+	s := newTestScraper(t, config.HealthcheckScraper{
+		Type:            "http",
+		ScrapeURL:       server.URL,
+		BadPageTitles:   []string{"Error"},
+		BadPagePatterns: []string{`Ray ID: [0-9a-f]+`},
+	})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "pattern:Ray ID: [0-9a-f]+", result.Details["bad_page_signature"])
+	assert.Contains(t, result.Message, "served a bad page matching")
+}
+
+func TestHTTPScraper_Scrape_BadPageNotMatched(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "", `{"items":[1,2,3]}`))
+	s := newTestScraper(t, config.HealthcheckScraper{
+		Type:            "http",
+		ScrapeURL:       server.URL,
+		BadPageTitles:   []string{"maintenance"},
+		BadPagePatterns: []string{`(?i)we'll be back`},
+		JSONPath:        "$.items",
+	})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Nil(t, result.Details["bad_page_signature"])
+	// The JSON check still sees the body read for the signatures
+	assert.Equal(t, 3, result.Details["length"])
+}
+
+func TestFactory_CreateScraper_InvalidBadPagePattern(t *testing.T) {
+	_, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:            "http",
+		ScrapeURL:       "http://localhost:8080/health",
+		BadPagePatterns: []string{"(unclosed"},
+	})
+
+	assert.ErrorContains(t, err, `invalid bad_page_patterns entry "(unclosed"`)
+}
@@ -0,0 +1,172 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+// httpOptions holds the settings shared by HTTP based scrapers
+type httpOptions struct {
+	annotationHeaders []string
+	sourceAddress     string
+}
+
+// newHTTPOptions extracts the shared HTTP settings from the scraper configuration
+func newHTTPOptions(scraperConfig config.HealthcheckScraper) httpOptions {
+	return httpOptions{
+		annotationHeaders: scraperConfig.AnnotationHeaders,
+		sourceAddress:     scraperConfig.SourceAddress,
+	}
+}
+
+// decorate adds the details shared by HTTP based scrapers to a result,
+// resp is nil when no response was received
+func (o httpOptions) decorate(result *ScrapeResult, resp *http.Response) *ScrapeResult {
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+
+	if o.sourceAddress != "" {
+		result.Details["source_address"] = o.sourceAddress
+	}
+
+	if resp != nil {
+		annotate(result, resp.Header, o.annotationHeaders)
+		if addr := responseRemoteAddr(resp); addr != "" {
+			setRemoteAddr(result.Details, addr)
+		}
+		if traceID := responseTraceID(resp); traceID != "" {
+			result.Details["trace_id"] = traceID
+		}
+		recordDoHLookup(result.Details, resp)
+		recordTLS(result.Details, resp)
+	}
+
+	return result
+}
+
+// defaultClientTimeout bounds the requests of HTTP based scrapers without request_timeout_ms
+const defaultClientTimeout = 10 * time.Second
+
+// clientTimeout returns the timeout of the scraper's HTTP client. With request_timeout_ms the
+// attempt context bounds each request instead, so the client must not cut it short.
+func clientTimeout(scraperConfig config.HealthcheckScraper) time.Duration {
+	if scraperConfig.RequestTimeoutMs > 0 {
+		return 0
+	}
+	return defaultClientTimeout
+}
+
+// newHTTPClient creates the HTTP client used by HTTP based scrapers
+func newHTTPClient(scraperConfig config.HealthcheckScraper) (*http.Client, error) {
+	transport, err := newHTTPTransport(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var next http.RoundTripper = &remoteAddrTransport{next: transport}
+	if scraperConfig.DoHResolverURL != "" {
+		next = &dohTransport{next: next}
+	}
+
+	return &http.Client{
+		Timeout:   clientTimeout(scraperConfig),
+		Transport: cacheTransport(instrumentTransport(traceTransport(next, scraperConfig), scraperConfig), scraperConfig),
+	}, nil
+}
+
+// newHTTPTransport creates the transport applying the shared dial, trust and minimum TLS
+// version settings of HTTP based scrapers
+func newHTTPTransport(scraperConfig config.HealthcheckScraper) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	dial, err := newDialContext(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport.DialContext = dial
+
+	minTLSVersion, err := parseTLSVersion(scraperConfig.MinTLSVersion)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs, err := newRootCAs(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: minTLSVersion}
+
+	return transport, nil
+}
+
+// newDialContext creates the dial function applying the scraper's source address, DNS cache
+// and DoH resolver, or dialing the Unix socket of a unix:// scrape URL
+func newDialContext(scraperConfig config.HealthcheckScraper) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if scraperConfig.SourceAddress != "" {
+		ip := net.ParseIP(scraperConfig.SourceAddress)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid source address: %s", scraperConfig.SourceAddress)
+		}
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+
+	dial := dialer.DialContext
+	if scraperConfig.DoHResolverURL != "" {
+		resolver, err := newDoHResolver(scraperConfig)
+		if err != nil {
+			return nil, err
+		}
+		// DoH resolutions are always cached briefly to avoid a lookup on every scrape
+		ttl := defaultDoHCacheTTL
+		if scraperConfig.DNSCacheTTLSeconds > 0 {
+			ttl = time.Duration(scraperConfig.DNSCacheTTLSeconds) * time.Second
+		}
+		cache := newDNSCache(ttl, dialer)
+		cache.lookupHost = resolver.lookupHost
+		dial = cache.DialContext
+	} else if scraperConfig.DNSCacheTTLSeconds > 0 {
+		cache := newDNSCache(time.Duration(scraperConfig.DNSCacheTTLSeconds)*time.Second, dialer)
+		dial = cache.DialContext
+	}
+
+	if scraperConfig.SourceAddress != "" {
+		dial = wrapBindErrors(dial, scraperConfig.SourceAddress)
+	}
+
+	// Requests to a Unix socket scrape URL go to the socket whatever their host
+	socketPath, _, ok, err := parseUnixScrapeURL(scraperConfig.ScrapeURL)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	return dial, nil
+}
+
+// wrapBindErrors turns failures to bind the source address into a clear error
+func wrapBindErrors(dial func(ctx context.Context, network, addr string) (net.Conn, error), sourceAddress string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil && errors.Is(err, syscall.EADDRNOTAVAIL) {
+			return nil, fmt.Errorf("failed to bind source address %s: %w", sourceAddress, err)
+		}
+		return conn, err
+	}
+}
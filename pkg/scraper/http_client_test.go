@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_InvalidSourceAddress(t *testing.T) {
+	client, err := newHTTPClient(config.HealthcheckScraper{SourceAddress: "not-an-ip"})
+
+	assert.Error(t, err)
+	assert.Nil(t, client)
+	assert.Contains(t, err.Error(), "invalid source address: not-an-ip")
+}
+
+func TestNewHTTPClient_RequestTimeout(t *testing.T) {
+	client, err := newHTTPClient(config.HealthcheckScraper{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultClientTimeout, client.Timeout)
+
+	// A request timeout above the default is not capped by the client
+	client, err = newHTTPClient(config.HealthcheckScraper{RequestTimeoutMs: 15000})
+	require.NoError(t, err)
+	assert.Zero(t, client.Timeout)
+	assert.Zero(t, NewHTTPScraper(config.HealthcheckScraper{RequestTimeoutMs: 15000}, logrus.New()).client.Timeout)
+}
+
+func TestHTTPOptions_Decorate(t *testing.T) {
+	options := newHTTPOptions(config.HealthcheckScraper{
+		SourceAddress:     "10.0.0.5",
+		AnnotationHeaders: []string{"X-Deploy-Id"},
+	})
+
+	header := http.Header{}
+	header.Set("X-Deploy-Id", "build-42")
+
+	result := options.decorate(&ScrapeResult{}, &http.Response{Header: header})
+
+	assert.Equal(t, "10.0.0.5", result.Details["source_address"])
+	assert.Equal(t, map[string]string{"X-Deploy-Id": "build-42"}, result.Details["annotations"])
+
+	// Without a response only the source address is reported
+	result = options.decorate(&ScrapeResult{}, nil)
+
+	assert.Equal(t, "10.0.0.5", result.Details["source_address"])
+	assert.NotContains(t, result.Details, "annotations")
+}
+
+func TestHTTPScraper_Scrape_SourceAddress(t *testing.T) {
+	var remoteHost string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteHost, _, _ = net.SplitHostPort(r.RemoteAddr)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	factory := NewFactory(logrus.New())
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:          "http",
+		ScrapeURL:     server.URL,
+		SourceAddress: "127.0.0.1",
+	})
+	require.NoError(t, err)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, "127.0.0.1", result.Details["source_address"])
+	assert.Equal(t, "127.0.0.1", remoteHost)
+}
+
+func TestHTTPScraper_Scrape_SourceAddressBindFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 192.0.2.0/24 is reserved for documentation and not assigned to any local interface
+	factory := NewFactory(logrus.New())
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:          "http",
+		ScrapeURL:     server.URL,
+		SourceAddress: "192.0.2.1",
+	})
+	require.NoError(t, err)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "failed to bind source address 192.0.2.1")
+	assert.Equal(t, "192.0.2.1", result.Details["source_address"])
+}
@@ -0,0 +1,52 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// countingBody counts the bytes read from a response body and remembers the first read error
+type countingBody struct {
+	io.ReadCloser
+	read int64
+	err  error
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.read += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// countBody wraps the response body to count the bytes read from it by all checks
+func countBody(resp *http.Response) *countingBody {
+	body := &countingBody{ReadCloser: resp.Body}
+	resp.Body = body
+	return body
+}
+
+// checkContentLength asserts the whole body was read and matches the declared Content-Length,
+// recording the declared and actual length in details. The body must have been read to its end.
+// The HTTP/1.1 client stops reading at the declared length, so a longer body is only detected
+// over HTTP/2, where it fails the read.
+func (h *HTTPScraper) checkContentLength(resp *http.Response, body *countingBody, details map[string]interface{}) (bool, string) {
+	details["content_length_actual"] = body.read
+	if resp.ContentLength < 0 {
+		return true, ""
+	}
+	details["content_length_declared"] = resp.ContentLength
+
+	if body.err != nil {
+		details["error"] = body.err.Error()
+		return false, fmt.Sprintf("Response from %s was truncated after %d of %d bytes declared by Content-Length: %v", h.config.ScrapeURL, body.read, resp.ContentLength, body.err)
+	}
+	if body.read != resp.ContentLength {
+		return false, fmt.Sprintf("Response from %s has %d bytes, Content-Length declared %d", h.config.ScrapeURL, body.read, resp.ContentLength)
+	}
+	return true, ""
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// truncateResponse returns a handler declaring a Content-Length of declared bytes but sending
+// only body before closing the connection, like a proxy cutting a response short
+func truncateResponse(t *testing.T, declared int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n")
+		buf.WriteString("Content-Length: " + strconv.Itoa(declared) + "\r\n\r\n" + body)
+		buf.Flush()
+	}
+}
+
+func TestHTTPScraper_Scrape_ContentLengthMatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+	s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, VerifyContentLength: true})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, int64(15), result.Details["content_length_declared"])
+	assert.Equal(t, int64(15), result.Details["content_length_actual"])
+}
+
+func TestHTTPScraper_Scrape_ContentLengthTruncated(t *testing.T) {
+	server := newTestServer(t, truncateResponse(t, 100, `{"status":"ok"`))
+	s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, VerifyContentLength: true})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, int64(100), result.Details["content_length_declared"])
+	assert.Equal(t, int64(14), result.Details["content_length_actual"])
+	assert.Contains(t, result.Message, "was truncated after 14 of 100 bytes declared by Content-Length")
+	assert.Equal(t, "unexpected EOF", result.Details["error"])
+}
+
+func TestHTTPScraper_Scrape_ContentLengthTruncatedOverridesBodyChecks(t *testing.T) {
+	server := newTestServer(t, truncateResponse(t, 100, `{"status":"ok"`))
+	s := newTestScraper(t, config.HealthcheckScraper{
+		Type:                "http",
+		ScrapeURL:           server.URL,
+		VerifyContentLength: true,
+		HealthExpression:    `body.status == "ok"`,
+	})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "was truncated after 14 of 100 bytes")
+}
+
+func TestHTTPScraper_Scrape_ContentLengthNotDeclared(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		w.Write([]byte("streamed"))
+	}))
+	defer server.Close()
+	s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, VerifyContentLength: true})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.NotContains(t, result.Details, "content_length_declared")
+	assert.Equal(t, int64(8), result.Details["content_length_actual"])
+}
+
+func TestHTTPScraper_Scrape_ContentLengthNotVerifiedByDefault(t *testing.T) {
+	server := newTestServer(t, truncateResponse(t, 100, `{"status":"ok"`))
+	s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.NotContains(t, result.Details, "content_length_actual")
+}
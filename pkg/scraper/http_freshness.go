@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// normalizeETag strips the weak validator prefix and the quotes of an entity tag, so that an
+// expected_etag matches however it was written
+func normalizeETag(etag string) string {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	return strings.Trim(etag, `"`)
+}
+
+// checkFreshness asserts the response content is at most max_age_seconds old according to its
+// Last-Modified header and that its ETag matches expected_etag, recording the observed headers
+// and the content age in details. The message describes stale content.
+func (h *HTTPScraper) checkFreshness(resp *http.Response, details map[string]interface{}, now time.Time) (bool, string) {
+	scrapeURL := h.config.ScrapeURL
+
+	lastModified := resp.Header.Get("Last-Modified")
+	etag := resp.Header.Get("ETag")
+	if lastModified != "" {
+		details["last_modified"] = lastModified
+	}
+	if etag != "" {
+		details["etag"] = etag
+	}
+
+	if h.config.MaxAgeSeconds > 0 {
+		if lastModified == "" {
+			return false, fmt.Sprintf("Response from %s has no Last-Modified header", scrapeURL)
+		}
+		modified, err := http.ParseTime(lastModified)
+		if err != nil {
+			return false, fmt.Sprintf("Invalid Last-Modified header %q from %s", lastModified, scrapeURL)
+		}
+		age := now.Sub(modified)
+		details["content_age_seconds"] = int64(age.Seconds())
+		if maxAge := time.Duration(h.config.MaxAgeSeconds) * time.Second; age > maxAge {
+			return false, fmt.Sprintf("Content of %s is stale: last modified %s ago at %s, expected at most %s", scrapeURL, age.Truncate(time.Second), modified.UTC().Format(time.RFC3339), maxAge)
+		}
+	}
+
+	if h.config.ExpectedETag != "" {
+		if etag == "" {
+			return false, fmt.Sprintf("Response from %s has no ETag header", scrapeURL)
+		}
+		if normalizeETag(etag) != normalizeETag(h.config.ExpectedETag) {
+			return false, fmt.Sprintf("ETag of %s is %s, expected %s", scrapeURL, etag, h.config.ExpectedETag)
+		}
+	}
+
+	return true, ""
+}
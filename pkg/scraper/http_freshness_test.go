@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPScraper_Scrape_Freshness(t *testing.T) {
+	recent := time.Now().Add(-10 * time.Minute).UTC().Format(http.TimeFormat)
+	stale := time.Now().Add(-3 * time.Hour).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		maxAge       int
+		expectedETag string
+		healthy      bool
+		message      string
+	}{
+		{name: "recently modified", headers: map[string]string{"Last-Modified": recent}, maxAge: 3600, healthy: true},
+		{name: "stale content", headers: map[string]string{"Last-Modified": stale}, maxAge: 3600, healthy: false, message: "is stale: last modified 3h0m0s ago"},
+		{name: "missing last modified", maxAge: 3600, healthy: false, message: "has no Last-Modified header"},
+		{name: "invalid last modified", headers: map[string]string{"Last-Modified": "yesterday"}, maxAge: 3600, healthy: false, message: `Invalid Last-Modified header "yesterday"`},
+		{name: "matching etag", headers: map[string]string{"ETag": `"v42"`}, expectedETag: "v42", healthy: true},
+		{name: "matching weak etag", headers: map[string]string{"ETag": `W/"v42"`}, expectedETag: `"v42"`, healthy: true},
+		{name: "mismatched etag", headers: map[string]string{"ETag": `"v41"`}, expectedETag: "v42", healthy: false, message: `ETag of`},
+		{name: "missing etag", expectedETag: "v42", healthy: false, message: "has no ETag header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tt.headers {
+					w.Header().Set(name, value)
+				}
+			}))
+			defer server.Close()
+
+			scraper := NewHTTPScraper(config.HealthcheckScraper{
+				ScrapeURL:     server.URL,
+				MaxAgeSeconds: tt.maxAge,
+				ExpectedETag:  tt.expectedETag,
+			}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			if tt.message != "" {
+				assert.Contains(t, result.Message, tt.message)
+			}
+			if value, ok := tt.headers["Last-Modified"]; ok {
+				assert.Equal(t, value, result.Details["last_modified"])
+			}
+			if value, ok := tt.headers["ETag"]; ok {
+				assert.Equal(t, value, result.Details["etag"])
+			}
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_FreshnessContentAge(t *testing.T) {
+	modified := time.Now().Add(-90 * time.Second).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	}))
+	defer server.Close()
+
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, MaxAgeSeconds: 60}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.InDelta(t, 90, result.Details["content_age_seconds"], 2)
+}
@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxProbeWindow keeps the probes of a scrape within the manager's 30 second scrape timeout,
+// leaving room for the last probe's request
+const maxProbeWindow = 25 * time.Second
+
+// probeWindow returns the window the probes are spread across, probe_window_seconds or half
+// the scrape interval, capped at maxProbeWindow. The interval is scrape_interval when set,
+// which scrape_interval_seconds cannot be combined with.
+func (h *HTTPScraper) probeWindow() time.Duration {
+	window := time.Duration(h.config.ProbeWindowSeconds) * time.Second
+	if window <= 0 {
+		interval := time.Duration(h.scrapeIntervalSeconds) * time.Second
+		// The interval was validated along with the rest of the configuration
+		if parsed, err := time.ParseDuration(h.config.ScrapeInterval); err == nil && parsed > 0 {
+			interval = parsed
+		}
+		window = interval / 2
+	}
+	return min(window, maxProbeWindow)
+}
+
+// scrapeProbes sends the configured number of GET requests to the scrape URL one after
+// another, evenly spread across the probe window, and is healthy if the share of them that
+// returned a 2xx status is at least min_success_ratio, by default all of them
+func (h *HTTPScraper) scrapeProbes(ctx context.Context) (*ScrapeResult, error) {
+	scrapeURL := h.config.ScrapeURL
+	window := h.probeWindow()
+	spacing := window / time.Duration(h.config.Probes)
+	h.logger.WithFields(logrus.Fields{
+		"url":    scrapeURL,
+		"probes": h.config.Probes,
+		"window": window.String(),
+	}).Debug("Starting HTTP probe window healthcheck")
+
+	minRatio := h.config.MinSuccessRatio
+	if minRatio <= 0 {
+		minRatio = 1
+	}
+
+	outcomes := make([]map[string]interface{}, h.config.Probes)
+	successes := 0
+	var maxLatency time.Duration
+	start := time.Now()
+	for i := range outcomes {
+		offset := spacing * time.Duration(i)
+		if wait := time.Until(start.Add(offset)); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+
+		// Probes the scrape was cancelled before count as failures
+		if err := ctx.Err(); err != nil {
+			outcomes[i] = map[string]interface{}{"offset_ms": offset.Milliseconds(), "error": err.Error()}
+			continue
+		}
+
+		probeStart := time.Now()
+		outcome, succeeded := h.probeRequest(ctx)
+		latency := time.Since(probeStart)
+		outcome["offset_ms"] = probeStart.Sub(start).Milliseconds()
+		outcome["latency_ms"] = latency.Milliseconds()
+		outcomes[i] = outcome
+
+		if succeeded {
+			successes++
+		}
+		maxLatency = max(maxLatency, latency)
+	}
+
+	ratio := float64(successes) / float64(h.config.Probes)
+	healthy := ratio >= minRatio
+	details := map[string]interface{}{
+		"probes":            outcomes,
+		"successes":         successes,
+		"success_ratio":     ratio,
+		"min_success_ratio": minRatio,
+		"probe_window_ms":   window.Milliseconds(),
+		"max_latency_ms":    maxLatency.Milliseconds(),
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"url":       scrapeURL,
+		"successes": successes,
+		"probes":    h.config.Probes,
+		"healthy":   healthy,
+	}).Info("HTTP probe window healthcheck completed")
+
+	message := fmt.Sprintf("%d of %d probes of %s over %s succeeded", successes, h.config.Probes, scrapeURL, window)
+	if !healthy {
+		message = fmt.Sprintf("%s, expected a success ratio of at least %g", message, minRatio)
+	}
+
+	return h.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil), nil
+}
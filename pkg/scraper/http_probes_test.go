@@ -0,0 +1,115 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failRequests returns a handler failing the given 1-based requests with a 503
+func failRequests(failing ...int) http.HandlerFunc {
+	var requests atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		for _, f := range failing {
+			if n == f {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func TestHTTPScraper_Scrape_Probes(t *testing.T) {
+	tests := []struct {
+		name      string
+		failing   []int
+		minRatio  float64
+		healthy   bool
+		successes int
+	}{
+		{name: "all succeed", healthy: true, successes: 4},
+		{name: "one fails the worst case", failing: []int{3}, healthy: false, successes: 3},
+		{name: "one fails within ratio", failing: []int{3}, minRatio: 0.75, healthy: true, successes: 3},
+		{name: "two fail below ratio", failing: []int{1, 4}, minRatio: 0.75, healthy: false, successes: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, failRequests(tt.failing...))
+			scraper := NewHTTPScraper(config.HealthcheckScraper{
+				ScrapeURL:          server.URL,
+				Probes:             4,
+				ProbeWindowSeconds: 1,
+				MinSuccessRatio:    tt.minRatio,
+			}, logrus.New())
+
+			start := time.Now()
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.successes, result.Details["successes"])
+			assert.Equal(t, float64(tt.successes)/4, result.Details["success_ratio"])
+			assert.Contains(t, result.Details, "max_latency_ms")
+			// The last probe is sent three quarters into the window
+			assert.GreaterOrEqual(t, time.Since(start), 750*time.Millisecond)
+
+			outcomes := result.Details["probes"].([]map[string]interface{})
+			require.Len(t, outcomes, 4)
+			for i, outcome := range outcomes {
+				assert.Contains(t, outcome, "latency_ms")
+				assert.GreaterOrEqual(t, outcome["offset_ms"], int64(i*250))
+			}
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_ProbesCancelled(t *testing.T) {
+	server := newTestServer(t, failRequests())
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL:          server.URL,
+		Probes:             4,
+		ProbeWindowSeconds: 2,
+		MinSuccessRatio:    0.5,
+	}, logrus.New())
+
+	// The scrape is cancelled after the first two probes
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	result, err := scraper.Scrape(ctx)
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, 2, result.Details["successes"])
+	outcomes := result.Details["probes"].([]map[string]interface{})
+	assert.Equal(t, context.DeadlineExceeded.Error(), outcomes[3]["error"])
+}
+
+func TestHTTPScraper_ProbeWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.HealthcheckScraper
+		expected time.Duration
+	}{
+		{name: "configured window", config: config.HealthcheckScraper{ProbeWindowSeconds: 5}, expected: 5 * time.Second},
+		{name: "half the interval", config: config.HealthcheckScraper{ScrapeIntervalSeconds: 20}, expected: 10 * time.Second},
+		{name: "half the duration interval", config: config.HealthcheckScraper{ScrapeInterval: "2s"}, expected: time.Second},
+		{name: "capped below the scrape timeout", config: config.HealthcheckScraper{ScrapeIntervalSeconds: 300}, expected: maxProbeWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewHTTPScraper(tt.config, logrus.New()).probeWindow())
+		})
+	}
+}
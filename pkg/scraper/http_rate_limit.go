@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minEpochReset tells epoch timestamps from delta seconds in reset headers: GitHub style
+// X-RateLimit-Reset headers carry a Unix time, the IETF RateLimit-Reset header the seconds
+// until the window resets
+const minEpochReset = 1_000_000_000
+
+// rateLimitHeader returns the first integer value of the X-RateLimit- or RateLimit- prefixed
+// header with the given suffix, and whether one was found
+func rateLimitHeader(header http.Header, suffix string) (int64, bool, error) {
+	for _, name := range []string{"X-RateLimit-" + suffix, "RateLimit-" + suffix} {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		// Some APIs list a value per policy, the first one is the one closest to exhaustion
+		value, _, _ = strings.Cut(value, ",")
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s header %q", name, value)
+		}
+		return parsed, true, nil
+	}
+	return 0, false, nil
+}
+
+// checkRateLimit asserts the remaining rate limit quota of the response is at least
+// min_quota_remaining requests or min_quota_percent of the limit, recording the remaining
+// quota, the limit and when it resets in details
+func (h *HTTPScraper) checkRateLimit(resp *http.Response, details map[string]interface{}, now time.Time) (bool, string) {
+	scrapeURL := h.config.ScrapeURL
+
+	remaining, ok, err := rateLimitHeader(resp.Header, "Remaining")
+	if err != nil {
+		return false, fmt.Sprintf("Failed to read rate limit from %s: %v", scrapeURL, err)
+	}
+	if !ok {
+		return false, fmt.Sprintf("Response from %s has no X-RateLimit-Remaining or RateLimit-Remaining header", scrapeURL)
+	}
+	details["rate_limit_remaining"] = remaining
+
+	limit, hasLimit, err := rateLimitHeader(resp.Header, "Limit")
+	if err != nil {
+		return false, fmt.Sprintf("Failed to read rate limit from %s: %v", scrapeURL, err)
+	}
+	if hasLimit {
+		details["rate_limit_limit"] = limit
+	}
+
+	reset, hasReset, err := rateLimitHeader(resp.Header, "Reset")
+	if err != nil {
+		return false, fmt.Sprintf("Failed to read rate limit from %s: %v", scrapeURL, err)
+	}
+	resetSuffix := ""
+	if hasReset {
+		resetAt := now.Add(time.Duration(reset) * time.Second)
+		if reset >= minEpochReset {
+			resetAt = time.Unix(reset, 0)
+		}
+		details["rate_limit_reset"] = resetAt.UTC().Format(time.RFC3339)
+		details["rate_limit_reset_seconds"] = int64(max(resetAt.Sub(now), 0).Seconds())
+		resetSuffix = fmt.Sprintf(", resetting at %s", resetAt.UTC().Format(time.RFC3339))
+	}
+
+	if percent := h.config.MinQuotaPercent; percent > 0 {
+		if !hasLimit || limit <= 0 {
+			return false, fmt.Sprintf("Response from %s has no X-RateLimit-Limit or RateLimit-Limit header to compare the remaining quota with", scrapeURL)
+		}
+		remainingPercent := float64(remaining) / float64(limit) * 100
+		details["rate_limit_remaining_percent"] = remainingPercent
+		if remainingPercent < percent {
+			return false, fmt.Sprintf("Rate limit of %s is near exhaustion: %d of %d requests (%.1f%%) remaining, expected at least %g%%%s", scrapeURL, remaining, limit, remainingPercent, percent, resetSuffix)
+		}
+		return true, fmt.Sprintf("Rate limit of %s has %d of %d requests remaining", scrapeURL, remaining, limit)
+	}
+
+	if minimum := int64(h.config.MinQuotaRemaining); remaining < minimum {
+		return false, fmt.Sprintf("Rate limit of %s is near exhaustion: %d requests remaining, expected at least %d%s", scrapeURL, remaining, minimum, resetSuffix)
+	}
+	return true, fmt.Sprintf("Rate limit of %s has %d requests remaining", scrapeURL, remaining)
+}
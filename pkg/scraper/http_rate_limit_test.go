@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sendHeaders returns a handler answering every request with the headers and an empty body
+func sendHeaders(headers map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range headers {
+			w.Header().Set(name, value)
+		}
+	}
+}
+
+func TestHTTPScraper_Scrape_QuotaRemaining(t *testing.T) {
+	server := newTestServer(t, sendHeaders(map[string]string{
+		"X-RateLimit-Remaining": "420",
+		"X-RateLimit-Limit":     "5000",
+	}))
+	s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, MinQuotaRemaining: 100})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, int64(420), result.Details["rate_limit_remaining"])
+	assert.Equal(t, int64(5000), result.Details["rate_limit_limit"])
+	assert.Contains(t, result.Message, "has 420 requests remaining")
+}
+
+func TestHTTPScraper_Scrape_QuotaNearExhaustion(t *testing.T) {
+	// GitHub style reset as a Unix time
+	reset := time.Now().Add(10 * time.Minute).Unix()
+	server := newTestServer(t, sendHeaders(map[string]string{
+		"X-RateLimit-Remaining": "12",
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Reset":     strconv.FormatInt(reset, 10),
+	}))
+	s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, MinQuotaRemaining: 100})
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "is near exhaustion: 12 requests remaining, expected at least 100, resetting at")
+	assert.Equal(t, time.Unix(reset, 0).UTC().Format(time.RFC3339), result.Details["rate_limit_reset"])
+	assert.InDelta(t, 600, result.Details["rate_limit_reset_seconds"], 2)
+}
+
+func TestHTTPScraper_Scrape_QuotaPercent(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining string
+		healthy   bool
+		message   string
+	}{
+		{name: "above threshold", remaining: "25", healthy: true, message: "has 25 of 100 requests remaining"},
+		{name: "below threshold", remaining: "5", healthy: false, message: "5 of 100 requests (5.0%) remaining, expected at least 10%, resetting at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// IETF style reset in seconds from now
+			server := newTestServer(t, sendHeaders(map[string]string{
+				"RateLimit-Remaining": tt.remaining,
+				"RateLimit-Limit":     "100",
+				"RateLimit-Reset":     "30",
+			}))
+			s := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, MinQuotaPercent: 10})
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+			assert.Equal(t, int64(30), result.Details["rate_limit_reset_seconds"])
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_QuotaHeadersMissing(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		config  config.HealthcheckScraper
+		message string
+	}{
+		{
+			name:    "no remaining header",
+			headers: map[string]string{},
+			config:  config.HealthcheckScraper{MinQuotaRemaining: 10},
+			message: "has no X-RateLimit-Remaining or RateLimit-Remaining header",
+		},
+		{
+			name:    "no limit for percent",
+			headers: map[string]string{"RateLimit-Remaining": "50"},
+			config:  config.HealthcheckScraper{MinQuotaPercent: 10},
+			message: "has no X-RateLimit-Limit or RateLimit-Limit header",
+		},
+		{
+			name:    "invalid value",
+			headers: map[string]string{"X-RateLimit-Remaining": "many"},
+			config:  config.HealthcheckScraper{MinQuotaRemaining: 10},
+			message: `invalid X-RateLimit-Remaining header "many"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, sendHeaders(tt.headers))
+			scraperConfig := tt.config
+			scraperConfig.Type = "http"
+			scraperConfig.ScrapeURL = server.URL
+			s := newTestScraper(t, scraperConfig)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestRateLimitHeader_FirstPolicy(t *testing.T) {
+	header := http.Header{}
+	header.Set("RateLimit-Remaining", "7, 950")
+
+	remaining, ok, err := rateLimitHeader(header, "Remaining")
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), remaining)
+}
+
+func TestFactory_CreateScraper_QuotaPercentAbove100(t *testing.T) {
+	_, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:            "http",
+		ScrapeURL:       "http://localhost",
+		MinQuotaPercent: 120,
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, "min_quota_percent 120 exceeds 100", err.Error())
+}
@@ -0,0 +1,74 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redirectAllowed reports whether a redirect to the host is allowed, which it is for the
+// scrape URL's own host and the hosts of allowed_redirect_hosts. Entries starting with "*."
+// allow any subdomain.
+func (h *HTTPScraper) redirectAllowed(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	if original, err := url.Parse(h.config.ScrapeURL); err == nil && strings.EqualFold(original.Hostname(), host) {
+		return true
+	}
+
+	for _, allowed := range h.config.AllowedRedirectHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok && strings.HasSuffix(host, suffix) {
+			return true
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRedirect follows redirects like the default policy, but stops before a redirect to a
+// host that is not allowed, so its response is returned and judged instead
+func (h *HTTPScraper) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !h.redirectAllowed(req.URL) {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// checkRedirects records the redirects that led to the response in details, and fails the
+// scrape when the last of them pointed to a host that is not allowed
+func (h *HTTPScraper) checkRedirects(resp *http.Response, details map[string]interface{}) (bool, string) {
+	// Walk back from the final request through the responses that redirected to it
+	var chain []string
+	for req := resp.Request; req != nil; {
+		chain = append([]string{req.URL.String()}, chain...)
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+
+	// A redirect to a host that is not allowed was not followed
+	var blocked *url.URL
+	if resp.StatusCode >= 300 && resp.StatusCode <= 399 {
+		if location, err := resp.Location(); err == nil && !h.redirectAllowed(location) {
+			blocked = location
+			chain = append(chain, location.String())
+		}
+	}
+
+	if len(chain) > 1 {
+		details["redirects"] = chain
+		details["final_url"] = chain[len(chain)-1]
+	}
+
+	if blocked != nil {
+		return false, fmt.Sprintf("Redirected from %s to %s, which is not an allowed redirect host", h.config.ScrapeURL, blocked.Host)
+	}
+	return true, ""
+}
@@ -0,0 +1,91 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redirectVia returns a handler redirecting /health to the target via /moved
+func redirectVia(targetURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			http.Redirect(w, r, "/moved", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, targetURL+"/login", http.StatusFound)
+		}
+	}
+}
+
+func TestHTTPScraper_Scrape_AllowedRedirect(t *testing.T) {
+	// The target is reached as localhost and the origin as 127.0.0.1, so they differ in host
+	target := newTestServer(t, respond(http.StatusOK, "", "login page"))
+	targetURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	origin := newTestServer(t, redirectVia(targetURL))
+	scraper := newTestScraper(t, config.HealthcheckScraper{
+		Type:                 "http",
+		ScrapeURL:            origin.URL + "/health",
+		AllowedRedirectHosts: []string{"localhost"},
+	})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	finalURL := targetURL + "/login"
+	assert.Equal(t, []string{origin.URL + "/health", origin.URL + "/moved", finalURL}, result.Details["redirects"])
+	assert.Equal(t, finalURL, result.Details["final_url"])
+}
+
+func TestHTTPScraper_Scrape_DisallowedRedirect(t *testing.T) {
+	// The target is reached as localhost and the origin as 127.0.0.1, so they differ in host
+	target := newTestServer(t, respond(http.StatusOK, "", "login page"))
+	targetURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	origin := newTestServer(t, redirectVia(targetURL))
+	scraper := newTestScraper(t, config.HealthcheckScraper{
+		Type:                 "http",
+		ScrapeURL:            origin.URL + "/health",
+		AllowedRedirectHosts: []string{"auth.example.com"},
+	})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	targetHost := strings.Replace(target.Listener.Addr().String(), "127.0.0.1", "localhost", 1)
+	assert.Contains(t, result.Message, "to "+targetHost+", which is not an allowed redirect host")
+	assert.Equal(t, http.StatusFound, result.Details["status_code"])
+	// The redirect on the same host was followed, the one to the other host was not
+	require.Len(t, result.Details["redirects"], 3)
+}
+
+func TestHTTPScraper_RedirectAllowed(t *testing.T) {
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL:            "http://api.internal:8080/health",
+		AllowedRedirectHosts: []string{"sso.example.com", "*.cdn.example.com"},
+	}, logrus.New())
+
+	tests := map[string]bool{
+		"https://api.internal/health":          true,
+		"https://sso.example.com/login":        true,
+		"https://SSO.example.com/login":        true,
+		"https://eu.cdn.example.com/health":    true,
+		"https://cdn.example.com.evil.io/":     false,
+		"http://captive.portal.local/":         false,
+		"https://example.com/sso.example.com/": false,
+	}
+	for target, allowed := range tests {
+		u, err := url.Parse(target)
+		require.NoError(t, err)
+		assert.Equal(t, allowed, scraper.redirectAllowed(u), target)
+	}
+}
@@ -0,0 +1,657 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPScraper(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		Type:                  "http",
+		ScrapeURL:             "http://localhost:8080/health",
+		PingURL:               "http://localhost:8081/ping",
+		ScrapeIntervalSeconds: 45,
+	}, logger)
+
+	assert.Equal(t, "http", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 45, scraper.GetScrapeInterval())
+	assert.NotNil(t, scraper.client)
+}
+
+func TestNewHTTPScraper_DefaultInterval(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: "http://localhost:8080/health"}, logger)
+
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestHTTPScraper_Scrape_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL}, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, 204, result.Details["status_code"])
+}
+
+func TestHTTPScraper_Scrape_Non2xxStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL}, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "HTTP status 503")
+}
+
+func TestHTTPScraper_Scrape_ConnectionError(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: "http://localhost:99999/health"}, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to connect to")
+}
+
+func TestHTTPScraper_Scrape_ReadFirstLine_Stream(t *testing.T) {
+	// Create a test server that streams events and never finishes the body
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(": keep-alive\n\ndata: {\"status\":\"ok\"}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, ReadFirstLine: true}, logger)
+
+	start := time.Now()
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, `{"status":"ok"}`, result.Details["first_line"])
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestHTTPScraper_Scrape_ReadFirstLine_Timeout(t *testing.T) {
+	// Create a test server that sends headers but never sends a line
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, ReadFirstLine: true}, logger)
+	scraper.firstLineTimeout = 100 * time.Millisecond
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to read first line")
+}
+
+func TestHTTPScraper_Scrape_ReadFirstLine_EmptyBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, ReadFirstLine: true}, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "Empty response from "+server.URL, result.Message)
+	assert.Equal(t, "parse", result.Details["error_type"])
+	assert.Contains(t, result.Details, "total_ms")
+}
+
+func TestHTTPScraper_Scrape_ReadFirstLine_HealthExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		stream     string
+		expression string
+		healthy    bool
+		firstLine  string
+	}{
+		{
+			name:       "healthy event",
+			stream:     "event: status\nid: 1\ndata: {\"status\":\"ok\"}\n\n",
+			expression: `body.status == "ok"`,
+			healthy:    true,
+			firstLine:  `{"status":"ok"}`,
+		},
+		{
+			name:       "unhealthy first event",
+			stream:     ": keep-alive\n\nevent: status\nid: 1\nretry: 1000\ndata: {\"status\":\"down\"}\n\ndata: {\"status\":\"ok\"}\n\n",
+			expression: `body.status == "ok"`,
+			healthy:    false,
+			firstLine:  `{"status":"down"}`,
+		},
+		{
+			name:       "plain text line",
+			stream:     "DEGRADED\nOK\n",
+			expression: `body == "OK"`,
+			healthy:    false,
+			firstLine:  "DEGRADED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.stream))
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+			}))
+			defer server.Close()
+
+			scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:             "http",
+				ScrapeURL:        server.URL,
+				ReadFirstLine:    true,
+				HealthExpression: tt.expression,
+			})
+			require.NoError(t, err)
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.firstLine, result.Details["first_line"])
+			assert.Equal(t, tt.healthy, result.Details["expression_result"])
+		})
+	}
+}
+
+// awaitBurst returns a handler that waits for the whole burst to arrive before answering,
+// failing the given number of requests with a 503
+func awaitBurst(burst, failures int) http.HandlerFunc {
+	var mu sync.Mutex
+	arrived := 0
+	all := make(chan struct{})
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrived++
+		n := arrived
+		if n == burst {
+			close(all)
+		}
+		mu.Unlock()
+
+		select {
+		case <-all:
+		case <-time.After(time.Second):
+			w.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func TestHTTPScraper_Scrape_Burst(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		quorum    int
+		healthy   bool
+		successes int
+	}{
+		{name: "all succeed", failures: 0, healthy: true, successes: 4},
+		{name: "one fails without quorum", failures: 1, healthy: false, successes: 3},
+		{name: "one fails within quorum", failures: 1, quorum: 3, healthy: true, successes: 3},
+		{name: "two fail below quorum", failures: 2, quorum: 3, healthy: false, successes: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, awaitBurst(4, tt.failures))
+			scraper := NewHTTPScraper(config.HealthcheckScraper{
+				ScrapeURL:   server.URL,
+				Burst:       4,
+				BurstQuorum: tt.quorum,
+			}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.successes, result.Details["successes"])
+			assert.Contains(t, result.Details, "max_latency_ms")
+
+			outcomes := result.Details["requests"].([]map[string]interface{})
+			require.Len(t, outcomes, 4)
+			for _, outcome := range outcomes {
+				assert.Contains(t, outcome, "latency_ms")
+				assert.NotEqual(t, http.StatusRequestTimeout, outcome["status_code"], "requests should be concurrent")
+			}
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_BurstConnectionErrors(t *testing.T) {
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL: "http://localhost:1",
+		Burst:     3,
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, 0, result.Details["successes"])
+	for _, outcome := range result.Details["requests"].([]map[string]interface{}) {
+		assert.Contains(t, outcome, "error")
+	}
+}
+
+func TestHTTPScraper_Scrape_Timings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	require.Contains(t, result.Details, "ttfb_ms")
+	assert.GreaterOrEqual(t, result.Details["total_ms"], int64(50))
+	assert.Less(t, result.Details["ttfb_ms"], result.Details["total_ms"])
+}
+
+func TestHTTPScraper_Scrape_MaxTTFB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	slow := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, MaxTTFBMs: 10}, logrus.New())
+
+	result, err := slow.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "expected at most 10ms")
+
+	relaxed := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, MaxTTFBMs: 5000}, logrus.New())
+
+	result, err = relaxed.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+}
+
+func TestHTTPScraper_Scrape_RequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, RequestTimeoutMs: 50}, logrus.New())
+
+	// The request times out on its own while the scrape's context is still live
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	result, err := scraper.Scrape(ctx)
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "timed out after 50ms")
+	assert.Equal(t, 50, result.Details["request_timeout_ms"])
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+	assert.NoError(t, ctx.Err())
+}
+
+func TestHTTPScraper_Scrape_RequestTimeoutWithinScrapeTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, RequestTimeoutMs: 5000}, logrus.New())
+
+	// The shorter scrape context still bounds a request with a longer timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := scraper.Scrape(ctx)
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to connect")
+	assert.NotContains(t, result.Details, "request_timeout_ms")
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestHTTPScraper_Scrape_RequestTimeoutBurst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL:        server.URL,
+		Burst:            3,
+		RequestTimeoutMs: 50,
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	for _, outcome := range result.Details["requests"].([]map[string]interface{}) {
+		assert.Equal(t, 50, outcome["request_timeout_ms"])
+	}
+}
+
+func TestHTTPScraper_Scrape_Trailer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("body"))
+		w.Header().Set("Grpc-Status", r.URL.Query().Get("status"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		status   string
+		expected string
+		healthy  bool
+	}{
+		{name: "expected value", status: "0", expected: "0", healthy: true},
+		{name: "unexpected value", status: "14", expected: "0", healthy: false},
+		{name: "any value", status: "14", healthy: true},
+		{name: "missing", status: "", expected: "0", healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewHTTPScraper(config.HealthcheckScraper{
+				ScrapeURL:            server.URL + "?status=" + tt.status,
+				TrailerKey:           "grpc-status",
+				ExpectedTrailerValue: tt.expected,
+			}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			if tt.status != "" {
+				assert.Equal(t, tt.status, result.Details["trailer"])
+			}
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_TrailerHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trailers", r.Header.Get("TE"))
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("body"))
+		// Simulates a proxy stripping the status trailer
+		if r.URL.Query().Get("strip") == "" {
+			w.Header().Set("Grpc-Status", "0")
+		}
+		w.Header().Set("Grpc-Message", "ok")
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		healthy  bool
+		trailers map[string]string
+	}{
+		{name: "delivered", healthy: true, trailers: map[string]string{"Grpc-Status": "0", "Grpc-Message": "ok"}},
+		{name: "stripped", query: "?strip=1", healthy: false, trailers: map[string]string{"Grpc-Message": "ok"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewHTTPScraper(config.HealthcheckScraper{
+				ScrapeURL:            server.URL + tt.query,
+				TrailerKey:           "grpc-status",
+				ExpectedTrailerValue: "0",
+			}, logrus.New())
+			scraper.client = server.Client()
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, "HTTP/2.0", result.Details["protocol"])
+			assert.Equal(t, tt.trailers, result.Details["trailers"])
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_InformationalResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "</style.css>; rel=preload")
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, 200, result.Details["status_code"])
+	assert.Equal(t, []int{103}, result.Details["informational_status_codes"])
+}
+
+func TestHTTPScraper_Scrape_HealthExpression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"degraded","checks":{"db":"up","cache":"down"}}`))
+	}))
+	defer server.Close()
+
+	factory := NewFactory(logrus.New())
+	s, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:              "http",
+		ScrapeURL:         server.URL,
+		HealthExpression:  `body.status == "ok" || body.checks.db == "up"`,
+		MessageExpression: `"status " + body.status + ", cache " + body.checks.cache`,
+	})
+	require.NoError(t, err)
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, "status degraded, cache down", result.Message)
+	assert.Equal(t, true, result.Details["expression_result"])
+}
+
+func TestHTTPScraper_Scrape_HealthExpressionFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		health     string
+		message    string
+		healthy    bool
+		wantResult string
+	}{
+		{name: "false", health: `body.status != "ok"`, wantResult: "health_expression is false"},
+		{name: "missing field", health: `body.checks.db == "up"`, wantResult: `Failed to evaluate health_expression`},
+		{name: "not a boolean", health: `body.status`, wantResult: "health_expression returned a string"},
+		{name: "broken message", health: `true`, message: `body.reason`, healthy: true, wantResult: "health_expression is true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:              "http",
+				ScrapeURL:         server.URL,
+				HealthExpression:  tt.health,
+				MessageExpression: tt.message,
+			})
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy)
+			assert.Contains(t, result.Message, tt.wantResult)
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_EmptyResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.HealthcheckScraper
+		body    string
+		healthy bool
+	}{
+		{name: "status only", body: "", healthy: true},
+		{name: "fail on empty", config: config.HealthcheckScraper{FailOnEmptyResponse: true}, body: "  \n", healthy: false},
+		{name: "fail on empty with body", config: config.HealthcheckScraper{FailOnEmptyResponse: true}, body: "OK", healthy: true},
+		{name: "json path", config: config.HealthcheckScraper{JSONPath: "nodes"}, body: "", healthy: false},
+		{name: "health expression", config: config.HealthcheckScraper{HealthExpression: "status == 200"}, body: "\t", healthy: false},
+		{name: "read first line", config: config.HealthcheckScraper{ReadFirstLine: true}, body: " \n\n", healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			tt.config.Type = "http"
+			tt.config.ScrapeURL = server.URL
+			s, err := NewFactory(logrus.New()).CreateScraper(tt.config)
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			if tt.healthy {
+				assert.NotContains(t, result.Details, "error_type")
+				return
+			}
+			assert.Equal(t, "Empty response from "+server.URL, result.Message)
+			assert.Equal(t, "parse", result.Details["error_type"])
+		})
+	}
+}
+
+// unreadable fails the test when it is read
+type unreadable struct {
+	t *testing.T
+}
+
+func (u unreadable) Read(p []byte) (int, error) {
+	u.t.Error("body was read past the first byte that is not whitespace")
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestHTTPScraper_CheckNotEmpty_StopsAtContent(t *testing.T) {
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: "http://localhost:8080/health", FailOnEmptyResponse: true}, logrus.New())
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(io.MultiReader(strings.NewReader(" \n{"), unreadable{t: t})),
+	}
+
+	ok, _ := scraper.checkNotEmpty(resp, map[string]interface{}{})
+
+	assert.True(t, ok)
+}
+
+func TestHTTPScraper_Scrape_HealthExpressionInvalidJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+
+	s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:             "http",
+		ScrapeURL:        server.URL,
+		HealthExpression: `status == 200`,
+	})
+	require.NoError(t, err)
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to parse response")
+}
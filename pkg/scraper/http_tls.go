@@ -0,0 +1,41 @@
+package scraper
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// defaultMinTLSVersion is the lowest TLS version scrapes accept when min_tls_version is unset
+const defaultMinTLSVersion = tls.VersionTLS12
+
+// tlsVersions maps the accepted min_tls_version values to their protocol versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parses a min_tls_version such as 1.2, returning defaultMinTLSVersion when it
+// is unset
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return defaultMinTLSVersion, nil
+	}
+	parsed, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("invalid min_tls_version %q, expected 1.0, 1.1, 1.2 or 1.3", version)
+	}
+	return parsed, nil
+}
+
+// recordTLS records the negotiated TLS version and cipher suite of an HTTPS response in the
+// result's details
+func recordTLS(details map[string]interface{}, resp *http.Response) {
+	if resp.TLS == nil {
+		return
+	}
+	details["tls_version"] = tls.VersionName(resp.TLS.Version)
+	details["tls_cipher_suite"] = tls.CipherSuiteName(resp.TLS.CipherSuite)
+}
@@ -0,0 +1,139 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSVersionServer starts a TLS server offering TLS 1.0 up to the given version and returns
+// it with its certificate in PEM
+func newTLSVersionServer(t *testing.T, maxVersion uint16) (*httptest.Server, string) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: maxVersion}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, string(certPEM)
+}
+
+func TestHTTPScraper_Scrape_MinTLSVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxVersion    uint16
+		minTLSVersion string
+		healthy       bool
+		version       string
+	}{
+		{name: "TLS 1.3 with default minimum", maxVersion: tls.VersionTLS13, healthy: true, version: "TLS 1.3"},
+		{name: "TLS 1.2 with default minimum", maxVersion: tls.VersionTLS12, healthy: true, version: "TLS 1.2"},
+		{name: "TLS 1.1 below default minimum", maxVersion: tls.VersionTLS11, healthy: false},
+		{name: "TLS 1.1 with lowered minimum", maxVersion: tls.VersionTLS11, minTLSVersion: "1.1", healthy: true, version: "TLS 1.1"},
+		{name: "TLS 1.3 meets minimum", maxVersion: tls.VersionTLS13, minTLSVersion: "1.3", healthy: true, version: "TLS 1.3"},
+		{name: "TLS 1.2 below minimum", maxVersion: tls.VersionTLS12, minTLSVersion: "1.3", healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, certPEM := newTLSVersionServer(t, tt.maxVersion)
+			s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:          "http",
+				ScrapeURL:     server.URL,
+				CACertPEM:     certPEM,
+				MinTLSVersion: tt.minTLSVersion,
+			})
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			if !tt.healthy {
+				// The handshake fails before a response could report a version
+				assert.Contains(t, result.Message, "protocol version")
+				assert.NotContains(t, result.Details, "tls_version")
+				return
+			}
+			assert.Equal(t, tt.version, result.Details["tls_version"])
+			assert.NotEmpty(t, result.Details["tls_cipher_suite"])
+		})
+	}
+}
+
+func TestTLSScraper_Scrape_MinTLSVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxVersion    uint16
+		minTLSVersion string
+		healthy       bool
+	}{
+		{name: "TLS 1.2 with default minimum", maxVersion: tls.VersionTLS12, healthy: true},
+		{name: "TLS 1.1 below default minimum", maxVersion: tls.VersionTLS11, healthy: false},
+		{name: "TLS 1.2 below minimum", maxVersion: tls.VersionTLS12, minTLSVersion: "1.3", healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, certPEM := newTLSVersionServer(t, tt.maxVersion)
+			s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:          "tls",
+				ScrapeURL:     server.URL,
+				CACertPEM:     certPEM,
+				MinTLSVersion: tt.minTLSVersion,
+			})
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			if tt.healthy {
+				assert.Equal(t, "TLS 1.2", result.Details["tls_version"])
+				assert.Contains(t, result.Message, "succeeded")
+				return
+			}
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, "TLS handshake with")
+			assert.Contains(t, result.Message, "protocol version")
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_PlainHTTPHasNoTLSDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:          "http",
+		ScrapeURL:     server.URL,
+		MinTLSVersion: "1.3",
+	})
+	require.NoError(t, err)
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.NotContains(t, result.Details, "tls_version")
+	assert.NotContains(t, result.Details, "tls_cipher_suite")
+}
+
+func TestFactory_CreateScraper_InvalidMinTLSVersion(t *testing.T) {
+	for _, scraperType := range []string{"http", "tls"} {
+		_, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+			Type:          scraperType,
+			ScrapeURL:     "https://localhost:8443/health",
+			MinTLSVersion: "TLS1.2",
+		})
+
+		assert.EqualError(t, err, `invalid min_tls_version "TLS1.2", expected 1.0, 1.1, 1.2 or 1.3`, scraperType)
+	}
+}
@@ -0,0 +1,43 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// unixScheme prefixes scrape URLs of HTTP endpoints served on a Unix socket, such as
+// unix:///var/run/docker.sock:/_ping
+const unixScheme = "unix://"
+
+// unixRequestHost is the host of requests sent over a Unix socket. The socket decides where
+// the request goes, so the host only fills the Host header.
+const unixRequestHost = "localhost"
+
+// parseUnixScrapeURL splits a unix:// scrape URL into the socket path and the URL of the
+// request sent over it. ok is false for other URLs.
+func parseUnixScrapeURL(scrapeURL string) (socketPath, requestURL string, ok bool, err error) {
+	rest, ok := strings.CutPrefix(scrapeURL, unixScheme)
+	if !ok {
+		return "", "", false, nil
+	}
+
+	socketPath, path, _ := strings.Cut(rest, ":")
+	if !strings.HasPrefix(socketPath, "/") || (path != "" && !strings.HasPrefix(path, "/")) {
+		return "", "", true, fmt.Errorf("invalid scrape_url %q, expected unix:///path/to/socket:/path", scrapeURL)
+	}
+	if path == "" {
+		path = "/"
+	}
+	return socketPath, "http://" + unixRequestHost + path, true, nil
+}
+
+// decorate adds the details shared by HTTP based scrapers to a result, and the socket path
+// when the scrape URL is a Unix socket
+func (h *HTTPScraper) decorate(result *ScrapeResult, resp *http.Response) *ScrapeResult {
+	result = h.httpOptions.decorate(result, resp)
+	if h.socketPath != "" {
+		result.Details["socket_path"] = h.socketPath
+	}
+	return result
+}
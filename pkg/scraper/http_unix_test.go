@@ -0,0 +1,164 @@
+package scraper
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUnixSocketServer starts an HTTP server on a Unix socket and returns the socket path
+func newUnixSocketServer(t *testing.T, handler http.Handler) string {
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return socketPath
+}
+
+func TestHTTPScraper_Scrape_UnixSocket(t *testing.T) {
+	socketPath := newUnixSocketServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_ping" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	tests := []struct {
+		name    string
+		path    string
+		healthy bool
+	}{
+		{name: "health path", path: ":/_ping", healthy: true},
+		{name: "unknown path", path: ":/missing", healthy: false},
+		{name: "root path", path: "", healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scrapeURL := "unix://" + socketPath + tt.path
+			s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:      "http",
+				ScrapeURL: scrapeURL,
+			})
+			require.NoError(t, err)
+
+			result, err := s.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, socketPath, result.Details["socket_path"])
+			assert.Contains(t, result.Message, scrapeURL)
+		})
+	}
+}
+
+func TestHTTPScraper_Scrape_UnixSocketBurst(t *testing.T) {
+	socketPath := newUnixSocketServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:      "http",
+		ScrapeURL: "unix://" + socketPath + ":/health",
+		Burst:     3,
+	})
+	require.NoError(t, err)
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, 3, result.Details["successes"])
+}
+
+func TestHTTPScraper_Scrape_UnixSocketScrapeCache(t *testing.T) {
+	healthy := newUnixSocketServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	failing := newUnixSocketServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	var results []*ScrapeResult
+	for _, socketPath := range []string{healthy, failing} {
+		s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+			Type:              "http",
+			ScrapeURL:         "unix://" + socketPath + ":/health",
+			EnableScrapeCache: true,
+		})
+		require.NoError(t, err)
+
+		result, err := s.Scrape(context.Background())
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	assert.True(t, results[0].Healthy, results[0].Message)
+	assert.False(t, results[1].Healthy, "the second socket must not be answered from the first socket's cached response")
+}
+
+func TestHTTPScraper_Scrape_UnixSocketMissing(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "missing.sock")
+	s, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:      "http",
+		ScrapeURL: "unix://" + socketPath + ":/health",
+	})
+	require.NoError(t, err)
+
+	result, err := s.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "no such file or directory")
+	assert.Equal(t, socketPath, result.Details["socket_path"])
+}
+
+func TestParseUnixScrapeURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		scrapeURL  string
+		socketPath string
+		requestURL string
+		unix       bool
+		err        string
+	}{
+		{name: "socket and path", scrapeURL: "unix:///var/run/docker.sock:/_ping", socketPath: "/var/run/docker.sock", requestURL: "http://localhost/_ping", unix: true},
+		{name: "path with query", scrapeURL: "unix:///run/app.sock:/health?verbose=1", socketPath: "/run/app.sock", requestURL: "http://localhost/health?verbose=1", unix: true},
+		{name: "socket only", scrapeURL: "unix:///run/app.sock", socketPath: "/run/app.sock", requestURL: "http://localhost/", unix: true},
+		{name: "relative socket", scrapeURL: "unix://app.sock:/health", unix: true, err: `invalid scrape_url "unix://app.sock:/health"`},
+		{name: "relative path", scrapeURL: "unix:///run/app.sock:health", unix: true, err: "expected unix:///path/to/socket:/path"},
+		{name: "http URL", scrapeURL: "http://localhost:8080/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			socketPath, requestURL, unix, err := parseUnixScrapeURL(tt.scrapeURL)
+
+			assert.Equal(t, tt.unix, unix)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.socketPath, socketPath)
+			assert.Equal(t, tt.requestURL, requestURL)
+		})
+	}
+}
+
+func TestFactory_CreateScraper_InvalidUnixScrapeURL(t *testing.T) {
+	_, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:      "http",
+		ScrapeURL: "unix://docker.sock:/_ping",
+	})
+
+	assert.ErrorContains(t, err, "expected unix:///path/to/socket:/path")
+}
@@ -0,0 +1,23 @@
+package scraper
+
+import (
+	"context"
+	"time"
+)
+
+// warmup sends a throwaway request to the scrape URL before the timed one, so that the
+// timed request reuses the connection and measures the steady-state latency rather than the
+// connection and TLS setup after an idle period. A failed warmup is only recorded, the timed
+// request decides the health. The returned details are added to those of the timed request.
+func (h *HTTPScraper) warmup(ctx context.Context) map[string]interface{} {
+	start := time.Now()
+	outcome, _ := h.probeRequest(ctx)
+	details := map[string]interface{}{
+		"warmup":    true,
+		"warmup_ms": time.Since(start).Milliseconds(),
+	}
+	if err, ok := outcome["error"]; ok {
+		details["warmup_error"] = err
+	}
+	return details
+}
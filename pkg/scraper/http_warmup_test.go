@@ -0,0 +1,81 @@
+package scraper
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWarmupTestServer counts the requests and connections it serves, hijacking and closing
+// the connection of the first request when failFirst is set
+func newWarmupTestServer(t *testing.T, failFirst bool) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	var requests, conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 && failFirst {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &requests, &conns
+}
+
+func TestHTTPScraper_Scrape_Warmup(t *testing.T) {
+	server, requests, conns := newWarmupTestServer(t, false)
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, Warmup: true}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), conns.Load())
+	assert.Equal(t, true, result.Details["warmup"])
+	assert.Contains(t, result.Details, "warmup_ms")
+	assert.NotContains(t, result.Details, "warmup_error")
+	assert.Equal(t, true, result.Details["connection_reused"])
+}
+
+func TestHTTPScraper_Scrape_WarmupFailure(t *testing.T) {
+	server, requests, _ := newWarmupTestServer(t, true)
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL, Warmup: true}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, true, result.Details["warmup"])
+	assert.Contains(t, result.Details, "warmup_error")
+	assert.Equal(t, false, result.Details["connection_reused"])
+}
+
+func TestHTTPScraper_Scrape_WithoutWarmup(t *testing.T) {
+	server, requests, _ := newWarmupTestServer(t, false)
+	scraper := NewHTTPScraper(config.HealthcheckScraper{ScrapeURL: server.URL}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.NotContains(t, result.Details, "warmup")
+	assert.NotContains(t, result.Details, "connection_reused")
+}
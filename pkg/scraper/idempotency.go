@@ -0,0 +1,308 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultRepeatDelay is the delay between the two requests when repeat_delay_ms is not set
+const defaultRepeatDelay = time.Second
+
+// maxReportedDifferences caps the differing paths listed in the details
+const maxReportedDifferences = 10
+
+// IdempotencyScraper implements the Scraper interface for checking an endpoint returns the
+// same response to repeated requests
+type IdempotencyScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	repeatDelay           time.Duration
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewIdempotencyScraper creates a new idempotency scraper
+func NewIdempotencyScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *IdempotencyScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	repeatDelay := defaultRepeatDelay
+	if scraperConfig.RepeatDelayMs > 0 {
+		repeatDelay = time.Duration(scraperConfig.RepeatDelayMs) * time.Millisecond
+	}
+
+	return &IdempotencyScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		repeatDelay:           repeatDelay,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Type returns the scraper type identifier
+func (i *IdempotencyScraper) Type() string {
+	return "idempotency"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (i *IdempotencyScraper) GetPingURL() string {
+	return i.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (i *IdempotencyScraper) GetScrapeInterval() int {
+	return i.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (i *IdempotencyScraper) Close() error {
+	i.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape requests the scrape URL twice, repeat_delay_ms apart, and is healthy when both
+// responses succeed with the same body once the ignored fields are removed
+func (i *IdempotencyScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	scrapeURL := i.config.ScrapeURL
+	i.logger.WithField("url", scrapeURL).Debug("Starting idempotency healthcheck")
+
+	first, resp, err := i.fetch(ctx)
+	if err != nil {
+		return i.failed(err, resp), nil
+	}
+
+	select {
+	case <-time.After(i.repeatDelay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	second, resp, err := i.fetch(ctx)
+	if err != nil {
+		return i.failed(err, resp), nil
+	}
+
+	details := map[string]interface{}{
+		"repeat_delay_ms": i.repeatDelay.Milliseconds(),
+		"first_sha256":    bodyDigest(first),
+		"second_sha256":   bodyDigest(second),
+	}
+
+	differences, compared := i.compare(first, second)
+	details["compared_as"] = compared
+	healthy := len(differences) == 0
+
+	i.logger.WithFields(logrus.Fields{
+		"url":         scrapeURL,
+		"differences": len(differences),
+		"healthy":     healthy,
+	}).Info("Idempotency healthcheck completed")
+
+	message := fmt.Sprintf("Repeated responses from %s are identical", scrapeURL)
+	if !healthy {
+		details["difference_count"] = len(differences)
+		if len(differences) > maxReportedDifferences {
+			differences = differences[:maxReportedDifferences]
+		}
+		details["differences"] = differences
+		message = fmt.Sprintf("Repeated responses from %s differ: %s", scrapeURL, strings.Join(differences, "; "))
+	}
+
+	return i.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
+
+// fetch requests the scrape URL and returns the body of a successful response. The response
+// is returned with the error when one was received.
+func (i *IdempotencyScraper) fetch(ctx context.Context) ([]byte, *http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", i.config.ScrapeURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, resp, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp, nil
+}
+
+// failed builds the unhealthy result of a request that did not return a body to compare
+func (i *IdempotencyScraper) failed(err error, resp *http.Response) *ScrapeResult {
+	details := map[string]interface{}{
+		"error": err.Error(),
+	}
+	if resp != nil {
+		setStatusCode(details, resp.StatusCode)
+	}
+	return i.decorate(&ScrapeResult{
+		Healthy:   false,
+		Message:   fmt.Sprintf("Request to %s failed: %v", i.config.ScrapeURL, err),
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp)
+}
+
+// compare returns a summary of the differences between the bodies and whether they were
+// compared as JSON, with the ignored fields removed, or byte for byte
+func (i *IdempotencyScraper) compare(first, second []byte) ([]string, string) {
+	var firstDoc, secondDoc interface{}
+	if json.Unmarshal(first, &firstDoc) == nil && json.Unmarshal(second, &secondDoc) == nil {
+		for _, field := range i.config.IgnoreFields {
+			firstDoc = removeJSONPath(firstDoc, field)
+			secondDoc = removeJSONPath(secondDoc, field)
+		}
+		var differences []string
+		diffJSON("$", firstDoc, secondDoc, &differences)
+		sort.Strings(differences)
+		return differences, "json"
+	}
+
+	if bytes.Equal(first, second) {
+		return nil, "bytes"
+	}
+	offset := 0
+	for offset < len(first) && offset < len(second) && first[offset] == second[offset] {
+		offset++
+	}
+	return []string{fmt.Sprintf("bodies of %d and %d bytes differ from byte %d", len(first), len(second), offset)}, "bytes"
+}
+
+// diffJSON appends the paths at which the decoded JSON documents differ
+func diffJSON(path string, first, second interface{}, differences *[]string) {
+	switch a := first.(type) {
+	case map[string]interface{}:
+		b, ok := second.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, value := range a {
+			other, found := b[key]
+			if !found {
+				*differences = append(*differences, fmt.Sprintf("%s.%s removed", path, key))
+				continue
+			}
+			diffJSON(path+"."+key, value, other, differences)
+		}
+		for key := range b {
+			if _, found := a[key]; !found {
+				*differences = append(*differences, fmt.Sprintf("%s.%s added", path, key))
+			}
+		}
+		return
+	case []interface{}:
+		b, ok := second.([]interface{})
+		if !ok {
+			break
+		}
+		if len(a) != len(b) {
+			*differences = append(*differences, fmt.Sprintf("%s length changed from %d to %d", path, len(a), len(b)))
+			return
+		}
+		for index := range a {
+			diffJSON(path+"."+strconv.Itoa(index), a[index], b[index], differences)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(first, second) {
+		*differences = append(*differences, fmt.Sprintf("%s changed from %s to %s", path, compactJSON(first), compactJSON(second)))
+	}
+}
+
+// removeJSONPath removes the value at the dot separated path from the decoded JSON document,
+// where a "*" segment matches every key or array element
+func removeJSONPath(doc interface{}, path string) interface{} {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc
+	}
+	removeSegments(doc, strings.Split(path, "."))
+	return doc
+}
+
+// removeSegments removes the value at the remaining path segments below the value
+func removeSegments(value interface{}, segments []string) {
+	segment, last := segments[0], len(segments) == 1
+
+	switch container := value.(type) {
+	case map[string]interface{}:
+		for key, child := range container {
+			if segment != "*" && segment != key {
+				continue
+			}
+			if last {
+				delete(container, key)
+			} else {
+				removeSegments(child, segments[1:])
+			}
+		}
+	case []interface{}:
+		for index, child := range container {
+			if segment != "*" && segment != strconv.Itoa(index) {
+				continue
+			}
+			if last {
+				// Array elements are blanked rather than removed so the indices stay stable
+				container[index] = nil
+			} else {
+				removeSegments(child, segments[1:])
+			}
+		}
+	}
+}
+
+// compactJSON renders a decoded JSON value for a difference summary, truncated to stay readable
+func compactJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(encoded) > 64 {
+		return string(encoded[:61]) + "..."
+	}
+	return string(encoded)
+}
+
+// bodyDigest returns the hex encoded SHA-256 of a response body
+func bodyDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,158 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answerInTurn returns a handler answering the n-th request with the n-th body, repeating the
+// last one afterwards
+func answerInTurn(bodies ...string) http.HandlerFunc {
+	var requests atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1)) - 1
+		if n >= len(bodies) {
+			n = len(bodies) - 1
+		}
+		w.Write([]byte(bodies[n]))
+	}
+}
+
+func TestNewIdempotencyScraper(t *testing.T) {
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL: "http://localhost:8080/catalog",
+		PingURL:   "http://localhost:8081/ping",
+	}, logrus.New())
+
+	assert.Equal(t, "idempotency", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+	assert.Equal(t, defaultRepeatDelay, scraper.repeatDelay)
+}
+
+func TestIdempotencyScraper_Scrape_Identical(t *testing.T) {
+	server := newTestServer(t, answerInTurn("plain text catalog"))
+
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL:     server.URL,
+		RepeatDelayMs: 1,
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, "bytes", result.Details["compared_as"])
+	assert.Equal(t, result.Details["first_sha256"], result.Details["second_sha256"])
+}
+
+func TestIdempotencyScraper_Scrape_BodiesDiffer(t *testing.T) {
+	server := newTestServer(t, answerInTurn("price: 10", "price: 12"))
+
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL:     server.URL,
+		RepeatDelayMs: 1,
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, []string{"bodies of 9 and 9 bytes differ from byte 8"}, result.Details["differences"])
+	assert.NotEqual(t, result.Details["first_sha256"], result.Details["second_sha256"])
+}
+
+func TestIdempotencyScraper_Scrape_JSONDifferences(t *testing.T) {
+	server := newTestServer(t, answerInTurn(
+		`{"items":[{"id":1,"price":10}],"currency":"EUR","served_at":"10:00:00"}`,
+		`{"items":[{"id":1,"price":12}],"region":"eu","served_at":"10:00:01"}`,
+	))
+
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL:     server.URL,
+		RepeatDelayMs: 1,
+		IgnoreFields:  []string{"served_at"},
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "json", result.Details["compared_as"])
+	assert.Equal(t, 3, result.Details["difference_count"])
+	assert.Equal(t, []string{
+		"$.currency removed",
+		"$.items.0.price changed from 10 to 12",
+		"$.region added",
+	}, result.Details["differences"])
+	assert.Contains(t, result.Message, "$.items.0.price changed from 10 to 12")
+}
+
+func TestIdempotencyScraper_Scrape_IgnoresVolatileFields(t *testing.T) {
+	server := newTestServer(t, answerInTurn(
+		`{"items":[{"id":1,"fetched_at":1}],"request_id":"a"}`,
+		`{"request_id":"b","items":[{"fetched_at":2,"id":1}]}`,
+	))
+
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL:     server.URL,
+		RepeatDelayMs: 1,
+		IgnoreFields:  []string{"request_id", "$.items.*.fetched_at"},
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, "json", result.Details["compared_as"])
+}
+
+func TestIdempotencyScraper_Scrape_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL:     server.URL,
+		RepeatDelayMs: 1,
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, http.StatusServiceUnavailable, result.Details["status_code"])
+}
+
+func TestIdempotencyScraper_Scrape_ConnectionError(t *testing.T) {
+	scraper := NewIdempotencyScraper(config.HealthcheckScraper{
+		ScrapeURL:     "http://localhost:1",
+		RepeatDelayMs: 1,
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "failed to connect")
+}
+
+func TestFactory_CreateScraper_IdempotencyRejectsScrapeCache(t *testing.T) {
+	_, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:              "idempotency",
+		ScrapeURL:         "http://localhost:8080/catalog",
+		EnableScrapeCache: true,
+	})
+
+	assert.ErrorContains(t, err, "enable_scrape_cache")
+}
@@ -0,0 +1,155 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// freshnessSource reads when a job last ran
+type freshnessSource interface {
+	LastRun(ctx context.Context) (time.Time, error)
+}
+
+// freshnessSources holds the sources of last run times keyed by their source_type identifier
+var freshnessSources = map[string]func(scraperConfig config.HealthcheckScraper, client *http.Client) (freshnessSource, error){
+	"file":  newFileFreshnessSource,
+	"http":  newHTTPFreshnessSource,
+	"redis": newRedisFreshnessSource,
+}
+
+// freshnessSourceNames returns the supported sources sorted by name
+func freshnessSourceNames() string {
+	names := make([]string, 0, len(freshnessSources))
+	for name := range freshnessSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// JobFreshnessScraper implements the Scraper interface for checking that a scheduled job
+// ran recently
+type JobFreshnessScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	source                freshnessSource
+	logger                *logrus.Logger
+	now                   func() time.Time
+}
+
+// NewJobFreshnessScraper creates a new job freshness scraper reading last run times from
+// the given source
+func NewJobFreshnessScraper(scraperConfig config.HealthcheckScraper, source freshnessSource, logger *logrus.Logger) *JobFreshnessScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &JobFreshnessScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		source:                source,
+		logger:                logger,
+		now:                   time.Now,
+	}
+}
+
+// Type returns the scraper type identifier
+func (j *JobFreshnessScraper) Type() string {
+	return "job-freshness"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (j *JobFreshnessScraper) GetPingURL() string {
+	return j.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (j *JobFreshnessScraper) GetScrapeInterval() int {
+	return j.scrapeIntervalSeconds
+}
+
+// Scrape reads when the job last ran and compares its age against the maximum age
+func (j *JobFreshnessScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	j.logger.WithField("source_type", j.config.SourceType).Debug("Starting job freshness healthcheck")
+
+	details := map[string]interface{}{
+		"source_type":     j.config.SourceType,
+		"max_age_seconds": j.config.MaxAgeSeconds,
+	}
+
+	lastRun, err := j.source.LastRun(ctx)
+	if err != nil {
+		details["error"] = err.Error()
+		message := fmt.Sprintf("Failed to read last run from %s source: %v", j.config.SourceType, err)
+		if errors.Is(err, errEmptyResponse) {
+			message = parseFailure(j.config.ScrapeURL, err, details)
+		}
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	age := j.now().Sub(lastRun)
+	maxAge := time.Duration(j.config.MaxAgeSeconds) * time.Second
+	healthy := age <= maxAge
+
+	details["last_run"] = lastRun
+	details["age_seconds"] = int64(age.Seconds())
+
+	message := fmt.Sprintf("Job last ran %s ago, within the maximum age of %s", age.Round(time.Second), maxAge)
+	if !healthy {
+		message = fmt.Sprintf("Job last ran %s ago, exceeding the maximum age of %s", age.Round(time.Second), maxAge)
+	}
+
+	j.logger.WithFields(logrus.Fields{
+		"source_type": j.config.SourceType,
+		"last_run":    lastRun,
+		"healthy":     healthy,
+	}).Info("Job freshness healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// parseTimestamp parses a last run time given as RFC 3339 or as Unix seconds, optionally
+// with a fractional part
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	// Parse the whole and fractional seconds separately to keep nanosecond precision
+	whole, fraction, _ := strings.Cut(value, ".")
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q, expected RFC 3339 or Unix seconds", value)
+	}
+	var nanos int64
+	if fraction != "" {
+		digits := (fraction + "000000000")[:9]
+		if nanos, err = strconv.ParseInt(digits, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q, expected RFC 3339 or Unix seconds", value)
+		}
+	}
+	return time.Unix(seconds, nanos), nil
+}
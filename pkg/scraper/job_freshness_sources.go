@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+// maxTimestampBodySize is the largest HTTP response read for a last run time
+const maxTimestampBodySize = 1 << 20
+
+// fileFreshnessSource reads the last run of a job from the modification time of a file it
+// touches on completion
+type fileFreshnessSource struct {
+	path string
+}
+
+// newFileFreshnessSource creates a source reading the modification time of file_path
+func newFileFreshnessSource(scraperConfig config.HealthcheckScraper, _ *http.Client) (freshnessSource, error) {
+	if scraperConfig.FilePath == "" {
+		return nil, fmt.Errorf("file source requires a file_path")
+	}
+	return &fileFreshnessSource{path: scraperConfig.FilePath}, nil
+}
+
+// LastRun returns the modification time of the file
+func (f *fileFreshnessSource) LastRun(ctx context.Context) (time.Time, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// httpFreshnessSource reads the last run of a job from an HTTP endpoint returning its
+// timestamp, either as the whole body or at a JSON path
+type httpFreshnessSource struct {
+	url      string
+	jsonPath string
+	client   *http.Client
+}
+
+// newHTTPFreshnessSource creates a source reading the timestamp served at scrape_url
+func newHTTPFreshnessSource(scraperConfig config.HealthcheckScraper, client *http.Client) (freshnessSource, error) {
+	if scraperConfig.ScrapeURL == "" {
+		return nil, fmt.Errorf("http source requires a scrape_url")
+	}
+	return &httpFreshnessSource{
+		url:      scraperConfig.ScrapeURL,
+		jsonPath: scraperConfig.JSONPath,
+		client:   client,
+	}, nil
+}
+
+// LastRun fetches the endpoint and parses the timestamp it returns
+func (h *httpFreshnessSource) LastRun(ctx context.Context) (time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", h.url, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return time.Time{}, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTimestampBodySize))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if strings.TrimSpace(string(body)) == "" {
+		return time.Time{}, errEmptyResponse
+	}
+	if h.jsonPath == "" {
+		return parseTimestamp(string(body))
+	}
+
+	var doc interface{}
+	if err := decodeJSON(bytes.NewReader(body), &doc); err != nil {
+		return time.Time{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	value, err := lookupJSONPath(doc, h.jsonPath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("JSON path %s not found: %w", h.jsonPath, err)
+	}
+
+	switch value := value.(type) {
+	case string:
+		return parseTimestamp(value)
+	case float64:
+		return parseTimestamp(strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		return time.Time{}, fmt.Errorf("JSON path %s is not a timestamp", h.jsonPath)
+	}
+}
+
+// redisFreshnessSource reads the last run of a job from a Redis key it sets on completion
+type redisFreshnessSource struct {
+	redisQueue
+}
+
+// newRedisFreshnessSource creates a source reading the timestamp stored in redis_key of the
+// redis://[user:password@]host:port[/db] URL in scrape_url
+func newRedisFreshnessSource(scraperConfig config.HealthcheckScraper, client *http.Client) (freshnessSource, error) {
+	if scraperConfig.RedisKey == "" {
+		return nil, fmt.Errorf("redis source requires a redis_key")
+	}
+
+	// The connection settings are parsed like those of the Redis queue backend
+	scraperConfig.QueueName = scraperConfig.RedisKey
+	queue, err := newRedisQueue(scraperConfig, client)
+	if err != nil {
+		return nil, err
+	}
+	return &redisFreshnessSource{redisQueue: *queue.(*redisQueue)}, nil
+}
+
+// LastRun connects to Redis, authenticates if configured and parses the key's value
+func (r *redisFreshnessSource) LastRun(ctx context.Context) (time.Time, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", r.address)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	reader := bufio.NewReader(conn)
+	if err := r.prepare(conn, reader); err != nil {
+		return time.Time{}, err
+	}
+
+	reply, err := redisCommand(conn, reader, "GET", r.key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseTimestamp(reply)
+}
@@ -0,0 +1,129 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileFreshnessSource_LastRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.done")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	lastRun := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, lastRun, lastRun))
+
+	source, err := newFileFreshnessSource(config.HealthcheckScraper{FilePath: path}, nil)
+	require.NoError(t, err)
+
+	got, err := source.LastRun(context.Background())
+	require.NoError(t, err)
+	assert.True(t, lastRun.Equal(got))
+
+	missing, err := newFileFreshnessSource(config.HealthcheckScraper{FilePath: filepath.Join(t.TempDir(), "missing")}, nil)
+	require.NoError(t, err)
+	_, err = missing.LastRun(context.Background())
+	assert.Error(t, err)
+}
+
+func TestHTTPFreshnessSource_LastRun(t *testing.T) {
+	want := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		body     string
+		jsonPath string
+	}{
+		{name: "plain RFC 3339", body: "2024-01-01T12:00:00Z\n"},
+		{name: "plain Unix seconds", body: "1704110400"},
+		{name: "JSON string", body: `{"job": {"last_success": "2024-01-01T12:00:00Z"}}`, jsonPath: "$.job.last_success"},
+		{name: "JSON number", body: `{"job": {"last_success": 1704110400}}`, jsonPath: "$.job.last_success"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			source, err := newHTTPFreshnessSource(config.HealthcheckScraper{ScrapeURL: server.URL, JSONPath: tt.jsonPath}, server.Client())
+			require.NoError(t, err)
+
+			got, err := source.LastRun(context.Background())
+			require.NoError(t, err)
+			assert.True(t, want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestHTTPFreshnessSource_LastRun_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		jsonPath string
+		err      string
+	}{
+		{name: "error status", status: http.StatusInternalServerError, err: "HTTP status 500"},
+		{name: "empty body", status: http.StatusOK, err: "empty response"},
+		{name: "empty body with JSON path", status: http.StatusOK, jsonPath: "$.job.last_success", err: "empty response"},
+		{name: "not a timestamp", status: http.StatusOK, body: "never", err: "invalid timestamp"},
+		{name: "missing JSON path", status: http.StatusOK, body: `{}`, jsonPath: "$.job.last_success", err: "JSON path $.job.last_success not found"},
+		{name: "JSON path not a timestamp", status: http.StatusOK, body: `{"job": {"last_success": true}}`, jsonPath: "$.job.last_success", err: "is not a timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			source, err := newHTTPFreshnessSource(config.HealthcheckScraper{ScrapeURL: server.URL, JSONPath: tt.jsonPath}, server.Client())
+			require.NoError(t, err)
+
+			_, err = source.LastRun(context.Background())
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestRedisFreshnessSource_LastRun(t *testing.T) {
+	address := startFakeRedis(t, "secret", map[string]string{"jobs:backup:last_run": "1704110400"})
+
+	source, err := newRedisFreshnessSource(config.HealthcheckScraper{
+		ScrapeURL: "redis://:secret@" + address + "/1",
+		RedisKey:  "jobs:backup:last_run",
+	}, nil)
+	require.NoError(t, err)
+
+	got, err := source.LastRun(context.Background())
+	require.NoError(t, err)
+	assert.True(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Equal(got))
+
+	missing, err := newRedisFreshnessSource(config.HealthcheckScraper{
+		ScrapeURL: "redis://:secret@" + address,
+		RedisKey:  "jobs:report:last_run",
+	}, nil)
+	require.NoError(t, err)
+	_, err = missing.LastRun(context.Background())
+	assert.ErrorContains(t, err, "key not found")
+}
+
+func TestNewRedisFreshnessSource_Validation(t *testing.T) {
+	_, err := newRedisFreshnessSource(config.HealthcheckScraper{ScrapeURL: "redis://localhost"}, nil)
+	assert.ErrorContains(t, err, "requires a redis_key")
+
+	_, err = newRedisFreshnessSource(config.HealthcheckScraper{ScrapeURL: "http://localhost", RedisKey: "last_run"}, nil)
+	assert.ErrorContains(t, err, "invalid redis URL")
+}
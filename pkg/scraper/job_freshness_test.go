@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFreshnessSource returns a fixed last run time or error
+type fakeFreshnessSource struct {
+	lastRun time.Time
+	err     error
+}
+
+func (f *fakeFreshnessSource) LastRun(ctx context.Context) (time.Time, error) {
+	return f.lastRun, f.err
+}
+
+func TestNewJobFreshnessScraper(t *testing.T) {
+	scraper := NewJobFreshnessScraper(config.HealthcheckScraper{
+		PingURL:       "http://localhost:8081/ping",
+		SourceType:    "file",
+		MaxAgeSeconds: 3600,
+	}, &fakeFreshnessSource{}, logrus.New())
+
+	assert.Equal(t, "job-freshness", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestJobFreshnessScraper_Scrape(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		lastRun time.Time
+		healthy bool
+		age     int64
+	}{
+		{name: "fresh", lastRun: now.Add(-30 * time.Minute), healthy: true, age: 1800},
+		{name: "at the maximum age", lastRun: now.Add(-time.Hour), healthy: true, age: 3600},
+		{name: "stale", lastRun: now.Add(-2 * time.Hour), healthy: false, age: 7200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewJobFreshnessScraper(config.HealthcheckScraper{
+				SourceType:    "http",
+				MaxAgeSeconds: 3600,
+			}, &fakeFreshnessSource{lastRun: tt.lastRun}, logrus.New())
+			scraper.now = func() time.Time { return now }
+
+			result, err := scraper.Scrape(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.lastRun, result.Details["last_run"])
+			assert.Equal(t, tt.age, result.Details["age_seconds"])
+			assert.Equal(t, "http", result.Details["source_type"])
+		})
+	}
+}
+
+func TestJobFreshnessScraper_Scrape_SourceError(t *testing.T) {
+	scraper := NewJobFreshnessScraper(config.HealthcheckScraper{
+		SourceType:    "redis",
+		MaxAgeSeconds: 3600,
+	}, &fakeFreshnessSource{err: errors.New("key not found")}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "Failed to read last run from redis source: key not found", result.Message)
+}
+
+func TestJobFreshnessScraper_Scrape_EmptyResponse(t *testing.T) {
+	scraper := NewJobFreshnessScraper(config.HealthcheckScraper{
+		SourceType:    "http",
+		ScrapeURL:     "http://jobs.internal/last-run",
+		MaxAgeSeconds: 3600,
+	}, &fakeFreshnessSource{err: errEmptyResponse}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "Empty response from http://jobs.internal/last-run", result.Message)
+	assert.Equal(t, "parse", result.Details["error_type"])
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-01-01T12:00:00Z", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"2024-01-01T14:00:00.5+02:00", time.Date(2024, 1, 1, 12, 0, 0, 500000000, time.UTC)},
+		{"1704110400", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"1704110400.25\n", time.Date(2024, 1, 1, 12, 0, 0, 250000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTimestamp(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.True(t, tt.want.Equal(got), "%s: got %s", tt.value, got)
+	}
+
+	_, err := parseTimestamp("yesterday")
+	assert.ErrorContains(t, err, "invalid timestamp")
+}
+
+func TestFactory_CreateScraper_JobFreshness_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{Type: "job-freshness", SourceType: "postgres", MaxAgeSeconds: 60})
+	assert.ErrorContains(t, err, "unsupported source_type")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "job-freshness", SourceType: "file", FilePath: "/tmp/done"})
+	assert.ErrorContains(t, err, "requires a positive max_age_seconds")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "job-freshness", SourceType: "file", MaxAgeSeconds: 60})
+	assert.ErrorContains(t, err, "requires a file_path")
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{Type: "job-freshness", SourceType: "file", FilePath: "/tmp/done", MaxAgeSeconds: 60})
+	require.NoError(t, err)
+	assert.Equal(t, "job-freshness", scraper.Type())
+}
@@ -0,0 +1,41 @@
+package scraper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookupJSONPath navigates a decoded JSON document using a dot separated path such as
+// "$.data.nodes" or "items.0.name", where numeric segments index into arrays
+func lookupJSONPath(doc interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc, nil
+	}
+
+	current := doc
+	for _, segment := range strings.Split(path, ".") {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[segment]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", segment)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, fmt.Errorf("segment %q is not a valid array index", segment)
+			}
+			if index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("index %d out of range for array of length %d", index, len(value))
+			}
+			current = value[index]
+		default:
+			return nil, fmt.Errorf("cannot navigate into %T at segment %q", current, segment)
+		}
+	}
+
+	return current, nil
+}
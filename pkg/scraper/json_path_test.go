@@ -0,0 +1,130 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupJSONPath(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"data":{"nodes":[{"name":"a"},{"name":"b"}]}}`), &doc))
+
+	value, err := lookupJSONPath(doc, "$.data.nodes.1.name")
+	require.NoError(t, err)
+	assert.Equal(t, "b", value)
+
+	value, err = lookupJSONPath(doc, "data.nodes")
+	require.NoError(t, err)
+	assert.Len(t, value, 2)
+
+	value, err = lookupJSONPath(doc, "$")
+	require.NoError(t, err)
+	assert.Equal(t, doc, value)
+}
+
+func TestLookupJSONPath_Errors(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"data":{"nodes":[1,2]}}`), &doc))
+
+	_, err := lookupJSONPath(doc, "data.missing")
+	assert.Contains(t, err.Error(), `key "missing" not found`)
+
+	_, err = lookupJSONPath(doc, "data.nodes.5")
+	assert.Contains(t, err.Error(), "index 5 out of range")
+
+	_, err = lookupJSONPath(doc, "data.nodes.first")
+	assert.Contains(t, err.Error(), "not a valid array index")
+
+	_, err = lookupJSONPath(doc, "data.nodes.0.name")
+	assert.Contains(t, err.Error(), "cannot navigate into")
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestHTTPScraper_Scrape_JSONArrayLength_Within(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"data":{"nodes":["a","b","c"]}}`))
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		JSONPath:  "data.nodes",
+		MinLength: intPtr(1),
+		MaxLength: intPtr(5),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, 3, result.Details["length"])
+}
+
+func TestHTTPScraper_Scrape_JSONArrayLength_Under(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"data":{"nodes":[]}}`))
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		JSONPath:  "data.nodes",
+		MinLength: intPtr(1),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "expected at least 1")
+	assert.Equal(t, 0, result.Details["length"])
+}
+
+func TestHTTPScraper_Scrape_JSONArrayLength_Over(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"data":{"nodes":["a","b","c"]}}`))
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		JSONPath:  "data.nodes",
+		MaxLength: intPtr(2),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "expected at most 2")
+	assert.Equal(t, 3, result.Details["length"])
+}
+
+func TestHTTPScraper_Scrape_JSONArrayLength_NotArray(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"data":{"nodes":"none"}}`))
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		JSONPath:  "data.nodes",
+		MinLength: intPtr(1),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "is not an array")
+}
+
+func TestHTTPScraper_Scrape_JSONArrayLength_InvalidJSON(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `invalid json`))
+	scraper := NewHTTPScraper(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		JSONPath:  "data.nodes",
+		MinLength: intPtr(1),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to parse response")
+}
@@ -0,0 +1,220 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// k8sNodeCondition is a condition of a Kubernetes node such as Ready or MemoryPressure
+type k8sNodeCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// k8sNode is a Kubernetes node with its conditions
+type k8sNode struct {
+	Name       string
+	Conditions []k8sNodeCondition
+}
+
+// ready reports whether the node's Ready condition is True
+func (n k8sNode) ready() bool {
+	for _, condition := range n.Conditions {
+		if condition.Type == "Ready" {
+			return condition.Status == "True"
+		}
+	}
+	return false
+}
+
+// problems returns the conditions of the node that are not in their healthy state: Ready
+// when not True, and any other condition such as DiskPressure when True
+func (n k8sNode) problems() []k8sNodeCondition {
+	var problems []k8sNodeCondition
+	for _, condition := range n.Conditions {
+		if (condition.Type == "Ready") != (condition.Status == "True") {
+			problems = append(problems, condition)
+		}
+	}
+	return problems
+}
+
+// kubernetesNodeClient lists the nodes of a Kubernetes cluster
+type kubernetesNodeClient interface {
+	ListNodes(ctx context.Context) ([]k8sNode, error)
+}
+
+// ListNodes returns every node of the cluster
+func (k *kubernetesAPI) ListNodes(ctx context.Context) ([]k8sNode, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Status struct {
+				Conditions []k8sNodeCondition `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := k.get(ctx, "/api/v1/nodes", &list); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]k8sNode, 0, len(list.Items))
+	for _, item := range list.Items {
+		nodes = append(nodes, k8sNode{Name: item.Metadata.Name, Conditions: item.Status.Conditions})
+	}
+	return nodes, nil
+}
+
+// K8sNodesScraper implements the Scraper interface for checking enough nodes of a Kubernetes
+// cluster are Ready
+type K8sNodesScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	client                kubernetesNodeClient
+	logger                *logrus.Logger
+}
+
+// NewK8sNodesScraper creates a new Kubernetes nodes scraper listing nodes with the given client
+func NewK8sNodesScraper(scraperConfig config.HealthcheckScraper, client kubernetesNodeClient, logger *logrus.Logger) *K8sNodesScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &K8sNodesScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		client:                client,
+		logger:                logger,
+	}
+}
+
+// Type returns the scraper type identifier
+func (k *K8sNodesScraper) Type() string {
+	return "k8s-nodes"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (k *K8sNodesScraper) GetPingURL() string {
+	return k.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (k *K8sNodesScraper) GetScrapeInterval() int {
+	return k.scrapeIntervalSeconds
+}
+
+// Close closes the Kubernetes client if it holds connections
+func (k *K8sNodesScraper) Close() error {
+	if closer, ok := k.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Scrape lists the nodes and is healthy when at least min_ready_nodes or min_ready_percent
+// of them, or all nodes if neither is set, are Ready
+func (k *K8sNodesScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	k.logger.Debug("Starting Kubernetes nodes healthcheck")
+
+	nodes, err := k.client.ListNodes(ctx)
+	if err != nil {
+		details := map[string]interface{}{
+			"error": err.Error(),
+		}
+		message := fmt.Sprintf("Failed to list nodes: %v", err)
+
+		var apiErr *kubernetesAPIError
+		if errors.As(err, &apiErr) {
+			setStatusCode(details, apiErr.StatusCode)
+			if apiErr.Reason != "" {
+				details["reason"] = apiErr.Reason
+			}
+			if apiErr.StatusCode == http.StatusForbidden {
+				message = fmt.Sprintf("Access to nodes denied, check the service account has a ClusterRole allowing to list nodes: %s", apiErr.Message)
+			}
+		}
+
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	ready := 0
+	notReady := []map[string]interface{}{}
+	for _, node := range nodes {
+		if node.ready() {
+			ready++
+			continue
+		}
+		notReady = append(notReady, map[string]interface{}{
+			"name":       node.Name,
+			"conditions": node.problems(),
+		})
+	}
+	sort.Slice(notReady, func(i, j int) bool {
+		return notReady[i]["name"].(string) < notReady[j]["name"].(string)
+	})
+
+	required := k.requiredReadyNodes(len(nodes))
+	healthy := ready >= required
+
+	details := map[string]interface{}{
+		"nodes":           len(nodes),
+		"ready_nodes":     ready,
+		"required_ready":  required,
+		"not_ready_nodes": notReady,
+	}
+
+	k.logger.WithFields(logrus.Fields{
+		"nodes":   len(nodes),
+		"ready":   ready,
+		"healthy": healthy,
+	}).Info("Kubernetes nodes healthcheck completed")
+
+	message := fmt.Sprintf("%d/%d nodes are Ready", ready, len(nodes))
+	if !healthy {
+		names := make([]string, 0, len(notReady))
+		for _, node := range notReady {
+			names = append(names, node["name"].(string))
+		}
+		message = fmt.Sprintf("%s, expected at least %d; not Ready: %v", message, required, names)
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// requiredReadyNodes returns how many of the cluster's nodes must be Ready
+func (k *K8sNodesScraper) requiredReadyNodes(total int) int {
+	switch {
+	case k.config.MinReadyNodes > 0:
+		return k.config.MinReadyNodes
+	case k.config.MinReadyPercent > 0:
+		return int(math.Ceil(float64(total) * k.config.MinReadyPercent / 100))
+	default:
+		return total
+	}
+}
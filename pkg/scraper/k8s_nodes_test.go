@@ -0,0 +1,138 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKubernetesNodeClient returns fixed nodes or an error
+type fakeKubernetesNodeClient struct {
+	nodes []k8sNode
+	err   error
+}
+
+func (f *fakeKubernetesNodeClient) ListNodes(ctx context.Context) ([]k8sNode, error) {
+	return f.nodes, f.err
+}
+
+// testNode returns a node with the given Ready status and extra conditions
+func testNode(name, ready string, conditions ...k8sNodeCondition) k8sNode {
+	return k8sNode{Name: name, Conditions: append(conditions, k8sNodeCondition{Type: "Ready", Status: ready})}
+}
+
+func TestNewK8sNodesScraper(t *testing.T) {
+	scraper := NewK8sNodesScraper(config.HealthcheckScraper{
+		PingURL: "http://localhost:8081/ping",
+	}, &fakeKubernetesNodeClient{}, logrus.New())
+
+	assert.Equal(t, "k8s-nodes", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestK8sNodesScraper_Scrape(t *testing.T) {
+	nodes := []k8sNode{
+		testNode("node-a", "True"),
+		testNode("node-b", "True"),
+		testNode("node-c", "True"),
+		testNode("node-d", "False", k8sNodeCondition{Type: "MemoryPressure", Status: "True"}, k8sNodeCondition{Type: "DiskPressure", Status: "False"}),
+	}
+
+	tests := []struct {
+		name     string
+		config   config.HealthcheckScraper
+		healthy  bool
+		required int
+	}{
+		{name: "all nodes required", config: config.HealthcheckScraper{}, healthy: false, required: 4},
+		{name: "min ready nodes met", config: config.HealthcheckScraper{MinReadyNodes: 3}, healthy: true, required: 3},
+		{name: "min ready percent met", config: config.HealthcheckScraper{MinReadyPercent: 75}, healthy: true, required: 3},
+		{name: "min ready percent rounds up", config: config.HealthcheckScraper{MinReadyPercent: 80}, healthy: false, required: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewK8sNodesScraper(tt.config, &fakeKubernetesNodeClient{nodes: nodes}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, 4, result.Details["nodes"])
+			assert.Equal(t, 3, result.Details["ready_nodes"])
+			assert.Equal(t, tt.required, result.Details["required_ready"])
+
+			notReady := result.Details["not_ready_nodes"].([]map[string]interface{})
+			require.Len(t, notReady, 1)
+			assert.Equal(t, "node-d", notReady[0]["name"])
+			assert.Equal(t, []k8sNodeCondition{
+				{Type: "MemoryPressure", Status: "True"},
+				{Type: "Ready", Status: "False"},
+			}, notReady[0]["conditions"])
+		})
+	}
+}
+
+func TestK8sNodesScraper_Scrape_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{
+			name:    "rbac denied",
+			err:     &kubernetesAPIError{StatusCode: http.StatusForbidden, Reason: "Forbidden", Message: "nodes is forbidden"},
+			message: "ClusterRole allowing to list nodes",
+		},
+		{
+			name:    "unreachable",
+			err:     fmt.Errorf("connection refused"),
+			message: "Failed to list nodes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewK8sNodesScraper(config.HealthcheckScraper{}, &fakeKubernetesNodeClient{err: tt.err}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestKubernetesAPI_ListNodes(t *testing.T) {
+	server, ca := newKubernetesTestServer(t, "secret")
+
+	api, err := newKubernetesAPI(config.HealthcheckScraper{Kubeconfig: writeKubeconfig(t, server.URL, ca, "secret")})
+	require.NoError(t, err)
+
+	nodes, err := api.ListNodes(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	assert.True(t, nodes[0].ready())
+	assert.False(t, nodes[1].ready())
+	assert.Equal(t, []k8sNodeCondition{
+		{Type: "DiskPressure", Status: "True", Reason: "KubeletHasDiskPressure"},
+		{Type: "Ready", Status: "False", Reason: "KubeletNotReady"},
+	}, nodes[1].problems())
+}
+
+func TestFactory_CreateScraper_K8sNodes_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{Type: "k8s-nodes", MinReadyPercent: 150})
+	assert.ErrorContains(t, err, "exceeds 100")
+}
@@ -0,0 +1,204 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// k8sWorkloadKinds maps the supported workload kinds to their resource in the apps/v1 API
+var k8sWorkloadKinds = map[string]string{
+	"deployment":  "deployments",
+	"statefulset": "statefulsets",
+}
+
+// k8sWorkloadKindNames returns the supported workload kinds for error messages
+func k8sWorkloadKindNames() string {
+	names := make([]string, 0, len(k8sWorkloadKinds))
+	for name := range k8sWorkloadKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// k8sWorkload is the replica status of a Deployment or StatefulSet
+type k8sWorkload struct {
+	Desired   int
+	Ready     int
+	Updated   int
+	Available int
+}
+
+// kubernetesWorkloadClient reads the replica status of Kubernetes workloads
+type kubernetesWorkloadClient interface {
+	GetWorkload(ctx context.Context, kind, namespace, name string) (*k8sWorkload, error)
+}
+
+// GetWorkload returns the replica status of the Deployment or StatefulSet
+func (k *kubernetesAPI) GetWorkload(ctx context.Context, kind, namespace, name string) (*k8sWorkload, error) {
+	path := fmt.Sprintf("/apis/apps/v1/namespaces/%s/%s/%s", url.PathEscape(namespace), k8sWorkloadKinds[kind], url.PathEscape(name))
+
+	var object struct {
+		Spec struct {
+			Replicas *int `json:"replicas"`
+		} `json:"spec"`
+		Status struct {
+			ReadyReplicas     int `json:"readyReplicas"`
+			UpdatedReplicas   int `json:"updatedReplicas"`
+			AvailableReplicas int `json:"availableReplicas"`
+		} `json:"status"`
+	}
+	if err := k.get(ctx, path, &object); err != nil {
+		return nil, err
+	}
+
+	// The API server defaults unset replicas to 1
+	desired := 1
+	if object.Spec.Replicas != nil {
+		desired = *object.Spec.Replicas
+	}
+
+	return &k8sWorkload{
+		Desired:   desired,
+		Ready:     object.Status.ReadyReplicas,
+		Updated:   object.Status.UpdatedReplicas,
+		Available: object.Status.AvailableReplicas,
+	}, nil
+}
+
+// K8sWorkloadScraper implements the Scraper interface for checking a Kubernetes Deployment
+// or StatefulSet has its replicas ready
+type K8sWorkloadScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	client                kubernetesWorkloadClient
+	logger                *logrus.Logger
+}
+
+// NewK8sWorkloadScraper creates a new Kubernetes workload scraper reading the workload with
+// the given client
+func NewK8sWorkloadScraper(scraperConfig config.HealthcheckScraper, client kubernetesWorkloadClient, logger *logrus.Logger) *K8sWorkloadScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &K8sWorkloadScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		client:                client,
+		logger:                logger,
+	}
+}
+
+// Type returns the scraper type identifier
+func (k *K8sWorkloadScraper) Type() string {
+	return "k8s-workload"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (k *K8sWorkloadScraper) GetPingURL() string {
+	return k.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (k *K8sWorkloadScraper) GetScrapeInterval() int {
+	return k.scrapeIntervalSeconds
+}
+
+// Close closes the Kubernetes client if it holds connections
+func (k *K8sWorkloadScraper) Close() error {
+	if closer, ok := k.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Scrape reads the workload and is healthy when at least min_ready_replicas, or all desired
+// replicas if unset, are ready
+func (k *K8sWorkloadScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	kind, namespace, name := k.config.WorkloadKind, k.config.Namespace, k.config.WorkloadName
+	k.logger.WithFields(logrus.Fields{
+		"kind":      kind,
+		"namespace": namespace,
+		"name":      name,
+	}).Debug("Starting Kubernetes workload healthcheck")
+
+	details := map[string]interface{}{
+		"kind":      kind,
+		"namespace": namespace,
+		"name":      name,
+	}
+
+	workload, err := k.client.GetWorkload(ctx, kind, namespace, name)
+	if err != nil {
+		details["error"] = err.Error()
+		message := fmt.Sprintf("Failed to read %s %s/%s: %v", kind, namespace, name, err)
+
+		var apiErr *kubernetesAPIError
+		if errors.As(err, &apiErr) {
+			setStatusCode(details, apiErr.StatusCode)
+			if apiErr.Reason != "" {
+				details["reason"] = apiErr.Reason
+			}
+			switch apiErr.StatusCode {
+			case http.StatusForbidden:
+				message = fmt.Sprintf("Access to %s %s/%s denied, check the RBAC permissions of the service account: %s", kind, namespace, name, apiErr.Message)
+			case http.StatusNotFound:
+				message = fmt.Sprintf("%s %s not found in namespace %s", kind, name, namespace)
+			}
+		}
+
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	details["desired_replicas"] = workload.Desired
+	details["ready_replicas"] = workload.Ready
+	details["updated_replicas"] = workload.Updated
+	details["available_replicas"] = workload.Available
+
+	required := workload.Desired
+	if k.config.MinReadyReplicas > 0 {
+		required = k.config.MinReadyReplicas
+		details["min_ready_replicas"] = required
+	}
+	healthy := workload.Ready >= required
+
+	k.logger.WithFields(logrus.Fields{
+		"kind":      kind,
+		"namespace": namespace,
+		"name":      name,
+		"ready":     workload.Ready,
+		"desired":   workload.Desired,
+		"healthy":   healthy,
+	}).Info("Kubernetes workload healthcheck completed")
+
+	message := fmt.Sprintf("%s %s/%s has %d/%d replicas ready", kind, namespace, name, workload.Ready, workload.Desired)
+	if !healthy {
+		message = fmt.Sprintf("%s, expected at least %d", message, required)
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
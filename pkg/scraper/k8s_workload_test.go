@@ -0,0 +1,124 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKubernetesWorkloadClient returns a fixed workload or error
+type fakeKubernetesWorkloadClient struct {
+	workload *k8sWorkload
+	err      error
+	kind     string
+}
+
+func (f *fakeKubernetesWorkloadClient) GetWorkload(ctx context.Context, kind, namespace, name string) (*k8sWorkload, error) {
+	f.kind = kind
+	return f.workload, f.err
+}
+
+// newTestK8sWorkloadScraper creates a scraper for the Deployment web in production
+func newTestK8sWorkloadScraper(client kubernetesWorkloadClient, minReady int) *K8sWorkloadScraper {
+	return NewK8sWorkloadScraper(config.HealthcheckScraper{
+		WorkloadKind:     "deployment",
+		WorkloadName:     "web",
+		Namespace:        "production",
+		MinReadyReplicas: minReady,
+	}, client, logrus.New())
+}
+
+func TestNewK8sWorkloadScraper(t *testing.T) {
+	scraper := NewK8sWorkloadScraper(config.HealthcheckScraper{
+		WorkloadKind: "statefulset",
+		WorkloadName: "db",
+		PingURL:      "http://localhost:8081/ping",
+	}, &fakeKubernetesWorkloadClient{}, logrus.New())
+
+	assert.Equal(t, "k8s-workload", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestK8sWorkloadScraper_Scrape(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload k8sWorkload
+		minReady int
+		healthy  bool
+	}{
+		{name: "all ready", workload: k8sWorkload{Desired: 3, Ready: 3}, healthy: true},
+		{name: "rollout in progress", workload: k8sWorkload{Desired: 3, Ready: 2}, healthy: false},
+		{name: "above minimum", workload: k8sWorkload{Desired: 3, Ready: 2}, minReady: 2, healthy: true},
+		{name: "below minimum", workload: k8sWorkload{Desired: 3, Ready: 1}, minReady: 2, healthy: false},
+		{name: "scaled to zero", workload: k8sWorkload{Desired: 0, Ready: 0}, healthy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeKubernetesWorkloadClient{workload: &tt.workload}
+			scraper := newTestK8sWorkloadScraper(client, tt.minReady)
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, "deployment", client.kind)
+			assert.Equal(t, tt.workload.Ready, result.Details["ready_replicas"])
+			assert.Equal(t, tt.workload.Desired, result.Details["desired_replicas"])
+		})
+	}
+}
+
+func TestK8sWorkloadScraper_Scrape_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{
+			name:    "rbac denied",
+			err:     &kubernetesAPIError{StatusCode: http.StatusForbidden, Reason: "Forbidden", Message: "deployments.apps \"web\" is forbidden"},
+			message: "check the RBAC permissions",
+		},
+		{
+			name:    "not found",
+			err:     &kubernetesAPIError{StatusCode: http.StatusNotFound, Reason: "NotFound"},
+			message: "deployment web not found in namespace production",
+		},
+		{
+			name:    "unreachable",
+			err:     fmt.Errorf("connection refused"),
+			message: "Failed to read deployment production/web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := newTestK8sWorkloadScraper(&fakeKubernetesWorkloadClient{err: tt.err}, 0)
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestFactory_CreateScraper_K8sWorkload_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{Type: "k8s-workload", WorkloadKind: "daemonset", WorkloadName: "web"})
+	assert.ErrorContains(t, err, "supported: deployment, statefulset")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "k8s-workload", WorkloadKind: "deployment"})
+	assert.ErrorContains(t, err, "requires a workload_name")
+}
@@ -0,0 +1,320 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+// serviceAccountDir is where Kubernetes mounts the service account of a pod
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// kubernetesAPIError is returned when the Kubernetes API rejected a request, carrying the
+// reason of the returned Status such as Forbidden or NotFound
+type kubernetesAPIError struct {
+	StatusCode int
+	Reason     string
+	Message    string
+}
+
+func (e *kubernetesAPIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("HTTP status %d from the Kubernetes API: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("HTTP status %d from the Kubernetes API", e.StatusCode)
+}
+
+// kubernetesAPI is a client of the Kubernetes API server authenticated either as the pod's
+// service account or with the credentials of a kubeconfig
+type kubernetesAPI struct {
+	server string
+	// namespace is used when a scraper does not configure one
+	namespace string
+	token     string
+	// tokenFile is re-read on every request, as service account tokens are rotated
+	tokenFile string
+	client    *http.Client
+}
+
+// newKubernetesAPI creates a Kubernetes API client from the scraper's kubeconfig, or from
+// the pod's service account when none is configured
+func newKubernetesAPI(scraperConfig config.HealthcheckScraper) (*kubernetesAPI, error) {
+	transport, err := newHTTPTransport(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+
+	var api *kubernetesAPI
+	if scraperConfig.Kubeconfig != "" {
+		api, err = loadKubeconfig(scraperConfig.Kubeconfig, tlsConfig)
+	} else {
+		api, err = inClusterKubernetesAPI(tlsConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	api.client = &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: instrumentTransport(transport, scraperConfig),
+	}
+	return api, nil
+}
+
+// inClusterKubernetesAPI configures the client from the service account and environment
+// Kubernetes provides to every pod
+func inClusterKubernetesAPI(tlsConfig *tls.Config) (*kubernetesAPI, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a Kubernetes cluster, set a kubeconfig")
+	}
+
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %w", err)
+	}
+	if err := addKubernetesCA(tlsConfig, ca); err != nil {
+		return nil, err
+	}
+
+	namespace := "default"
+	if contents, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace")); err == nil {
+		namespace = strings.TrimSpace(string(contents))
+	}
+
+	return &kubernetesAPI{
+		server:    "https://" + net.JoinHostPort(host, port),
+		namespace: namespace,
+		tokenFile: filepath.Join(serviceAccountDir, "token"),
+	}, nil
+}
+
+// kubeconfig is the subset of a kubeconfig file used to reach the API server. Only the JSON
+// form is supported, as written by `kubectl config view --raw --flatten -o json`.
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Contexts       []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster   string `json:"cluster"`
+			User      string `json:"user"`
+			Namespace string `json:"namespace"`
+		} `json:"context"`
+	} `json:"contexts"`
+	Clusters []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			Token                 string `json:"token"`
+			TokenFile             string `json:"tokenFile"`
+			ClientCertificate     string `json:"client-certificate"`
+			ClientCertificateData string `json:"client-certificate-data"`
+			ClientKey             string `json:"client-key"`
+			ClientKeyData         string `json:"client-key-data"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+// loadKubeconfig configures the client from the current context of the kubeconfig at path.
+// Relative file references are resolved against the kubeconfig's directory.
+func loadKubeconfig(path string, tlsConfig *tls.Config) (*kubernetesAPI, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	var kc kubeconfig
+	if err := json.Unmarshal(contents, &kc); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig, only the JSON form is supported: %w", err)
+	}
+
+	resolve := func(file string) string {
+		if file == "" || filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(filepath.Dir(path), file)
+	}
+	// readData returns inline base64 data or the contents of the referenced file
+	readData := func(name, data, file string) ([]byte, error) {
+		if data != "" {
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s-data in kubeconfig: %w", name, err)
+			}
+			return decoded, nil
+		}
+		if file == "" {
+			return nil, nil
+		}
+		contents, err := os.ReadFile(resolve(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of kubeconfig: %w", name, err)
+		}
+		return contents, nil
+	}
+
+	contextIndex := -1
+	for i, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext {
+			contextIndex = i
+		}
+	}
+	if contextIndex < 0 {
+		return nil, fmt.Errorf("kubeconfig context %q not found", kc.CurrentContext)
+	}
+	kubeContext := kc.Contexts[contextIndex].Context
+
+	api := &kubernetesAPI{namespace: kubeContext.Namespace}
+	if api.namespace == "" {
+		api.namespace = "default"
+	}
+
+	clusterFound := false
+	for _, c := range kc.Clusters {
+		if c.Name != kubeContext.Cluster {
+			continue
+		}
+		clusterFound = true
+		if _, err := url.Parse(c.Cluster.Server); err != nil || c.Cluster.Server == "" {
+			return nil, fmt.Errorf("invalid server %q of kubeconfig cluster %s", c.Cluster.Server, c.Name)
+		}
+		api.server = strings.TrimSuffix(c.Cluster.Server, "/")
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+
+		ca, err := readData("certificate-authority", c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority)
+		if err != nil {
+			return nil, err
+		}
+		if ca != nil {
+			if err := addKubernetesCA(tlsConfig, ca); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !clusterFound {
+		return nil, fmt.Errorf("kubeconfig cluster %q not found", kubeContext.Cluster)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != kubeContext.User {
+			continue
+		}
+		api.token = u.User.Token
+		api.tokenFile = resolve(u.User.TokenFile)
+
+		cert, err := readData("client-certificate", u.User.ClientCertificateData, u.User.ClientCertificate)
+		if err != nil {
+			return nil, err
+		}
+		key, err := readData("client-key", u.User.ClientKeyData, u.User.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil || key != nil {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate in kubeconfig: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+	}
+
+	return api, nil
+}
+
+// addKubernetesCA trusts the cluster's CA in addition to the roots already configured
+func addKubernetesCA(tlsConfig *tls.Config, ca []byte) error {
+	certs, err := parsePEMCertificates(ca)
+	if err != nil {
+		return fmt.Errorf("invalid Kubernetes cluster CA: %w", err)
+	}
+
+	if tlsConfig.RootCAs == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		tlsConfig.RootCAs = pool
+	}
+	for _, cert := range certs {
+		tlsConfig.RootCAs.AddCert(cert)
+	}
+	return nil
+}
+
+// get decodes the JSON object at the API path into target
+func (k *kubernetesAPI) get(ctx context.Context, path string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", k.server+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	token := k.token
+	if k.tokenFile != "" {
+		contents, err := os.ReadFile(k.tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read token: %w", err)
+		}
+		token = strings.TrimSpace(string(contents))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &kubernetesAPIError{StatusCode: resp.StatusCode}
+		var status struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&status) == nil {
+			apiErr.Reason = status.Reason
+			apiErr.Message = status.Message
+		}
+		return apiErr
+	}
+
+	if err := decodeJSON(resp.Body, target); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// Close closes the idle connections of the client's HTTP client
+func (k *kubernetesAPI) Close() error {
+	k.client.CloseIdleConnections()
+	return nil
+}
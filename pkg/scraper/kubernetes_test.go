@@ -0,0 +1,149 @@
+package scraper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKubernetesTestServer starts a fake API server serving a Deployment named web and two
+// nodes to requests with the bearer token, and returns it with the PEM of its CA
+func newKubernetesTestServer(t *testing.T, token string) (*httptest.Server, []byte) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","reason":"Forbidden","message":"deployments.apps \"web\" is forbidden"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/apis/apps/v1/namespaces/production/deployments/web":
+			w.Write([]byte(`{"spec":{"replicas":3},"status":{"replicas":3,"readyReplicas":2,"updatedReplicas":3,"availableReplicas":2}}`))
+		case "/api/v1/nodes":
+			w.Write([]byte(`{"items":[{"metadata":{"name":"node-a"},"status":{"conditions":[{"type":"Ready","status":"True"}]}},{"metadata":{"name":"node-b"},"status":{"conditions":[{"type":"DiskPressure","status":"True","reason":"KubeletHasDiskPressure"},{"type":"Ready","status":"False","reason":"KubeletNotReady"}]}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind":"Status","reason":"NotFound","message":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, ca
+}
+
+// writeKubeconfig writes a JSON kubeconfig for the server authenticating with the token
+func writeKubeconfig(t *testing.T, server string, ca []byte, token string) string {
+	kc := map[string]interface{}{
+		"current-context": "test",
+		"contexts": []map[string]interface{}{
+			{"name": "test", "context": map[string]string{"cluster": "cluster", "user": "user", "namespace": "production"}},
+		},
+		"clusters": []map[string]interface{}{
+			{"name": "cluster", "cluster": map[string]string{"server": server, "certificate-authority-data": base64.StdEncoding.EncodeToString(ca)}},
+		},
+		"users": []map[string]interface{}{
+			{"name": "user", "user": map[string]string{"token": token}},
+		},
+	}
+	contents, err := json.Marshal(kc)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "kubeconfig.json")
+	require.NoError(t, os.WriteFile(path, contents, 0o600))
+	return path
+}
+
+func TestKubernetesAPI_Kubeconfig(t *testing.T) {
+	server, ca := newKubernetesTestServer(t, "secret")
+
+	api, err := newKubernetesAPI(config.HealthcheckScraper{Kubeconfig: writeKubeconfig(t, server.URL, ca, "secret")})
+	require.NoError(t, err)
+	assert.Equal(t, "production", api.namespace)
+
+	workload, err := api.GetWorkload(context.Background(), "deployment", "production", "web")
+
+	require.NoError(t, err)
+	assert.Equal(t, &k8sWorkload{Desired: 3, Ready: 2, Updated: 3, Available: 2}, workload)
+}
+
+func TestKubernetesAPI_Errors(t *testing.T) {
+	server, ca := newKubernetesTestServer(t, "secret")
+
+	denied, err := newKubernetesAPI(config.HealthcheckScraper{Kubeconfig: writeKubeconfig(t, server.URL, ca, "other")})
+	require.NoError(t, err)
+
+	_, err = denied.GetWorkload(context.Background(), "deployment", "production", "web")
+
+	var apiErr *kubernetesAPIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, "Forbidden", apiErr.Reason)
+
+	allowed, err := newKubernetesAPI(config.HealthcheckScraper{Kubeconfig: writeKubeconfig(t, server.URL, ca, "secret")})
+	require.NoError(t, err)
+
+	_, err = allowed.GetWorkload(context.Background(), "statefulset", "production", "web")
+
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "NotFound", apiErr.Reason)
+}
+
+func TestKubernetesAPI_InCluster(t *testing.T) {
+	server, ca := newKubernetesTestServer(t, "rotated")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("initial\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "namespace"), []byte("production"), 0o600))
+	original := serviceAccountDir
+	serviceAccountDir = dir
+	t.Cleanup(func() { serviceAccountDir = original })
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Setenv("KUBERNETES_SERVICE_HOST", host)
+	t.Setenv("KUBERNETES_SERVICE_PORT", port)
+
+	api, err := newKubernetesAPI(config.HealthcheckScraper{})
+	require.NoError(t, err)
+	assert.Equal(t, "production", api.namespace)
+
+	_, err = api.GetWorkload(context.Background(), "deployment", "production", "web")
+	require.Error(t, err)
+
+	// The token is re-read on every request as Kubernetes rotates it
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("rotated\n"), 0o600))
+
+	_, err = api.GetWorkload(context.Background(), "deployment", "production", "web")
+	require.NoError(t, err)
+}
+
+func TestKubernetesAPI_NotInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	_, err := newKubernetesAPI(config.HealthcheckScraper{})
+
+	assert.ErrorContains(t, err, "not running in a Kubernetes cluster")
+}
+
+func TestKubernetesAPI_InvalidKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte("apiVersion: v1\n"), 0o600))
+
+	_, err := newKubernetesAPI(config.HealthcheckScraper{Kubeconfig: path})
+
+	assert.ErrorContains(t, err, "only the JSON form is supported")
+}
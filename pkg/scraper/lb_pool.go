@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LBPoolScraper implements the Scraper interface for checking the number of healthy
+// backends of a load balancer
+type LBPoolScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewLBPoolScraper creates a new load balancer pool scraper
+func NewLBPoolScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *LBPoolScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &LBPoolScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Type returns the scraper type identifier
+func (l *LBPoolScraper) Type() string {
+	return "lb-pool"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (l *LBPoolScraper) GetPingURL() string {
+	return l.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (l *LBPoolScraper) GetScrapeInterval() int {
+	return l.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (l *LBPoolScraper) Close() error {
+	l.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape fetches the load balancer's status, counts the healthy backends of the configured
+// pool, or of all pools if none is configured, and compares them to the minimum
+func (l *LBPoolScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	scrapeURL := l.config.ScrapeURL
+	l.logger.WithFields(logrus.Fields{
+		"url":    scrapeURL,
+		"format": l.config.Format,
+	}).Debug("Starting load balancer pool healthcheck")
+
+	parse, ok := lbPoolFormats[l.config.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format: %s", l.config.Format)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", scrapeURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return l.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to connect to %s: %v", scrapeURL, err),
+			Timestamp: time.Now(),
+			Details: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	details := map[string]interface{}{
+		DetailStatusCode: resp.StatusCode,
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return l.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, scrapeURL),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	pools, err := parse(resp.Body)
+	if err != nil {
+		details["error"] = err.Error()
+		return l.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to parse %s status from %s: %v", l.config.Format, scrapeURL, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	target := "all pools"
+	if l.config.Backend != "" {
+		target = "pool " + l.config.Backend
+		pool, ok := pools[l.config.Backend]
+		if !ok {
+			return l.decorate(&ScrapeResult{
+				Healthy:   false,
+				Message:   fmt.Sprintf("Pool %s not found at %s", l.config.Backend, scrapeURL),
+				Timestamp: time.Now(),
+				Details:   details,
+			}, resp), nil
+		}
+		pools = map[string]*lbPool{l.config.Backend: pool}
+	}
+
+	var total, healthy int
+	for _, pool := range pools {
+		total += pool.Total
+		healthy += pool.Healthy
+	}
+	details["pools"] = pools
+	details["total_backends"] = total
+	details["healthy_backends"] = healthy
+
+	// Without a configured minimum a pool needs at least one healthy backend
+	minHealthy := l.config.MinHealthyBackends
+	if minHealthy <= 0 {
+		minHealthy = 1
+	}
+	isHealthy := healthy >= minHealthy
+
+	l.logger.WithFields(logrus.Fields{
+		"url":              scrapeURL,
+		"healthy_backends": healthy,
+		"total_backends":   total,
+		"healthy":          isHealthy,
+	}).Info("Load balancer pool healthcheck completed")
+
+	message := fmt.Sprintf("%d of %d backends of %s are healthy", healthy, total, target)
+	if !isHealthy {
+		message = fmt.Sprintf("%s, expected at least %d", message, minHealthy)
+	}
+
+	return l.decorate(&ScrapeResult{
+		Healthy:   isHealthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
@@ -0,0 +1,165 @@
+package scraper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// lbPool is the backend count of a load balancer pool
+type lbPool struct {
+	Total   int `json:"total"`
+	Healthy int `json:"healthy"`
+}
+
+// lbPoolFormats holds the parsers of the supported status formats keyed by their format identifier
+var lbPoolFormats = map[string]func(body io.Reader) (map[string]*lbPool, error){
+	"envoy-json":      parseEnvoyClusters,
+	"haproxy-csv":     parseHAProxyCSV,
+	"nginx-plus-json": parseNginxPlusUpstreams,
+}
+
+// lbPoolFormatNames returns the supported status formats sorted by name
+func lbPoolFormatNames() string {
+	names := make([]string, 0, len(lbPoolFormats))
+	for name := range lbPoolFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// parseHAProxyCSV counts the servers of every backend in HAProxy's CSV statistics
+// (the stats page with ;csv appended). Servers that are UP, including those going down,
+// and servers without health checks are healthy.
+func parseHAProxyCSV(body io.Reader) (map[string]*lbPool, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "# "))] = i
+	}
+	// Rows too short for any of the columns read are skipped
+	lastColumn := 0
+	for _, name := range []string{"pxname", "svname", "status"} {
+		i, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("missing %s column", name)
+		}
+		lastColumn = max(lastColumn, i)
+	}
+
+	pools := make(map[string]*lbPool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) <= lastColumn {
+			continue
+		}
+
+		// Frontend and backend rows aggregate the servers
+		server := record[columns["svname"]]
+		if server == "FRONTEND" || server == "BACKEND" {
+			continue
+		}
+
+		pool := poolOf(pools, record[columns["pxname"]])
+		pool.Total++
+		status := record[columns["status"]]
+		if strings.HasPrefix(status, "UP") || status == "no check" {
+			pool.Healthy++
+		}
+	}
+
+	return pools, nil
+}
+
+// parseEnvoyClusters counts the hosts of every cluster in Envoy's admin /clusters?format=json
+// output. Hosts are healthy unless EDS reports them otherwise or any health check failed.
+func parseEnvoyClusters(body io.Reader) (map[string]*lbPool, error) {
+	var clusters struct {
+		ClusterStatuses []struct {
+			Name         string `json:"name"`
+			HostStatuses []struct {
+				HealthStatus map[string]interface{} `json:"health_status"`
+			} `json:"host_statuses"`
+		} `json:"cluster_statuses"`
+	}
+	if err := decodeJSON(body, &clusters); err != nil {
+		return nil, err
+	}
+
+	pools := make(map[string]*lbPool)
+	for _, cluster := range clusters.ClusterStatuses {
+		pool := poolOf(pools, cluster.Name)
+		for _, host := range cluster.HostStatuses {
+			pool.Total++
+			if envoyHostHealthy(host.HealthStatus) {
+				pool.Healthy++
+			}
+		}
+	}
+
+	return pools, nil
+}
+
+// envoyHostHealthy reports whether an Envoy host's health status allows routing to it
+func envoyHostHealthy(status map[string]interface{}) bool {
+	if eds, ok := status["eds_health_status"].(string); ok && eds != "HEALTHY" && eds != "DEGRADED" && eds != "UNKNOWN" {
+		return false
+	}
+	for flag, value := range status {
+		if strings.HasPrefix(flag, "failed_") && value == true {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNginxPlusUpstreams counts the peers of every upstream in the NGINX Plus API's
+// /http/upstreams output. Peers in the "up" state are healthy.
+func parseNginxPlusUpstreams(body io.Reader) (map[string]*lbPool, error) {
+	var upstreams map[string]struct {
+		Peers []struct {
+			State string `json:"state"`
+		} `json:"peers"`
+	}
+	if err := decodeJSON(body, &upstreams); err != nil {
+		return nil, err
+	}
+
+	pools := make(map[string]*lbPool)
+	for name, upstream := range upstreams {
+		pool := poolOf(pools, name)
+		for _, peer := range upstream.Peers {
+			pool.Total++
+			if peer.State == "up" {
+				pool.Healthy++
+			}
+		}
+	}
+
+	return pools, nil
+}
+
+// poolOf returns the named pool, adding it if needed
+func poolOf(pools map[string]*lbPool, name string) *lbPool {
+	pool, ok := pools[name]
+	if !ok {
+		pool = &lbPool{}
+		pools[name] = pool
+	}
+	return pool
+}
@@ -0,0 +1,109 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const haproxyStatsCSV = `# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,dreq,dresp,ereq,econ,eresp,wretr,wredis,status,weight
+http-in,FRONTEND,,,0,1,2000,3,0,0,0,0,0,,,,,OPEN,
+web,app1,0,0,0,1,,2,0,0,,0,,0,0,0,0,UP,1
+web,app2,0,0,0,1,,2,0,0,,0,,0,0,0,0,UP 1/3,1
+web,app3,0,0,0,1,,2,0,0,,0,,0,0,0,0,DOWN,1
+web,app4,0,0,0,1,,2,0,0,,0,,0,0,0,0,MAINT,1
+web,BACKEND,0,0,0,1,200,3,0,0,0,0,,0,0,0,0,UP,3
+api,api1,0,0,0,1,,2,0,0,,0,,0,0,0,0,no check,1
+`
+
+const envoyClustersJSON = `{
+  "cluster_statuses": [
+    {
+      "name": "web",
+      "host_statuses": [
+        {"address": {"socket_address": {"address": "10.0.0.1", "port_value": 80}}, "health_status": {"eds_health_status": "HEALTHY"}},
+        {"address": {"socket_address": {"address": "10.0.0.2", "port_value": 80}}, "health_status": {"eds_health_status": "HEALTHY", "failed_active_health_check": true}},
+        {"address": {"socket_address": {"address": "10.0.0.3", "port_value": 80}}, "health_status": {"eds_health_status": "DRAINING"}}
+      ]
+    },
+    {
+      "name": "api",
+      "host_statuses": [
+        {"address": {"socket_address": {"address": "10.0.1.1", "port_value": 8080}}, "health_status": {"eds_health_status": "DEGRADED"}}
+      ]
+    }
+  ]
+}`
+
+const nginxPlusUpstreamsJSON = `{
+  "web": {
+    "peers": [
+      {"id": 0, "server": "10.0.0.1:80", "state": "up"},
+      {"id": 1, "server": "10.0.0.2:80", "state": "unhealthy"},
+      {"id": 2, "server": "10.0.0.3:80", "state": "draining"}
+    ],
+    "keepalive": 0,
+    "zone": "web"
+  },
+  "api": {
+    "peers": [
+      {"id": 0, "server": "10.0.1.1:8080", "state": "up"}
+    ],
+    "zone": "api"
+  }
+}`
+
+func TestLBPoolFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		body   string
+		pools  map[string]*lbPool
+	}{
+		{
+			format: "haproxy-csv",
+			body:   haproxyStatsCSV,
+			pools:  map[string]*lbPool{"web": {Total: 4, Healthy: 2}, "api": {Total: 1, Healthy: 1}},
+		},
+		{
+			format: "envoy-json",
+			body:   envoyClustersJSON,
+			pools:  map[string]*lbPool{"web": {Total: 3, Healthy: 1}, "api": {Total: 1, Healthy: 1}},
+		},
+		{
+			format: "nginx-plus-json",
+			body:   nginxPlusUpstreamsJSON,
+			pools:  map[string]*lbPool{"web": {Total: 3, Healthy: 1}, "api": {Total: 1, Healthy: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			pools, err := lbPoolFormats[tt.format](strings.NewReader(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.pools, pools)
+		})
+	}
+}
+
+func TestParseHAProxyCSV_MissingColumn(t *testing.T) {
+	_, err := parseHAProxyCSV(strings.NewReader("# pxname,svname\nweb,app1\n"))
+	assert.ErrorContains(t, err, "missing status column")
+}
+
+func TestParseHAProxyCSV_ShortRows(t *testing.T) {
+	// The name columns come after status, so rows ending at status cannot be counted
+	csv := "# status,weight,pxname,svname\nUP,1,web,app1\nUP\nDOWN,1,web\nDOWN,1,web,app2\n"
+
+	pools, err := parseHAProxyCSV(strings.NewReader(csv))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*lbPool{"web": {Total: 2, Healthy: 1}}, pools)
+}
+
+func TestLBPoolFormats_InvalidJSON(t *testing.T) {
+	for _, format := range []string{"envoy-json", "nginx-plus-json"} {
+		_, err := lbPoolFormats[format](strings.NewReader("not json"))
+		assert.Error(t, err, format)
+	}
+}
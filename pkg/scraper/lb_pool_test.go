@@ -0,0 +1,123 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLBPoolScraper(t *testing.T) {
+	scraper := NewLBPoolScraper(config.HealthcheckScraper{
+		ScrapeURL: "http://localhost:8404/stats;csv",
+		PingURL:   "http://localhost:8081/ping",
+		Format:    "haproxy-csv",
+	}, logrus.New())
+
+	assert.Equal(t, "lb-pool", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestLBPoolScraper_Scrape(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "", haproxyStatsCSV))
+
+	tests := []struct {
+		name       string
+		backend    string
+		minHealthy int
+		healthy    bool
+		total      int
+		up         int
+	}{
+		{name: "all pools above default minimum", healthy: true, total: 5, up: 3},
+		{name: "all pools below minimum", minHealthy: 4, healthy: false, total: 5, up: 3},
+		{name: "pool meets minimum", backend: "web", minHealthy: 2, healthy: true, total: 4, up: 2},
+		{name: "pool below minimum", backend: "web", minHealthy: 3, healthy: false, total: 4, up: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewLBPoolScraper(config.HealthcheckScraper{
+				ScrapeURL:          server.URL,
+				Format:             "haproxy-csv",
+				Backend:            tt.backend,
+				MinHealthyBackends: tt.minHealthy,
+			}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.total, result.Details["total_backends"])
+			assert.Equal(t, tt.up, result.Details["healthy_backends"])
+			assert.Contains(t, result.Details, "pools")
+		})
+	}
+}
+
+func TestLBPoolScraper_Scrape_UnknownBackend(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "", nginxPlusUpstreamsJSON))
+
+	scraper := NewLBPoolScraper(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		Format:    "nginx-plus-json",
+		Backend:   "missing",
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Pool missing not found")
+}
+
+func TestLBPoolScraper_Scrape_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+	}{
+		{name: "error status", status: http.StatusServiceUnavailable, body: "", message: "HTTP status 503"},
+		{name: "unparseable body", status: http.StatusOK, body: "<html>", message: "Failed to parse envoy-json status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, respond(tt.status, "", tt.body))
+
+			scraper := NewLBPoolScraper(config.HealthcheckScraper{
+				ScrapeURL: server.URL,
+				Format:    "envoy-json",
+			}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestFactory_CreateScraper_LBPool_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "lb-pool",
+		ScrapeURL: "http://localhost:8404/stats;csv",
+		Format:    "traefik",
+	})
+	assert.ErrorContains(t, err, "unsupported format")
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{
+		Type:      "lb-pool",
+		ScrapeURL: "http://localhost:8404/stats;csv",
+		Format:    "haproxy-csv",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "lb-pool", scraper.Type())
+}
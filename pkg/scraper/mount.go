@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// mountProbeSize is the size of the file written to probe a mount
+const mountProbeSize = 64
+
+// MountScraper implements the Scraper interface for checking a mounted filesystem is writable
+type MountScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	logger                *logrus.Logger
+	probe                 func(dir string) (time.Duration, time.Duration, error)
+}
+
+// mountStepError is a failure of one step of the mount probe
+type mountStepError struct {
+	step string
+	err  error
+}
+
+func (e *mountStepError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.step, e.err)
+}
+
+func (e *mountStepError) Unwrap() error {
+	return e.err
+}
+
+// NewMountScraper creates a new mount scraper probing the configured mount path
+func NewMountScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *MountScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &MountScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		logger:                logger,
+		probe:                 probeMount,
+	}
+}
+
+// Type returns the scraper type identifier
+func (m *MountScraper) Type() string {
+	return "mount"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (m *MountScraper) GetPingURL() string {
+	return m.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (m *MountScraper) GetScrapeInterval() int {
+	return m.scrapeIntervalSeconds
+}
+
+// Scrape writes a small temporary file to the mount path, reads it back and deletes it
+func (m *MountScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	path := m.config.MountPath
+	m.logger.WithField("path", path).Debug("Starting mount healthcheck")
+
+	// Operations on a hung network mount can block indefinitely and ignore the context,
+	// so the probe runs in the background and is abandoned once the context is done
+	type outcome struct {
+		write, read time.Duration
+		err         error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		write, read, err := m.probe(path)
+		done <- outcome{write: write, read: read, err: err}
+	}()
+
+	details := map[string]interface{}{
+		"path": path,
+	}
+
+	var result outcome
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		details["error"] = ctx.Err().Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Mount %s did not respond: %v", path, ctx.Err()),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	if result.err != nil {
+		details["error"] = result.err.Error()
+		var stepErr *mountStepError
+		if errors.As(result.err, &stepErr) {
+			details["step"] = stepErr.step
+		}
+		if errors.Is(result.err, syscall.ESTALE) {
+			details["stale"] = true
+		}
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Mount %s is not writable: %v", path, result.err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	details["write_ms"] = result.write.Milliseconds()
+	details["read_ms"] = result.read.Milliseconds()
+	setLatency(details, result.write+result.read)
+
+	m.logger.WithFields(logrus.Fields{
+		"path":       path,
+		"latency_ms": details["latency_ms"],
+	}).Info("Mount healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   true,
+		Message:   fmt.Sprintf("Mount %s is writable", path),
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// probeMount writes random data to a temporary file in dir, reads it back and removes it,
+// returning how long the write and the read took
+func probeMount(dir string) (time.Duration, time.Duration, error) {
+	data := make([]byte, mountProbeSize)
+	rand.Read(data)
+
+	start := time.Now()
+	file, err := os.CreateTemp(dir, ".healthcheck-*")
+	if err != nil {
+		return 0, 0, &mountStepError{step: "create", err: err}
+	}
+	name := file.Name()
+	// Remove the probe even when a later step fails
+	defer os.Remove(name)
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return 0, 0, &mountStepError{step: "write", err: err}
+	}
+	// Sync so the write reaches the server instead of only the local page cache
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return 0, 0, &mountStepError{step: "sync", err: err}
+	}
+	if err := file.Close(); err != nil {
+		return 0, 0, &mountStepError{step: "close", err: err}
+	}
+	write := time.Since(start)
+
+	start = time.Now()
+	read, err := os.ReadFile(name)
+	if err != nil {
+		return 0, 0, &mountStepError{step: "read", err: err}
+	}
+	if !bytes.Equal(read, data) {
+		return 0, 0, &mountStepError{step: "verify", err: fmt.Errorf("read back %d bytes that differ from the %d written", len(read), len(data))}
+	}
+	readDuration := time.Since(start)
+
+	if err := os.Remove(name); err != nil {
+		return 0, 0, &mountStepError{step: "delete", err: err}
+	}
+
+	return write, readDuration, nil
+}
@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMountScraper(t *testing.T) {
+	scraper := NewMountScraper(config.HealthcheckScraper{
+		MountPath: "/mnt/data",
+		PingURL:   "http://localhost:8081/ping",
+	}, logrus.New())
+
+	assert.Equal(t, "mount", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestMountScraper_Scrape_Writable(t *testing.T) {
+	dir := t.TempDir()
+	scraper := NewMountScraper(config.HealthcheckScraper{MountPath: dir}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Contains(t, result.Details, "latency_ms")
+	assert.Contains(t, result.Details, "write_ms")
+	assert.Contains(t, result.Details, "read_ms")
+
+	// The probe file is removed again
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestMountScraper_Scrape_MissingPath(t *testing.T) {
+	scraper := NewMountScraper(config.HealthcheckScraper{MountPath: filepath.Join(t.TempDir(), "missing")}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "create", result.Details["step"])
+}
+
+func TestMountScraper_Scrape_StaleHandle(t *testing.T) {
+	scraper := NewMountScraper(config.HealthcheckScraper{MountPath: "/mnt/data"}, logrus.New())
+	scraper.probe = func(dir string) (time.Duration, time.Duration, error) {
+		return 0, 0, &mountStepError{step: "read", err: &os.PathError{Op: "open", Path: dir, Err: syscall.ESTALE}}
+	}
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "read", result.Details["step"])
+	assert.Equal(t, true, result.Details["stale"])
+}
+
+func TestMountScraper_Scrape_HungMount(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	scraper := NewMountScraper(config.HealthcheckScraper{MountPath: "/mnt/data"}, logrus.New())
+	scraper.probe = func(dir string) (time.Duration, time.Duration, error) {
+		<-release
+		return 0, 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := scraper.Scrape(ctx)
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "did not respond")
+}
@@ -0,0 +1,344 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultPipelineDeadline bounds the round trip when max_latency_ms is not set
+	defaultPipelineDeadline = 10 * time.Second
+	// pipelinePollInterval is how often an HTTP output is polled for the message
+	pipelinePollInterval = 250 * time.Millisecond
+	// pipelineTokenPlaceholder is replaced with the correlation token in pipeline_message
+	pipelineTokenPlaceholder = "{{token}}"
+	// defaultPipelineMessage is published when pipeline_message is not set
+	defaultPipelineMessage = `{"healthcheck_token":"{{token}}"}`
+)
+
+// pipelineInput publishes messages to the input of a pipeline
+type pipelineInput interface {
+	Publish(ctx context.Context, payload []byte) error
+}
+
+// pipelineOutput subscribes to the output of a pipeline
+type pipelineOutput interface {
+	Subscribe(ctx context.Context) (pipelineSubscription, error)
+}
+
+// pipelineSubscription receives the messages arriving at a pipeline's output
+type pipelineSubscription interface {
+	// Await blocks until a message containing the token arrives or the context is done
+	Await(ctx context.Context, token string) error
+	Close() error
+}
+
+// pipelineEndpoints holds the pipeline inputs and outputs keyed by URL scheme
+var pipelineEndpoints = map[string]struct {
+	input  func(u *url.URL, client *http.Client) (pipelineInput, error)
+	output func(u *url.URL, client *http.Client) (pipelineOutput, error)
+}{
+	"http":  {input: newHTTPPipelineInput, output: newHTTPPipelineOutput},
+	"https": {input: newHTTPPipelineInput, output: newHTTPPipelineOutput},
+	"nats":  {input: newNATSPipelineInput, output: newNATSPipelineOutput},
+}
+
+// pipelineSchemes returns the supported pipeline URL schemes sorted by name
+func pipelineSchemes() string {
+	schemes := make([]string, 0, len(pipelineEndpoints))
+	for scheme := range pipelineEndpoints {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return strings.Join(schemes, ", ")
+}
+
+// newPipelineEndpoints creates the input and output of the pipeline from the publish and
+// subscribe URLs
+func newPipelineEndpoints(scraperConfig config.HealthcheckScraper, client *http.Client) (pipelineInput, pipelineOutput, error) {
+	publishURL, err := parsePipelineURL("publish_url", scraperConfig.PublishURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	subscribeURL, err := parsePipelineURL("subscribe_url", scraperConfig.SubscribeURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	input, err := pipelineEndpoints[publishURL.Scheme].input(publishURL, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	output, err := pipelineEndpoints[subscribeURL.Scheme].output(subscribeURL, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return input, output, nil
+}
+
+// parsePipelineURL parses the URL of the named key, checking its scheme is supported
+func parsePipelineURL(key, rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("pipeline scraper requires a %s", key)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid %s: %s", key, rawURL)
+	}
+	if _, ok := pipelineEndpoints[u.Scheme]; !ok {
+		return nil, fmt.Errorf("pipeline scraper has unsupported %s scheme %q, supported: %s", key, u.Scheme, pipelineSchemes())
+	}
+	return u, nil
+}
+
+// PipelineScraper implements the Scraper interface for checking a message published to the
+// input of a processing pipeline arrives at its output
+type PipelineScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	input                 pipelineInput
+	output                pipelineOutput
+	logger                *logrus.Logger
+}
+
+// NewPipelineScraper creates a new pipeline scraper publishing to the input and awaiting
+// messages from the output
+func NewPipelineScraper(scraperConfig config.HealthcheckScraper, input pipelineInput, output pipelineOutput, logger *logrus.Logger) *PipelineScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &PipelineScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		input:                 input,
+		output:                output,
+		logger:                logger,
+	}
+}
+
+// Type returns the scraper type identifier
+func (p *PipelineScraper) Type() string {
+	return "pipeline"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (p *PipelineScraper) GetPingURL() string {
+	return p.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (p *PipelineScraper) GetScrapeInterval() int {
+	return p.scrapeIntervalSeconds
+}
+
+// deadline returns how long a message may take to go through the pipeline
+func (p *PipelineScraper) deadline() time.Duration {
+	if p.config.MaxLatencyMs > 0 {
+		return time.Duration(p.config.MaxLatencyMs) * time.Millisecond
+	}
+	return defaultPipelineDeadline
+}
+
+// Scrape subscribes to the output, publishes a message carrying a unique correlation token
+// to the input and is healthy when a message with the token arrives at the output before
+// the deadline
+func (p *PipelineScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	token, err := newPipelineToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate correlation token: %w", err)
+	}
+	p.logger.WithField("token", token).Debug("Starting pipeline healthcheck")
+
+	details := map[string]interface{}{
+		"token": token,
+	}
+	unhealthy := func(message string, err error) (*ScrapeResult, error) {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("%s: %v", message, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	deadline := p.deadline()
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	// Subscribe first so that a message going through quickly is not missed
+	subscription, err := p.output.Subscribe(ctx)
+	if err != nil {
+		return unhealthy("Failed to subscribe to the pipeline output", err)
+	}
+	defer subscription.Close()
+
+	message := p.config.PipelineMessage
+	if message == "" {
+		message = defaultPipelineMessage
+	}
+	payload := []byte(strings.ReplaceAll(message, pipelineTokenPlaceholder, token))
+
+	start := time.Now()
+	if err := p.input.Publish(ctx, payload); err != nil {
+		return unhealthy("Failed to publish to the pipeline input", err)
+	}
+	details["publish_latency_ms"] = time.Since(start).Milliseconds()
+
+	err = subscription.Await(ctx, token)
+	latency := time.Since(start)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			details["deadline_ms"] = deadline.Milliseconds()
+			return &ScrapeResult{
+				Healthy:   false,
+				Message:   fmt.Sprintf("Message %s did not arrive at the pipeline output within %dms", token, deadline.Milliseconds()),
+				Timestamp: time.Now(),
+				Details:   details,
+			}, nil
+		}
+		return unhealthy("Failed to receive from the pipeline output", err)
+	}
+	details["end_to_end_latency_ms"] = latency.Milliseconds()
+
+	p.logger.WithFields(logrus.Fields{
+		"token":      token,
+		"latency_ms": latency.Milliseconds(),
+	}).Info("Pipeline healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   true,
+		Message:   fmt.Sprintf("Message went through the pipeline in %dms", latency.Milliseconds()),
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// newPipelineToken returns a random correlation token
+func newPipelineToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return "hc-" + hex.EncodeToString(token), nil
+}
+
+// httpPipelineInput publishes messages by POSTing them to a URL, such as a Kafka REST proxy
+// topic or an ingestion API
+type httpPipelineInput struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPPipelineInput(u *url.URL, client *http.Client) (pipelineInput, error) {
+	return &httpPipelineInput{url: u.String(), client: client}, nil
+}
+
+// Publish POSTs the payload and expects a 2xx response
+func (h *httpPipelineInput) Publish(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", h.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// httpPipelineOutput polls a URL, such as a search API over the pipeline's sink, for the message
+type httpPipelineOutput struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPPipelineOutput(u *url.URL, client *http.Client) (pipelineOutput, error) {
+	return &httpPipelineOutput{url: u.String(), client: client}, nil
+}
+
+// Subscribe returns a subscription polling the URL, as HTTP outputs need no setup
+func (h *httpPipelineOutput) Subscribe(ctx context.Context) (pipelineSubscription, error) {
+	return h, nil
+}
+
+// Await polls the URL until its response body contains the token. Failed polls are retried,
+// with the last failure reported if the token never shows up.
+func (h *httpPipelineOutput) Await(ctx context.Context, token string) error {
+	ticker := time.NewTicker(pipelinePollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		found, err := h.poll(ctx, token)
+		if found {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w, last poll failed: %v", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the URL once and reports whether the body contains the token
+func (h *httpPipelineOutput) poll(ctx context.Context, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", h.url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Contains(body, []byte(token)), nil
+}
+
+// Close does nothing as polling holds no resources between requests
+func (h *httpPipelineOutput) Close() error {
+	return nil
+}
@@ -0,0 +1,263 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// natsEndpoint publishes to or subscribes to a NATS subject using the NATS client protocol
+type natsEndpoint struct {
+	address  string
+	subject  string
+	user     string
+	password string
+	token    string
+}
+
+// newNATSEndpoint creates a NATS endpoint from a nats://[user:password@|token@]host[:port]/subject URL
+func newNATSEndpoint(u *url.URL) (*natsEndpoint, error) {
+	subject := strings.Trim(u.Path, "/")
+	if subject == "" {
+		return nil, fmt.Errorf("NATS URL %s requires a subject as its path", u.Redacted())
+	}
+
+	endpoint := &natsEndpoint{
+		address: u.Host,
+		subject: subject,
+	}
+	if u.Port() == "" {
+		endpoint.address = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			endpoint.user, endpoint.password = u.User.Username(), password
+		} else {
+			endpoint.token = u.User.Username()
+		}
+	}
+
+	return endpoint, nil
+}
+
+func newNATSPipelineInput(u *url.URL, _ *http.Client) (pipelineInput, error) {
+	return newNATSEndpoint(u)
+}
+
+func newNATSPipelineOutput(u *url.URL, _ *http.Client) (pipelineOutput, error) {
+	return newNATSEndpoint(u)
+}
+
+// natsConn is a connection to a NATS server that completed the handshake
+type natsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	// stop ends the interruption of reads on context cancellation
+	stop func() bool
+}
+
+// connect dials the server, reads its INFO and sends CONNECT with the credentials. Reads are
+// interrupted once the context is done.
+func (n *natsEndpoint) connect(ctx context.Context) (*natsConn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.address)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c := &natsConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		stop:   context.AfterFunc(ctx, func() { conn.SetDeadline(aLongTimeAgo) }),
+	}
+
+	line, err := c.readLine()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		c.Close()
+		return nil, fmt.Errorf("unexpected greeting: %s", line)
+	}
+
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "healthcheck",
+		"lang":     "go",
+		"protocol": 1,
+	}
+	if n.user != "" {
+		options["user"], options["pass"] = n.user, n.password
+	}
+	if n.token != "" {
+		options["auth_token"] = n.token
+	}
+	connect, _ := json.Marshal(options)
+	if err := c.write("CONNECT " + string(connect) + "\r\n"); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if err := c.flush(); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// aLongTimeAgo is a deadline in the past, failing pending reads immediately
+var aLongTimeAgo = time.Unix(1, 0)
+
+// Publish publishes the payload to the subject and waits for the server to process it
+func (n *natsEndpoint) Publish(ctx context.Context, payload []byte) error {
+	c, err := n.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.write(fmt.Sprintf("PUB %s %d\r\n%s\r\n", n.subject, len(payload), payload)); err != nil {
+		return c.contextError(ctx, err)
+	}
+	return c.contextError(ctx, c.flush())
+}
+
+// Subscribe subscribes to the subject and returns once the server registered the subscription
+func (n *natsEndpoint) Subscribe(ctx context.Context) (pipelineSubscription, error) {
+	c, err := n.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.write(fmt.Sprintf("SUB %s 1\r\n", n.subject)); err != nil {
+		c.Close()
+		return nil, c.contextError(ctx, err)
+	}
+	if err := c.flush(); err != nil {
+		c.Close()
+		return nil, c.contextError(ctx, err)
+	}
+	return c, nil
+}
+
+// Await reads messages until one containing the token arrives, answering the server's PINGs
+func (c *natsConn) Await(ctx context.Context, token string) error {
+	for {
+		payload, err := c.next()
+		if err != nil {
+			return c.contextError(ctx, err)
+		}
+		if strings.Contains(string(payload), token) {
+			return nil
+		}
+	}
+}
+
+// next returns the payload of the next message, answering PINGs and failing on -ERR
+func (c *natsConn) next() ([]byte, error) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <size>
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("malformed MSG: %s", line)
+			}
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return nil, fmt.Errorf("malformed MSG: %s", line)
+			}
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(c.reader, payload); err != nil {
+				return nil, err
+			}
+			return payload[:size], nil
+		case line == "PING":
+			if err := c.write("PONG\r\n"); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return nil, fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// flush sends a PING and waits for its PONG, by which time the server processed every
+// command sent before it
+func (c *natsConn) flush() error {
+	if err := c.write("PING\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if err := c.write("PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// contextError returns the context's error in place of the I/O error it caused.
+// The conn deadline matches the ctx deadline, so the read can time out before
+// ctx.Err() is set; a timeout past the deadline is reported as DeadlineExceeded
+func (c *natsConn) contextError(ctx context.Context, err error) error {
+	var netErr net.Error
+	if err == nil || !errors.As(err, &netErr) || !netErr.Timeout() {
+		return err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+func (c *natsConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *natsConn) write(command string) error {
+	_, err := io.WriteString(c.conn, command)
+	return err
+}
+
+// Close unsubscribes by closing the connection
+func (c *natsConn) Close() error {
+	c.stop()
+	return c.conn.Close()
+}
@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATS is a minimal NATS server standing in for a pipeline: messages published to the
+// input subject are delivered to subscribers of the output subject unless stalled
+type fakeNATS struct {
+	input, output string
+	token         string
+	stalled       bool
+
+	mu          sync.Mutex
+	subscribers map[net.Conn]string
+}
+
+// startFakeNATS serves the fake server, requiring the auth token if set, and returns its address
+func startFakeNATS(t *testing.T, server *fakeNATS) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server.subscribers = make(map[net.Conn]string)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func (f *fakeNATS) serve(conn net.Conn) {
+	defer func() {
+		f.mu.Lock()
+		delete(f.subscribers, conn)
+		f.mu.Unlock()
+		conn.Close()
+	}()
+
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\",\"auth_required\":%t}\r\n", f.token != "")
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "CONNECT":
+			if f.token != "" && !strings.Contains(line, `"auth_token":"`+f.token+`"`) {
+				io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case "PING":
+			io.WriteString(conn, "PONG\r\n")
+		case "SUB":
+			f.mu.Lock()
+			f.subscribers[conn] = fields[1]
+			f.mu.Unlock()
+		case "PUB":
+			size, _ := strconv.Atoi(fields[2])
+			payload := make([]byte, size+2)
+			io.ReadFull(reader, payload)
+			if fields[1] == f.input && !f.stalled {
+				f.deliver(payload[:size])
+			}
+		}
+	}
+}
+
+// deliver sends the payload to the subscribers of the output subject, preceded by a PING
+// as real servers send them at any time
+func (f *fakeNATS) deliver(payload []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for conn, subject := range f.subscribers {
+		if subject == f.output {
+			fmt.Fprintf(conn, "PING\r\nMSG %s 1 %d\r\n%s\r\n", f.output, len(payload), payload)
+		}
+	}
+}
+
+// scrapeNATSPipeline scrapes a pipeline from the input to the output subject of the server
+func scrapeNATSPipeline(t *testing.T, address, credentials string, maxLatencyMs int) *ScrapeResult {
+	scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:         "pipeline",
+		PublishURL:   "nats://" + credentials + address + "/orders.in",
+		SubscribeURL: "nats://" + credentials + address + "/orders.out",
+		MaxLatencyMs: maxLatencyMs,
+	})
+	require.NoError(t, err)
+
+	result, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	return result
+}
+
+func TestPipelineScraper_Scrape_NATS(t *testing.T) {
+	address := startFakeNATS(t, &fakeNATS{input: "orders.in", output: "orders.out", token: "s3cret"})
+
+	result := scrapeNATSPipeline(t, address, "s3cret@", 0)
+
+	assert.True(t, result.Healthy, result.Message)
+	assert.Contains(t, result.Details, "end_to_end_latency_ms")
+}
+
+func TestPipelineScraper_Scrape_NATS_Stalled(t *testing.T) {
+	address := startFakeNATS(t, &fakeNATS{input: "orders.in", output: "orders.out", stalled: true})
+
+	start := time.Now()
+	result := scrapeNATSPipeline(t, address, "", 100)
+
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "did not arrive at the pipeline output within 100ms")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestPipelineScraper_Scrape_NATS_AuthorizationViolation(t *testing.T) {
+	address := startFakeNATS(t, &fakeNATS{input: "orders.in", output: "orders.out", token: "s3cret"})
+
+	result := scrapeNATSPipeline(t, address, "wrong@", 0)
+
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to subscribe to the pipeline output")
+	assert.Contains(t, result.Message, "Authorization Violation")
+}
+
+func TestNewNATSEndpoint(t *testing.T) {
+	u, err := url.Parse("nats://app:pw@nats.internal/events.in")
+	require.NoError(t, err)
+
+	endpoint, err := newNATSEndpoint(u)
+
+	require.NoError(t, err)
+	assert.Equal(t, &natsEndpoint{address: "nats.internal:4222", subject: "events.in", user: "app", password: "pw"}, endpoint)
+}
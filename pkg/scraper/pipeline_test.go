@@ -0,0 +1,161 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePipeline delivers every published payload to its subscription after a delay, dropping
+// them when stalled
+type fakePipeline struct {
+	delay      time.Duration
+	stalled    bool
+	publishErr error
+	messages   chan []byte
+}
+
+func newFakePipeline() *fakePipeline {
+	return &fakePipeline{messages: make(chan []byte, 10)}
+}
+
+func (f *fakePipeline) Publish(ctx context.Context, payload []byte) error {
+	if f.publishErr != nil {
+		return f.publishErr
+	}
+	if !f.stalled {
+		time.AfterFunc(f.delay, func() { f.messages <- payload })
+	}
+	return nil
+}
+
+func (f *fakePipeline) Subscribe(ctx context.Context) (pipelineSubscription, error) {
+	return f, nil
+}
+
+func (f *fakePipeline) Await(ctx context.Context, token string) error {
+	for {
+		select {
+		case payload := <-f.messages:
+			if strings.Contains(string(payload), token) {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (f *fakePipeline) Close() error {
+	return nil
+}
+
+func TestNewPipelineScraper(t *testing.T) {
+	pipeline := newFakePipeline()
+	scraper := NewPipelineScraper(config.HealthcheckScraper{
+		PingURL: "http://localhost:8081/ping",
+	}, pipeline, pipeline, logrus.New())
+
+	assert.Equal(t, "pipeline", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestPipelineScraper_Scrape(t *testing.T) {
+	pipeline := newFakePipeline()
+	pipeline.delay = 20 * time.Millisecond
+	// A message left over from an earlier scrape does not count
+	pipeline.messages <- []byte(`{"healthcheck_token":"hc-earlier"}`)
+	scraper := NewPipelineScraper(config.HealthcheckScraper{}, pipeline, pipeline, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Regexp(t, "^hc-[0-9a-f]{32}$", result.Details["token"])
+	assert.GreaterOrEqual(t, result.Details["end_to_end_latency_ms"], int64(20))
+}
+
+func TestPipelineScraper_Scrape_Stalled(t *testing.T) {
+	pipeline := newFakePipeline()
+	pipeline.stalled = true
+	scraper := NewPipelineScraper(config.HealthcheckScraper{MaxLatencyMs: 50}, pipeline, pipeline, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "did not arrive at the pipeline output within 50ms")
+	assert.Equal(t, int64(50), result.Details["deadline_ms"])
+}
+
+func TestPipelineScraper_Scrape_PublishError(t *testing.T) {
+	pipeline := newFakePipeline()
+	pipeline.publishErr = errors.New("connection refused")
+	scraper := NewPipelineScraper(config.HealthcheckScraper{}, pipeline, pipeline, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to publish to the pipeline input: connection refused")
+}
+
+func TestPipelineScraper_Scrape_HTTP(t *testing.T) {
+	var mu sync.Mutex
+	var stored []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/ingest":
+			body, _ := io.ReadAll(r.Body)
+			stored = append(stored, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == "GET" && r.URL.Path == "/search":
+			w.Write([]byte("[" + strings.Join(stored, ",") + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:            "pipeline",
+		PublishURL:      server.URL + "/ingest",
+		SubscribeURL:    server.URL + "/search",
+		PipelineMessage: `{"records":[{"value":{"token":"{{token}}"}}]}`,
+	})
+	require.NoError(t, err)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Contains(t, stored[0], `{"records":[{"value":{"token":"hc-`)
+}
+
+func TestFactory_CreateScraper_Pipeline_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{Type: "pipeline", SubscribeURL: "nats://localhost/out"})
+	assert.ErrorContains(t, err, "requires a publish_url")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "pipeline", PublishURL: "kafka://localhost/in", SubscribeURL: "nats://localhost/out"})
+	assert.ErrorContains(t, err, "supported: http, https, nats")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "pipeline", PublishURL: "nats://localhost", SubscribeURL: "nats://localhost/out"})
+	assert.ErrorContains(t, err, "requires a subject")
+}
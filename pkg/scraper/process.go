@@ -0,0 +1,155 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// processInfo identifies a running process
+type processInfo struct {
+	PID int
+	// Name is the executable name, which the kernel truncates to 15 characters
+	Name string
+	// Cmdline is the command line with the arguments separated by spaces
+	Cmdline string
+}
+
+// ProcessScraper implements the Scraper interface for checking a named process is running
+type ProcessScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	cmdline               *regexp.Regexp
+	logger                *logrus.Logger
+	list                  func() ([]processInfo, error)
+}
+
+// NewProcessScraper creates a new process scraper matching processes by the configured name
+// and command line pattern
+func NewProcessScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (*ProcessScraper, error) {
+	if scraperConfig.ProcessName == "" && scraperConfig.CmdlinePattern == "" {
+		return nil, fmt.Errorf("process scraper requires a process_name or cmdline_pattern")
+	}
+
+	var cmdline *regexp.Regexp
+	if scraperConfig.CmdlinePattern != "" {
+		var err error
+		if cmdline, err = regexp.Compile(scraperConfig.CmdlinePattern); err != nil {
+			return nil, fmt.Errorf("invalid cmdline_pattern: %w", err)
+		}
+	}
+
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &ProcessScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		cmdline:               cmdline,
+		logger:                logger,
+		list:                  listProcesses,
+	}, nil
+}
+
+// Type returns the scraper type identifier
+func (p *ProcessScraper) Type() string {
+	return "process"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (p *ProcessScraper) GetPingURL() string {
+	return p.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (p *ProcessScraper) GetScrapeInterval() int {
+	return p.scrapeIntervalSeconds
+}
+
+// Scrape lists the running processes and is healthy when at least min_count of them (default
+// 1) match the configured name and command line pattern
+func (p *ProcessScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	p.logger.WithFields(logrus.Fields{
+		"process_name":    p.config.ProcessName,
+		"cmdline_pattern": p.config.CmdlinePattern,
+	}).Debug("Starting process healthcheck")
+
+	minCount := p.config.MinCount
+	if minCount <= 0 {
+		minCount = 1
+	}
+	details := map[string]interface{}{
+		"min_count": minCount,
+	}
+
+	processes, err := p.list()
+	if err != nil {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to list processes: %v", err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	pids := []int{}
+	self := os.Getpid()
+	for _, process := range processes {
+		if process.PID != self && p.matches(process) {
+			pids = append(pids, process.PID)
+		}
+	}
+	sort.Ints(pids)
+
+	details["pids"] = pids
+	details["count"] = len(pids)
+	healthy := len(pids) >= minCount
+
+	p.logger.WithFields(logrus.Fields{
+		"process_name":    p.config.ProcessName,
+		"cmdline_pattern": p.config.CmdlinePattern,
+		"count":           len(pids),
+		"healthy":         healthy,
+	}).Info("Process healthcheck completed")
+
+	message := fmt.Sprintf("%d matching processes running", len(pids))
+	if !healthy {
+		message = fmt.Sprintf("%s, expected at least %d", message, minCount)
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// matches reports whether the process has the configured name and its command line matches
+// the configured pattern, whichever of them are set
+func (p *ProcessScraper) matches(process processInfo) bool {
+	if p.config.ProcessName != "" && !processNameMatches(process, p.config.ProcessName) {
+		return false
+	}
+	return p.cmdline == nil || p.cmdline.MatchString(process.Cmdline)
+}
+
+// processNameMatches reports whether the process has the executable name, of which the kernel
+// only keeps the first 15 characters
+func processNameMatches(process processInfo, name string) bool {
+	if len(name) > 15 {
+		name = name[:15]
+	}
+	return process.Name == name
+}
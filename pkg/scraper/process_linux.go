@@ -0,0 +1,44 @@
+//go:build linux
+
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procDir is where the proc filesystem listing the processes is mounted
+var procDir = "/proc"
+
+// listProcesses lists the running processes from the proc filesystem
+func listProcesses() ([]processInfo, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []processInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		// Processes may exit while being listed
+		comm, err := os.ReadFile(filepath.Join(procDir, entry.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+		cmdline, _ := os.ReadFile(filepath.Join(procDir, entry.Name(), "cmdline"))
+
+		processes = append(processes, processInfo{
+			PID:     pid,
+			Name:    strings.TrimSuffix(string(comm), "\n"),
+			Cmdline: strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " ")),
+		})
+	}
+
+	return processes, nil
+}
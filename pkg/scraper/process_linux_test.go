@@ -0,0 +1,52 @@
+//go:build linux
+
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProcesses(t *testing.T) {
+	dir := t.TempDir()
+	writeProc := func(pid, comm, cmdline string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, pid), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, pid, "comm"), []byte(comm+"\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, pid, "cmdline"), []byte(cmdline), 0o644))
+	}
+	writeProc("1", "init", "/sbin/init\x00")
+	writeProc("42", "nginx", "nginx\x00-g\x00daemon off;\x00")
+	// Kernel threads have an empty command line
+	writeProc("2", "kthreadd", "")
+	// Entries that are not processes are skipped
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sys"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uptime"), []byte("1.0 1.0"), 0o644))
+
+	original := procDir
+	procDir = dir
+	t.Cleanup(func() { procDir = original })
+
+	processes, err := listProcesses()
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []processInfo{
+		{PID: 1, Name: "init", Cmdline: "/sbin/init"},
+		{PID: 2, Name: "kthreadd", Cmdline: ""},
+		{PID: 42, Name: "nginx", Cmdline: "nginx -g daemon off;"},
+	}, processes)
+}
+
+func TestListProcesses_Self(t *testing.T) {
+	processes, err := listProcesses()
+
+	require.NoError(t, err)
+	pids := make([]int, 0, len(processes))
+	for _, process := range processes {
+		pids = append(pids, process.PID)
+	}
+	assert.Contains(t, pids, os.Getpid())
+}
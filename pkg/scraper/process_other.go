@@ -0,0 +1,10 @@
+//go:build !linux
+
+package scraper
+
+import "fmt"
+
+// listProcesses is only supported on Linux, where processes are listed from /proc
+func listProcesses() ([]processInfo, error) {
+	return nil, fmt.Errorf("listing processes is only supported on Linux")
+}
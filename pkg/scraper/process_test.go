@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProcesses is the process list returned to the scrapers under test
+var testProcesses = []processInfo{
+	{PID: 1, Name: "tini", Cmdline: "/sbin/tini -- /docker-entrypoint.sh"},
+	{PID: 42, Name: "nginx", Cmdline: "nginx: master process nginx -g daemon off;"},
+	{PID: 43, Name: "nginx", Cmdline: "nginx: worker process"},
+	{PID: 44, Name: "nginx", Cmdline: "nginx: worker process"},
+	{PID: 70, Name: "postgres-export", Cmdline: "/usr/local/bin/postgres-exporter-v2 --web.listen-address=:9187"},
+}
+
+// newTestProcessScraper creates a process scraper listing the test processes
+func newTestProcessScraper(t *testing.T, scraperConfig config.HealthcheckScraper) *ProcessScraper {
+	scraper, err := NewProcessScraper(scraperConfig, logrus.New())
+	require.NoError(t, err)
+	scraper.list = func() ([]processInfo, error) {
+		return testProcesses, nil
+	}
+	return scraper
+}
+
+func TestNewProcessScraper(t *testing.T) {
+	scraper, err := NewProcessScraper(config.HealthcheckScraper{
+		ProcessName: "nginx",
+		PingURL:     "http://localhost:8081/ping",
+	}, logrus.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, "process", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestNewProcessScraper_Validation(t *testing.T) {
+	_, err := NewProcessScraper(config.HealthcheckScraper{}, logrus.New())
+	assert.ErrorContains(t, err, "requires a process_name or cmdline_pattern")
+
+	_, err = NewProcessScraper(config.HealthcheckScraper{CmdlinePattern: "("}, logrus.New())
+	assert.ErrorContains(t, err, "invalid cmdline_pattern")
+}
+
+func TestProcessScraper_Scrape(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.HealthcheckScraper
+		pids    []int
+		healthy bool
+	}{
+		{name: "by name", config: config.HealthcheckScraper{ProcessName: "nginx"}, pids: []int{42, 43, 44}, healthy: true},
+		{name: "by cmdline", config: config.HealthcheckScraper{CmdlinePattern: "worker process$"}, pids: []int{43, 44}, healthy: true},
+		{name: "by name and cmdline", config: config.HealthcheckScraper{ProcessName: "nginx", CmdlinePattern: "master"}, pids: []int{42}, healthy: true},
+		{name: "name truncated by the kernel", config: config.HealthcheckScraper{ProcessName: "postgres-exporter-v2"}, pids: []int{70}, healthy: true},
+		{name: "minimum count met", config: config.HealthcheckScraper{CmdlinePattern: "worker", MinCount: 2}, pids: []int{43, 44}, healthy: true},
+		{name: "below minimum count", config: config.HealthcheckScraper{CmdlinePattern: "worker", MinCount: 4}, pids: []int{43, 44}, healthy: false},
+		{name: "not running", config: config.HealthcheckScraper{ProcessName: "redis-server"}, pids: []int{}, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := newTestProcessScraper(t, tt.config)
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.pids, result.Details["pids"])
+			assert.Equal(t, len(tt.pids), result.Details["count"])
+		})
+	}
+}
+
+func TestProcessScraper_Scrape_ListError(t *testing.T) {
+	scraper := newTestProcessScraper(t, config.HealthcheckScraper{ProcessName: "nginx"})
+	scraper.list = func() ([]processInfo, error) {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to list processes")
+}
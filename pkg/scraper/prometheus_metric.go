@@ -0,0 +1,206 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// metricOperators maps the supported operators to the comparison a healthy value must satisfy
+var metricOperators = map[string]func(value, threshold float64) bool{
+	">":  func(value, threshold float64) bool { return value > threshold },
+	">=": func(value, threshold float64) bool { return value >= threshold },
+	"<":  func(value, threshold float64) bool { return value < threshold },
+	"<=": func(value, threshold float64) bool { return value <= threshold },
+	"==": func(value, threshold float64) bool { return value == threshold },
+	"!=": func(value, threshold float64) bool { return value != threshold },
+}
+
+// PrometheusMetricScraper implements the Scraper interface for checking a metric of a
+// Prometheus exposition endpoint against a threshold
+type PrometheusMetricScraper struct {
+	httpOptions
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewPrometheusMetricScraper creates a new Prometheus metric scraper
+func NewPrometheusMetricScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *PrometheusMetricScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &PrometheusMetricScraper{
+		httpOptions:           newHTTPOptions(scraperConfig),
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Type returns the scraper type identifier
+func (p *PrometheusMetricScraper) Type() string {
+	return "prometheus-metric"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (p *PrometheusMetricScraper) GetPingURL() string {
+	return p.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (p *PrometheusMetricScraper) GetScrapeInterval() int {
+	return p.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (p *PrometheusMetricScraper) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape fetches the exposition from the scrape URL and compares every series of the
+// configured metric matching the label matchers against the threshold
+func (p *PrometheusMetricScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	scrapeURL := p.config.ScrapeURL
+	p.logger.WithField("url", scrapeURL).Debug("Starting Prometheus metric healthcheck")
+
+	compare, ok := metricOperators[p.config.Operator]
+	if !ok {
+		return nil, fmt.Errorf("unsupported operator: %s", p.config.Operator)
+	}
+	if p.config.Threshold == nil {
+		return nil, fmt.Errorf("no threshold configured")
+	}
+	threshold := *p.config.Threshold
+
+	req, err := http.NewRequestWithContext(ctx, "GET", scrapeURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return p.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to connect to %s: %v", scrapeURL, err),
+			Timestamp: time.Now(),
+			Details: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	details := map[string]interface{}{
+		DetailStatusCode: resp.StatusCode,
+		"metric_name":    p.config.MetricName,
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return p.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, scrapeURL),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	samples, err := parsePrometheusText(resp.Body)
+	if err != nil {
+		details["error"] = err.Error()
+		return p.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to parse metrics from %s: %v", scrapeURL, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	var matched []metricSample
+	for _, sample := range samples {
+		if sample.matches(p.config.MetricName, p.config.Labels) {
+			matched = append(matched, sample)
+		}
+	}
+
+	// A missing metric usually means a misconfiguration or a restarted target, so report it
+	// separately from a breached threshold
+	if len(matched) == 0 {
+		details["missing"] = true
+		return p.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Metric %s%s not found at %s", p.config.MetricName, formatLabels(p.config.Labels), scrapeURL),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	healthy := true
+	observed := matched[0]
+	for _, sample := range matched {
+		if !compare(sample.value, threshold) {
+			healthy = false
+			observed = sample
+			break
+		}
+	}
+
+	details["value"] = observed.value
+	details["labels"] = observed.labels
+	details["matched_series"] = len(matched)
+
+	message := fmt.Sprintf("Metric %s%s is %g, %s %g", p.config.MetricName, formatLabels(observed.labels), observed.value, p.config.Operator, threshold)
+	if !healthy {
+		message = fmt.Sprintf("Metric %s%s is %g, expected %s %g", p.config.MetricName, formatLabels(observed.labels), observed.value, p.config.Operator, threshold)
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"url":     scrapeURL,
+		"metric":  p.config.MetricName,
+		"value":   observed.value,
+		"healthy": healthy,
+	}).Info("Prometheus metric healthcheck completed")
+
+	return p.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, resp), nil
+}
+
+// formatLabels formats labels like a Prometheus selector, sorted by label name
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metricsBody = `# HELP queue_depth Jobs waiting in the queue.
+# TYPE queue_depth gauge
+queue_depth{queue="emails"} 12
+queue_depth{queue="reports"} 250
+# HELP up Whether the app is up.
+# TYPE up gauge
+up 1
+`
+
+// float64Ptr returns a pointer to the given threshold
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func TestNewPrometheusMetricScraper(t *testing.T) {
+	scraper := NewPrometheusMetricScraper(config.HealthcheckScraper{
+		ScrapeURL:  "http://localhost:8080/metrics",
+		PingURL:    "http://localhost:8081/ping",
+		MetricName: "up",
+		Operator:   "==",
+		Threshold:  float64Ptr(1),
+	}, logrus.New())
+
+	assert.Equal(t, "prometheus-metric", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestPrometheusMetricScraper_Scrape(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "text/plain; version=0.0.4", metricsBody))
+
+	tests := []struct {
+		name      string
+		metric    string
+		labels    map[string]string
+		operator  string
+		threshold float64
+		healthy   bool
+		value     float64
+	}{
+		{name: "unlabelled gauge", metric: "up", operator: "==", threshold: 1, healthy: true, value: 1},
+		{name: "label matcher within threshold", metric: "queue_depth", labels: map[string]string{"queue": "emails"}, operator: "<", threshold: 100, healthy: true, value: 12},
+		{name: "label matcher breaching threshold", metric: "queue_depth", labels: map[string]string{"queue": "reports"}, operator: "<", threshold: 100, healthy: false, value: 250},
+		{name: "any series breaching threshold", metric: "queue_depth", operator: "<=", threshold: 100, healthy: false, value: 250},
+		{name: "all series within threshold", metric: "queue_depth", operator: ">", threshold: 10, healthy: true, value: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewPrometheusMetricScraper(config.HealthcheckScraper{
+				ScrapeURL:  server.URL,
+				MetricName: tt.metric,
+				Labels:     tt.labels,
+				Operator:   tt.operator,
+				Threshold:  float64Ptr(tt.threshold),
+			}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.value, result.Details["value"])
+			assert.NotContains(t, result.Details, "missing")
+		})
+	}
+}
+
+func TestPrometheusMetricScraper_Scrape_MissingMetric(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "text/plain; version=0.0.4", metricsBody))
+
+	scraper := NewPrometheusMetricScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		MetricName: "queue_depth",
+		Labels:     map[string]string{"queue": "invoices"},
+		Operator:   "<",
+		Threshold:  float64Ptr(100),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, true, result.Details["missing"])
+	assert.Contains(t, result.Message, `queue_depth{queue="invoices"} not found`)
+	assert.NotContains(t, result.Details, "value")
+}
+
+func TestPrometheusMetricScraper_Scrape_InvalidExposition(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "text/plain; version=0.0.4", "up{job=api} 1\n"))
+
+	scraper := NewPrometheusMetricScraper(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		MetricName: "up",
+		Operator:   "==",
+		Threshold:  float64Ptr(1),
+	}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to parse metrics")
+}
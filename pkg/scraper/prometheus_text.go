@@ -0,0 +1,149 @@
+package scraper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// metricSample is a single sample of the Prometheus text exposition format
+type metricSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// matches reports whether the sample has the given name and carries all given labels
+func (s metricSample) matches(name string, labels map[string]string) bool {
+	if s.name != name {
+		return false
+	}
+	for key, value := range labels {
+		if s.labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePrometheusText parses the samples of a Prometheus text exposition body,
+// skipping comments and blank lines
+func parsePrometheusText(r io.Reader) ([]metricSample, error) {
+	var samples []metricSample
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sample, err := parseSampleLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		samples = append(samples, sample)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// parseSampleLine parses a line of the form name{label="value",...} value [timestamp]
+func parseSampleLine(line string) (metricSample, error) {
+	sample := metricSample{labels: make(map[string]string)}
+
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd <= 0 {
+		return sample, fmt.Errorf("invalid sample %q", line)
+	}
+	sample.name = line[:nameEnd]
+	rest := line[nameEnd:]
+
+	if strings.HasPrefix(rest, "{") {
+		labels, remaining, err := parseLabels(rest[1:])
+		if err != nil {
+			return sample, err
+		}
+		sample.labels = labels
+		rest = remaining
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return sample, fmt.Errorf("invalid sample %q", line)
+	}
+
+	value, err := parseSampleValue(fields[0])
+	if err != nil {
+		return sample, fmt.Errorf("invalid value for %s: %w", sample.name, err)
+	}
+	sample.value = value
+
+	return sample, nil
+}
+
+// parseLabels parses a label set up to its closing brace and returns the remaining input
+func parseLabels(input string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+
+	for {
+		input = strings.TrimLeft(input, " \t,")
+		if strings.HasPrefix(input, "}") {
+			return labels, input[1:], nil
+		}
+
+		eq := strings.Index(input, "=")
+		if eq <= 0 {
+			return nil, "", fmt.Errorf("invalid label set")
+		}
+		key := strings.TrimSpace(input[:eq])
+		input = strings.TrimLeft(input[eq+1:], " \t")
+
+		if !strings.HasPrefix(input, `"`) {
+			return nil, "", fmt.Errorf("label %s has an unquoted value", key)
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(input) && input[i] != '"'; i++ {
+			if input[i] == '\\' && i+1 < len(input) {
+				i++
+				switch input[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(input[i])
+				}
+				continue
+			}
+			value.WriteByte(input[i])
+		}
+		if i >= len(input) {
+			return nil, "", fmt.Errorf("label %s has an unterminated value", key)
+		}
+
+		labels[key] = value.String()
+		input = input[i+1:]
+	}
+}
+
+// parseSampleValue parses a sample value including the special values +Inf, -Inf and NaN
+func parseSampleValue(value string) (float64, error) {
+	switch value {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
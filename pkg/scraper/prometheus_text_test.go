@@ -0,0 +1,67 @@
+package scraper
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleExposition = `# HELP http_requests_total Total HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",code="200"} 1027 1395066363000
+http_requests_total{method="POST",code="500"} 3
+
+# A plain gauge without labels
+queue_depth 42
+escaped{path="C:\\dir\"x\"",note="a\nb"} 1.5e3
+up +Inf
+`
+
+func TestParsePrometheusText(t *testing.T) {
+	samples, err := parsePrometheusText(strings.NewReader(sampleExposition))
+
+	require.NoError(t, err)
+	require.Len(t, samples, 5)
+
+	assert.Equal(t, "http_requests_total", samples[0].name)
+	assert.Equal(t, map[string]string{"method": "GET", "code": "200"}, samples[0].labels)
+	assert.Equal(t, 1027.0, samples[0].value)
+
+	assert.Equal(t, "queue_depth", samples[2].name)
+	assert.Empty(t, samples[2].labels)
+	assert.Equal(t, 42.0, samples[2].value)
+
+	assert.Equal(t, `C:\dir"x"`, samples[3].labels["path"])
+	assert.Equal(t, "a\nb", samples[3].labels["note"])
+	assert.Equal(t, 1500.0, samples[3].value)
+
+	assert.True(t, math.IsInf(samples[4].value, 1))
+}
+
+func TestParsePrometheusText_Invalid(t *testing.T) {
+	tests := []string{
+		`metric{label="value" 1`,
+		`metric{label=value} 1`,
+		`metric not-a-number`,
+		`metric`,
+	}
+
+	for _, body := range tests {
+		t.Run(body, func(t *testing.T) {
+			_, err := parsePrometheusText(strings.NewReader(body))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMetricSample_Matches(t *testing.T) {
+	sample := metricSample{name: "up", labels: map[string]string{"job": "api", "instance": "a"}}
+
+	assert.True(t, sample.matches("up", nil))
+	assert.True(t, sample.matches("up", map[string]string{"job": "api"}))
+	assert.False(t, sample.matches("up", map[string]string{"job": "db"}))
+	assert.False(t, sample.matches("down", nil))
+}
@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// queueBackend reads the current depth of a queue
+type queueBackend interface {
+	Depth(ctx context.Context) (int64, error)
+}
+
+// queueBackends holds the queue backends keyed by their queue_backend identifier
+var queueBackends = map[string]func(scraperConfig config.HealthcheckScraper, client *http.Client) (queueBackend, error){
+	"rabbitmq": newRabbitMQQueue,
+	"redis":    newRedisQueue,
+	"sqs":      newSQSQueue,
+}
+
+// queueBackendNames returns the supported queue backends sorted by name
+func queueBackendNames() string {
+	names := make([]string, 0, len(queueBackends))
+	for name := range queueBackends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// QueueDepthScraper implements the Scraper interface for alerting on queue backlogs
+type QueueDepthScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	backend               queueBackend
+	logger                *logrus.Logger
+}
+
+// NewQueueDepthScraper creates a new queue depth scraper reading depths from the given backend
+func NewQueueDepthScraper(scraperConfig config.HealthcheckScraper, backend queueBackend, logger *logrus.Logger) *QueueDepthScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &QueueDepthScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		backend:               backend,
+		logger:                logger,
+	}
+}
+
+// Type returns the scraper type identifier
+func (q *QueueDepthScraper) Type() string {
+	return "queue-depth"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (q *QueueDepthScraper) GetPingURL() string {
+	return q.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (q *QueueDepthScraper) GetScrapeInterval() int {
+	return q.scrapeIntervalSeconds
+}
+
+// Close closes the connections held by the queue backend, if any
+func (q *QueueDepthScraper) Close() error {
+	if closer, ok := q.backend.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Scrape reads the queue depth and compares it against the maximum depth
+func (q *QueueDepthScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	q.logger.WithFields(logrus.Fields{
+		"backend": q.config.QueueBackend,
+		"queue":   q.config.QueueName,
+	}).Debug("Starting queue depth healthcheck")
+
+	details := map[string]interface{}{
+		"backend":   q.config.QueueBackend,
+		"max_depth": q.config.MaxDepth,
+	}
+
+	depth, err := q.backend.Depth(ctx)
+	if err != nil {
+		details["error"] = err.Error()
+		message := fmt.Sprintf("Failed to read %s queue depth: %v", q.config.QueueBackend, err)
+		if errors.Is(err, errEmptyResponse) {
+			message = parseFailure(q.config.ScrapeURL, err, details)
+		}
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	details["depth"] = depth
+	healthy := depth <= q.config.MaxDepth
+
+	message := fmt.Sprintf("Queue depth %d is within the maximum of %d", depth, q.config.MaxDepth)
+	if !healthy {
+		message = fmt.Sprintf("Queue depth %d exceeds the maximum of %d", depth, q.config.MaxDepth)
+	}
+
+	q.logger.WithFields(logrus.Fields{
+		"backend": q.config.QueueBackend,
+		"depth":   depth,
+		"healthy": healthy,
+	}).Info("Queue depth healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQueueBackend returns a fixed depth or error
+type fakeQueueBackend struct {
+	depth int64
+	err   error
+}
+
+func (f *fakeQueueBackend) Depth(ctx context.Context) (int64, error) {
+	return f.depth, f.err
+}
+
+func TestNewQueueDepthScraper(t *testing.T) {
+	scraper := NewQueueDepthScraper(config.HealthcheckScraper{
+		PingURL:      "http://localhost:8081/ping",
+		QueueBackend: "redis",
+	}, &fakeQueueBackend{}, logrus.New())
+
+	assert.Equal(t, "queue-depth", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestQueueDepthScraper_Scrape(t *testing.T) {
+	tests := []struct {
+		name    string
+		depth   int64
+		healthy bool
+	}{
+		{name: "below maximum", depth: 5, healthy: true},
+		{name: "at maximum", depth: 100, healthy: true},
+		{name: "above maximum", depth: 101, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewQueueDepthScraper(config.HealthcheckScraper{
+				QueueBackend: "redis",
+				MaxDepth:     100,
+			}, &fakeQueueBackend{depth: tt.depth}, logrus.New())
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.depth, result.Details["depth"])
+			assert.Equal(t, int64(100), result.Details["max_depth"])
+		})
+	}
+}
+
+func TestQueueDepthScraper_Scrape_BackendError(t *testing.T) {
+	scraper := NewQueueDepthScraper(config.HealthcheckScraper{
+		QueueBackend: "sqs",
+		MaxDepth:     100,
+	}, &fakeQueueBackend{err: errors.New("access denied")}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "access denied")
+	assert.NotContains(t, result.Details, "depth")
+}
+
+func TestQueueDepthScraper_Scrape_EmptyResponse(t *testing.T) {
+	scraper := NewQueueDepthScraper(config.HealthcheckScraper{
+		QueueBackend: "sqs",
+		ScrapeURL:    "https://sqs.eu-west-1.amazonaws.com/123456789012/orders",
+		MaxDepth:     100,
+	}, &fakeQueueBackend{err: fmt.Errorf("failed to parse response: %w", errEmptyResponse)}, logrus.New())
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "Empty response from https://sqs.eu-west-1.amazonaws.com/123456789012/orders", result.Message)
+	assert.Equal(t, "parse", result.Details["error_type"])
+}
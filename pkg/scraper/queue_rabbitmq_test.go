@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRabbitMQQueue_Depth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "guest" || password != "guest" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.RawPath != "/api/queues/%2F/orders" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name":"orders","messages":1500,"messages_ready":1400}`))
+	}))
+	defer server.Close()
+
+	backend, err := newRabbitMQQueue(config.HealthcheckScraper{
+		ScrapeURL: "http://guest:guest@" + server.Listener.Addr().String(),
+		QueueName: "orders",
+	}, server.Client())
+	require.NoError(t, err)
+
+	depth, err := backend.Depth(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), depth)
+}
+
+func TestRabbitMQQueue_Depth_NoStatistics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"orders"}`))
+	}))
+	defer server.Close()
+
+	backend, err := newRabbitMQQueue(config.HealthcheckScraper{
+		ScrapeURL:  server.URL,
+		QueueName:  "orders",
+		QueueVHost: "production",
+	}, server.Client())
+	require.NoError(t, err)
+
+	_, err = backend.Depth(context.Background())
+
+	assert.ErrorContains(t, err, "not available yet")
+}
+
+func TestRabbitMQQueue_Depth_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	backend, err := newRabbitMQQueue(config.HealthcheckScraper{
+		ScrapeURL: server.URL,
+		QueueName: "missing",
+	}, server.Client())
+	require.NoError(t, err)
+
+	_, err = backend.Depth(context.Background())
+
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,155 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"healthcheck/pkg/config"
+)
+
+// redisQueue reads the length of a Redis list with LLEN
+type redisQueue struct {
+	address  string
+	username string
+	password string
+	database int
+	key      string
+}
+
+// newRedisQueue creates a Redis list backend from a redis://[user:password@]host:port[/db] URL
+func newRedisQueue(scraperConfig config.HealthcheckScraper, _ *http.Client) (queueBackend, error) {
+	u, err := url.Parse(scraperConfig.ScrapeURL)
+	if err != nil || u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("invalid redis URL: %s", scraperConfig.ScrapeURL)
+	}
+	if scraperConfig.QueueName == "" {
+		return nil, fmt.Errorf("redis queue backend requires a queue_name")
+	}
+
+	queue := &redisQueue{
+		address: u.Host,
+		key:     scraperConfig.QueueName,
+	}
+	if u.Port() == "" {
+		queue.address = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	if u.User != nil {
+		queue.username = u.User.Username()
+		queue.password, _ = u.User.Password()
+	}
+
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		queue.database, err = strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis database: %s", db)
+		}
+	}
+
+	return queue, nil
+}
+
+// Depth connects to Redis, authenticates if configured and returns the list length
+func (r *redisQueue) Depth(ctx context.Context) (int64, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", r.address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	reader := bufio.NewReader(conn)
+	if err := r.prepare(conn, reader); err != nil {
+		return 0, err
+	}
+
+	reply, err := redisCommand(conn, reader, "LLEN", r.key)
+	if err != nil {
+		return 0, err
+	}
+
+	length, err := strconv.ParseInt(reply, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected LLEN reply: %s", reply)
+	}
+
+	return length, nil
+}
+
+// prepare authenticates and selects the database on a new connection if configured
+func (r *redisQueue) prepare(conn net.Conn, reader *bufio.Reader) error {
+	if r.password != "" {
+		args := []string{"AUTH", r.password}
+		if r.username != "" {
+			args = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := redisCommand(conn, reader, args...); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if r.database != 0 {
+		if _, err := redisCommand(conn, reader, "SELECT", strconv.Itoa(r.database)); err != nil {
+			return fmt.Errorf("failed to select database %d: %w", r.database, err)
+		}
+	}
+
+	return nil
+}
+
+// redisCommand sends a command in the RESP protocol and returns its simple string, integer
+// or bulk string reply
+func redisCommand(conn net.Conn, reader *bufio.Reader, args ...string) (string, error) {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := conn.Write([]byte(command.String())); err != nil {
+		return "", err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+
+	if line == "" {
+		return "", fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("redis error: %s", line[1:])
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("unexpected reply: %s", line)
+		}
+		if length < 0 {
+			return "", fmt.Errorf("key not found")
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return "", err
+		}
+		return string(data[:length]), nil
+	default:
+		return "", fmt.Errorf("unexpected reply: %s", line)
+	}
+}
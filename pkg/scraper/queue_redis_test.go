@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeRedis serves a minimal RESP server with the given keys, requiring the password
+// if set. LLEN replies with a key's value as the list length.
+func startFakeRedis(t *testing.T, password string, keys map[string]string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn, password, keys)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveFakeRedis(conn net.Conn, password string, keys map[string]string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := password == ""
+
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+
+		args := make([]string, count)
+		for i := range args {
+			reader.ReadString('\n')
+			arg, _ := reader.ReadString('\n')
+			args[i] = strings.TrimSpace(arg)
+		}
+
+		switch {
+		case args[0] == "AUTH" && args[len(args)-1] == password:
+			authenticated = true
+			conn.Write([]byte("+OK\r\n"))
+		case args[0] == "AUTH":
+			conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+		case !authenticated:
+			conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+		case args[0] == "SELECT":
+			conn.Write([]byte("+OK\r\n"))
+		case args[0] == "LLEN":
+			conn.Write([]byte(":" + keys[args[1]] + "\r\n"))
+		case args[0] == "GET":
+			value, ok := keys[args[1]]
+			if !ok {
+				conn.Write([]byte("$-1\r\n"))
+				continue
+			}
+			conn.Write([]byte("$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"))
+		default:
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+	}
+}
+
+func TestRedisQueue_Depth(t *testing.T) {
+	address := startFakeRedis(t, "", map[string]string{"jobs": "42"})
+
+	backend, err := newRedisQueue(config.HealthcheckScraper{
+		ScrapeURL: "redis://" + address + "/2",
+		QueueName: "jobs",
+	}, nil)
+	require.NoError(t, err)
+
+	depth, err := backend.Depth(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), depth)
+}
+
+func TestRedisQueue_Depth_Auth(t *testing.T) {
+	address := startFakeRedis(t, "secret", map[string]string{"jobs": "7"})
+
+	backend, err := newRedisQueue(config.HealthcheckScraper{
+		ScrapeURL: "redis://:secret@" + address,
+		QueueName: "jobs",
+	}, nil)
+	require.NoError(t, err)
+
+	depth, err := backend.Depth(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), depth)
+
+	backend, err = newRedisQueue(config.HealthcheckScraper{
+		ScrapeURL: "redis://:wrong@" + address,
+		QueueName: "jobs",
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = backend.Depth(context.Background())
+
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestNewRedisQueue_Invalid(t *testing.T) {
+	tests := []config.HealthcheckScraper{
+		{ScrapeURL: "http://localhost:6379", QueueName: "jobs"},
+		{ScrapeURL: "redis://localhost:6379"},
+		{ScrapeURL: "redis://localhost:6379/zero", QueueName: "jobs"},
+	}
+
+	for _, scraperConfig := range tests {
+		_, err := newRedisQueue(scraperConfig, nil)
+		assert.Error(t, err, scraperConfig.ScrapeURL)
+	}
+}
+
+func TestNewRedisQueue_DefaultPort(t *testing.T) {
+	backend, err := newRedisQueue(config.HealthcheckScraper{
+		ScrapeURL: "redis://redis.internal",
+		QueueName: "jobs",
+	}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "redis.internal:6379", backend.(*redisQueue).address)
+}
@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+// sqsQueue reads the approximate number of messages of an SQS queue
+type sqsQueue struct {
+	queueURL string
+	endpoint string
+	region   string
+	creds    awsCredentials
+	client   *http.Client
+}
+
+// newSQSQueue creates an SQS backend for the queue URL, signing requests with the
+// credentials from the standard AWS environment variables
+func newSQSQueue(scraperConfig config.HealthcheckScraper, client *http.Client) (queueBackend, error) {
+	u, err := url.Parse(scraperConfig.ScrapeURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid SQS queue URL: %s", scraperConfig.ScrapeURL)
+	}
+
+	// Queue URLs look like https://sqs.<region>.amazonaws.com/<account>/<queue>
+	region := scraperConfig.AWSRegion
+	if region == "" {
+		parts := strings.Split(u.Hostname(), ".")
+		if len(parts) < 4 || parts[0] != "sqs" {
+			return nil, fmt.Errorf("cannot determine the region of %s, set aws_region", scraperConfig.ScrapeURL)
+		}
+		region = parts[1]
+	}
+
+	creds, err := awsCredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsQueue{
+		queueURL: scraperConfig.ScrapeURL,
+		endpoint: u.Scheme + "://" + u.Host + "/",
+		region:   region,
+		creds:    creds,
+		client:   client,
+	}, nil
+}
+
+// Depth returns the ApproximateNumberOfMessages attribute of the queue
+func (s *sqsQueue) Depth(ctx context.Context) (int64, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"QueueUrl":       s.queueURL,
+		"AttributeNames": []string{"ApproximateNumberOfMessages"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS.GetQueueAttributes")
+	signAWSRequest(req, body, s.creds, s.region, "sqs", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("HTTP status %d from SQS: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var attributes struct {
+		Attributes map[string]string `json:"Attributes"`
+	}
+	if err := decodeJSON(resp.Body, &attributes); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	value, ok := attributes.Attributes["ApproximateNumberOfMessages"]
+	if !ok {
+		return 0, fmt.Errorf("response has no ApproximateNumberOfMessages attribute")
+	}
+
+	depth, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ApproximateNumberOfMessages %q", value)
+	}
+
+	return depth, nil
+}
+
+// Close closes the idle connections of the backend's HTTP client
+func (s *sqsQueue) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setTestAWSCredentials sets the AWS credential environment variables for a test
+func setTestAWSCredentials(t *testing.T) {
+	os.Setenv("AWS_ACCESS_KEY_ID", testAWSCredentials.accessKeyID)
+	os.Setenv("AWS_SECRET_ACCESS_KEY", testAWSCredentials.secretAccessKey)
+	t.Cleanup(func() {
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	})
+}
+
+func TestSQSQueue_Depth(t *testing.T) {
+	setTestAWSCredentials(t)
+
+	var gotQueueURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "AmazonSQS.GetQueueAttributes" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/sqs/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		gotQueueURL, _ = req["QueueUrl"].(string)
+		w.Write([]byte(`{"Attributes":{"ApproximateNumberOfMessages":"37"}}`))
+	}))
+	defer server.Close()
+
+	backend, err := newSQSQueue(config.HealthcheckScraper{
+		ScrapeURL: server.URL + "/123456789012/orders",
+		AWSRegion: "eu-west-1",
+	}, server.Client())
+	require.NoError(t, err)
+
+	depth, err := backend.Depth(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(37), depth)
+	assert.Equal(t, server.URL+"/123456789012/orders", gotQueueURL)
+}
+
+func TestSQSQueue_Depth_Error(t *testing.T) {
+	setTestAWSCredentials(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.sqs#QueueDoesNotExist"}`))
+	}))
+	defer server.Close()
+
+	backend, err := newSQSQueue(config.HealthcheckScraper{
+		ScrapeURL: server.URL + "/123456789012/missing",
+		AWSRegion: "eu-west-1",
+	}, server.Client())
+	require.NoError(t, err)
+
+	_, err = backend.Depth(context.Background())
+
+	assert.ErrorContains(t, err, "QueueDoesNotExist")
+}
+
+func TestNewSQSQueue_Region(t *testing.T) {
+	setTestAWSCredentials(t)
+
+	backend, err := newSQSQueue(config.HealthcheckScraper{
+		ScrapeURL: "https://sqs.ap-southeast-2.amazonaws.com/123456789012/orders",
+	}, http.DefaultClient)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ap-southeast-2", backend.(*sqsQueue).region)
+	assert.Equal(t, "https://sqs.ap-southeast-2.amazonaws.com/", backend.(*sqsQueue).endpoint)
+
+	_, err = newSQSQueue(config.HealthcheckScraper{
+		ScrapeURL: "http://localhost:4566/000000000000/orders",
+	}, http.DefaultClient)
+
+	assert.ErrorContains(t, err, "aws_region")
+}
+
+func TestSQSQueue_Depth_EmptyResponse(t *testing.T) {
+	setTestAWSCredentials(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	backend, err := newSQSQueue(config.HealthcheckScraper{
+		ScrapeURL: server.URL + "/123456789012/orders",
+		AWSRegion: "eu-west-1",
+	}, server.Client())
+	require.NoError(t, err)
+
+	_, err = backend.Depth(context.Background())
+
+	assert.ErrorIs(t, err, errEmptyResponse)
+}
@@ -0,0 +1,449 @@
+package scraper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// registration describes a scraper type the factory can create
+type registration struct {
+	description string
+	create      func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error)
+}
+
+// TypeInfo describes a registered scraper type
+type TypeInfo struct {
+	Type        string
+	Description string
+}
+
+// registry holds all scraper types keyed by their type identifier
+var registry = map[string]registration{
+	"aws-health": {
+		description: "Checks a CloudWatch alarm is not in the ALARM state",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.AlarmName == "" {
+				return nil, fmt.Errorf("aws-health scraper requires an alarm_name")
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			cloudWatch, err := newCloudWatchAPI(scraperConfig, client)
+			if err != nil {
+				return nil, err
+			}
+			return NewAWSHealthScraper(scraperConfig, cloudWatch, logger), nil
+		},
+	},
+	"cloudflared-tunnel-connector": {
+		description: "Checks a cloudflared tunnel connector via its /ready metrics endpoint",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			s := NewCloudflaredTunnelScraper(scraperConfig.ScrapeURL, scraperConfig.PingURL, scraperConfig.ScrapeIntervalSeconds, logger)
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s.client = client
+			s.httpOptions = newHTTPOptions(scraperConfig)
+			return s, nil
+		},
+	},
+	"dns-consistency": {
+		description: "Checks several DNS resolvers agree on the addresses of a hostname",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.Hostname == "" {
+				return nil, fmt.Errorf("dns-consistency scraper requires a hostname")
+			}
+			if len(scraperConfig.Resolvers) < 2 {
+				return nil, fmt.Errorf("dns-consistency scraper requires at least 2 resolvers")
+			}
+			return NewDNSConsistencyScraper(scraperConfig.Hostname, scraperConfig.Resolvers, scraperConfig.PingURL, scraperConfig.ScrapeIntervalSeconds, logger), nil
+		},
+	},
+	"fd-usage": {
+		description: "Checks a process uses at most a share of its open file descriptors limit",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			s, err := NewFDUsageScraper(scraperConfig, logger)
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		},
+	},
+	"graphql": {
+		description: "Checks a GraphQL query, or an introspection query by default, succeeds without errors",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewGraphQLScraper(scraperConfig, logger)
+			s.client = client
+			return s, nil
+		},
+	},
+	"grpc-stream": {
+		description: "Checks a gRPC streaming RPC delivers its first message within a deadline",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.GRPCMethod == "" {
+				return nil, fmt.Errorf("grpc-stream scraper requires a grpc_method")
+			}
+			if _, err := base64.StdEncoding.DecodeString(scraperConfig.GRPCRequestMessage); err != nil {
+				return nil, fmt.Errorf("invalid grpc_request_message: %w", err)
+			}
+			transport, err := newHTTPTransport(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewGRPCStreamScraper(scraperConfig, logger)
+			s.client = &http.Client{
+				Transport: instrumentTransport(traceTransport(&remoteAddrTransport{next: newGRPCTransport(transport)}, scraperConfig), scraperConfig),
+			}
+			return s, nil
+		},
+	},
+	"grpc-web": {
+		description: "Checks a unary RPC called through a gRPC-web gateway returns an OK grpc-status",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.GRPCMethod == "" {
+				return nil, fmt.Errorf("grpc-web scraper requires a grpc_method")
+			}
+			if _, err := base64.StdEncoding.DecodeString(scraperConfig.GRPCRequestMessage); err != nil {
+				return nil, fmt.Errorf("invalid grpc_request_message: %w", err)
+			}
+			transport, err := newHTTPTransport(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewGRPCWebScraper(scraperConfig, logger)
+			s.client.Transport = instrumentTransport(traceTransport(&remoteAddrTransport{next: transport}, scraperConfig), scraperConfig)
+			return s, nil
+		},
+	},
+	"http": {
+		description: "Checks a generic HTTP endpoint returns a 2xx status",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.BurstQuorum > scraperConfig.Burst {
+				return nil, fmt.Errorf("burst_quorum %d exceeds burst %d", scraperConfig.BurstQuorum, scraperConfig.Burst)
+			}
+			// The cache would collapse the concurrent requests of a burst into one
+			if scraperConfig.Burst > 1 && scraperConfig.EnableScrapeCache {
+				return nil, fmt.Errorf("burst cannot be combined with enable_scrape_cache")
+			}
+			// Trailers follow the body, which neither a burst nor read_first_line reads to the end
+			if scraperConfig.TrailerKey != "" && (scraperConfig.Burst > 1 || scraperConfig.ReadFirstLine) {
+				return nil, fmt.Errorf("trailer_key cannot be combined with burst or read_first_line")
+			}
+			if scraperConfig.MinSuccessRatio > 1 {
+				return nil, fmt.Errorf("min_success_ratio %g exceeds 1", scraperConfig.MinSuccessRatio)
+			}
+			if time.Duration(scraperConfig.ProbeWindowSeconds)*time.Second > maxProbeWindow {
+				return nil, fmt.Errorf("probe_window_seconds %d exceeds the maximum of %s", scraperConfig.ProbeWindowSeconds, maxProbeWindow)
+			}
+			if scraperConfig.MinQuotaPercent > 100 {
+				return nil, fmt.Errorf("min_quota_percent %g exceeds 100", scraperConfig.MinQuotaPercent)
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewHTTPScraper(scraperConfig, logger)
+			s.client = client
+			if len(scraperConfig.AllowedRedirectHosts) > 0 {
+				s.client.CheckRedirect = s.checkRedirect
+			}
+			if s.badPagePatterns, err = compileBadPagePatterns(scraperConfig.BadPagePatterns); err != nil {
+				return nil, err
+			}
+			// Compile the expressions here so a typo fails at startup instead of on every scrape
+			if scraperConfig.HealthExpression != "" {
+				if s.healthExpression, err = compileExpression(scraperConfig.HealthExpression); err != nil {
+					return nil, fmt.Errorf("invalid health_expression: %w", err)
+				}
+			}
+			if scraperConfig.MessageExpression != "" {
+				if s.messageExpression, err = compileExpression(scraperConfig.MessageExpression); err != nil {
+					return nil, fmt.Errorf("invalid message_expression: %w", err)
+				}
+			}
+			return s, nil
+		},
+	},
+	"idempotency": {
+		description: "Checks an endpoint returns the same response to two requests made a short delay apart",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			// The cache would answer the second request with the first response
+			if scraperConfig.EnableScrapeCache {
+				return nil, fmt.Errorf("idempotency scraper cannot be combined with enable_scrape_cache")
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewIdempotencyScraper(scraperConfig, logger)
+			s.client = client
+			return s, nil
+		},
+	},
+	"job-freshness": {
+		description: "Checks a scheduled job ran recently from a file's mtime, an HTTP timestamp or a Redis key",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			newSource, ok := freshnessSources[scraperConfig.SourceType]
+			if !ok {
+				return nil, fmt.Errorf("job-freshness scraper has unsupported source_type %q, supported: %s", scraperConfig.SourceType, freshnessSourceNames())
+			}
+			if scraperConfig.MaxAgeSeconds <= 0 {
+				return nil, fmt.Errorf("job-freshness scraper requires a positive max_age_seconds")
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			source, err := newSource(scraperConfig, client)
+			if err != nil {
+				return nil, err
+			}
+			return NewJobFreshnessScraper(scraperConfig, source, logger), nil
+		},
+	},
+	"k8s-nodes": {
+		description: "Checks enough nodes of a Kubernetes cluster are Ready",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.MinReadyPercent > 100 {
+				return nil, fmt.Errorf("k8s-nodes scraper min_ready_percent %g exceeds 100", scraperConfig.MinReadyPercent)
+			}
+			api, err := newKubernetesAPI(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			return NewK8sNodesScraper(scraperConfig, api, logger), nil
+		},
+	},
+	"k8s-workload": {
+		description: "Checks a Kubernetes Deployment or StatefulSet has its replicas ready",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if _, ok := k8sWorkloadKinds[scraperConfig.WorkloadKind]; !ok {
+				return nil, fmt.Errorf("k8s-workload scraper has unsupported workload_kind %q, supported: %s", scraperConfig.WorkloadKind, k8sWorkloadKindNames())
+			}
+			if scraperConfig.WorkloadName == "" {
+				return nil, fmt.Errorf("k8s-workload scraper requires a workload_name")
+			}
+			api, err := newKubernetesAPI(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			// Default to the namespace of the kubeconfig context or the pod
+			if scraperConfig.Namespace == "" {
+				scraperConfig.Namespace = api.namespace
+			}
+			return NewK8sWorkloadScraper(scraperConfig, api, logger), nil
+		},
+	},
+	"lb-pool": {
+		description: "Checks a HAProxy, Envoy or NGINX Plus load balancer has enough healthy backends",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if _, ok := lbPoolFormats[scraperConfig.Format]; !ok {
+				return nil, fmt.Errorf("lb-pool scraper has unsupported format %q, supported: %s", scraperConfig.Format, lbPoolFormatNames())
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewLBPoolScraper(scraperConfig, logger)
+			s.client = client
+			return s, nil
+		},
+	},
+	"mount": {
+		description: "Checks a mounted filesystem such as NFS or SMB is writable",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.MountPath == "" {
+				return nil, fmt.Errorf("mount scraper requires a mount_path")
+			}
+			return NewMountScraper(scraperConfig, logger), nil
+		},
+	},
+	"pipeline": {
+		description: "Checks a message published to a pipeline's input arrives at its output",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			input, output, err := newPipelineEndpoints(scraperConfig, client)
+			if err != nil {
+				return nil, err
+			}
+			return NewPipelineScraper(scraperConfig, input, output, logger), nil
+		},
+	},
+	"process": {
+		description: "Checks a process matching a name or command line pattern is running",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			s, err := NewProcessScraper(scraperConfig, logger)
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		},
+	},
+	"prometheus-metric": {
+		description: "Checks a Prometheus metric value against a threshold",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.MetricName == "" {
+				return nil, fmt.Errorf("prometheus-metric scraper requires a metric_name")
+			}
+			if _, ok := metricOperators[scraperConfig.Operator]; !ok {
+				return nil, fmt.Errorf("prometheus-metric scraper has unsupported operator %q", scraperConfig.Operator)
+			}
+			if scraperConfig.Threshold == nil {
+				return nil, fmt.Errorf("prometheus-metric scraper requires a threshold")
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewPrometheusMetricScraper(scraperConfig, logger)
+			s.client = client
+			return s, nil
+		},
+	},
+	"queue-depth": {
+		description: "Checks a SQS, Redis list or RabbitMQ queue is not backed up",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			newBackend, ok := queueBackends[scraperConfig.QueueBackend]
+			if !ok {
+				return nil, fmt.Errorf("queue-depth scraper has unsupported queue_backend %q, supported: %s", scraperConfig.QueueBackend, queueBackendNames())
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			backend, err := newBackend(scraperConfig, client)
+			if err != nil {
+				return nil, err
+			}
+			return NewQueueDepthScraper(scraperConfig, backend, logger), nil
+		},
+	},
+	"s3-roundtrip": {
+		description: "Checks an object can be written, read back and deleted in an S3 compatible bucket",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s3, err := newS3API(scraperConfig, client)
+			if err != nil {
+				return nil, err
+			}
+			return NewS3RoundtripScraper(scraperConfig, s3, logger), nil
+		},
+	},
+	"self": {
+		description: "Checks the healthcheck process's own goroutines and heap stay within their limits",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			return NewSelfScraper(scraperConfig, logger), nil
+		},
+	},
+	"srv-discovery": {
+		description: "Discovers the instances of a service from a DNS SRV record and checks a quorum of them",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			if scraperConfig.SRVName == "" {
+				return nil, fmt.Errorf("srv-discovery scraper requires a srv_name")
+			}
+			if scraperConfig.SRVScheme != "" && !srvSchemes[scraperConfig.SRVScheme] {
+				return nil, fmt.Errorf("srv-discovery scraper has unsupported srv_scheme %q, supported: http, https, tcp", scraperConfig.SRVScheme)
+			}
+			dial, err := newDialContext(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			client, err := newHTTPClient(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s := NewSRVDiscoveryScraper(scraperConfig, logger)
+			s.client = client
+			s.dial = dial
+			return s, nil
+		},
+	},
+	"stun": {
+		description: "Checks a STUN or TURN server answers binding requests with the reflexive address",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			return NewSTUNScraper(scraperConfig, logger)
+		},
+	},
+	"tls": {
+		description: "Checks a TLS endpoint completes a verified handshake with a valid certificate",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			dial, err := newDialContext(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			rootCAs, err := newRootCAs(scraperConfig)
+			if err != nil {
+				return nil, err
+			}
+			s, err := NewTLSScraper(scraperConfig, logger)
+			if err != nil {
+				return nil, err
+			}
+			s.dial = dial
+			s.rootCAs = rootCAs
+			return s, nil
+		},
+	},
+	"vault": {
+		description: "Checks a HashiCorp Vault instance is initialized and unsealed",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			return createVaultScraper("vault", scraperConfig, logger)
+		},
+	},
+	"vault-seal": {
+		description: "Checks HashiCorp Vault seal status via /v1/sys/health or /v1/sys/seal-status",
+		create: func(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+			return createVaultScraper("vault-seal", scraperConfig, logger)
+		},
+	},
+}
+
+// RegisteredTypes returns all registered scraper types sorted by type identifier
+func RegisteredTypes() []TypeInfo {
+	types := make([]TypeInfo, 0, len(registry))
+	for scraperType, reg := range registry {
+		types = append(types, TypeInfo{
+			Type:        scraperType,
+			Description: reg.description,
+		})
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Type < types[j].Type
+	})
+
+	return types
+}
+
+// createVaultScraper creates a Vault scraper reporting the given type identifier
+func createVaultScraper(scraperType string, scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (Scraper, error) {
+	client, err := newHTTPClient(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewVaultScraper(scraperConfig.ScrapeURL, scraperConfig.PingURL, scraperConfig.ScrapeIntervalSeconds, scraperConfig.VaultNamespace, scraperConfig.VaultStandbyHealthy, logger)
+	s.scraperType = scraperType
+	s.client = client
+	s.httpOptions = newHTTPOptions(scraperConfig)
+	return s, nil
+}
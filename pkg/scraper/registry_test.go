@@ -0,0 +1,23 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisteredTypes(t *testing.T) {
+	types := RegisteredTypes()
+
+	assert.Len(t, types, len(registry))
+	for i, info := range types {
+		assert.NotEmpty(t, info.Description, "type %s should have a description", info.Type)
+		if i > 0 {
+			assert.Less(t, types[i-1].Type, info.Type, "types should be sorted")
+		}
+	}
+	assert.Contains(t, types, TypeInfo{
+		Type:        "cloudflared-tunnel-connector",
+		Description: registry["cloudflared-tunnel-connector"].description,
+	})
+}
@@ -0,0 +1,52 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+)
+
+// remoteAddrKey is the request context key of the address a request was sent to
+type remoteAddrKey struct{}
+
+// remoteAddr holds the address of the connection a request was sent on
+type remoteAddr struct {
+	mu   sync.Mutex
+	addr string
+}
+
+// remoteAddrTransport records the remote address of the connection each request is sent on,
+// which decorate reports as remote_addr to show which backend answered
+type remoteAddrTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip sends the request with a trace recording the address of the connection it got
+func (t *remoteAddrTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	holder := &remoteAddr{}
+	ctx := context.WithValue(req.Context(), remoteAddrKey{}, holder)
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			holder.mu.Lock()
+			defer holder.mu.Unlock()
+			holder.addr = info.Conn.RemoteAddr().String()
+		},
+	})
+	return t.next.RoundTrip(req.WithContext(ctx))
+}
+
+// responseRemoteAddr returns the remote address the response was received from, empty when
+// it was not recorded
+func responseRemoteAddr(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	holder, ok := resp.Request.Context().Value(remoteAddrKey{}).(*remoteAddr)
+	if !ok {
+		return ""
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	return holder.addr
+}
@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteAddr_HTTPScrapers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"__schema":{"queryType":{"name":"Query"}}}}`))
+	}))
+	defer server.Close()
+
+	for _, scraperType := range []string{"http", "graphql"} {
+		t.Run(scraperType, func(t *testing.T) {
+			scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+				Type:      scraperType,
+				ScrapeURL: server.URL,
+			})
+			require.NoError(t, err)
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.True(t, result.Healthy, result.Message)
+			assert.Equal(t, server.Listener.Addr().String(), result.Details["remote_addr"])
+		})
+	}
+}
+
+func TestRemoteAddr_AfterRedirect(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	origin := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer origin.Close()
+
+	scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:      "http",
+		ScrapeURL: origin.URL,
+	})
+	require.NoError(t, err)
+
+	result, err := scraper.Scrape(context.Background())
+
+	// The backend that answered is the one redirected to
+	require.NoError(t, err)
+	assert.Equal(t, target.Listener.Addr().String(), result.Details["remote_addr"])
+}
+
+func TestResponseRemoteAddr_NotRecorded(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://localhost/health", nil)
+
+	assert.Empty(t, responseRemoteAddr(&http.Response{Request: req}))
+	assert.Empty(t, responseRemoteAddr(&http.Response{}))
+}
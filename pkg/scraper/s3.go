@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+// s3Client writes, reads and deletes objects of a bucket
+type s3Client interface {
+	PutObject(ctx context.Context, key string, body []byte) error
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// s3API is an s3Client using the S3 REST API, which S3 compatible stores such as MinIO or
+// the Google Cloud Storage interoperability API implement as well
+type s3API struct {
+	// baseURL is the URL of the bucket, objects are addressed by appending their key
+	baseURL *url.URL
+	region  string
+	creds   awsCredentials
+	client  *http.Client
+}
+
+// newS3API creates an S3 client for the configured bucket. The bucket is addressed virtual
+// hosted style on the region's endpoint, or path style on the scrape URL when it points at
+// an S3 compatible store. Requests are signed with the configured credentials, or those from
+// the standard AWS environment variables.
+func newS3API(scraperConfig config.HealthcheckScraper, client *http.Client) (*s3API, error) {
+	if scraperConfig.Bucket == "" {
+		return nil, fmt.Errorf("s3-roundtrip scraper requires a bucket")
+	}
+
+	region := scraperConfig.AWSRegion
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", scraperConfig.Bucket, region)
+	if scraperConfig.ScrapeURL != "" {
+		endpoint = strings.TrimSuffix(scraperConfig.ScrapeURL, "/") + "/" + url.PathEscape(scraperConfig.Bucket) + "/"
+	}
+	baseURL, err := url.Parse(endpoint)
+	if err != nil || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", endpoint)
+	}
+
+	creds, err := awsCredentialsFromConfig(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3API{
+		baseURL: baseURL,
+		region:  region,
+		creds:   creds,
+		client:  client,
+	}, nil
+}
+
+// PutObject writes the object with the given body
+func (s *s3API) PutObject(ctx context.Context, key string, body []byte) error {
+	resp, err := s.do(ctx, "PUT", key, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return s3Error(resp, http.StatusOK)
+}
+
+// GetObject reads the body of the object
+func (s *s3API) GetObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, "GET", key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := s3Error(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return body, nil
+}
+
+// DeleteObject deletes the object
+func (s *s3API) DeleteObject(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, "DELETE", key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return s3Error(resp, http.StatusNoContent, http.StatusOK)
+}
+
+// do sends a signed request for the object with the given key
+func (s *s3API) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	objectURL := s.baseURL.JoinPath(strings.Split(key, "/")...)
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// S3 requires the payload hash to be sent along with the signature
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	signAWSRequest(req, body, s.creds, s.region, "s3", time.Now())
+
+	return s.client.Do(req)
+}
+
+// Close closes the idle connections of the client's HTTP client
+func (s *s3API) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// s3Error converts a response without one of the expected status codes to an error
+func s3Error(resp *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var errorResponse struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(message, &errorResponse) == nil && errorResponse.Code != "" {
+		return fmt.Errorf("HTTP status %d from S3: %s: %s", resp.StatusCode, errorResponse.Code, errorResponse.Message)
+	}
+	return fmt.Errorf("HTTP status %d from S3: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultS3KeyPrefix is where the round trip objects are written when s3_key_prefix is not set
+const defaultS3KeyPrefix = "healthcheck/"
+
+// S3RoundtripScraper implements the Scraper interface for checking an object can be written,
+// read back and deleted in a bucket
+type S3RoundtripScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	client                s3Client
+	logger                *logrus.Logger
+}
+
+// NewS3RoundtripScraper creates a new S3 round trip scraper using the given client
+func NewS3RoundtripScraper(scraperConfig config.HealthcheckScraper, client s3Client, logger *logrus.Logger) *S3RoundtripScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	if scraperConfig.S3KeyPrefix == "" {
+		scraperConfig.S3KeyPrefix = defaultS3KeyPrefix
+	}
+
+	return &S3RoundtripScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		client:                client,
+		logger:                logger,
+	}
+}
+
+// Type returns the scraper type identifier
+func (s *S3RoundtripScraper) Type() string {
+	return "s3-roundtrip"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (s *S3RoundtripScraper) GetPingURL() string {
+	return s.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (s *S3RoundtripScraper) GetScrapeInterval() int {
+	return s.scrapeIntervalSeconds
+}
+
+// Close closes the S3 client if it holds connections
+func (s *S3RoundtripScraper) Close() error {
+	if closer, ok := s.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Scrape writes a small object with a unique key, reads it back and deletes it. It is
+// healthy when every step succeeds within max_latency_ms, if set, and the object read back
+// matches the one written.
+func (s *S3RoundtripScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	bucket := s.config.Bucket
+	s.logger.WithField("bucket", bucket).Debug("Starting S3 round trip healthcheck")
+
+	token := make([]byte, 8)
+	rand.Read(token)
+	key := fmt.Sprintf("%s%d-%s", s.config.S3KeyPrefix, time.Now().UnixNano(), hex.EncodeToString(token))
+	payload := []byte("healthcheck " + hex.EncodeToString(token))
+
+	details := map[string]interface{}{
+		"bucket": bucket,
+		"key":    key,
+	}
+
+	failedStep, err := s.roundtrip(ctx, key, payload, details)
+	if err != nil {
+		details["failed_step"] = failedStep
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("S3 round trip in bucket %s failed at %s: %v", bucket, failedStep, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	total := details["total_latency_ms"].(int64)
+	healthy := true
+	message := fmt.Sprintf("S3 round trip in bucket %s took %dms", bucket, total)
+	if s.config.MaxLatencyMs > 0 {
+		details["max_latency_ms"] = s.config.MaxLatencyMs
+		for _, name := range []string{"put", "get", "delete"} {
+			if latency := details[name+"_latency_ms"].(int64); latency > int64(s.config.MaxLatencyMs) {
+				healthy = false
+				details["failed_step"] = name
+				message = fmt.Sprintf("S3 %s in bucket %s took %dms, exceeding %dms", name, bucket, latency, s.config.MaxLatencyMs)
+				break
+			}
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"bucket":           bucket,
+		"total_latency_ms": total,
+		"healthy":          healthy,
+	}).Info("S3 round trip healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// roundtrip writes, reads back and deletes the object, recording the latency of every step
+// in the details, and returns the step that failed
+func (s *S3RoundtripScraper) roundtrip(ctx context.Context, key string, payload []byte, details map[string]interface{}) (string, error) {
+	var total time.Duration
+	timed := func(step string, run func() error) error {
+		start := time.Now()
+		err := run()
+		latency := time.Since(start)
+		total += latency
+		details[step+"_latency_ms"] = latency.Milliseconds()
+		details["total_latency_ms"] = total.Milliseconds()
+		return err
+	}
+
+	if err := timed("put", func() error {
+		return s.client.PutObject(ctx, key, payload)
+	}); err != nil {
+		return "put", err
+	}
+
+	getErr := timed("get", func() error {
+		body, err := s.client.GetObject(ctx, key)
+		if err == nil && !bytes.Equal(body, payload) {
+			return fmt.Errorf("object read back differs from the object written")
+		}
+		return err
+	})
+	// The object is deleted even if reading it failed, so failed scrapes leave nothing behind
+	deleteErr := timed("delete", func() error {
+		return s.client.DeleteObject(ctx, key)
+	})
+
+	if getErr != nil {
+		return "get", getErr
+	}
+	if deleteErr != nil {
+		return "delete", deleteErr
+	}
+	return "", nil
+}
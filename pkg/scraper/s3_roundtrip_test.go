@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockS3Client stores objects in memory, failing or delaying steps as configured
+type mockS3Client struct {
+	objects map[string][]byte
+	errs    map[string]error
+	delays  map[string]time.Duration
+	corrupt bool
+	deletes int
+}
+
+func newMockS3Client() *mockS3Client {
+	return &mockS3Client{
+		objects: make(map[string][]byte),
+		errs:    make(map[string]error),
+		delays:  make(map[string]time.Duration),
+	}
+}
+
+func (m *mockS3Client) step(name string) error {
+	time.Sleep(m.delays[name])
+	return m.errs[name]
+}
+
+func (m *mockS3Client) PutObject(ctx context.Context, key string, body []byte) error {
+	if err := m.step("put"); err != nil {
+		return err
+	}
+	m.objects[key] = body
+	return nil
+}
+
+func (m *mockS3Client) GetObject(ctx context.Context, key string) ([]byte, error) {
+	if err := m.step("get"); err != nil {
+		return nil, err
+	}
+	if m.corrupt {
+		return []byte("stale"), nil
+	}
+	return m.objects[key], nil
+}
+
+func (m *mockS3Client) DeleteObject(ctx context.Context, key string) error {
+	m.deletes++
+	if err := m.step("delete"); err != nil {
+		return err
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+// newTestS3RoundtripScraper creates an S3 round trip scraper of the bucket using the client
+func newTestS3RoundtripScraper(client s3Client, maxLatencyMs int) *S3RoundtripScraper {
+	return NewS3RoundtripScraper(config.HealthcheckScraper{
+		Bucket:       "probes",
+		MaxLatencyMs: maxLatencyMs,
+	}, client, logrus.New())
+}
+
+func TestNewS3RoundtripScraper(t *testing.T) {
+	scraper := NewS3RoundtripScraper(config.HealthcheckScraper{
+		Bucket:  "probes",
+		PingURL: "http://localhost:8081/ping",
+	}, newMockS3Client(), logrus.New())
+
+	assert.Equal(t, "s3-roundtrip", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+	assert.Equal(t, defaultS3KeyPrefix, scraper.config.S3KeyPrefix)
+}
+
+func TestS3RoundtripScraper_Scrape_Success(t *testing.T) {
+	client := newMockS3Client()
+
+	result, err := newTestS3RoundtripScraper(client, 0).Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.True(t, strings.HasPrefix(result.Details["key"].(string), "healthcheck/"))
+	for _, key := range []string{"put_latency_ms", "get_latency_ms", "delete_latency_ms", "total_latency_ms"} {
+		assert.Contains(t, result.Details, key)
+	}
+	assert.Empty(t, client.objects, "object should have been deleted")
+}
+
+func TestS3RoundtripScraper_Scrape_StepFails(t *testing.T) {
+	tests := []struct {
+		step    string
+		deletes int
+	}{
+		{step: "put", deletes: 0},
+		{step: "get", deletes: 1},
+		{step: "delete", deletes: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.step, func(t *testing.T) {
+			client := newMockS3Client()
+			client.errs[tt.step] = fmt.Errorf("HTTP status 503 from S3: SlowDown: Please reduce your request rate.")
+
+			result, err := newTestS3RoundtripScraper(client, 0).Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.False(t, result.Healthy)
+			assert.Equal(t, tt.step, result.Details["failed_step"])
+			assert.Contains(t, result.Message, "failed at "+tt.step+": HTTP status 503")
+			assert.Equal(t, tt.deletes, client.deletes)
+		})
+	}
+}
+
+func TestS3RoundtripScraper_Scrape_ObjectMismatch(t *testing.T) {
+	client := newMockS3Client()
+	client.corrupt = true
+
+	result, err := newTestS3RoundtripScraper(client, 0).Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "get", result.Details["failed_step"])
+	assert.Contains(t, result.Message, "object read back differs from the object written")
+	assert.Empty(t, client.objects, "object should have been deleted")
+}
+
+func TestS3RoundtripScraper_Scrape_SlowStep(t *testing.T) {
+	client := newMockS3Client()
+	client.delays["get"] = 30 * time.Millisecond
+
+	result, err := newTestS3RoundtripScraper(client, 20).Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "get", result.Details["failed_step"])
+	assert.Contains(t, result.Message, "S3 get in bucket probes took")
+	assert.Contains(t, result.Message, "exceeding 20ms")
+}
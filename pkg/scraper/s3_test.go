@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newS3TestServer creates an in-memory S3 compatible store accepting path style requests
+// signed by the given access key
+func newS3TestServer(t *testing.T, accessKeyID string) (*httptest.Server, map[string][]byte) {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential="+accessKeyID+"/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/s3/aws4_request") ||
+			r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<Error><Code>SignatureDoesNotMatch</Code><Message>The request signature we calculated does not match</Message></Error>`))
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "PUT":
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case "GET":
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+				return
+			}
+			w.Write(body)
+		case "DELETE":
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, objects
+}
+
+// newTestS3API creates an S3 client of the test server's bucket
+func newTestS3API(t *testing.T, server *httptest.Server) *s3API {
+	api, err := newS3API(config.HealthcheckScraper{
+		ScrapeURL:          server.URL,
+		Bucket:             "probes",
+		AWSAccessKeyID:     "AKIDEXAMPLE",
+		AWSSecretAccessKey: "secret",
+	}, server.Client())
+	require.NoError(t, err)
+	return api
+}
+
+func TestS3API_Roundtrip(t *testing.T) {
+	server, objects := newS3TestServer(t, "AKIDEXAMPLE")
+	api := newTestS3API(t, server)
+	ctx := context.Background()
+
+	require.NoError(t, api.PutObject(ctx, "healthcheck/1", []byte("payload")))
+	assert.Equal(t, []byte("payload"), objects["/probes/healthcheck/1"])
+
+	body, err := api.GetObject(ctx, "healthcheck/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), body)
+
+	require.NoError(t, api.DeleteObject(ctx, "healthcheck/1"))
+	assert.Empty(t, objects)
+}
+
+func TestS3API_Errors(t *testing.T) {
+	server, _ := newS3TestServer(t, "AKIDEXAMPLE")
+	api := newTestS3API(t, server)
+
+	_, err := api.GetObject(context.Background(), "missing")
+	assert.EqualError(t, err, "HTTP status 404 from S3: NoSuchKey: The specified key does not exist.")
+
+	api.creds.accessKeyID = "AKIDOTHER"
+	err = api.PutObject(context.Background(), "healthcheck/1", []byte("payload"))
+	assert.ErrorContains(t, err, "HTTP status 403 from S3: SignatureDoesNotMatch")
+}
+
+func TestNewS3API_Endpoints(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	api, err := newS3API(config.HealthcheckScraper{Bucket: "probes", AWSRegion: "eu-west-1"}, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "https://probes.s3.eu-west-1.amazonaws.com/", api.baseURL.String())
+	assert.Equal(t, "eu-west-1", api.region)
+
+	api, err = newS3API(config.HealthcheckScraper{Bucket: "probes", ScrapeURL: "http://minio:9000/"}, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "http://minio:9000/probes/", api.baseURL.String())
+	assert.Equal(t, "us-east-1", api.region)
+
+	_, err = newS3API(config.HealthcheckScraper{}, http.DefaultClient)
+	assert.EqualError(t, err, "s3-roundtrip scraper requires a bucket")
+}
@@ -0,0 +1,203 @@
+package scraper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/pkg/config"
+)
+
+const (
+	// defaultScrapeCacheTTL is how long cached responses are reused when no TTL is configured
+	defaultScrapeCacheTTL = 5 * time.Second
+	// maxCachedBodySize is the largest response body that is cached
+	maxCachedBodySize = 1 << 20
+)
+
+// sharedScrapeCache is shared by all scrapers with the scrape cache enabled
+var sharedScrapeCache = newScrapeCache()
+
+// scrapeCache holds recent responses keyed by transport scope, method, URL and request headers
+type scrapeCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// cachedResponse is a response that is being fetched until ready is closed
+type cachedResponse struct {
+	ready  chan struct{}
+	ok     bool
+	stored time.Time
+	resp   *http.Response
+	body   []byte
+}
+
+// newScrapeCache creates an empty scrape cache
+func newScrapeCache() *scrapeCache {
+	return &scrapeCache{entries: make(map[string]*cachedResponse)}
+}
+
+// acquire returns the entry for the key and whether the caller owns it and must fetch the
+// response. Entries being fetched or younger than the TTL are shared.
+func (c *scrapeCache) acquire(key string, ttl time.Duration) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.ready:
+			if entry.ok && time.Since(entry.stored) < ttl {
+				return entry, false
+			}
+		default:
+			return entry, false
+		}
+	}
+
+	entry := &cachedResponse{ready: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// release marks the entry as fetched, dropping it from the cache unless it holds a response
+func (c *scrapeCache) release(key string, entry *cachedResponse) {
+	if !entry.ok {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.ready)
+}
+
+// scrapeCacheTransport serves identical requests of scrapers sharing the cache from one response
+type scrapeCacheTransport struct {
+	next  http.RoundTripper
+	cache *scrapeCache
+	ttl   time.Duration
+	// scope sets apart the requests of scrapers whose transports differ
+	scope string
+}
+
+// cacheTransport wraps the transport with the shared scrape cache when the scraper enables it
+func cacheTransport(transport http.RoundTripper, scraperConfig config.HealthcheckScraper) http.RoundTripper {
+	if !scraperConfig.EnableScrapeCache {
+		return transport
+	}
+
+	ttl := time.Duration(scraperConfig.ScrapeCacheTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultScrapeCacheTTL
+	}
+
+	return &scrapeCacheTransport{next: transport, cache: sharedScrapeCache, ttl: ttl, scope: transportScope(scraperConfig)}
+}
+
+// transportScope fingerprints the settings deciding how a scraper's requests reach the server,
+// so scrapers only share responses fetched over the same network path and TLS setup. Requests
+// over a Unix socket all go to localhost, so the socket path is part of it too.
+func transportScope(scraperConfig config.HealthcheckScraper) string {
+	socketPath, _, _, _ := parseUnixScrapeURL(scraperConfig.ScrapeURL)
+
+	hash := sha256.New()
+	for _, setting := range []string{
+		socketPath,
+		scraperConfig.SourceAddress,
+		scraperConfig.MinTLSVersion,
+		scraperConfig.CACertPEM,
+		scraperConfig.CACertFile,
+		scraperConfig.DoHResolverURL,
+		strconv.Itoa(scraperConfig.DNSCacheTTLSeconds),
+		strconv.FormatBool(scraperConfig.InjectTraceHeader),
+	} {
+		hash.Write([]byte(setting))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// RoundTrip returns a cached or in-flight response for identical requests and fetches it otherwise.
+// Only GET and HEAD requests without a body are cached.
+func (t *scrapeCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != "GET" && req.Method != "HEAD") || (req.Body != nil && req.Body != http.NoBody) {
+		return t.next.RoundTrip(req)
+	}
+
+	key := scrapeCacheKey(t.scope, req)
+	entry, owner := t.cache.acquire(key, t.ttl)
+	if !owner {
+		select {
+		case <-entry.ready:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		if entry.ok {
+			return entry.response(req), nil
+		}
+		// The shared request failed or was not cacheable, so make our own
+		return t.next.RoundTrip(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.cache.release(key, entry)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodySize+1))
+	if err != nil || len(body) > maxCachedBodySize {
+		t.cache.release(key, entry)
+		// Hand the caller what was read followed by the rest of the body
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	entry.resp = resp
+	entry.body = body
+	entry.stored = time.Now()
+	entry.ok = true
+	t.cache.release(key, entry)
+
+	return entry.response(req), nil
+}
+
+// response returns a copy of the cached response for the request
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	resp := *e.resp
+	resp.Header = e.resp.Header.Clone()
+	resp.Trailer = e.resp.Trailer.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(e.body))
+	resp.ContentLength = int64(len(e.body))
+	resp.Request = req
+	return &resp
+}
+
+// scrapeCacheKey identifies a request by the transport scope, its method, URL and headers
+func scrapeCacheKey(scope string, req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(scope + "\n" + req.Method + " " + req.URL.String())
+	for _, name := range names {
+		key.WriteString("\n" + name + ": " + strings.Join(req.Header[name], ", "))
+	}
+	return key.String()
+}
@@ -0,0 +1,184 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countRequests returns a handler counting the requests it answers after the delay
+func countRequests(requests *atomic.Int32, delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(delay)
+		w.Header().Set("X-Request", "served")
+		w.Write([]byte("ok"))
+	}
+}
+
+func TestScrapeCache_SecondScrapeHitsCache(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+
+	first := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, EnableScrapeCache: true})
+	second := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, EnableScrapeCache: true})
+
+	firstResult, err := first.Scrape(context.Background())
+	require.NoError(t, err)
+	secondResult, err := second.Scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, firstResult.Healthy)
+	assert.True(t, secondResult.Healthy)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestScrapeCache_KeyedByTransport(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+
+	first := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, EnableScrapeCache: true})
+	second := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, EnableScrapeCache: true, SourceAddress: "127.0.0.1"})
+	third := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL, EnableScrapeCache: true, MinTLSVersion: "1.2"})
+
+	for _, s := range []Scraper{first, second, third} {
+		result, err := s.Scrape(context.Background())
+		require.NoError(t, err)
+		assert.True(t, result.Healthy)
+	}
+
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestTransportScope(t *testing.T) {
+	base := config.HealthcheckScraper{ScrapeURL: "https://example.com/health"}
+	scope := transportScope(base)
+
+	assert.Equal(t, scope, transportScope(config.HealthcheckScraper{ScrapeURL: "https://example.com/health", Name: "other"}))
+	for _, changed := range []config.HealthcheckScraper{
+		{SourceAddress: "10.0.0.1"},
+		{MinTLSVersion: "1.3"},
+		{CACertPEM: "-----BEGIN CERTIFICATE-----"},
+		{CACertFile: "/etc/ssl/ca.pem"},
+		{DoHResolverURL: "https://dns.example.com/dns-query"},
+		{DNSCacheTTLSeconds: 30},
+		{InjectTraceHeader: true},
+	} {
+		assert.NotEqual(t, scope, transportScope(changed))
+	}
+}
+
+func TestScrapeCache_Disabled(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+
+	first := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL})
+	second := newTestScraper(t, config.HealthcheckScraper{Type: "http", ScrapeURL: server.URL})
+
+	first.Scrape(context.Background())
+	second.Scrape(context.Background())
+
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestScrapeCacheTransport_Expires(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+	transport := &scrapeCacheTransport{next: http.DefaultTransport, cache: newScrapeCache(), ttl: 50 * time.Millisecond}
+	client := &http.Client{Transport: transport}
+
+	get := func() string {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, "ok", get())
+	assert.Equal(t, "ok", get())
+	assert.Equal(t, int32(1), requests.Load())
+
+	time.Sleep(60 * time.Millisecond)
+
+	assert.Equal(t, "ok", get())
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestScrapeCacheTransport_KeyedByHeaders(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+	client := &http.Client{Transport: &scrapeCacheTransport{next: http.DefaultTransport, cache: newScrapeCache(), ttl: time.Minute}}
+
+	for _, namespace := range []string{"a", "b", "a"} {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Vault-Namespace", namespace)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestScrapeCacheTransport_SharesInFlightRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 100*time.Millisecond))
+	client := &http.Client{Transport: &scrapeCacheTransport{next: http.DefaultTransport, cache: newScrapeCache(), ttl: time.Minute}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, "served", resp.Header.Get("X-Request"))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestScrapeCacheTransport_BypassesRequestsWithBody(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+	client := &http.Client{Transport: &scrapeCacheTransport{next: http.DefaultTransport, cache: newScrapeCache(), ttl: time.Minute}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query":"{ health }"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestScrapeCacheTransport_FailuresNotCached(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, countRequests(&requests, 0))
+	cache := newScrapeCache()
+	client := &http.Client{Transport: &scrapeCacheTransport{next: http.DefaultTransport, cache: cache, ttl: time.Minute}}
+
+	server.Close()
+	_, err := client.Get(server.URL)
+	assert.Error(t, err)
+
+	assert.Empty(t, cache.entries)
+	assert.Equal(t, int32(0), requests.Load())
+}
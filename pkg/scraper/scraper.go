@@ -5,7 +5,8 @@ import (
 	"time"
 )
 
-// Scraper defines the interface for healthcheck scrapers
+// Scraper defines the interface for healthcheck scrapers. Scrapers holding connections
+// may also implement io.Closer, which is called once the scraper is removed.
 type Scraper interface {
 	// Type returns the type identifier for this scraper
 	Type() string
@@ -26,4 +27,11 @@ type ScrapeResult struct {
 	Message   string
 	Timestamp time.Time
 	Details   map[string]interface{}
+	// Skipped is set on results of scrapes that did not run, such as while the scraper's gate
+	// is closed, Healthy is then meaningless
+	Skipped bool
+	// Type and Duration are the scraper's type and how long the scrape took, set by the
+	// manager on the results it publishes to result sinks
+	Type     string
+	Duration time.Duration
 }
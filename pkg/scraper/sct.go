@@ -0,0 +1,160 @@
+package scraper
+
+import (
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+var (
+	// oidEmbeddedSCTList is the X.509 extension carrying SCTs embedded in a certificate
+	oidEmbeddedSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+	// oidOCSPSCTList is the OCSP single response extension carrying SCTs
+	oidOCSPSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 5}
+	// oidOCSPBasic identifies a basic OCSP response
+	oidOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+)
+
+// sctCounts holds the number of signed certificate timestamps found per delivery mechanism
+type sctCounts struct {
+	TLSExtension int `json:"tls_extension"`
+	OCSP         int `json:"ocsp"`
+	Certificate  int `json:"certificate"`
+}
+
+// total returns the number of SCTs across all delivery mechanisms
+func (c sctCounts) total() int {
+	return c.TLSExtension + c.OCSP + c.Certificate
+}
+
+// countSCTs counts the SCTs delivered with the TLS handshake, the stapled OCSP response
+// and embedded in the leaf certificate
+func countSCTs(state tls.ConnectionState) (sctCounts, error) {
+	counts := sctCounts{TLSExtension: len(state.SignedCertificateTimestamps)}
+
+	if len(state.PeerCertificates) > 0 {
+		for _, ext := range state.PeerCertificates[0].Extensions {
+			if !ext.Id.Equal(oidEmbeddedSCTList) {
+				continue
+			}
+			n, err := countSCTExtension(ext.Value)
+			if err != nil {
+				return counts, fmt.Errorf("invalid embedded SCT list: %w", err)
+			}
+			counts.Certificate = n
+		}
+	}
+
+	if len(state.OCSPResponse) > 0 {
+		n, err := countOCSPSCTs(state.OCSPResponse)
+		if err != nil {
+			return counts, fmt.Errorf("invalid stapled OCSP response: %w", err)
+		}
+		counts.OCSP = n
+	}
+
+	return counts, nil
+}
+
+// countSCTExtension counts the SCTs of an extension value, an OCTET STRING wrapping a TLS
+// encoded SignedCertificateTimestampList
+func countSCTExtension(value []byte) (int, error) {
+	var list []byte
+	if _, err := asn1.Unmarshal(value, &list); err != nil {
+		return 0, err
+	}
+	return countSCTList(list)
+}
+
+// countSCTList counts the entries of a TLS encoded SignedCertificateTimestampList
+func countSCTList(list []byte) (int, error) {
+	if len(list) < 2 {
+		return 0, fmt.Errorf("truncated SCT list")
+	}
+	if int(binary.BigEndian.Uint16(list)) != len(list)-2 {
+		return 0, fmt.Errorf("SCT list length mismatch")
+	}
+
+	count := 0
+	for rest := list[2:]; len(rest) > 0; count++ {
+		if len(rest) < 2 {
+			return 0, fmt.Errorf("truncated SCT")
+		}
+		length := int(binary.BigEndian.Uint16(rest))
+		if length == 0 || len(rest) < 2+length {
+			return 0, fmt.Errorf("invalid SCT length")
+		}
+		rest = rest[2+length:]
+	}
+
+	return count, nil
+}
+
+// ocspResponse is the outer structure of an OCSP response (RFC 6960)
+type ocspResponse struct {
+	Status        asn1.Enumerated
+	ResponseBytes ocspResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type ocspBasicResponse struct {
+	TBSResponseData    ocspResponseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseData struct {
+	Version     int `asn1:"optional,default:0,explicit,tag:0"`
+	ResponderID asn1.RawValue
+	ProducedAt  time.Time `asn1:"generalized"`
+	Responses   []ocspSingleResponse
+	Extensions  []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspSingleResponse struct {
+	CertID           asn1.RawValue
+	CertStatus       asn1.RawValue
+	ThisUpdate       time.Time        `asn1:"generalized"`
+	NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+// countOCSPSCTs counts the SCTs in the single response extensions of an OCSP response
+func countOCSPSCTs(der []byte) (int, error) {
+	var resp ocspResponse
+	if _, err := asn1.Unmarshal(der, &resp); err != nil {
+		return 0, err
+	}
+	if !resp.ResponseBytes.ResponseType.Equal(oidOCSPBasic) {
+		return 0, fmt.Errorf("unsupported response type %v", resp.ResponseBytes.ResponseType)
+	}
+
+	var basic ocspBasicResponse
+	if _, err := asn1.Unmarshal(resp.ResponseBytes.Response, &basic); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, single := range basic.TBSResponseData.Responses {
+		for _, ext := range single.SingleExtensions {
+			if !ext.Id.Equal(oidOCSPSCTList) {
+				continue
+			}
+			n, err := countSCTExtension(ext.Value)
+			if err != nil {
+				return 0, err
+			}
+			count += n
+		}
+	}
+
+	return count, nil
+}
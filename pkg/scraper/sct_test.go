@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeSCTList encodes SCTs as a TLS SignedCertificateTimestampList
+func encodeSCTList(scts ...[]byte) []byte {
+	var entries []byte
+	for _, sct := range scts {
+		entries = binary.BigEndian.AppendUint16(entries, uint16(len(sct)))
+		entries = append(entries, sct...)
+	}
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(entries))), entries...)
+}
+
+// encodeSCTExtension encodes SCTs as the value of an SCT list extension
+func encodeSCTExtension(t *testing.T, scts ...[]byte) []byte {
+	value, err := asn1.Marshal(encodeSCTList(scts...))
+	require.NoError(t, err)
+	return value
+}
+
+// encodeOCSPResponse encodes a basic OCSP response whose single response carries the SCTs
+func encodeOCSPResponse(t *testing.T, scts ...[]byte) []byte {
+	single := ocspSingleResponse{
+		CertID:     asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Class: asn1.ClassUniversal, Bytes: []byte{}},
+		CertStatus: asn1.RawValue{Tag: 0, Class: asn1.ClassContextSpecific, Bytes: []byte{}},
+		ThisUpdate: time.Now().UTC().Truncate(time.Second),
+	}
+	if len(scts) > 0 {
+		single.SingleExtensions = []pkix.Extension{{Id: oidOCSPSCTList, Value: encodeSCTExtension(t, scts...)}}
+	}
+
+	basic, err := asn1.Marshal(ocspBasicResponse{
+		TBSResponseData: ocspResponseData{
+			ResponderID: asn1.RawValue{Tag: 2, Class: asn1.ClassContextSpecific, IsCompound: true, Bytes: []byte{0x04, 0x00}},
+			ProducedAt:  time.Now().UTC().Truncate(time.Second),
+			Responses:   []ocspSingleResponse{single},
+		},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          asn1.BitString{Bytes: []byte{0}, BitLength: 8},
+	})
+	require.NoError(t, err)
+
+	der, err := asn1.Marshal(ocspResponse{
+		ResponseBytes: ocspResponseBytes{ResponseType: oidOCSPBasic, Response: basic},
+	})
+	require.NoError(t, err)
+	return der
+}
+
+func TestCountSCTList(t *testing.T) {
+	count, err := countSCTList(encodeSCTList([]byte("sct-1"), []byte("sct-2")))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestCountSCTList_Invalid(t *testing.T) {
+	tests := map[string][]byte{
+		"truncated":       {0x00},
+		"length mismatch": {0x00, 0x05, 0x00, 0x01, 'a'},
+		"truncated entry": {0x00, 0x03, 0x00, 0x05, 'a'},
+		"empty entry":     {0x00, 0x02, 0x00, 0x00},
+	}
+
+	for name, list := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := countSCTList(list)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCountOCSPSCTs(t *testing.T) {
+	count, err := countOCSPSCTs(encodeOCSPResponse(t, []byte("sct-1"), []byte("sct-2"), []byte("sct-3")))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = countOCSPSCTs(encodeOCSPResponse(t))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	_, err = countOCSPSCTs([]byte("not an OCSP response"))
+	assert.Error(t, err)
+}
+
+func TestCountSCTs(t *testing.T) {
+	state := tls.ConnectionState{
+		SignedCertificateTimestamps: [][]byte{[]byte("sct-1")},
+		OCSPResponse:                encodeOCSPResponse(t, []byte("sct-2")),
+		PeerCertificates: []*x509.Certificate{{
+			Extensions: []pkix.Extension{{Id: oidEmbeddedSCTList, Value: encodeSCTExtension(t, []byte("sct-3"), []byte("sct-4"))}},
+		}},
+	}
+
+	counts, err := countSCTs(state)
+
+	require.NoError(t, err)
+	assert.Equal(t, sctCounts{TLSExtension: 1, OCSP: 1, Certificate: 2}, counts)
+	assert.Equal(t, 4, counts.total())
+}
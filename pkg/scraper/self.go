@@ -0,0 +1,155 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMaxGoroutines is the number of goroutines the agent may run by default
+	defaultMaxGoroutines = 10000
+	// defaultMaxHeapMB is the heap the agent may allocate by default, in megabytes
+	defaultMaxHeapMB = 1024
+)
+
+// selfStats is a snapshot of the agent's own runtime statistics
+type selfStats struct {
+	Goroutines int
+	HeapAlloc  uint64
+	HeapSys    uint64
+	NumGC      uint32
+	// LastGCPause is the pause of the latest garbage collection, MaxGCPause the longest of the
+	// recent ones the runtime keeps
+	LastGCPause  time.Duration
+	MaxGCPause   time.Duration
+	TotalGCPause time.Duration
+}
+
+// readSelfStats reads the runtime statistics of the running process
+func readSelfStats() selfStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := selfStats{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapSys:      mem.HeapSys,
+		NumGC:        mem.NumGC,
+		TotalGCPause: time.Duration(mem.PauseTotalNs),
+	}
+	if mem.NumGC > 0 {
+		stats.LastGCPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+	}
+	for i := range min(int(mem.NumGC), len(mem.PauseNs)) {
+		stats.MaxGCPause = max(stats.MaxGCPause, time.Duration(mem.PauseNs[i]))
+	}
+	return stats
+}
+
+// SelfScraper implements the Scraper interface for monitoring the resource usage of the
+// healthcheck process itself, catching leaks such as those of a misbehaving scraper
+type SelfScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	maxGoroutines         int
+	maxHeapMB             int
+	logger                *logrus.Logger
+	stats                 func() selfStats
+}
+
+// NewSelfScraper creates a new scraper of the agent's own resource usage
+func NewSelfScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *SelfScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	maxGoroutines := scraperConfig.MaxGoroutines
+	if maxGoroutines <= 0 {
+		maxGoroutines = defaultMaxGoroutines
+	}
+	maxHeapMB := scraperConfig.MaxHeapMB
+	if maxHeapMB <= 0 {
+		maxHeapMB = defaultMaxHeapMB
+	}
+
+	return &SelfScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		maxGoroutines:         maxGoroutines,
+		maxHeapMB:             maxHeapMB,
+		logger:                logger,
+		stats:                 readSelfStats,
+	}
+}
+
+// Type returns the scraper type identifier
+func (s *SelfScraper) Type() string {
+	return "self"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (s *SelfScraper) GetPingURL() string {
+	return s.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (s *SelfScraper) GetScrapeInterval() int {
+	return s.scrapeIntervalSeconds
+}
+
+// Scrape reads the agent's goroutine count, heap usage and garbage collection pauses, and is
+// unhealthy when the goroutines exceed max_goroutines or the heap exceeds max_heap_mb
+func (s *SelfScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	s.logger.Debug("Starting self healthcheck")
+
+	stats := s.stats()
+	heapMB := float64(stats.HeapAlloc) / (1 << 20)
+	details := map[string]interface{}{
+		"goroutines":        stats.Goroutines,
+		"max_goroutines":    s.maxGoroutines,
+		"heap_alloc_bytes":  stats.HeapAlloc,
+		"heap_sys_bytes":    stats.HeapSys,
+		"heap_alloc_mb":     heapMB,
+		"max_heap_mb":       s.maxHeapMB,
+		"num_gc":            stats.NumGC,
+		"gc_pause_last_ms":  float64(stats.LastGCPause.Microseconds()) / 1000,
+		"gc_pause_max_ms":   float64(stats.MaxGCPause.Microseconds()) / 1000,
+		"gc_pause_total_ms": stats.TotalGCPause.Milliseconds(),
+	}
+
+	var problems []string
+	if stats.Goroutines > s.maxGoroutines {
+		problems = append(problems, fmt.Sprintf("%d goroutines, expected at most %d", stats.Goroutines, s.maxGoroutines))
+	}
+	if heapMB > float64(s.maxHeapMB) {
+		problems = append(problems, fmt.Sprintf("%.1fMB heap, expected at most %dMB", heapMB, s.maxHeapMB))
+	}
+	healthy := len(problems) == 0
+
+	s.logger.WithFields(logrus.Fields{
+		"goroutines":    stats.Goroutines,
+		"heap_alloc_mb": heapMB,
+		"healthy":       healthy,
+	}).Info("Self healthcheck completed")
+
+	message := fmt.Sprintf("Healthcheck process runs %d goroutines with a %.1fMB heap", stats.Goroutines, heapMB)
+	if !healthy {
+		message = fmt.Sprintf("Healthcheck process uses too many resources: %s", strings.Join(problems, ", "))
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfScraper_Scrape(t *testing.T) {
+	tests := []struct {
+		name    string
+		stats   selfStats
+		healthy bool
+		message string
+	}{
+		{
+			name:    "within limits",
+			stats:   selfStats{Goroutines: 40, HeapAlloc: 64 << 20},
+			healthy: true,
+			message: "Healthcheck process runs 40 goroutines with a 64.0MB heap",
+		},
+		{
+			name:    "goroutine leak",
+			stats:   selfStats{Goroutines: 600, HeapAlloc: 64 << 20},
+			healthy: false,
+			message: "Healthcheck process uses too many resources: 600 goroutines, expected at most 500",
+		},
+		{
+			name:    "heap growth",
+			stats:   selfStats{Goroutines: 40, HeapAlloc: 300 << 20},
+			healthy: false,
+			message: "Healthcheck process uses too many resources: 300.0MB heap, expected at most 256MB",
+		},
+		{
+			name:    "both exceeded",
+			stats:   selfStats{Goroutines: 600, HeapAlloc: 300 << 20},
+			healthy: false,
+			message: "Healthcheck process uses too many resources: 600 goroutines, expected at most 500, 300.0MB heap, expected at most 256MB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := NewSelfScraper(config.HealthcheckScraper{MaxGoroutines: 500, MaxHeapMB: 256}, logrus.New())
+			scraper.stats = func() selfStats { return tt.stats }
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy)
+			assert.Equal(t, tt.message, result.Message)
+			assert.Equal(t, tt.stats.Goroutines, result.Details["goroutines"])
+			assert.Equal(t, tt.stats.HeapAlloc, result.Details["heap_alloc_bytes"])
+		})
+	}
+}
+
+func TestSelfScraper_Scrape_GCPauses(t *testing.T) {
+	scraper := NewSelfScraper(config.HealthcheckScraper{}, logrus.New())
+	scraper.stats = func() selfStats {
+		return selfStats{
+			Goroutines:   10,
+			NumGC:        12,
+			LastGCPause:  250 * time.Microsecond,
+			MaxGCPause:   3 * time.Millisecond,
+			TotalGCPause: 9 * time.Millisecond,
+		}
+	}
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, uint32(12), result.Details["num_gc"])
+	assert.Equal(t, 0.25, result.Details["gc_pause_last_ms"])
+	assert.Equal(t, 3.0, result.Details["gc_pause_max_ms"])
+	assert.Equal(t, int64(9), result.Details["gc_pause_total_ms"])
+	assert.Equal(t, defaultMaxGoroutines, result.Details["max_goroutines"])
+	assert.Equal(t, defaultMaxHeapMB, result.Details["max_heap_mb"])
+}
+
+func TestReadSelfStats(t *testing.T) {
+	stats := readSelfStats()
+
+	assert.Positive(t, stats.Goroutines)
+	assert.Positive(t, stats.HeapAlloc)
+	assert.GreaterOrEqual(t, stats.MaxGCPause, stats.LastGCPause)
+}
@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"io"
+	"net/http"
+
+	"healthcheck/pkg/config"
+	"healthcheck/pkg/metrics"
+)
+
+var (
+	scrapeRequestBytes = metrics.DefaultRegistry.NewCounterVec(
+		"healthcheck_scrape_request_bytes_total",
+		"Total bytes of request bodies sent by scrapes.",
+		"scraper",
+	)
+	scrapeResponseBytes = metrics.DefaultRegistry.NewCounterVec(
+		"healthcheck_scrape_response_bytes_total",
+		"Total bytes of response bodies received by scrapes.",
+		"scraper",
+	)
+)
+
+// sizeMetricsTransport counts the request and response body bytes of a scraper's requests
+type sizeMetricsTransport struct {
+	next    http.RoundTripper
+	scraper string
+}
+
+// instrumentTransport wraps the transport to record size metrics when the scraper enables them
+func instrumentTransport(transport http.RoundTripper, scraperConfig config.HealthcheckScraper) http.RoundTripper {
+	if !scraperConfig.SizeMetrics {
+		return transport
+	}
+	return &sizeMetricsTransport{next: transport, scraper: scraperConfig.Name}
+}
+
+// RoundTrip counts the bodies as they are read, so streamed and aborted bodies only
+// account for the bytes actually transferred
+func (t *sizeMetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		// A RoundTripper must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Body = &countingReadCloser{ReadCloser: req.Body, counter: scrapeRequestBytes, scraper: t.scraper}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = &countingReadCloser{ReadCloser: resp.Body, counter: scrapeResponseBytes, scraper: t.scraper}
+	return resp, nil
+}
+
+// countingReadCloser adds the bytes read through it to a counter
+type countingReadCloser struct {
+	io.ReadCloser
+	counter *metrics.CounterVec
+	scraper string
+}
+
+// Read reads from the wrapped body and counts the bytes read
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if n > 0 {
+		c.counter.Add(float64(n), c.scraper)
+	}
+	return n, err
+}
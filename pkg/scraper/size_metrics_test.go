@@ -0,0 +1,47 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentTransport_Disabled(t *testing.T) {
+	transport := http.DefaultTransport
+
+	assert.Equal(t, transport, instrumentTransport(transport, config.HealthcheckScraper{Name: "api"}))
+}
+
+func TestSizeMetricsTransport_CountsBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(config.HealthcheckScraper{Name: "size-metrics-test", SizeMetrics: true})
+	require.NoError(t, err)
+
+	requestBefore := scrapeRequestBytes.Value("size-metrics-test")
+	responseBefore := scrapeResponseBytes.Value("size-metrics-test")
+
+	req, err := http.NewRequestWithContext(context.Background(), "POST", server.URL, strings.NewReader("hello"))
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 5.0, scrapeRequestBytes.Value("size-metrics-test")-requestBefore)
+	assert.Equal(t, 10.0, scrapeResponseBytes.Value("size-metrics-test")-responseBefore)
+}
@@ -0,0 +1,209 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// srvSchemes are the supported ways of checking a discovered target
+var srvSchemes = map[string]bool{"tcp": true, "http": true, "https": true}
+
+// srvTarget is the result of checking one discovered target
+type srvTarget struct {
+	Target     string `json:"target"`
+	Healthy    bool   `json:"healthy"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SRVDiscoveryScraper implements the Scraper interface for checking every instance of a
+// service discovered through a DNS SRV record
+type SRVDiscoveryScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	scheme                string
+	logger                *logrus.Logger
+	client                *http.Client
+	lookupSRV             func(ctx context.Context, name string) ([]*net.SRV, error)
+	dial                  func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// NewSRVDiscoveryScraper creates a new SRV discovery scraper
+func NewSRVDiscoveryScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) *SRVDiscoveryScraper {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	scheme := scraperConfig.SRVScheme
+	if scheme == "" {
+		scheme = "tcp"
+	}
+
+	var dialer net.Dialer
+	return &SRVDiscoveryScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		scheme:                scheme,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		lookupSRV: lookupSRV,
+		dial:      dialer.DialContext,
+	}
+}
+
+// lookupSRV resolves the SRV record with the system resolver
+func lookupSRV(ctx context.Context, name string) ([]*net.SRV, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	return records, err
+}
+
+// Type returns the scraper type identifier
+func (s *SRVDiscoveryScraper) Type() string {
+	return "srv-discovery"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (s *SRVDiscoveryScraper) GetPingURL() string {
+	return s.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (s *SRVDiscoveryScraper) GetScrapeInterval() int {
+	return s.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (s *SRVDiscoveryScraper) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape resolves the SRV record, checks every discovered target concurrently and is
+// healthy if at least the quorum of them are healthy
+func (s *SRVDiscoveryScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	name := s.config.SRVName
+	s.logger.WithField("srv_name", name).Debug("Starting SRV discovery healthcheck")
+
+	details := map[string]interface{}{
+		"srv_name": name,
+	}
+
+	records, err := s.lookupSRV(ctx, name)
+	if err != nil {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to resolve SRV record %s: %v", name, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	targets := make([]srvTarget, len(records))
+	var wg sync.WaitGroup
+	for i, record := range records {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			address := net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+			targets[i] = s.checkTarget(ctx, address)
+		}()
+	}
+	wg.Wait()
+
+	healthyTargets := 0
+	for _, target := range targets {
+		if target.Healthy {
+			healthyTargets++
+		}
+	}
+
+	// Without a configured quorum a majority of the targets must be healthy
+	quorum := s.config.Quorum
+	if quorum <= 0 {
+		quorum = len(targets)/2 + 1
+	}
+	healthy := len(targets) > 0 && healthyTargets >= quorum
+
+	details["targets"] = targets
+	details["total_targets"] = len(targets)
+	details["healthy_targets"] = healthyTargets
+	details["quorum"] = quorum
+
+	s.logger.WithFields(logrus.Fields{
+		"srv_name":        name,
+		"total_targets":   len(targets),
+		"healthy_targets": healthyTargets,
+		"healthy":         healthy,
+	}).Info("SRV discovery healthcheck completed")
+
+	var message string
+	switch {
+	case len(targets) == 0:
+		message = fmt.Sprintf("SRV record %s has no targets", name)
+	case healthy:
+		message = fmt.Sprintf("%d of %d targets of %s are healthy", healthyTargets, len(targets), name)
+	default:
+		message = fmt.Sprintf("%d of %d targets of %s are healthy, expected at least %d", healthyTargets, len(targets), name, quorum)
+	}
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// checkTarget checks a discovered target by connecting to it, or for the http and https
+// schemes by requesting the configured path and expecting a 2xx status
+func (s *SRVDiscoveryScraper) checkTarget(ctx context.Context, address string) (target srvTarget) {
+	target.Target = address
+	start := time.Now()
+	defer func() {
+		target.LatencyMs = time.Since(start).Milliseconds()
+	}()
+
+	if s.scheme == "tcp" {
+		conn, err := s.dial(ctx, "tcp", address)
+		if err != nil {
+			target.Error = err.Error()
+			return target
+		}
+		conn.Close()
+		target.Healthy = true
+		return target
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.scheme+"://"+address+s.config.SRVPath, nil)
+	if err != nil {
+		target.Error = err.Error()
+		return target
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		target.Error = err.Error()
+		return target
+	}
+	resp.Body.Close()
+
+	target.StatusCode = resp.StatusCode
+	target.Healthy = resp.StatusCode >= 200 && resp.StatusCode <= 299
+	return target
+}
@@ -0,0 +1,144 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// srvRecord returns an SRV record pointing at the host:port of the given address
+func srvRecord(t *testing.T, address string) *net.SRV {
+	host, port, err := net.SplitHostPort(address)
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return &net.SRV{Target: host + ".", Port: uint16(p)}
+}
+
+// closedAddress returns the address of a listener that is no longer accepting connections
+func closedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	return address
+}
+
+// newTestSRVDiscoveryScraper creates an SRV discovery scraper resolving to the given records
+func newTestSRVDiscoveryScraper(scraperConfig config.HealthcheckScraper, records ...*net.SRV) *SRVDiscoveryScraper {
+	scraperConfig.SRVName = "_http._tcp.api.service.consul"
+	scraper := NewSRVDiscoveryScraper(scraperConfig, logrus.New())
+	scraper.lookupSRV = func(ctx context.Context, name string) ([]*net.SRV, error) {
+		return records, nil
+	}
+	return scraper
+}
+
+func TestNewSRVDiscoveryScraper(t *testing.T) {
+	scraper := NewSRVDiscoveryScraper(config.HealthcheckScraper{
+		SRVName: "_http._tcp.api.service.consul",
+		PingURL: "http://localhost:8081/ping",
+	}, logrus.New())
+
+	assert.Equal(t, "srv-discovery", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+	assert.Equal(t, "tcp", scraper.scheme)
+}
+
+func TestSRVDiscoveryScraper_Scrape_TCP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	up := srvRecord(t, server.Listener.Addr().String())
+	down := srvRecord(t, closedAddress(t))
+
+	tests := []struct {
+		name    string
+		records []*net.SRV
+		quorum  int
+		healthy bool
+		up      int
+	}{
+		{name: "all targets up", records: []*net.SRV{up, up}, healthy: true, up: 2},
+		{name: "majority down", records: []*net.SRV{up, down, down}, healthy: false, up: 1},
+		{name: "configured quorum met", records: []*net.SRV{up, down, down}, quorum: 1, healthy: true, up: 1},
+		{name: "no targets", records: nil, healthy: false, up: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := newTestSRVDiscoveryScraper(config.HealthcheckScraper{Quorum: tt.quorum}, tt.records...)
+
+			result, err := scraper.Scrape(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, len(tt.records), result.Details["total_targets"])
+			assert.Equal(t, tt.up, result.Details["healthy_targets"])
+			assert.Len(t, result.Details["targets"], len(tt.records))
+		})
+	}
+}
+
+func TestSRVDiscoveryScraper_Scrape_HTTP(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	scraper := newTestSRVDiscoveryScraper(config.HealthcheckScraper{SRVScheme: "http", SRVPath: "/health"},
+		srvRecord(t, healthy.Listener.Addr().String()),
+		srvRecord(t, failing.Listener.Addr().String()),
+	)
+
+	result, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, 2, result.Details["quorum"])
+
+	targets := result.Details["targets"].([]srvTarget)
+	assert.Equal(t, healthy.Listener.Addr().String(), targets[0].Target)
+	assert.True(t, targets[0].Healthy)
+	assert.Equal(t, http.StatusOK, targets[0].StatusCode)
+	assert.False(t, targets[1].Healthy)
+	assert.Equal(t, http.StatusServiceUnavailable, targets[1].StatusCode)
+}
+
+func TestSRVDiscoveryScraper_Scrape_LookupError(t *testing.T) {
+	scraper := newTestSRVDiscoveryScraper(config.HealthcheckScraper{})
+	scraper.lookupSRV = func(ctx context.Context, name string) ([]*net.SRV, error) {
+		return nil, errors.New("no such host")
+	}
+
+	result, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to resolve SRV record _http._tcp.api.service.consul")
+}
+
+func TestFactory_CreateScraper_SRVDiscovery_Validation(t *testing.T) {
+	factory := NewFactory(logrus.New())
+
+	_, err := factory.CreateScraper(config.HealthcheckScraper{Type: "srv-discovery"})
+	assert.ErrorContains(t, err, "requires a srv_name")
+
+	_, err = factory.CreateScraper(config.HealthcheckScraper{Type: "srv-discovery", SRVName: "_api._tcp.example.com", SRVScheme: "udp"})
+	assert.ErrorContains(t, err, "unsupported srv_scheme")
+
+	scraper, err := factory.CreateScraper(config.HealthcheckScraper{Type: "srv-discovery", SRVName: "_api._tcp.example.com", SRVScheme: "https"})
+	require.NoError(t, err)
+	assert.Equal(t, "srv-discovery", scraper.Type())
+}
@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// starttlsProtocol upgrades a plaintext connection to the point where the TLS handshake starts
+type starttlsProtocol struct {
+	defaultPort string
+	negotiate   func(conn net.Conn) error
+}
+
+// starttlsProtocols holds the protocols the TLS scraper can negotiate STARTTLS for
+var starttlsProtocols = map[string]starttlsProtocol{
+	"imap":     {defaultPort: "143", negotiate: negotiateIMAP},
+	"postgres": {defaultPort: "5432", negotiate: negotiatePostgres},
+	"smtp":     {defaultPort: "25", negotiate: negotiateSMTP},
+}
+
+// starttlsProtocolNames returns the supported STARTTLS protocols sorted by name
+func starttlsProtocolNames() string {
+	names := make([]string, 0, len(starttlsProtocols))
+	for name := range starttlsProtocols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// negotiateSMTP reads the greeting, introduces itself and issues STARTTLS
+func negotiateSMTP(conn net.Conn) error {
+	text := textproto.NewConn(conn)
+
+	if _, _, err := text.ReadResponse(220); err != nil {
+		return fmt.Errorf("SMTP greeting: %w", err)
+	}
+
+	if err := text.PrintfLine("EHLO healthcheck"); err != nil {
+		return err
+	}
+	_, capabilities, err := text.ReadResponse(250)
+	if err != nil {
+		return fmt.Errorf("SMTP EHLO: %w", err)
+	}
+	if !strings.Contains(strings.ToUpper(capabilities), "STARTTLS") {
+		return fmt.Errorf("SMTP server does not offer STARTTLS")
+	}
+
+	if err := text.PrintfLine("STARTTLS"); err != nil {
+		return err
+	}
+	if _, _, err := text.ReadResponse(220); err != nil {
+		return fmt.Errorf("SMTP STARTTLS: %w", err)
+	}
+
+	return nil
+}
+
+// negotiateIMAP reads the greeting and issues STARTTLS
+func negotiateIMAP(conn net.Conn) error {
+	text := textproto.NewConn(conn)
+
+	greeting, err := text.ReadLine()
+	if err != nil {
+		return fmt.Errorf("IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("IMAP greeting: %s", greeting)
+	}
+
+	if err := text.PrintfLine("hc1 STARTTLS"); err != nil {
+		return err
+	}
+
+	// Skip untagged responses until the tagged completion
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return fmt.Errorf("IMAP STARTTLS: %w", err)
+		}
+		if strings.HasPrefix(line, "hc1 ") {
+			if !strings.HasPrefix(line, "hc1 OK") {
+				return fmt.Errorf("IMAP STARTTLS: %s", strings.TrimPrefix(line, "hc1 "))
+			}
+			return nil
+		}
+	}
+}
+
+// postgresSSLRequestCode is the protocol version number of a PostgreSQL SSLRequest message
+const postgresSSLRequestCode = 80877103
+
+// negotiatePostgres sends an SSLRequest and expects the server to accept it
+func negotiatePostgres(conn net.Conn) error {
+	request := binary.BigEndian.AppendUint32(nil, 8)
+	request = binary.BigEndian.AppendUint32(request, postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	// Read exactly one byte so nothing of the handshake is consumed
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return fmt.Errorf("PostgreSQL SSLRequest: %w", err)
+	}
+	if response[0] != 'S' {
+		return fmt.Errorf("PostgreSQL server does not accept SSL")
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSTARTTLSServer accepts connections, runs the plaintext exchange and then completes
+// a TLS handshake with the certificate if the exchange returns true
+func startSTARTTLSServer(t *testing.T, cert tls.Certificate, exchange func(conn net.Conn, r *bufio.Reader) bool) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if exchange(conn, bufio.NewReader(conn)) {
+					tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// smtpExchange plays an SMTP server offering the given extensions
+func smtpExchange(extensions ...string) func(conn net.Conn, r *bufio.Reader) bool {
+	return func(conn net.Conn, r *bufio.Reader) bool {
+		io.WriteString(conn, "220 mail.example.com ESMTP\r\n")
+		if line, _ := r.ReadString('\n'); !strings.HasPrefix(line, "EHLO") {
+			return false
+		}
+		io.WriteString(conn, "250-mail.example.com\r\n")
+		for _, extension := range extensions {
+			io.WriteString(conn, "250-"+extension+"\r\n")
+		}
+		io.WriteString(conn, "250 8BITMIME\r\n")
+		if line, _ := r.ReadString('\n'); line != "STARTTLS\r\n" {
+			return false
+		}
+		io.WriteString(conn, "220 Ready to start TLS\r\n")
+		return true
+	}
+}
+
+// imapExchange plays an IMAP server answering STARTTLS with the given tagged status
+func imapExchange(status string) func(conn net.Conn, r *bufio.Reader) bool {
+	return func(conn net.Conn, r *bufio.Reader) bool {
+		io.WriteString(conn, "* OK [CAPABILITY IMAP4rev1 STARTTLS] ready\r\n")
+		line, _ := r.ReadString('\n')
+		tag, command, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if command != "STARTTLS" {
+			return false
+		}
+		io.WriteString(conn, "* CAPABILITY IMAP4rev1 STARTTLS\r\n")
+		io.WriteString(conn, tag+" "+status+"\r\n")
+		return strings.HasPrefix(status, "OK")
+	}
+}
+
+// postgresExchange plays a PostgreSQL server answering an SSLRequest with the given byte
+func postgresExchange(answer byte) func(conn net.Conn, r *bufio.Reader) bool {
+	return func(conn net.Conn, r *bufio.Reader) bool {
+		request := make([]byte, 8)
+		if _, err := io.ReadFull(r, request); err != nil {
+			return false
+		}
+		if binary.BigEndian.Uint32(request[4:]) != postgresSSLRequestCode {
+			return false
+		}
+		conn.Write([]byte{answer})
+		return answer == 'S'
+	}
+}
+
+func TestTLSScraper_Scrape_StartTLS(t *testing.T) {
+	tests := []struct {
+		protocol string
+		exchange func(conn net.Conn, r *bufio.Reader) bool
+		healthy  bool
+		message  string
+	}{
+		{protocol: "smtp", exchange: smtpExchange("STARTTLS"), healthy: true},
+		{protocol: "smtp", exchange: smtpExchange(), message: "does not offer STARTTLS"},
+		{protocol: "imap", exchange: imapExchange("OK Begin TLS negotiation now"), healthy: true},
+		{protocol: "imap", exchange: imapExchange("BAD STARTTLS disabled"), message: "BAD STARTTLS disabled"},
+		{protocol: "postgres", exchange: postgresExchange('S'), healthy: true},
+		{protocol: "postgres", exchange: postgresExchange('N'), message: "does not accept SSL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol+" "+tt.message, func(t *testing.T) {
+			cert, roots := newTestCertificate(t, 90*24*time.Hour)
+			address := startSTARTTLSServer(t, cert, tt.exchange)
+			scraper := newTestTLSScraper(t, address, roots, config.HealthcheckScraper{StartTLS: tt.protocol})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := scraper.Scrape(ctx)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.protocol, result.Details["starttls"])
+			if tt.healthy {
+				assert.Equal(t, "CN=healthcheck test", result.Details["subject"])
+				assert.Contains(t, result.Details, "days_remaining")
+			} else {
+				assert.Contains(t, result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestTLSScraper_Scrape_StartTLSTimeout(t *testing.T) {
+	cert, roots := newTestCertificate(t, 90*24*time.Hour)
+	// A server that never sends its greeting
+	address := startSTARTTLSServer(t, cert, func(conn net.Conn, r *bufio.Reader) bool {
+		r.ReadByte()
+		return false
+	})
+	scraper := newTestTLSScraper(t, address, roots, config.HealthcheckScraper{StartTLS: "smtp"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	result, err := scraper.Scrape(ctx)
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "STARTTLS negotiation")
+}
+
+func TestNewTLSScraper_StartTLS(t *testing.T) {
+	scraper, err := NewTLSScraper(config.HealthcheckScraper{ScrapeURL: "mail.example.com", StartTLS: "smtp"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mail.example.com:25", scraper.address)
+
+	scraper, err = NewTLSScraper(config.HealthcheckScraper{ScrapeURL: "mail.example.com:587", StartTLS: "smtp"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mail.example.com:587", scraper.address)
+
+	_, err = NewTLSScraper(config.HealthcheckScraper{ScrapeURL: "ldap.example.com", StartTLS: "ldap"}, nil)
+	assert.ErrorContains(t, err, "imap, postgres, smtp")
+}
@@ -0,0 +1,370 @@
+package scraper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultSTUNPort is the port of STUN and TURN servers when the target has none
+	defaultSTUNPort = "3478"
+	// stunInitialRTO is how long the first binding request waits for a response before it is
+	// retransmitted, doubling for every retransmission as in RFC 8489
+	stunInitialRTO = 500 * time.Millisecond
+	// defaultSTUNTimeout bounds a binding transaction when the scrape's context has no deadline
+	defaultSTUNTimeout = 5 * time.Second
+
+	stunMagicCookie          = 0x2112A442
+	stunHeaderSize           = 20
+	stunBindingRequest       = 0x0001
+	stunBindingSuccess       = 0x0101
+	stunBindingError         = 0x0111
+	stunAttrMappedAddress    = 0x0001
+	stunAttrErrorCode        = 0x0009
+	stunAttrXORMappedAddress = 0x0020
+	stunAttrSoftware         = 0x8022
+)
+
+// stunResponse is a parsed binding response
+type stunResponse struct {
+	mappedAddress *net.UDPAddr
+	// xor is set when the address came from XOR-MAPPED-ADDRESS rather than MAPPED-ADDRESS
+	xor      bool
+	software string
+	// errorCode and errorReason are set for a binding error response
+	errorCode   int
+	errorReason string
+}
+
+// STUNScraper implements the Scraper interface for checking a STUN or TURN server answers
+// binding requests with the client's reflexive address
+type STUNScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	address               string
+	dial                  func(ctx context.Context, network, addr string) (net.Conn, error)
+	logger                *logrus.Logger
+}
+
+// NewSTUNScraper creates a new STUN scraper for the host:port or stun:/turn: URI in the
+// scrape URL
+func NewSTUNScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (*STUNScraper, error) {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	address, err := parseSTUNAddress(scraperConfig.ScrapeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	return &STUNScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		address:               address,
+		dial:                  dialer.DialContext,
+		logger:                logger,
+	}, nil
+}
+
+// parseSTUNAddress returns the address to send binding requests to for a host:port or a
+// STUN URI such as stun:stun.example.com:3478, defaulting to port 3478
+func parseSTUNAddress(target string) (string, error) {
+	host := target
+	for _, scheme := range []string{"stun://", "turn://", "stun:", "turn:"} {
+		if strings.HasPrefix(host, scheme) {
+			host = strings.TrimPrefix(host, scheme)
+			break
+		}
+	}
+	// Transport parameters such as ?transport=udp do not apply, requests are always sent over UDP
+	host, _, _ = strings.Cut(host, "?")
+
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname, port = strings.Trim(host, "[]"), defaultSTUNPort
+	}
+	if hostname == "" {
+		return "", fmt.Errorf("invalid STUN target %q: missing host", target)
+	}
+	return net.JoinHostPort(hostname, port), nil
+}
+
+// Type returns the scraper type identifier
+func (s *STUNScraper) Type() string {
+	return "stun"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (s *STUNScraper) GetPingURL() string {
+	return s.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (s *STUNScraper) GetScrapeInterval() int {
+	return s.scrapeIntervalSeconds
+}
+
+// Scrape sends a binding request to the server and is healthy when it answers with a
+// binding success response carrying the mapped address, within max_latency_ms if set
+func (s *STUNScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	s.logger.WithField("address", s.address).Debug("Starting STUN healthcheck")
+
+	details := map[string]interface{}{
+		"address": s.address,
+	}
+	unhealthy := func(message string, err error) (*ScrapeResult, error) {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("%s: %v", message, err),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSTUNTimeout)
+		defer cancel()
+	}
+
+	conn, err := s.dial(ctx, "udp", s.address)
+	if err != nil {
+		return unhealthy(fmt.Sprintf("Failed to connect to %s", s.address), err)
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	response, attempts, err := stunBinding(ctx, conn)
+	latency := time.Since(start)
+	details["attempts"] = attempts
+	if err != nil {
+		return unhealthy(fmt.Sprintf("STUN binding request to %s failed", s.address), err)
+	}
+	setLatency(details, latency)
+	if response.software != "" {
+		details["software"] = response.software
+	}
+
+	if response.errorCode != 0 {
+		details["error_code"] = response.errorCode
+		details["error_reason"] = response.errorReason
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("STUN server %s answered with error %d %s", s.address, response.errorCode, response.errorReason),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+
+	details["mapped_address"] = response.mappedAddress.String()
+	details["mapped_address_xor"] = response.xor
+
+	healthy := true
+	message := fmt.Sprintf("STUN server %s returned mapped address %s in %dms", s.address, response.mappedAddress, latency.Milliseconds())
+	if s.config.MaxLatencyMs > 0 && latency > time.Duration(s.config.MaxLatencyMs)*time.Millisecond {
+		healthy = false
+		message = fmt.Sprintf("STUN server %s answered in %dms, above the maximum of %dms", s.address, latency.Milliseconds(), s.config.MaxLatencyMs)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"address":        s.address,
+		"mapped_address": response.mappedAddress.String(),
+		"healthy":        healthy,
+	}).Info("STUN healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// stunBinding sends a binding request over the connection, retransmitting it with a doubling
+// timeout until a response to it arrives or the context is done, and returns the response
+// and the number of requests sent. Datagrams that are not a response to the request are
+// ignored, while a malformed response to it fails the transaction.
+func stunBinding(ctx context.Context, conn net.Conn) (*stunResponse, int, error) {
+	var transactionID [12]byte
+	if _, err := rand.Read(transactionID[:]); err != nil {
+		return nil, 0, err
+	}
+	request := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint16(request[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint32(request[4:8], stunMagicCookie)
+	copy(request[8:20], transactionID[:])
+
+	// Cancelling the scrape interrupts a pending read
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	deadline, _ := ctx.Deadline()
+	buf := make([]byte, 1500)
+	rto := stunInitialRTO
+	attempts := 0
+	var malformed error
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, attempts, stunTimeoutError(err, malformed)
+		}
+		if !time.Now().Before(deadline) {
+			return nil, attempts, stunTimeoutError(context.DeadlineExceeded, malformed)
+		}
+		if _, err := conn.Write(request); err != nil {
+			return nil, attempts, err
+		}
+		attempts++
+
+		retransmit := time.Now().Add(rto)
+		rto *= 2
+		if retransmit.After(deadline) {
+			retransmit = deadline
+		}
+		conn.SetReadDeadline(retransmit)
+
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				// A refused port surfaces as a read error on connected UDP sockets
+				return nil, attempts, err
+			}
+			if n < stunHeaderSize || binary.BigEndian.Uint32(buf[4:8]) != stunMagicCookie {
+				malformed = fmt.Errorf("%d byte datagram is not a STUN message", n)
+				continue
+			}
+			if [12]byte(buf[8:20]) != transactionID {
+				continue
+			}
+			response, err := parseSTUNResponse(buf[:n])
+			if err != nil {
+				return nil, attempts, fmt.Errorf("malformed response: %w", err)
+			}
+			return response, attempts, nil
+		}
+	}
+}
+
+// stunTimeoutError describes a binding transaction that got no valid response in time,
+// mentioning the last malformed response if any arrived
+func stunTimeoutError(err, malformed error) error {
+	if malformed != nil {
+		return fmt.Errorf("no valid response before %v, last response was malformed: %v", err, malformed)
+	}
+	return fmt.Errorf("no response before %v", err)
+}
+
+// parseSTUNResponse parses a binding response whose header was checked to be a STUN message
+func parseSTUNResponse(message []byte) (*stunResponse, error) {
+	messageType := binary.BigEndian.Uint16(message[0:2])
+	length := int(binary.BigEndian.Uint16(message[2:4]))
+	if length != len(message)-stunHeaderSize {
+		return nil, fmt.Errorf("message length %d does not match the %d bytes of attributes received", length, len(message)-stunHeaderSize)
+	}
+	if messageType != stunBindingSuccess && messageType != stunBindingError {
+		return nil, fmt.Errorf("unexpected message type 0x%04x", messageType)
+	}
+
+	response := &stunResponse{}
+	attributes := message[stunHeaderSize:]
+	for len(attributes) > 0 {
+		if len(attributes) < 4 {
+			return nil, fmt.Errorf("truncated attribute header")
+		}
+		attrType := binary.BigEndian.Uint16(attributes[0:2])
+		attrLength := int(binary.BigEndian.Uint16(attributes[2:4]))
+		if 4+attrLength > len(attributes) {
+			return nil, fmt.Errorf("attribute 0x%04x of %d bytes exceeds the message", attrType, attrLength)
+		}
+		value := attributes[4 : 4+attrLength]
+
+		switch attrType {
+		case stunAttrXORMappedAddress:
+			addr, err := parseSTUNAddressAttribute(value, message[4:20])
+			if err != nil {
+				return nil, fmt.Errorf("invalid XOR-MAPPED-ADDRESS: %w", err)
+			}
+			response.mappedAddress, response.xor = addr, true
+		case stunAttrMappedAddress:
+			if response.mappedAddress == nil {
+				addr, err := parseSTUNAddressAttribute(value, nil)
+				if err != nil {
+					return nil, fmt.Errorf("invalid MAPPED-ADDRESS: %w", err)
+				}
+				response.mappedAddress = addr
+			}
+		case stunAttrErrorCode:
+			if len(value) < 4 {
+				return nil, fmt.Errorf("invalid ERROR-CODE of %d bytes", len(value))
+			}
+			response.errorCode = int(value[2]&0x07)*100 + int(value[3])
+			response.errorReason = string(value[4:])
+		case stunAttrSoftware:
+			response.software = string(value)
+		}
+
+		// Attributes are padded to a multiple of 4 bytes
+		padded := (4 + attrLength + 3) &^ 3
+		attributes = attributes[min(padded, len(attributes)):]
+	}
+
+	if messageType == stunBindingError {
+		if response.errorCode == 0 {
+			return nil, fmt.Errorf("binding error response without an ERROR-CODE")
+		}
+		return response, nil
+	}
+	if response.mappedAddress == nil {
+		return nil, fmt.Errorf("binding success response without a mapped address")
+	}
+	return response, nil
+}
+
+// parseSTUNAddressAttribute parses a MAPPED-ADDRESS, or an XOR-MAPPED-ADDRESS when given the
+// magic cookie and transaction ID it is XORed with
+func parseSTUNAddressAttribute(value, xorKey []byte) (*net.UDPAddr, error) {
+	if len(value) < 4 {
+		return nil, fmt.Errorf("attribute of %d bytes is too short", len(value))
+	}
+	var size int
+	switch value[1] {
+	case 0x01:
+		size = net.IPv4len
+	case 0x02:
+		size = net.IPv6len
+	default:
+		return nil, fmt.Errorf("unknown address family 0x%02x", value[1])
+	}
+	if len(value) != 4+size {
+		return nil, fmt.Errorf("address of %d bytes does not match its family", len(value)-4)
+	}
+
+	port := binary.BigEndian.Uint16(value[2:4])
+	ip := make(net.IP, size)
+	copy(ip, value[4:])
+	if xorKey != nil {
+		port ^= uint16(stunMagicCookie >> 16)
+		for i := range ip {
+			ip[i] ^= xorKey[i]
+		}
+	}
+	return &net.UDPAddr{IP: ip, Port: int(port)}, nil
+}
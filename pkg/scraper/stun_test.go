@@ -0,0 +1,208 @@
+package scraper
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stunAttribute encodes a STUN attribute with its padding
+func stunAttribute(attrType uint16, value []byte) []byte {
+	attr := make([]byte, 4, 4+len(value)+3)
+	binary.BigEndian.PutUint16(attr[0:2], attrType)
+	binary.BigEndian.PutUint16(attr[2:4], uint16(len(value)))
+	attr = append(attr, value...)
+	for len(attr)%4 != 0 {
+		attr = append(attr, 0)
+	}
+	return attr
+}
+
+// stunMessage encodes a STUN message answering the request with the given attributes
+func stunMessage(messageType uint16, request []byte, attributes ...[]byte) []byte {
+	message := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint16(message[0:2], messageType)
+	copy(message[4:20], request[4:20])
+	for _, attr := range attributes {
+		message = append(message, attr...)
+	}
+	binary.BigEndian.PutUint16(message[2:4], uint16(len(message)-stunHeaderSize))
+	return message
+}
+
+// xorMappedAddress encodes the client's IPv4 address as an XOR-MAPPED-ADDRESS
+func xorMappedAddress(addr *net.UDPAddr) []byte {
+	value := make([]byte, 8)
+	value[1] = 0x01
+	binary.BigEndian.PutUint16(value[2:4], uint16(addr.Port)^uint16(stunMagicCookie>>16))
+	binary.BigEndian.PutUint32(value[4:8], binary.BigEndian.Uint32(addr.IP.To4())^stunMagicCookie)
+	return value
+}
+
+// newSTUNTestServer starts a UDP responder answering every request with the reply built by
+// respond, sending nothing when it returns nil, and returns its address
+func newSTUNTestServer(t *testing.T, respond func(request []byte, from *net.UDPAddr) []byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := respond(buf[:n], from.(*net.UDPAddr)); reply != nil {
+				conn.WriteTo(reply, from)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// scrapeSTUN scrapes the STUN server at the address within the timeout
+func scrapeSTUN(t *testing.T, address string, timeout time.Duration) *ScrapeResult {
+	scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{Type: "stun", ScrapeURL: "stun:" + address})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result, err := scraper.Scrape(ctx)
+	require.NoError(t, err)
+	return result
+}
+
+func TestNewSTUNScraper(t *testing.T) {
+	scraper, err := NewSTUNScraper(config.HealthcheckScraper{
+		ScrapeURL: "stun:stun.example.com",
+		PingURL:   "http://localhost:8081/ping",
+	}, logrus.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, "stun", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+	assert.Equal(t, "stun.example.com:3478", scraper.address)
+}
+
+func TestParseSTUNAddress(t *testing.T) {
+	tests := map[string]string{
+		"stun.example.com":                    "stun.example.com:3478",
+		"stun:stun.example.com:19302":         "stun.example.com:19302",
+		"turn:turn.example.com?transport=udp": "turn.example.com:3478",
+		"stun://[2001:db8::1]:3479":           "[2001:db8::1]:3479",
+		"turn://turn.example.com:5349?x=y":    "turn.example.com:5349",
+		"192.0.2.10:3478":                     "192.0.2.10:3478",
+	}
+	for target, want := range tests {
+		got, err := parseSTUNAddress(target)
+		require.NoError(t, err, target)
+		assert.Equal(t, want, got, target)
+	}
+
+	_, err := parseSTUNAddress("stun:")
+	assert.Error(t, err)
+}
+
+func TestSTUNScraper_Scrape_Success(t *testing.T) {
+	address := newSTUNTestServer(t, func(request []byte, from *net.UDPAddr) []byte {
+		return stunMessage(stunBindingSuccess, request,
+			stunAttribute(stunAttrSoftware, []byte("coturn")),
+			stunAttribute(stunAttrXORMappedAddress, xorMappedAddress(from)))
+	})
+
+	result := scrapeSTUN(t, address, 2*time.Second)
+
+	assert.True(t, result.Healthy, result.Message)
+	assert.Contains(t, result.Details["mapped_address"], "127.0.0.1:")
+	assert.Equal(t, true, result.Details["mapped_address_xor"])
+	assert.Equal(t, "coturn", result.Details["software"])
+	assert.Equal(t, 1, result.Details["attempts"])
+}
+
+func TestSTUNScraper_Scrape_Retransmits(t *testing.T) {
+	requests := 0
+	address := newSTUNTestServer(t, func(request []byte, from *net.UDPAddr) []byte {
+		requests++
+		if requests == 1 {
+			return nil
+		}
+		return stunMessage(stunBindingSuccess, request, stunAttribute(stunAttrXORMappedAddress, xorMappedAddress(from)))
+	})
+
+	result := scrapeSTUN(t, address, 2*time.Second)
+
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, 2, result.Details["attempts"])
+}
+
+func TestSTUNScraper_Scrape_Unhealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(request []byte, from *net.UDPAddr) []byte
+		message string
+	}{
+		{
+			name: "error response",
+			respond: func(request []byte, from *net.UDPAddr) []byte {
+				return stunMessage(stunBindingError, request, stunAttribute(stunAttrErrorCode, append([]byte{0, 0, 4, 20}, "Unknown Attribute"...)))
+			},
+			message: "error 420 Unknown Attribute",
+		},
+		{
+			name: "missing mapped address",
+			respond: func(request []byte, from *net.UDPAddr) []byte {
+				return stunMessage(stunBindingSuccess, request)
+			},
+			message: "without a mapped address",
+		},
+		{
+			name: "other transaction",
+			respond: func(request []byte, from *net.UDPAddr) []byte {
+				other := append([]byte(nil), request...)
+				other[19] ^= 0xff
+				return stunMessage(stunBindingSuccess, other, stunAttribute(stunAttrXORMappedAddress, xorMappedAddress(from)))
+			},
+			message: "no response",
+		},
+		{
+			name: "truncated",
+			respond: func(request []byte, from *net.UDPAddr) []byte {
+				return stunMessage(stunBindingSuccess, request, stunAttribute(stunAttrXORMappedAddress, xorMappedAddress(from)))[:24]
+			},
+			message: "malformed",
+		},
+		{
+			name: "not stun",
+			respond: func(request []byte, from *net.UDPAddr) []byte {
+				return []byte("HTTP/1.1 400 Bad Request\r\n\r\n")
+			},
+			message: "not a STUN message",
+		},
+		{
+			name: "no response",
+			respond: func(request []byte, from *net.UDPAddr) []byte {
+				return nil
+			},
+			message: "no response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := scrapeSTUN(t, newSTUNTestServer(t, tt.respond), 200*time.Millisecond)
+
+			assert.False(t, result.Healthy)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
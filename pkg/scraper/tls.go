@@ -0,0 +1,259 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TLSScraper implements the Scraper interface for checking a TLS endpoint's handshake and certificate
+type TLSScraper struct {
+	config                config.HealthcheckScraper
+	scrapeIntervalSeconds int
+	address               string
+	serverName            string
+	rootCAs               *x509.CertPool
+	minTLSVersion         uint16
+	crls                  *crlCache
+	dial                  func(ctx context.Context, network, addr string) (net.Conn, error)
+	logger                *logrus.Logger
+}
+
+// NewTLSScraper creates a new TLS scraper for the host:port or https:// URL in the scrape URL
+func NewTLSScraper(scraperConfig config.HealthcheckScraper, logger *logrus.Logger) (*TLSScraper, error) {
+	scrapeIntervalSeconds := scraperConfig.ScrapeIntervalSeconds
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	defaultPort := "443"
+	if scraperConfig.StartTLS != "" {
+		protocol, ok := starttlsProtocols[scraperConfig.StartTLS]
+		if !ok {
+			return nil, fmt.Errorf("tls scraper has unsupported starttls protocol %q, supported: %s", scraperConfig.StartTLS, starttlsProtocolNames())
+		}
+		defaultPort = protocol.defaultPort
+	}
+
+	address, serverName, err := parseTLSAddress(scraperConfig.ScrapeURL, defaultPort)
+	if err != nil {
+		return nil, err
+	}
+
+	minTLSVersion, err := parseTLSVersion(scraperConfig.MinTLSVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	return &TLSScraper{
+		config:                scraperConfig,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		address:               address,
+		serverName:            serverName,
+		minTLSVersion:         minTLSVersion,
+		dial:                  dialer.DialContext,
+		crls:                  sharedCRLCache,
+		logger:                logger,
+	}, nil
+}
+
+// parseTLSAddress returns the address to dial and the server name to verify for a
+// host:port or URL, defaulting to the given port
+func parseTLSAddress(target, defaultPort string) (string, string, error) {
+	host := target
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid TLS target %s: %w", target, err)
+		}
+		host = u.Host
+	}
+
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname, port = strings.Trim(host, "[]"), defaultPort
+	}
+	if hostname == "" {
+		return "", "", fmt.Errorf("invalid TLS target %s: missing host", target)
+	}
+
+	return net.JoinHostPort(hostname, port), hostname, nil
+}
+
+// Type returns the scraper type identifier
+func (t *TLSScraper) Type() string {
+	return "tls"
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (t *TLSScraper) GetPingURL() string {
+	return t.config.PingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (t *TLSScraper) GetScrapeInterval() int {
+	return t.scrapeIntervalSeconds
+}
+
+// Scrape performs a TLS handshake with the endpoint and checks its certificate
+func (t *TLSScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	t.logger.WithField("address", t.address).Debug("Starting TLS healthcheck")
+
+	details := map[string]interface{}{
+		"address": t.address,
+	}
+	if t.config.StartTLS != "" {
+		details["starttls"] = t.config.StartTLS
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: t.serverName,
+		RootCAs:    t.rootCAs,
+		MinVersion: t.minTLSVersion,
+	}
+	// Both connections of a resumption check share the cache the session is resumed from
+	if t.config.CheckResumption {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(1)
+	}
+
+	tlsConn, latency, message, err := t.handshake(ctx, tlsConfig)
+	if err != nil {
+		details["error"] = err.Error()
+		return &ScrapeResult{
+			Healthy:   false,
+			Message:   message,
+			Timestamp: time.Now(),
+			Details:   details,
+		}, nil
+	}
+	defer tlsConn.Close()
+	details["handshake_ms"] = latency.Milliseconds()
+
+	state := tlsConn.ConnectionState()
+	leaf := state.PeerCertificates[0]
+	daysRemaining := int(time.Until(leaf.NotAfter).Hours() / 24)
+
+	details["subject"] = leaf.Subject.String()
+	details["issuer"] = leaf.Issuer.String()
+	details["not_after"] = leaf.NotAfter
+	details["days_remaining"] = daysRemaining
+	details["tls_version"] = tls.VersionName(state.Version)
+	details["tls_cipher_suite"] = tls.CipherSuiteName(state.CipherSuite)
+
+	healthy, message := t.checkCertificate(ctx, state, daysRemaining, details)
+	if healthy && t.config.CheckResumption {
+		if resumed, resumeMessage := t.checkResumption(ctx, tlsConn, tlsConfig, details); resumed {
+			message += ", session resumed"
+		} else {
+			healthy, message = false, resumeMessage
+		}
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"address":        t.address,
+		"days_remaining": daysRemaining,
+		"healthy":        healthy,
+	}).Info("TLS healthcheck completed")
+
+	return &ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details:   details,
+	}, nil
+}
+
+// handshake dials the endpoint, negotiates STARTTLS if configured and completes a TLS
+// handshake, returning the connection and how long the handshake took. A failure is returned
+// with a message naming the step that failed.
+func (t *TLSScraper) handshake(ctx context.Context, tlsConfig *tls.Config) (*tls.Conn, time.Duration, string, error) {
+	conn, err := t.dial(ctx, "tcp", t.address)
+	if err != nil {
+		return nil, 0, fmt.Sprintf("Failed to connect to %s: %v", t.address, err), err
+	}
+
+	if t.config.StartTLS != "" {
+		if err := t.negotiateStartTLS(ctx, conn); err != nil {
+			conn.Close()
+			return nil, 0, fmt.Sprintf("STARTTLS negotiation with %s failed: %v", t.address, err), err
+		}
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	start := time.Now()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, 0, fmt.Sprintf("TLS handshake with %s failed: %v", t.address, err), err
+	}
+	return tlsConn, time.Since(start), "", nil
+}
+
+// negotiateStartTLS runs the configured protocol's STARTTLS exchange on the plaintext
+// connection, bounded by the context's deadline
+func (t *TLSScraper) negotiateStartTLS(ctx context.Context, conn net.Conn) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	// Unblock the exchange if the context is cancelled before it completes
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	return starttlsProtocols[t.config.StartTLS].negotiate(conn)
+}
+
+// checkCertificate applies the configured certificate checks to a verified connection
+func (t *TLSScraper) checkCertificate(ctx context.Context, state tls.ConnectionState, daysRemaining int, details map[string]interface{}) (bool, string) {
+	if t.config.MinDaysRemaining > 0 && daysRemaining < t.config.MinDaysRemaining {
+		return false, fmt.Sprintf("Certificate of %s expires in %d days, expected at least %d", t.address, daysRemaining, t.config.MinDaysRemaining)
+	}
+
+	if t.config.RequireSCT {
+		counts, err := countSCTs(state)
+		if err != nil {
+			details["error"] = err.Error()
+			return false, fmt.Sprintf("Failed to read SCTs of %s: %v", t.address, err)
+		}
+
+		details["sct_count"] = counts.total()
+		details["sct_sources"] = counts
+		if counts.total() == 0 {
+			return false, fmt.Sprintf("Certificate of %s has no signed certificate timestamps", t.address)
+		}
+	}
+
+	if t.config.CheckCRL {
+		status, err := checkCRL(ctx, t.crls, state)
+		if err != nil {
+			details["crl_error"] = err.Error()
+			if t.config.CRLHardFail {
+				return false, fmt.Sprintf("Failed to check CRL of %s: %v", t.address, err)
+			}
+			// Soft-fail: an unreachable CRL does not make the endpoint unhealthy
+			t.logger.WithError(err).WithField("address", t.address).Warn("CRL check failed")
+		} else {
+			details["crl_next_update"] = status.nextUpdate
+			details["crl_cached"] = status.cached
+			details["revoked"] = status.revoked
+			if status.revoked {
+				return false, fmt.Sprintf("Certificate of %s is revoked", t.address)
+			}
+		}
+	}
+
+	return true, fmt.Sprintf("TLS handshake with %s succeeded, certificate expires in %d days", t.address, daysRemaining)
+}
@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+)
+
+// resumptionTicketWait bounds how long the first connection of a resumption check waits for
+// the session tickets TLS 1.3 servers send after the handshake
+const resumptionTicketWait = 500 * time.Millisecond
+
+// checkResumption makes a second connection with the session of the first one and asserts it
+// was resumed, recording whether it was and the latency of the second handshake in details.
+// The message describes a failed resumption.
+func (t *TLSScraper) checkResumption(ctx context.Context, first *tls.Conn, tlsConfig *tls.Config, details map[string]interface{}) (bool, string) {
+	// TLS 1.3 session tickets arrive after the handshake and are only processed by a read,
+	// which ends at the deadline or when the server closes the connection
+	if first.ConnectionState().Version == tls.VersionTLS13 {
+		first.SetReadDeadline(time.Now().Add(resumptionTicketWait))
+		first.Read(make([]byte, 1))
+	}
+
+	second, latency, message, err := t.handshake(ctx, tlsConfig)
+	if err != nil {
+		details["error"] = err.Error()
+		return false, fmt.Sprintf("Resumption check failed: %s", message)
+	}
+	defer second.Close()
+
+	resumed := second.ConnectionState().DidResume
+	details["session_resumed"] = resumed
+	details["resumed_handshake_ms"] = latency.Milliseconds()
+
+	if !resumed {
+		return false, fmt.Sprintf("TLS session resumption with %s failed, the second handshake took %dms", t.address, latency.Milliseconds())
+	}
+	return true, ""
+}
@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startResumptionTLSServer accepts TLS connections with the given configuration until the
+// test ends, closing each connection after its handshake
+func startResumptionTLSServer(t *testing.T, tlsConfig *tls.Config) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestTLSScraper_Scrape_CheckResumption(t *testing.T) {
+	cert, roots := newTestCertificate(t, 90*24*time.Hour)
+
+	tests := []struct {
+		name           string
+		maxVersion     uint16
+		ticketsEnabled bool
+		healthy        bool
+	}{
+		{name: "TLS 1.3 tickets", maxVersion: tls.VersionTLS13, ticketsEnabled: true, healthy: true},
+		{name: "TLS 1.2 tickets", maxVersion: tls.VersionTLS12, ticketsEnabled: true, healthy: true},
+		{name: "TLS 1.3 without tickets", maxVersion: tls.VersionTLS13, healthy: false},
+		{name: "TLS 1.2 without tickets", maxVersion: tls.VersionTLS12, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startResumptionTLSServer(t, &tls.Config{
+				Certificates:           []tls.Certificate{cert},
+				MaxVersion:             tt.maxVersion,
+				SessionTicketsDisabled: !tt.ticketsEnabled,
+			})
+			scraper := newTestTLSScraper(t, address, roots, config.HealthcheckScraper{CheckResumption: true})
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.healthy, result.Details["session_resumed"])
+			assert.Contains(t, result.Details, "handshake_ms")
+			assert.Contains(t, result.Details, "resumed_handshake_ms")
+			if tt.healthy {
+				assert.Contains(t, result.Message, "session resumed")
+			} else {
+				assert.Contains(t, result.Message, "TLS session resumption with "+address+" failed")
+			}
+		})
+	}
+}
+
+func TestTLSScraper_Scrape_WithoutResumptionCheck(t *testing.T) {
+	cert, roots := newTestCertificate(t, 90*24*time.Hour)
+	address := startResumptionTLSServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+	scraper := newTestTLSScraper(t, address, roots, config.HealthcheckScraper{})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.NotContains(t, result.Details, "session_resumed")
+	assert.NotContains(t, result.Details, "resumed_handshake_ms")
+}
@@ -0,0 +1,210 @@
+package scraper
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate creates a self-signed certificate for 127.0.0.1 valid for the given
+// duration, with the given extra extensions
+func newTestCertificate(t *testing.T, validFor time.Duration, extensions ...pkix.Extension) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "healthcheck test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(validFor),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		ExtraExtensions:       extensions,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+// startTLSServer accepts TLS connections with the certificate until the test ends
+func startTLSServer(t *testing.T, cert tls.Certificate) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// newTestTLSScraper creates a TLS scraper for the address trusting the given roots
+func newTestTLSScraper(t *testing.T, address string, roots *x509.CertPool, scraperConfig config.HealthcheckScraper) *TLSScraper {
+	scraperConfig.ScrapeURL = address
+	scraper, err := NewTLSScraper(scraperConfig, logrus.New())
+	require.NoError(t, err)
+	scraper.rootCAs = roots
+	return scraper
+}
+
+func TestNewTLSScraper(t *testing.T) {
+	scraper, err := NewTLSScraper(config.HealthcheckScraper{
+		ScrapeURL: "https://example.com",
+		PingURL:   "http://localhost:8081/ping",
+	}, logrus.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, "tls", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+	assert.Equal(t, "example.com:443", scraper.address)
+	assert.Equal(t, "example.com", scraper.serverName)
+}
+
+func TestParseTLSAddress(t *testing.T) {
+	tests := []struct {
+		target     string
+		address    string
+		serverName string
+	}{
+		{"example.com:8443", "example.com:8443", "example.com"},
+		{"example.com", "example.com:443", "example.com"},
+		{"https://example.com:8443/health", "example.com:8443", "example.com"},
+		{"https://[::1]", "[::1]:443", "::1"},
+	}
+
+	for _, tt := range tests {
+		address, serverName, err := parseTLSAddress(tt.target, "443")
+		require.NoError(t, err, tt.target)
+		assert.Equal(t, tt.address, address, tt.target)
+		assert.Equal(t, tt.serverName, serverName, tt.target)
+	}
+
+	_, _, err := parseTLSAddress("https://", "443")
+	assert.Error(t, err)
+}
+
+func TestTLSScraper_Scrape_Success(t *testing.T) {
+	cert, roots := newTestCertificate(t, 90*24*time.Hour)
+	address := startTLSServer(t, cert)
+
+	scraper := newTestTLSScraper(t, address, roots, config.HealthcheckScraper{MinDaysRemaining: 30})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, "CN=healthcheck test", result.Details["subject"])
+	assert.InDelta(t, 89, result.Details["days_remaining"], 1)
+	assert.NotContains(t, result.Details, "sct_count")
+}
+
+func TestTLSScraper_Scrape_ExpiringSoon(t *testing.T) {
+	cert, roots := newTestCertificate(t, 10*24*time.Hour)
+	address := startTLSServer(t, cert)
+
+	scraper := newTestTLSScraper(t, address, roots, config.HealthcheckScraper{MinDaysRemaining: 30})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "expected at least 30")
+}
+
+func TestTLSScraper_Scrape_UntrustedCertificate(t *testing.T) {
+	cert, _ := newTestCertificate(t, 90*24*time.Hour)
+	address := startTLSServer(t, cert)
+
+	scraper := newTestTLSScraper(t, address, x509.NewCertPool(), config.HealthcheckScraper{})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "TLS handshake")
+}
+
+func TestTLSScraper_Scrape_RequireSCT(t *testing.T) {
+	withEmbedded, embeddedRoots := newTestCertificate(t, 90*24*time.Hour,
+		pkix.Extension{Id: oidEmbeddedSCTList, Value: encodeSCTExtension(t, []byte("sct-1"), []byte("sct-2"))})
+
+	withExtension, extensionRoots := newTestCertificate(t, 90*24*time.Hour)
+	withExtension.SignedCertificateTimestamps = [][]byte{[]byte("sct-1")}
+
+	withOCSP, ocspRoots := newTestCertificate(t, 90*24*time.Hour)
+	withOCSP.OCSPStaple = encodeOCSPResponse(t, []byte("sct-1"))
+
+	without, withoutRoots := newTestCertificate(t, 90*24*time.Hour)
+
+	tests := []struct {
+		name    string
+		cert    tls.Certificate
+		roots   *x509.CertPool
+		healthy bool
+		counts  sctCounts
+	}{
+		{name: "embedded in certificate", cert: withEmbedded, roots: embeddedRoots, healthy: true, counts: sctCounts{Certificate: 2}},
+		{name: "TLS extension", cert: withExtension, roots: extensionRoots, healthy: true, counts: sctCounts{TLSExtension: 1}},
+		{name: "stapled OCSP response", cert: withOCSP, roots: ocspRoots, healthy: true, counts: sctCounts{OCSP: 1}},
+		{name: "no SCTs", cert: without, roots: withoutRoots, healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startTLSServer(t, tt.cert)
+			scraper := newTestTLSScraper(t, address, tt.roots, config.HealthcheckScraper{RequireSCT: true})
+
+			result, err := scraper.Scrape(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.healthy, result.Healthy, result.Message)
+			assert.Equal(t, tt.counts.total(), result.Details["sct_count"])
+			assert.Equal(t, tt.counts, result.Details["sct_sources"])
+		})
+	}
+}
+
+func TestTLSScraper_Scrape_ConnectionError(t *testing.T) {
+	scraper := newTestTLSScraper(t, "127.0.0.1:1", nil, config.HealthcheckScraper{})
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to connect")
+}
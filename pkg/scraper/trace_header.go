@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"healthcheck/pkg/config"
+)
+
+// traceparentTransport adds a W3C traceparent header with a new trace ID to every request,
+// so each probe appears as its own trace in the tracing backend of the scraped service
+type traceparentTransport struct {
+	next http.RoundTripper
+}
+
+// traceTransport wraps the transport to inject a traceparent header when the scraper enables it
+func traceTransport(transport http.RoundTripper, scraperConfig config.HealthcheckScraper) http.RoundTripper {
+	if !scraperConfig.InjectTraceHeader {
+		return transport
+	}
+	return &traceparentTransport{next: transport}
+}
+
+// RoundTrip sends a copy of the request carrying a new traceparent
+func (t *traceparentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("Traceparent", newTraceparent())
+	return t.next.RoundTrip(req)
+}
+
+// newTraceparent returns a traceparent of a new sampled trace with random, non-zero trace and
+// parent IDs in the version 00 format 00-<trace-id>-<parent-id>-01
+func newTraceparent() string {
+	traceID := make([]byte, 16)
+	parentID := make([]byte, 8)
+	rand.Read(traceID)
+	rand.Read(parentID)
+	// All zero IDs are invalid, so always set one bit
+	traceID[15] |= 1
+	parentID[7] |= 1
+	return "00-" + hex.EncodeToString(traceID) + "-" + hex.EncodeToString(parentID) + "-01"
+}
+
+// responseTraceID returns the trace ID of the traceparent the response was requested with,
+// empty when none was sent
+func responseTraceID(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	parts := strings.Split(resp.Request.Header.Get("Traceparent"), "-")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"healthcheck/pkg/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// traceparentPattern matches a version 00 traceparent
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-01$`)
+
+func TestNewTraceparent(t *testing.T) {
+	first, second := newTraceparent(), newTraceparent()
+
+	assert.Regexp(t, traceparentPattern, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestTraceTransport_InjectsTraceparent(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Traceparent"))
+	}))
+	defer server.Close()
+
+	scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:              "http",
+		ScrapeURL:         server.URL,
+		InjectTraceHeader: true,
+	})
+	require.NoError(t, err)
+
+	first, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	second, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	// Every scrape starts a new trace, whose ID is reported in the details
+	require.Len(t, received, 2)
+	for i, result := range []*ScrapeResult{first, second} {
+		match := traceparentPattern.FindStringSubmatch(received[i])
+		require.NotNil(t, match, received[i])
+		assert.Equal(t, match[1], result.Details["trace_id"])
+	}
+	assert.NotEqual(t, first.Details["trace_id"], second.Details["trace_id"])
+}
+
+func TestTraceTransport_Disabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Traceparent"))
+	}))
+	defer server.Close()
+
+	scraper, err := NewFactory(logrus.New()).CreateScraper(config.HealthcheckScraper{
+		Type:      "http",
+		ScrapeURL: server.URL,
+	})
+	require.NoError(t, err)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.NotContains(t, result.Details, "trace_id")
+}
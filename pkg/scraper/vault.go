@@ -0,0 +1,171 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VaultHealthResponse represents the response from the Vault /v1/sys/health endpoint.
+// The /v1/sys/seal-status endpoint returns a compatible body without the standby
+// fields but with the unseal threshold and progress.
+type VaultHealthResponse struct {
+	Initialized        bool   `json:"initialized"`
+	Sealed             bool   `json:"sealed"`
+	Standby            bool   `json:"standby"`
+	PerformanceStandby bool   `json:"performance_standby"`
+	Version            string `json:"version"`
+	ClusterName        string `json:"cluster_name"`
+	Threshold          int    `json:"t"`
+	Progress           int    `json:"progress"`
+}
+
+// VaultScraper implements the Scraper interface for HashiCorp Vault healthchecks
+type VaultScraper struct {
+	httpOptions
+	scraperType           string
+	scrapeURL             string
+	pingURL               string
+	scrapeIntervalSeconds int
+	namespace             string
+	standbyHealthy        bool
+	logger                *logrus.Logger
+	client                *http.Client
+}
+
+// NewVaultScraper creates a new Vault scraper
+func NewVaultScraper(scrapeURL, pingURL string, scrapeIntervalSeconds int, namespace string, standbyHealthy bool, logger *logrus.Logger) *VaultScraper {
+	// Set default interval if not specified
+	if scrapeIntervalSeconds <= 0 {
+		scrapeIntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	return &VaultScraper{
+		scraperType:           "vault",
+		scrapeURL:             scrapeURL,
+		pingURL:               pingURL,
+		scrapeIntervalSeconds: scrapeIntervalSeconds,
+		namespace:             namespace,
+		standbyHealthy:        standbyHealthy,
+		logger:                logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Type returns the scraper type identifier
+func (v *VaultScraper) Type() string {
+	return v.scraperType
+}
+
+// GetPingURL returns the URL to ping on successful healthcheck
+func (v *VaultScraper) GetPingURL() string {
+	return v.pingURL
+}
+
+// GetScrapeInterval returns the scrape interval in seconds
+func (v *VaultScraper) GetScrapeInterval() int {
+	return v.scrapeIntervalSeconds
+}
+
+// Close closes the idle connections of the scraper's HTTP client
+func (v *VaultScraper) Close() error {
+	v.client.CloseIdleConnections()
+	return nil
+}
+
+// Scrape performs the healthcheck by calling the /v1/sys/health or /v1/sys/seal-status endpoint
+func (v *VaultScraper) Scrape(ctx context.Context) (*ScrapeResult, error) {
+	v.logger.WithField("url", v.scrapeURL).Debug("Starting Vault healthcheck")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", v.scrapeURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return v.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("Failed to connect to %s: %v", v.scrapeURL, err),
+			Timestamp: time.Now(),
+			Details: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, nil), nil
+	}
+	defer resp.Body.Close()
+
+	// Vault encodes its state in the status code and still returns the health body for
+	// standby (429, 473), DR secondary (472), uninitialized (501) and sealed (503) nodes
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusTooManyRequests, 472, 473, http.StatusNotImplemented, http.StatusServiceUnavailable:
+	default:
+		return v.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   fmt.Sprintf("HTTP status %d from %s", resp.StatusCode, v.scrapeURL),
+			Timestamp: time.Now(),
+			Details: map[string]interface{}{
+				DetailStatusCode: resp.StatusCode,
+			},
+		}, resp), nil
+	}
+
+	var healthResp VaultHealthResponse
+	if err := decodeJSON(resp.Body, &healthResp); err != nil {
+		details := map[string]interface{}{}
+		return v.decorate(&ScrapeResult{
+			Healthy:   false,
+			Message:   parseFailure(v.scrapeURL, err, details),
+			Timestamp: time.Now(),
+			Details:   details,
+		}, resp), nil
+	}
+
+	standby := healthResp.Standby || healthResp.PerformanceStandby
+
+	var healthy bool
+	var message string
+	switch {
+	case !healthResp.Initialized:
+		message = "Vault is not initialized"
+	case healthResp.Sealed && healthResp.Threshold > 0:
+		message = fmt.Sprintf("Vault is sealed (unseal progress %d/%d)", healthResp.Progress, healthResp.Threshold)
+	case healthResp.Sealed:
+		message = "Vault is sealed"
+	case standby && !v.standbyHealthy:
+		message = "Vault is in standby"
+	default:
+		healthy = true
+		message = fmt.Sprintf("Vault unsealed (version %s)", healthResp.Version)
+	}
+
+	v.logger.WithFields(logrus.Fields{
+		"status_code": resp.StatusCode,
+		"initialized": healthResp.Initialized,
+		"sealed":      healthResp.Sealed,
+		"standby":     standby,
+		"version":     healthResp.Version,
+		"healthy":     healthy,
+	}).Info("Vault healthcheck completed")
+
+	return v.decorate(&ScrapeResult{
+		Healthy:   healthy,
+		Message:   message,
+		Timestamp: time.Now(),
+		Details: map[string]interface{}{
+			DetailStatusCode: resp.StatusCode,
+			"initialized":    healthResp.Initialized,
+			"sealed":         healthResp.Sealed,
+			"standby":        standby,
+			"version":        healthResp.Version,
+		},
+	}, resp), nil
+}
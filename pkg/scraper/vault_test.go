@@ -0,0 +1,176 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVaultScraper(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewVaultScraper("http://localhost:8200/v1/sys/health", "http://localhost:8081/ping", 60, "", false, logger)
+
+	assert.Equal(t, "vault", scraper.Type())
+	assert.Equal(t, "http://localhost:8081/ping", scraper.GetPingURL())
+	assert.Equal(t, 60, scraper.GetScrapeInterval())
+	assert.NotNil(t, scraper.client)
+}
+
+func TestNewVaultScraper_DefaultInterval(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewVaultScraper("http://localhost:8200/v1/sys/health", "http://localhost:8081/ping", 0, "", false, logger)
+
+	assert.Equal(t, 30, scraper.GetScrapeInterval()) // Should default to 30 seconds
+}
+
+func TestVaultScraper_Scrape_Unsealed(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"initialized":true,"sealed":false,"standby":false,"version":"1.15.2"}`))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Contains(t, result.Message, "Vault unsealed (version 1.15.2)")
+	assert.Equal(t, false, result.Details["sealed"])
+	assert.Equal(t, false, result.Details["standby"])
+	assert.Equal(t, "1.15.2", result.Details["version"])
+}
+
+func TestVaultScraper_Scrape_Sealed(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusServiceUnavailable, "application/json", `{"initialized":true,"sealed":true,"standby":true,"version":"1.15.2"}`))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", true, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Vault is sealed")
+	assert.Equal(t, true, result.Details["sealed"])
+	assert.Equal(t, 503, result.Details["status_code"])
+}
+
+func TestVaultScraper_Scrape_NotInitialized(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusNotImplemented, "application/json", `{"initialized":false,"sealed":true,"standby":true}`))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Vault is not initialized")
+}
+
+func TestVaultScraper_Scrape_Standby(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusTooManyRequests, "application/json", `{"initialized":true,"sealed":false,"standby":true,"version":"1.15.2"}`))
+
+	logger := logrus.New()
+
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Vault is in standby")
+	assert.Equal(t, true, result.Details["standby"])
+
+	// Standby nodes are healthy when configured as acceptable
+	scraper = NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", true, logger)
+	result, err = scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+}
+
+func TestVaultScraper_Scrape_PerformanceStandby(t *testing.T) {
+	server := newTestServer(t, respond(473, "application/json", `{"initialized":true,"sealed":false,"standby":false,"performance_standby":true}`))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, true, result.Details["standby"])
+}
+
+func TestVaultScraper_Scrape_Namespace(t *testing.T) {
+	var namespace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"initialized":true,"sealed":false,"standby":false}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "team-a", false, logger)
+
+	_, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", namespace)
+}
+
+func TestVaultScraper_Scrape_UnexpectedStatus(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusInternalServerError, "application/json", ``))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "HTTP status 500")
+}
+
+func TestVaultScraper_Scrape_ConnectionError(t *testing.T) {
+	logger := logrus.New()
+	scraper := NewVaultScraper("http://localhost:99999/v1/sys/health", "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Failed to connect to")
+}
+
+func TestVaultScraper_Scrape_SealStatusSealed(t *testing.T) {
+	// /v1/sys/seal-status always answers 200 and reports the unseal progress
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"type":"shamir","initialized":true,"sealed":true,"t":3,"n":5,"progress":1,"version":"1.15.2"}`))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "Vault is sealed (unseal progress 1/3)")
+}
+
+func TestVaultScraper_Scrape_SealStatusUnsealed(t *testing.T) {
+	server := newTestServer(t, respond(http.StatusOK, "application/json", `{"type":"shamir","initialized":true,"sealed":false,"t":3,"n":5,"progress":0,"version":"1.15.2"}`))
+
+	logger := logrus.New()
+	scraper := NewVaultScraper(server.URL, "http://localhost:8081/ping", 30, "", false, logger)
+
+	result, err := scraper.Scrape(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, "1.15.2", result.Details["version"])
+}